@@ -3,12 +3,21 @@ package main
 import (
 	"context"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/varubogu/gbf_discord_bot_go/internal/config"
 	"github.com/varubogu/gbf_discord_bot_go/internal/discord"
+	"github.com/varubogu/gbf_discord_bot_go/internal/lifecycle"
 	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+	"github.com/varubogu/gbf_discord_bot_go/internal/version"
 )
 
+// shutdownTimeout bounds how long in-flight work may take to drain on shutdown
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Load configuration from environment variables
 	cfg, err := config.Load()
@@ -22,22 +31,52 @@ func main() {
 	logger := log.InitLogger(cfg.LogLevel)
 	log.SetGlobalLogger(logger)
 
-	logger.Info("Starting GBF Discord Bot", "version", "v0.1.0")
+	logger.Info("Starting GBF Discord Bot", "version", version.String())
 	logger.Debug("Configuration loaded", "log_level", cfg.LogLevel)
 
+	// Create context for graceful shutdown, cancelled on interrupt or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The lifecycle manager drains in-flight work before running shutdown hooks.
+	// Hooks run in reverse order: Discord session, storage, then logs.
+	lc := lifecycle.New(logger)
+	lc.OnShutdown("logs", func(ctx context.Context) error {
+		return log.Sync()
+	})
+
+	// Open persistent storage
+	store, err := storage.Open(ctx, cfg, logger)
+	if err != nil {
+		logger.Error("Failed to open storage", "error", err.Error())
+		os.Exit(1)
+	}
+	lc.OnShutdown("storage", func(ctx context.Context) error {
+		return store.Close()
+	})
+
 	// Create Discord bot instance
-	bot, err := discord.New(cfg, logger)
+	bot, err := discord.New(cfg, logger, store, lc)
 	if err != nil {
 		logger.Error("Failed to create Discord bot", "error", err.Error())
+		_ = lc.Shutdown(shutdownTimeout)
 		os.Exit(1)
 	}
 
-	// Create context for graceful shutdown
-	ctx := context.Background()
+	// Start the bot (this will block until shutdown is requested)
+	runErr := bot.Start(ctx)
+	if runErr != nil {
+		logger.Error("Bot encountered an error", "error", runErr.Error())
+	}
+
+	// Stop listening for signals so a second interrupt terminates immediately
+	stop()
 
-	// Start the bot (this will block until shutdown)
-	if err := bot.Start(ctx); err != nil {
-		logger.Error("Bot encountered an error", "error", err.Error())
+	if err := lc.Shutdown(shutdownTimeout); err != nil {
+		logger.Error("Shutdown did not complete cleanly", "error", err.Error())
+		os.Exit(1)
+	}
+	if runErr != nil {
 		os.Exit(1)
 	}
 
@@ -1,46 +1,93 @@
 package commands
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/config"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
 	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/permissions"
 )
 
-// AdminCommand handles admin command functionality
+// reloadTimeout bounds how long a reload may take
+const reloadTimeout = 30 * time.Second
+
+// Reloader reloads configuration and data of the running bot
+type Reloader interface {
+	Reload(ctx context.Context) (*ReloadReport, error)
+}
+
+// ReloadReport summarizes what changed during a reload
+type ReloadReport struct {
+	ConfigChanges       []config.Change
+	Battles             gbf.CatalogDiff
+	ChangedGuildIDs     []string
+	SyncedCommandsCount int
+}
+
+// statusTimeout bounds how long collecting status information may take.
+// The slash command defers its response first, so this only has to stay well
+// within the 15 minutes Discord allows for editing a deferred response.
+const statusTimeout = 5 * time.Second
+
+// StatusProvider supplies runtime information for the status command
+type StatusProvider interface {
+	Status(ctx context.Context, includeStorage bool) *StatusInfo
+}
+
+// StatusInfo is a snapshot of the bot's runtime state
+type StatusInfo struct {
+	Version            string
+	Commit             string
+	BuildDate          string
+	Uptime             time.Duration
+	HeartbeatLatency   time.Duration
+	GuildCount         int
+	Goroutines         int
+	HeapAlloc          uint64
+	SysMemory          uint64
+	ActiveRecruitments int
+	LastReload         time.Time
+	Commands           []string
+
+	// Storage health, only collected for admins
+	StorageChecked bool
+	StorageLatency time.Duration
+	StorageError   error
+}
+
+// AdminCommand handles admin command functionality.
+// Access control is enforced by the bot through the permissions package.
 type AdminCommand struct {
-	logger          *log.Logger
-	controlRoleID   string
-	controlRoleName string
+	logger      *log.Logger
+	locales     *i18n.Resolver
+	reloader    Reloader
+	status      StatusProvider
+	permissions *permissions.Manager
 }
 
 // NewAdminCommand creates a new admin command handler
-func NewAdminCommand(logger *log.Logger) *AdminCommand {
+func NewAdminCommand(logger *log.Logger, locales *i18n.Resolver, reloader Reloader, status StatusProvider, permissionManager *permissions.Manager) *AdminCommand {
 	return &AdminCommand{
-		logger:          logger,
-		controlRoleName: "gbf_bot_control", // Default role name matching Python bot
+		logger:      logger,
+		locales:     locales,
+		reloader:    reloader,
+		status:      status,
+		permissions: permissionManager,
 	}
 }
 
-// SetControlRole sets the control role for admin commands
-func (a *AdminCommand) SetControlRole(roleID, roleName string) {
-	a.controlRoleID = roleID
-	a.controlRoleName = roleName
-}
-
 // HandleReloadPrefixCommand handles the prefix version of reload command (!reload)
-func (a *AdminCommand) HandleReloadPrefixCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
+func (a *AdminCommand) HandleReloadPrefixCommand(s Session, m *discordgo.MessageCreate) {
 	logger := a.logger.WithDiscordContext(m.GuildID, m.ChannelID, m.Author.ID).WithCommand("reload")
 
-	// Check permissions
-	if !a.hasAdminPermission(s, m.GuildID, m.Author.ID, logger) {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ You don't have permission to use this command. Required role: `"+a.controlRoleName+"`")
-		if err != nil {
-			logger.WithError(err).Error("Failed to send permission denied message")
-		}
-		return
-	}
-
 	// Perform reload operation
-	result := a.performReload(logger)
+	result := a.performReload(a.locales.Message(m), logger)
 
 	_, err := s.ChannelMessageSend(m.ChannelID, result)
 	if err != nil {
@@ -52,36 +99,29 @@ func (a *AdminCommand) HandleReloadPrefixCommand(s *discordgo.Session, m *discor
 }
 
 // HandleReloadSlashCommand handles the slash version of reload command (/reload)
-func (a *AdminCommand) HandleReloadSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func (a *AdminCommand) HandleReloadSlashCommand(s Session, i *discordgo.InteractionCreate) {
 	logger := a.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("reload")
 
-	// Check permissions
-	if !a.hasAdminPermissionInteraction(s, i, logger) {
-		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "❌ You don't have permission to use this command. Required role: `" + a.controlRoleName + "`",
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
-		if err != nil {
-			logger.WithError(err).Error("Failed to respond with permission denied")
-		}
+	// A reload can outlast the initial response deadline, so defer and edit in the report
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to reload slash command")
 		return
 	}
 
 	// Perform reload operation
-	result := a.performReload(logger)
+	result := a.performReload(a.locales.Interaction(i), logger)
 
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: result,
-			Flags:   discordgo.MessageFlagsEphemeral,
-		},
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &result,
 	})
 	if err != nil {
-		logger.WithError(err).Error("Failed to respond to reload slash command")
+		logger.WithError(err).Error("Failed to edit reload slash command response")
 		return
 	}
 
@@ -97,10 +137,11 @@ func (a *AdminCommand) GetReloadSlashCommandDefinition() *discordgo.ApplicationC
 }
 
 // HandleStatusPrefixCommand handles the prefix version of status command (!status)
-func (a *AdminCommand) HandleStatusPrefixCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
+func (a *AdminCommand) HandleStatusPrefixCommand(s Session, m *discordgo.MessageCreate) {
 	logger := a.logger.WithDiscordContext(m.GuildID, m.ChannelID, m.Author.ID).WithCommand("status")
 
-	embed := a.buildStatusEmbed()
+	subject := permissions.SubjectFromMessage(s, m)
+	embed := a.buildStatusEmbed(a.locales.Message(m), a.isAdmin(s, subject))
 
 	_, err := s.ChannelMessageSendEmbed(m.ChannelID, embed)
 	if err != nil {
@@ -112,22 +153,29 @@ func (a *AdminCommand) HandleStatusPrefixCommand(s *discordgo.Session, m *discor
 }
 
 // HandleStatusSlashCommand handles the slash version of status command (/status)
-func (a *AdminCommand) HandleStatusSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func (a *AdminCommand) HandleStatusSlashCommand(s Session, i *discordgo.InteractionCreate) {
 	logger := a.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("status")
 
-	embed := a.buildStatusEmbed()
-
+	// The storage health check can outlast the initial response deadline, so defer and edit in the embed
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{embed},
-		},
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 	})
 	if err != nil {
 		logger.WithError(err).Error("Failed to respond to status slash command")
 		return
 	}
 
+	subject := permissions.SubjectFromInteraction(i)
+	embed := a.buildStatusEmbed(a.locales.Interaction(i), a.isAdmin(s, subject))
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to edit status slash command response")
+		return
+	}
+
 	logger.Info("Status slash command executed successfully")
 }
 
@@ -139,100 +187,149 @@ func (a *AdminCommand) GetStatusSlashCommandDefinition() *discordgo.ApplicationC
 	}
 }
 
-// hasAdminPermission checks if user has admin permission for message commands
-func (a *AdminCommand) hasAdminPermission(s *discordgo.Session, guildID, userID string, logger *log.Logger) bool {
-	if guildID == "" {
-		return false // No permission check in DM
-	}
-
-	member, err := s.GuildMember(guildID, userID)
-	if err != nil {
-		logger.WithError(err).Error("Failed to get guild member")
-		return false
-	}
+// performReload performs the actual reload operation
+func (a *AdminCommand) performReload(tr i18n.Localizer, logger *log.Logger) string {
+	logger.Info("Performing bot reload operation")
 
-	return a.checkMemberRoles(s, guildID, member, logger)
-}
+	ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+	defer cancel()
 
-// hasAdminPermissionInteraction checks if user has admin permission for interactions
-func (a *AdminCommand) hasAdminPermissionInteraction(s *discordgo.Session, i *discordgo.InteractionCreate, logger *log.Logger) bool {
-	if i.GuildID == "" {
-		return false // No permission check in DM
+	report, err := a.reloader.Reload(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Reload failed")
+		if report == nil {
+			return tr.T("reload.failed", err)
+		}
+		return tr.T("reload.partially_failed", err) + "\n" + FormatReloadReport(tr, report)
 	}
 
-	if i.Member == nil {
-		return false
-	}
+	logger.Info("Reload completed",
+		"config_changes", len(report.ConfigChanges),
+		"battles_added", len(report.Battles.Added),
+		"battles_removed", len(report.Battles.Removed),
+		"battles_changed", len(report.Battles.Changed),
+		"guilds_changed", len(report.ChangedGuildIDs))
 
-	return a.checkMemberRoles(s, i.GuildID, i.Member, logger)
+	return tr.T("reload.succeeded") + "\n" + FormatReloadReport(tr, report)
 }
 
-// checkMemberRoles checks if member has required roles
-func (a *AdminCommand) checkMemberRoles(s *discordgo.Session, guildID string, member *discordgo.Member, logger *log.Logger) bool {
-	guild, err := s.Guild(guildID)
-	if err != nil {
-		logger.WithError(err).Error("Failed to get guild")
-		return false
-	}
-
-	// Check for Administrator permission
-	permissions, err := s.UserChannelPermissions(member.User.ID, guildID)
-	if err == nil && permissions&discordgo.PermissionAdministrator != 0 {
-		return true
+// FormatReloadReport formats a reload report as a diff summary
+func FormatReloadReport(tr i18n.Localizer, report *ReloadReport) string {
+	var lines []string
+
+	if len(report.ConfigChanges) == 0 {
+		lines = append(lines, tr.T("reload.report.config_unchanged"))
+	} else {
+		var keys []string
+		for _, change := range report.ConfigChanges {
+			key := "`" + change.Key + "`"
+			if change.RestartRequired {
+				key += tr.T("reload.report.restart_required")
+			}
+			keys = append(keys, key)
+		}
+		lines = append(lines, tr.T("reload.report.config", strings.Join(keys, ", ")))
 	}
 
-	// Check for control role by name or ID
-	for _, roleID := range member.Roles {
-		for _, role := range guild.Roles {
-			if role.ID == roleID {
-				if role.Name == a.controlRoleName || role.ID == a.controlRoleID {
-					return true
-				}
+	if report.Battles.IsEmpty() {
+		lines = append(lines, tr.T("reload.report.battles_unchanged"))
+	} else {
+		lines = append(lines, tr.T("reload.report.battles",
+			len(report.Battles.Added), len(report.Battles.Removed), len(report.Battles.Changed)))
+		for _, group := range []struct {
+			label string
+			ids   []string
+		}{
+			{tr.T("reload.report.added"), report.Battles.Added},
+			{tr.T("reload.report.removed"), report.Battles.Removed},
+			{tr.T("reload.report.changed"), report.Battles.Changed},
+		} {
+			if len(group.ids) > 0 {
+				lines = append(lines, fmt.Sprintf("  %s: `%s`", group.label, strings.Join(group.ids, "`, `")))
 			}
 		}
 	}
 
-	return false
-}
+	if len(report.ChangedGuildIDs) == 0 {
+		lines = append(lines, tr.T("reload.report.guilds_unchanged"))
+	} else {
+		lines = append(lines, tr.T("reload.report.guilds", len(report.ChangedGuildIDs)))
+	}
 
-// performReload performs the actual reload operation
-func (a *AdminCommand) performReload(logger *log.Logger) string {
-	logger.Info("Performing bot reload operation")
+	lines = append(lines, tr.T("reload.report.commands", report.SyncedCommandsCount))
 
-	// In a real implementation, this would:
-	// - Reload configuration
-	// - Refresh external connections
-	// - Update command registrations
-	// - Clear caches
+	return strings.Join(lines, "\n")
+}
 
-	// For now, simulate a successful reload
-	return "✅ Bot components reloaded successfully!"
+// isAdmin reports whether the subject may use admin commands
+func (a *AdminCommand) isAdmin(s Session, subject permissions.Subject) bool {
+	return a.permissions.Check(context.Background(), s, subject, "reload").Allowed
 }
 
 // buildStatusEmbed builds the status embed message
-func (a *AdminCommand) buildStatusEmbed() *discordgo.MessageEmbed {
-	return &discordgo.MessageEmbed{
-		Title: "Bot Status",
+func (a *AdminCommand) buildStatusEmbed(tr i18n.Localizer, includeStorage bool) *discordgo.MessageEmbed {
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+
+	info := a.status.Status(ctx, includeStorage)
+
+	lastReload := tr.T("status.never")
+	if !info.LastReload.IsZero() {
+		lastReload = fmt.Sprintf("<t:%d:R>", info.LastReload.Unix())
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: tr.T("status.title"),
 		Color: 0x00ff00,
 		Fields: []*discordgo.MessageEmbedField{
 			{
-				Name:   "Status",
-				Value:  "✅ Online",
+				Name:   tr.T("status.status"),
+				Value:  tr.T("status.online"),
+				Inline: true,
+			},
+			{
+				Name:   tr.T("status.version"),
+				Value:  formatVersion(tr, info),
+				Inline: true,
+			},
+			{
+				Name:   tr.T("status.uptime"),
+				Value:  formatDuration(tr, info.Uptime),
+				Inline: true,
+			},
+			{
+				Name:   tr.T("status.gateway_latency"),
+				Value:  formatLatency(tr, info.HeartbeatLatency),
+				Inline: true,
+			},
+			{
+				Name:   tr.T("status.guilds"),
+				Value:  fmt.Sprintf("%d", info.GuildCount),
 				Inline: true,
 			},
 			{
-				Name:   "Version",
-				Value:  "v0.1.0",
+				Name:   tr.T("status.active_recruitments"),
+				Value:  fmt.Sprintf("%d", info.ActiveRecruitments),
 				Inline: true,
 			},
 			{
-				Name:   "Language",
-				Value:  "Go",
+				Name:   tr.T("status.goroutines"),
+				Value:  fmt.Sprintf("%d", info.Goroutines),
 				Inline: true,
 			},
 			{
-				Name:   "Commands",
-				Value:  "ping, help, reload, status",
+				Name:   tr.T("status.memory"),
+				Value:  fmt.Sprintf("%.1f MiB heap / %.1f MiB sys", mebibytes(info.HeapAlloc), mebibytes(info.SysMemory)),
+				Inline: true,
+			},
+			{
+				Name:   tr.T("status.last_reload"),
+				Value:  lastReload,
+				Inline: true,
+			},
+			{
+				Name:   tr.T("status.commands"),
+				Value:  strings.Join(info.Commands, ", "),
 				Inline: false,
 			},
 		},
@@ -240,4 +337,57 @@ func (a *AdminCommand) buildStatusEmbed() *discordgo.MessageEmbed {
 			Text: "GBF Discord Bot - Go Edition",
 		},
 	}
+
+	if info.StorageChecked {
+		storage := tr.T("status.storage_ok", formatLatency(tr, info.StorageLatency))
+		if info.StorageError != nil {
+			storage = "❌ " + info.StorageError.Error()
+			embed.Color = 0xf39c12
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr.T("status.storage"),
+			Value:  storage,
+			Inline: false,
+		})
+	}
+
+	return embed
+}
+
+// formatVersion formats the version, commit and build date of the bot
+func formatVersion(tr i18n.Localizer, info *StatusInfo) string {
+	value := fmt.Sprintf("%s (`%s`)", info.Version, info.Commit)
+	if info.BuildDate != "" {
+		value += "\n" + tr.T("status.built", info.BuildDate)
+	}
+	return value
+}
+
+// formatDuration formats an uptime such as "3d 4h 5m"
+func formatDuration(tr i18n.Localizer, d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	if days > 0 {
+		return tr.T("duration.days", days, hours, minutes)
+	}
+	if hours > 0 {
+		return tr.T("duration.hours", hours, minutes)
+	}
+	return tr.T("duration.minutes", minutes)
+}
+
+// formatLatency formats a latency in milliseconds
+func formatLatency(tr i18n.Localizer, d time.Duration) string {
+	if d <= 0 {
+		return tr.T("common.not_available")
+	}
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
+
+// mebibytes converts bytes to MiB
+func mebibytes(bytes uint64) float64 {
+	return float64(bytes) / (1024 * 1024)
 }
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/permissions"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+// recordingReloader records how many interaction responses were sent before it ran
+type recordingReloader struct {
+	session         *commandstest.Session
+	respondedBefore int
+}
+
+func (r *recordingReloader) Reload(ctx context.Context) (*ReloadReport, error) {
+	r.respondedBefore = len(r.session.Responses())
+	return &ReloadReport{}, nil
+}
+
+func TestAdminCommand_HandleReloadSlashCommand(t *testing.T) {
+	s := commandstest.NewSession()
+	reloader := &recordingReloader{session: s}
+	a := NewAdminCommand(log.InitLogger("error"), i18n.NewResolver(nil), reloader, nil, nil)
+
+	a.HandleReloadSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("admin1"), "reload"))
+
+	if reloader.respondedBefore != 1 {
+		t.Fatalf("reload ran after %d responses, expected the deferred response first", reloader.respondedBefore)
+	}
+	response := s.LastResponse()
+	if response.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Errorf("response type = %v, expected a deferred response", response.Type)
+	}
+	if response.Data == nil || response.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Error("deferred response is not ephemeral")
+	}
+	edits := s.ResponseEdits()
+	if len(edits) != 1 {
+		t.Fatalf("got %d response edits, expected the report", len(edits))
+	}
+	if content := *edits[0].Content; !strings.Contains(content, "reloaded successfully") {
+		t.Errorf("report = %q", content)
+	}
+}
+
+// recordingStatus records how many interaction responses were sent before it ran
+type recordingStatus struct {
+	session         *commandstest.Session
+	respondedBefore int
+}
+
+func (r *recordingStatus) Status(ctx context.Context, includeStorage bool) *StatusInfo {
+	r.respondedBefore = len(r.session.Responses())
+	return &StatusInfo{Version: "v1.2.3", Commit: "abc123"}
+}
+
+func TestAdminCommand_HandleStatusSlashCommand(t *testing.T) {
+	s := commandstest.NewSession()
+	status := &recordingStatus{session: s}
+	a := NewAdminCommand(log.InitLogger("error"), i18n.NewResolver(nil), nil, status, permissions.NewManager(storage.NewMemoryStore()))
+
+	a.HandleStatusSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("u1"), "status"))
+
+	if status.respondedBefore != 1 {
+		t.Fatalf("status was collected after %d responses, expected the deferred response first", status.respondedBefore)
+	}
+	if response := s.LastResponse(); response.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Errorf("response type = %v, expected a deferred response", response.Type)
+	}
+	edits := s.ResponseEdits()
+	if len(edits) != 1 || edits[0].Embeds == nil || len(*edits[0].Embeds) != 1 {
+		t.Fatalf("got edits %v, expected the status embed", edits)
+	}
+	if value := commandstest.FieldValue((*edits[0].Embeds)[0], "Version"); !strings.Contains(value, "v1.2.3") {
+		t.Errorf("version field = %q", value)
+	}
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+// Discord limits of autocomplete results
+const (
+	maxAutocompleteChoices = 25
+	maxChoiceNameLength    = 100
+)
+
+// focusedOption returns the option being typed in an autocomplete interaction and
+// the name of its subcommand path, e.g. "calendar remove", or nil if there is none
+func focusedOption(options []*discordgo.ApplicationCommandInteractionDataOption) (*discordgo.ApplicationCommandInteractionDataOption, string) {
+	for _, option := range options {
+		switch option.Type {
+		case discordgo.ApplicationCommandOptionSubCommand, discordgo.ApplicationCommandOptionSubCommandGroup:
+			if focused, path := focusedOption(option.Options); focused != nil {
+				if path == "" {
+					return focused, option.Name
+				}
+				return focused, option.Name + " " + path
+			}
+		default:
+			if option.Focused {
+				return option, ""
+			}
+		}
+	}
+	return nil, ""
+}
+
+// focusedText returns what the user has typed so far in the focused option
+func focusedText(option *discordgo.ApplicationCommandInteractionDataOption) string {
+	if option == nil || option.Value == nil {
+		return ""
+	}
+	// Numbers being typed may arrive as strings
+	return fmt.Sprint(option.Value)
+}
+
+// choiceName shortens a choice name to what Discord accepts
+func choiceName(name string) string {
+	if runes := []rune(name); len(runes) > maxChoiceNameLength {
+		return string(runes[:maxChoiceNameLength-1]) + "…"
+	}
+	return name
+}
+
+// battleChoices suggests the battles matching query, named in the user's language
+func battleChoices(tr i18n.Localizer, battleManager *gbf.BattleManager, query string) []*discordgo.ApplicationCommandOptionChoice {
+	battles := battleManager.Search(query, maxAutocompleteChoices)
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(battles))
+	for _, battle := range battles {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  choiceName(fmt.Sprintf("%s (%s)", battleDisplayName(tr, battle), battle.ID)),
+			Value: battle.ID,
+		})
+	}
+	return choices
+}
+
+// respondAutocomplete sends the suggestions for the option being typed
+func respondAutocomplete(s Session, i *discordgo.InteractionCreate, logger *log.Logger, choices []*discordgo.ApplicationCommandOptionChoice) {
+	if len(choices) > maxAutocompleteChoices {
+		choices = choices[:maxAutocompleteChoices]
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to autocomplete")
+	}
+}
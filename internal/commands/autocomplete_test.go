@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+func TestBattleCommand_Autocomplete(t *testing.T) {
+	c := NewBattleCommand(log.InitLogger("error"), i18n.NewResolver(nil), gbf.NewBattleManager())
+	s := commandstest.NewSession()
+
+	tests := []struct {
+		name        string
+		command     string
+		option      string
+		query       string
+		userLocale  discordgo.Locale
+		wantValues  []string
+		wantFirstAs string
+	}{
+		{
+			name:        "battle types by ID",
+			command:     "battles",
+			option:      "type",
+			query:       "baha",
+			wantValues:  []string{"baha_hl", "ubaha_hl"},
+			wantFirstAs: "Baha HL",
+		},
+		{
+			name:        "battle types by Japanese label",
+			command:     "battles",
+			option:      "type",
+			query:       "古戦場",
+			userLocale:  discordgo.Japanese,
+			wantValues:  []string{"gw_nm"},
+			wantFirstAs: "古戦場 NM",
+		},
+		{
+			name:        "battles by alias",
+			command:     "battle",
+			option:      "id",
+			query:       "pbhl",
+			wantValues:  []string{"baha_hl"},
+			wantFirstAs: "Proto Bahamut (Hard) (baha_hl)",
+		},
+		{
+			name:        "battles by Japanese name, named in Japanese",
+			command:     "battle",
+			option:      "id",
+			query:       "ダークラプチャー",
+			userLocale:  discordgo.Japanese,
+			wantValues:  []string{"faa_hl"},
+			wantFirstAs: "ダークラプチャーHARD (faa_hl)",
+		},
+		{
+			name:    "no match",
+			command: "battle",
+			option:  "id",
+			query:   "zzz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := commandstest.Autocomplete(testGuildID, testChannelID, commandstest.Member("user1"), tt.command,
+				commandstest.Focused(commandstest.StringOption(tt.option, tt.query)))
+			i.Locale = tt.userLocale
+
+			c.HandleAutocomplete(s, i)
+
+			values := choiceValues(t, s)
+			if !slices.Equal(values, tt.wantValues) {
+				t.Errorf("choices = %v, want %v", values, tt.wantValues)
+			}
+			if tt.wantFirstAs != "" && len(values) > 0 {
+				if got := s.LastResponse().Data.Choices[0].Name; got != tt.wantFirstAs {
+					t.Errorf("first choice name = %q, want %q", got, tt.wantFirstAs)
+				}
+			}
+		})
+	}
+}
+
+func TestFocusedOption(t *testing.T) {
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		commandstest.SubcommandGroup("calendar", commandstest.Subcommand("remove",
+			commandstest.StringOption("reason", "done"),
+			commandstest.Focused(commandstest.StringOption("id", "12")),
+		)),
+	}
+
+	option, path := focusedOption(options)
+	if option == nil || option.Name != "id" || path != "calendar remove" {
+		t.Fatalf("focusedOption() = %+v, %q, want id in calendar remove", option, path)
+	}
+	if got := focusedText(option); got != "12" {
+		t.Errorf("focusedText() = %q, want 12", got)
+	}
+
+	if option, _ := focusedOption(options[0].Options[0].Options[:1]); option != nil {
+		t.Errorf("focusedOption() without a focused option = %+v, want nil", option)
+	}
+}
+
+func TestChoiceName(t *testing.T) {
+	short := "Proto Bahamut"
+	if got := choiceName(short); got != short {
+		t.Errorf("choiceName(%q) = %q", short, got)
+	}
+
+	long := ""
+	for len([]rune(long)) < 150 {
+		long += "バハムート"
+	}
+	got := []rune(choiceName(long))
+	if len(got) != maxChoiceNameLength || got[len(got)-1] != '…' {
+		t.Errorf("choiceName() of %d runes = %d runes ending in %q", len([]rune(long)), len(got), got[len(got)-1])
+	}
+}
@@ -6,25 +6,28 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
 	"github.com/varubogu/gbf_discord_bot_go/internal/log"
 )
 
 // BattleCommand handles battle-related command functionality
 type BattleCommand struct {
 	logger        *log.Logger
+	locales       *i18n.Resolver
 	battleManager *gbf.BattleManager
 }
 
 // NewBattleCommand creates a new battle command handler
-func NewBattleCommand(logger *log.Logger) *BattleCommand {
+func NewBattleCommand(logger *log.Logger, locales *i18n.Resolver, battleManager *gbf.BattleManager) *BattleCommand {
 	return &BattleCommand{
 		logger:        logger,
-		battleManager: gbf.NewBattleManager(),
+		locales:       locales,
+		battleManager: battleManager,
 	}
 }
 
 // HandleBattlesListPrefixCommand handles the prefix version of battles list command (!battles)
-func (b *BattleCommand) HandleBattlesListPrefixCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
+func (b *BattleCommand) HandleBattlesListPrefixCommand(s Session, m *discordgo.MessageCreate) {
 	logger := b.logger.WithDiscordContext(m.GuildID, m.ChannelID, m.Author.ID).WithCommand("battles")
 
 	// Parse arguments - check if specific type requested
@@ -34,7 +37,7 @@ func (b *BattleCommand) HandleBattlesListPrefixCommand(s *discordgo.Session, m *
 		battleType = gbf.BattleType(strings.ToLower(args[1]))
 	}
 
-	embed := b.buildBattlesListEmbed(battleType)
+	embed := b.buildBattlesListEmbed(b.locales.Message(m), battleType)
 
 	_, err := s.ChannelMessageSendEmbed(m.ChannelID, embed)
 	if err != nil {
@@ -46,7 +49,7 @@ func (b *BattleCommand) HandleBattlesListPrefixCommand(s *discordgo.Session, m *
 }
 
 // HandleBattlesListSlashCommand handles the slash version of battles list command (/battles)
-func (b *BattleCommand) HandleBattlesListSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func (b *BattleCommand) HandleBattlesListSlashCommand(s Session, i *discordgo.InteractionCreate) {
 	logger := b.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("battles")
 
 	// Parse options
@@ -55,7 +58,7 @@ func (b *BattleCommand) HandleBattlesListSlashCommand(s *discordgo.Session, i *d
 		battleType = gbf.BattleType(i.ApplicationCommandData().Options[0].StringValue())
 	}
 
-	embed := b.buildBattlesListEmbed(battleType)
+	embed := b.buildBattlesListEmbed(b.locales.Interaction(i), battleType)
 
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -72,13 +75,14 @@ func (b *BattleCommand) HandleBattlesListSlashCommand(s *discordgo.Session, i *d
 }
 
 // HandleBattleInfoPrefixCommand handles the prefix version of battle info command (!battle <id>)
-func (b *BattleCommand) HandleBattleInfoPrefixCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
+func (b *BattleCommand) HandleBattleInfoPrefixCommand(s Session, m *discordgo.MessageCreate) {
 	logger := b.logger.WithDiscordContext(m.GuildID, m.ChannelID, m.Author.ID).WithCommand("battle")
+	tr := b.locales.Message(m)
 
 	// Parse arguments
 	args := strings.Fields(m.Content)
 	if len(args) < 2 {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ Please specify a battle ID. Usage: `!battle <id>`")
+		_, err := s.ChannelMessageSend(m.ChannelID, tr.T("battle.usage"))
 		if err != nil {
 			logger.WithError(err).Error("Failed to send usage message")
 		}
@@ -86,7 +90,7 @@ func (b *BattleCommand) HandleBattleInfoPrefixCommand(s *discordgo.Session, m *d
 	}
 
 	battleID := args[1]
-	embed := b.buildBattleInfoEmbed(battleID)
+	embed := b.buildBattleInfoEmbed(tr, battleID)
 
 	_, err := s.ChannelMessageSendEmbed(m.ChannelID, embed)
 	if err != nil {
@@ -98,7 +102,7 @@ func (b *BattleCommand) HandleBattleInfoPrefixCommand(s *discordgo.Session, m *d
 }
 
 // HandleBattleInfoSlashCommand handles the slash version of battle info command (/battle)
-func (b *BattleCommand) HandleBattleInfoSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func (b *BattleCommand) HandleBattleInfoSlashCommand(s Session, i *discordgo.InteractionCreate) {
 	logger := b.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("battle")
 
 	// Get battle ID from options
@@ -107,7 +111,7 @@ func (b *BattleCommand) HandleBattleInfoSlashCommand(s *discordgo.Session, i *di
 		battleID = i.ApplicationCommandData().Options[0].StringValue()
 	}
 
-	embed := b.buildBattleInfoEmbed(battleID)
+	embed := b.buildBattleInfoEmbed(b.locales.Interaction(i), battleID)
 
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -123,6 +127,34 @@ func (b *BattleCommand) HandleBattleInfoSlashCommand(s *discordgo.Session, i *di
 	logger.Info("Battle info slash command executed successfully", "battle_id", battleID)
 }
 
+// HandleAutocomplete suggests battle types for /battles and battles for /battle
+func (b *BattleCommand) HandleAutocomplete(s Session, i *discordgo.InteractionCreate) {
+	logger := b.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand(i.ApplicationCommandData().Name)
+	tr := b.locales.Interaction(i)
+
+	option, _ := focusedOption(i.ApplicationCommandData().Options)
+	query := focusedText(option)
+
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	switch i.ApplicationCommandData().Name {
+	case "battles":
+		query = strings.ToLower(query)
+		for _, battleType := range b.battleManager.Types() {
+			label := battleTypeLabel(tr, battleType)
+			if strings.Contains(string(battleType), query) || strings.Contains(strings.ToLower(label), query) {
+				choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+					Name:  label,
+					Value: string(battleType),
+				})
+			}
+		}
+	case "battle":
+		choices = battleChoices(tr, b.battleManager, query)
+	}
+
+	respondAutocomplete(s, i, logger, choices)
+}
+
 // GetBattlesListSlashCommandDefinition returns the slash command definition for battles list
 func (b *BattleCommand) GetBattlesListSlashCommandDefinition() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
@@ -134,20 +166,8 @@ func (b *BattleCommand) GetBattlesListSlashCommandDefinition() *discordgo.Applic
 				Name:        "type",
 				Description: "Filter by battle type",
 				Required:    false,
-				Choices: []*discordgo.ApplicationCommandOptionChoice{
-					{Name: "High Level", Value: "hl"},
-					{Name: "Faa HL", Value: "faa_hl"},
-					{Name: "Baha HL", Value: "baha_hl"},
-					{Name: "UBaha HL", Value: "ubaha_hl"},
-					{Name: "Akasha HL", Value: "akasha_hl"},
-					{Name: "Luci HL", Value: "luci_hl"},
-					{Name: "Guild War", Value: "gw"},
-					{Name: "Guild War NM", Value: "gw_nm"},
-					{Name: "Event", Value: "event"},
-					{Name: "Event HL", Value: "event_hl"},
-					{Name: "Training", Value: "train"},
-					{Name: "Custom", Value: "custom"},
-				},
+				// Suggested from the catalog, see HandleAutocomplete
+				Autocomplete: true,
 			},
 		},
 	}
@@ -160,39 +180,39 @@ func (b *BattleCommand) GetBattleInfoSlashCommandDefinition() *discordgo.Applica
 		Description: "Shows detailed information about a specific battle",
 		Options: []*discordgo.ApplicationCommandOption{
 			{
-				Type:        discordgo.ApplicationCommandOptionString,
-				Name:        "id",
-				Description: "Battle ID to get information for",
-				Required:    true,
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "id",
+				Description:  "Battle ID or alias to get information for",
+				Required:     true,
+				Autocomplete: true,
 			},
 		},
 	}
 }
 
 // buildBattlesListEmbed builds the battles list embed message
-func (b *BattleCommand) buildBattlesListEmbed(battleType gbf.BattleType) *discordgo.MessageEmbed {
+func (b *BattleCommand) buildBattlesListEmbed(tr i18n.Localizer, battleType gbf.BattleType) *discordgo.MessageEmbed {
 	embed := &discordgo.MessageEmbed{
-		Title: "GBF Battle List",
 		Color: 0x3498db,
 	}
 
 	var battles []*gbf.BattleInfo
 	if battleType != "" {
 		if !gbf.IsValidBattleType(battleType) {
-			embed.Title = "Invalid Battle Type"
-			embed.Description = fmt.Sprintf("The battle type `%s` is not valid.", battleType)
+			embed.Title = tr.T("battles.invalid_type.title")
+			embed.Description = tr.T("battles.invalid_type.description", battleType)
 			embed.Color = 0xe74c3c
 			return embed
 		}
 		battles = b.battleManager.GetBattlesByType(battleType)
-		embed.Title = fmt.Sprintf("GBF Battle List - %s", strings.ToUpper(string(battleType)))
+		embed.Title = tr.T("battles.title_type", battleTypeLabel(tr, battleType))
 	} else {
 		battles = b.battleManager.GetActiveBattles()
-		embed.Title = "GBF Battle List - Active Battles"
+		embed.Title = tr.T("battles.title_active")
 	}
 
 	if len(battles) == 0 {
-		embed.Description = "No battles found for the specified criteria."
+		embed.Description = tr.T("battles.none")
 		embed.Color = 0xf39c12
 		return embed
 	}
@@ -211,91 +231,129 @@ func (b *BattleCommand) buildBattlesListEmbed(battleType gbf.BattleType) *discor
 				status = "🟢"
 			}
 			battleList = append(battleList, fmt.Sprintf("%s `%s` - %s (Lv.%d)",
-				status, battle.ID, battle.Name, battle.Level))
+				status, battle.ID, battleDisplayName(tr, battle), battle.Level))
 		}
 
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-			Name:   fmt.Sprintf("%s Battles", strings.ToUpper(string(bType))),
+			Name:   tr.T("battles.group", battleTypeLabel(tr, bType)),
 			Value:  strings.Join(battleList, "\n"),
 			Inline: false,
 		})
 	}
 
 	embed.Footer = &discordgo.MessageEmbedFooter{
-		Text: "Use !battle <id> or /battle <id> for detailed information",
+		Text: tr.T("battles.footer"),
 	}
 
 	return embed
 }
 
 // buildBattleInfoEmbed builds the battle info embed message
-func (b *BattleCommand) buildBattleInfoEmbed(battleID string) *discordgo.MessageEmbed {
+func (b *BattleCommand) buildBattleInfoEmbed(tr i18n.Localizer, battleID string) *discordgo.MessageEmbed {
 	embed := &discordgo.MessageEmbed{
 		Color: 0x3498db,
 	}
 
 	if battleID == "" {
-		embed.Title = "Battle ID Required"
-		embed.Description = "Please specify a battle ID to get information."
+		embed.Title = tr.T("battle.id_required.title")
+		embed.Description = tr.T("battle.id_required.description")
 		embed.Color = 0xe74c3c
 		return embed
 	}
 
 	battle, err := b.battleManager.GetBattle(battleID)
 	if err != nil {
-		embed.Title = "Battle Not Found"
-		embed.Description = fmt.Sprintf("No battle found with ID: `%s`", battleID)
+		embed.Title = tr.T("battle.not_found.title")
+		embed.Description = tr.T("battle.not_found.description", battleID)
 		embed.Color = 0xe74c3c
 		return embed
 	}
 
-	embed.Title = fmt.Sprintf("Battle Info: %s", battle.Name)
+	embed.Title = tr.T("battle.title", battleDisplayName(tr, battle))
 	embed.Description = battle.Description
 
-	status := "🔴 Inactive"
+	status := tr.T("battle.inactive")
 	statusColor := 0xe74c3c
 	if battle.IsActive {
-		status = "🟢 Active"
+		status = tr.T("battle.active")
 		statusColor = 0x27ae60
 	}
 	embed.Color = statusColor
 
 	embed.Fields = []*discordgo.MessageEmbedField{
 		{
-			Name:   "Battle ID",
+			Name:   tr.T("battle.id"),
 			Value:  battle.ID,
 			Inline: true,
 		},
 		{
-			Name:   "Level",
+			Name:   tr.T("battle.level"),
 			Value:  fmt.Sprintf("%d", battle.Level),
 			Inline: true,
 		},
 		{
-			Name:   "Type",
+			Name:   tr.T("battle.type"),
 			Value:  string(battle.Type),
 			Inline: true,
 		},
 		{
-			Name:   "Min Rank",
+			Name:   tr.T("battle.min_rank"),
 			Value:  fmt.Sprintf("%d", battle.MinRank),
 			Inline: true,
 		},
 		{
-			Name:   "Max Players",
+			Name:   tr.T("battle.max_players"),
 			Value:  fmt.Sprintf("%d", battle.MaxPlayers),
 			Inline: true,
 		},
 		{
-			Name:   "Status",
+			Name:   tr.T("battle.status"),
 			Value:  status,
 			Inline: true,
 		},
 	}
+	// The other name is shown alongside the one in the title
+	if name := battle.Names["ja"]; name != "" && tr.Locale() != i18n.Japanese {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: tr.T("battle.japanese_name"), Value: name, Inline: true})
+	} else if name != "" && name != battle.Name {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: tr.T("battle.english_name"), Value: battle.Name, Inline: true})
+	}
+	if battle.Element != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: tr.T("battle.element"), Value: elementLabel(tr, battle.Element), Inline: true})
+	}
+	if len(battle.Aliases) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: tr.T("battle.aliases"), Value: strings.Join(battle.Aliases, ", "), Inline: true})
+	}
+	if battle.RaidCode != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: tr.T("battle.raid"), Value: battle.RaidCode, Inline: false})
+	}
+	if gw := battle.GuildWar; gw != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr.T("battle.guild_war"),
+			Value:  tr.T("battle.guild_war_value", formatNumber(gw.Honors), gw.MeatCost, gw.APCost, formatClearTime(gw.ClearTime)),
+			Inline: false,
+		})
+	}
 
 	embed.Footer = &discordgo.MessageEmbedFooter{
-		Text: fmt.Sprintf("Created: %s", battle.CreatedAt.Format("2006-01-02 15:04:05")),
+		Text: tr.T("battle.created", battle.CreatedAt.Format("2006-01-02 15:04:05")),
 	}
 
 	return embed
 }
+
+// battleTypeLabel returns the name of a battle type in the locale
+func battleTypeLabel(tr i18n.Localizer, battleType gbf.BattleType) string {
+	if label, exists := tr.Lookup("battles.types." + string(battleType)); exists {
+		return label
+	}
+	return strings.ToUpper(string(battleType))
+}
+
+// battleDisplayName returns the name of a battle in the locale, falling back to its English name
+func battleDisplayName(tr i18n.Localizer, battle *gbf.BattleInfo) string {
+	if name := battle.Names[string(tr.Locale())]; name != "" {
+		return name
+	}
+	return battle.Name
+}
@@ -0,0 +1,216 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/sheets"
+)
+
+const (
+	// maxBattleLogSize limits the size of an uploaded battle log
+	maxBattleLogSize = 1 << 20
+	// maxBattleLogPlayers limits the players listed in a battle log embed
+	maxBattleLogPlayers = 20
+)
+
+// BattleLogCommand analyzes pasted or uploaded battle logs
+type BattleLogCommand struct {
+	logger             *log.Logger
+	locales            *i18n.Resolver
+	battleManager      *gbf.BattleManager
+	recruitmentManager *gbf.RecruitmentManager
+	// fetch downloads an attachment; replaced in tests
+	fetch func(ctx context.Context, url string) ([]byte, error)
+}
+
+// NewBattleLogCommand creates a new battle log command handler
+func NewBattleLogCommand(logger *log.Logger, locales *i18n.Resolver, battleManager *gbf.BattleManager, recruitmentManager *gbf.RecruitmentManager) *BattleLogCommand {
+	return &BattleLogCommand{
+		logger:             logger,
+		locales:            locales,
+		battleManager:      battleManager,
+		recruitmentManager: recruitmentManager,
+		fetch:              fetchAttachment,
+	}
+}
+
+// HandleSlashCommand handles the slash version of battlelog command (/battlelog).
+// The response is deferred since downloading a log can take a while.
+func (c *BattleLogCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("battlelog")
+	tr := c.locales.Interaction(i)
+
+	data := i.ApplicationCommandData()
+	var attachmentID, text string
+	for _, opt := range data.Options {
+		switch opt.Name {
+		case "file":
+			attachmentID, _ = opt.Value.(string)
+		case "text":
+			text = opt.StringValue()
+		}
+	}
+	var attachment *discordgo.MessageAttachment
+	if data.Resolved != nil {
+		attachment = data.Resolved.Attachments[attachmentID]
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to battlelog slash command")
+		return
+	}
+
+	// A log posted in the thread of a recruitment message belongs to that
+	// recruitment; such threads share the ID of the message they start from
+	var recruitment *gbf.Recruitment
+	if found, err := c.recruitmentManager.GetRecruitmentByMessage(i.ChannelID); err == nil && found.GuildID == i.GuildID {
+		recruitment = found
+	}
+
+	edit := &discordgo.WebhookEdit{}
+	summary, err := c.analyze(context.Background(), tr, attachment, text)
+	if err == nil {
+		embed, file := c.buildSummary(tr, summary, recruitment)
+		edit.Embeds = &[]*discordgo.MessageEmbed{embed}
+		edit.Files = []*discordgo.File{file}
+	} else {
+		logger.WithError(err).Warn("Failed to analyze battle log")
+		content := errorMessage(tr, err)
+		edit.Content = &content
+	}
+
+	if _, err := s.InteractionResponseEdit(i.Interaction, edit); err != nil {
+		logger.WithError(err).Error("Failed to edit battlelog slash command response")
+		return
+	}
+
+	recruitmentID := ""
+	if recruitment != nil {
+		recruitmentID = recruitment.ID
+	}
+	logger.Info("Battlelog slash command executed successfully", "recruitment_id", recruitmentID)
+}
+
+// analyze reads a battle log from an attachment or pasted text and summarizes it
+func (c *BattleLogCommand) analyze(ctx context.Context, tr i18n.Localizer, attachment *discordgo.MessageAttachment, text string) (*gbf.BattleLogSummary, error) {
+	content := []byte(text)
+	if attachment != nil {
+		if attachment.Size > maxBattleLogSize {
+			return nil, errors.New(tr.T("battlelog.too_large", maxBattleLogSize>>20))
+		}
+		var err error
+		if content, err = c.fetch(ctx, attachment.URL); err != nil {
+			return nil, err
+		}
+	} else if text == "" {
+		return nil, errors.New(tr.T("battlelog.input_required"))
+	}
+
+	actions, err := gbf.ParseBattleLog(content)
+	if err != nil {
+		return nil, err
+	}
+	return gbf.SummarizeBattleLog(actions), nil
+}
+
+// buildSummary builds the summary embed of a battle log and its CSV breakdown
+func (c *BattleLogCommand) buildSummary(tr i18n.Localizer, summary *gbf.BattleLogSummary, recruitment *gbf.Recruitment) (*discordgo.MessageEmbed, *discordgo.File) {
+	embed := &discordgo.MessageEmbed{
+		Title:       tr.T("battlelog.title"),
+		Description: tr.T("battlelog.summary", summary.Turns, formatNumber(int(summary.Damage)), formatNumber(int(summary.Damage)/summary.Turns)),
+		Color:       0x3498db,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: tr.T("battlelog.footer"),
+		},
+	}
+
+	filename := "battle_log.csv"
+	if recruitment != nil {
+		battleName := recruitment.BattleID
+		if battle, err := c.battleManager.GetBattle(recruitment.BattleID); err == nil {
+			battleName = battleDisplayName(tr, battle)
+		}
+		embed.Title = tr.T("battlelog.battle_title", battleName)
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  tr.T("battlelog.recruitment"),
+			Value: fmt.Sprintf("`%s`", recruitment.ID),
+		})
+		filename = fmt.Sprintf("battle_log_%s.csv", recruitment.ID)
+	}
+
+	var kinds string
+	for _, kind := range gbf.DamageKinds {
+		kinds += tr.T("battlelog.kind", tr.T("battlelog.kinds."+string(kind)), formatNumber(int(summary.ByKind[kind])), percentOf(summary.ByKind[kind], summary.Damage)) + "\n"
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  tr.T("battlelog.by_kind"),
+		Value: kinds,
+	})
+
+	for n, player := range summary.Players[:min(len(summary.Players), maxBattleLogPlayers)] {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: fmt.Sprintf("%d. %s", n+1, player.Player),
+			Value: tr.T("battlelog.player", formatNumber(int(player.Damage)), player.Share*100) + "\n" +
+				tr.T("battlelog.player_kinds",
+					formatNumber(int(player.ByKind[gbf.DamageKindNormal])),
+					formatNumber(int(player.ByKind[gbf.DamageKindSkill])),
+					formatNumber(int(player.ByKind[gbf.DamageKindCharge]))),
+			Inline: true,
+		})
+	}
+	if hidden := len(summary.Players) - maxBattleLogPlayers; hidden > 0 {
+		embed.Footer.Text = tr.T("battlelog.more_players", hidden) + " " + embed.Footer.Text
+	}
+
+	// Encoding rows in memory cannot fail
+	content, _ := sheets.CSV{}.Encode(&sheets.Workbook{Sheets: []*sheets.Sheet{{Name: "battle_log", Rows: summary.ExportRows()}}})
+	return embed, &discordgo.File{
+		Name:        filename,
+		ContentType: "text/csv",
+		Reader:      bytes.NewReader(content),
+	}
+}
+
+// percentOf returns part as a percentage of total
+func percentOf(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// GetSlashCommandDefinition returns the slash command definition for battlelog
+func (c *BattleLogCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	dmPermission := false
+
+	return &discordgo.ApplicationCommand{
+		Name:         "battlelog",
+		Description:  "Analyzes a battle log: damage per player, by source and turns",
+		DMPermission: &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionAttachment,
+				Name:        "file",
+				Description: "Battle log as a text or JSON file",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "text",
+				Description: "Pasted battle log with hits separated by ;, e.g. Turn 1; Alice skill 120000; Bob ca 900000",
+				Required:    false,
+				MaxLength:   6000,
+			},
+		},
+	}
+}
@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+func newTestBattleLogCommand(files map[string]string) (*BattleLogCommand, *gbf.RecruitmentManager) {
+	battleManager := gbf.NewBattleManager()
+	recruitmentManager := gbf.NewRecruitmentManager(battleManager)
+	c := NewBattleLogCommand(log.InitLogger("error"), i18n.NewResolver(nil), battleManager, recruitmentManager)
+	c.fetch = func(ctx context.Context, url string) ([]byte, error) {
+		content, ok := files[url]
+		if !ok {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return []byte(content), nil
+	}
+	return c, recruitmentManager
+}
+
+func TestBattleLogCommand(t *testing.T) {
+	files := map[string]string{
+		"https://cdn/log.json": `[{"turn": 1, "player": "Alice", "type": "skill", "damage": 300000}, {"turn": 2, "player": "Bob", "type": "ca", "damage": 100000}]`,
+	}
+	c, recruitmentManager := newTestBattleLogCommand(files)
+	if err := recruitmentManager.CreateRecruitment(&gbf.Recruitment{ID: "rec1", GuildID: testGuildID, BattleID: "akasha_hl", HostUserID: "host1"}); err != nil {
+		t.Fatalf("CreateRecruitment() error = %v", err)
+	}
+	if err := recruitmentManager.SetMessageID("rec1", "thread1"); err != nil {
+		t.Fatalf("SetMessageID() error = %v", err)
+	}
+	s := commandstest.NewSession()
+
+	run := func(channelID string, attachment *discordgo.MessageAttachment, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.WebhookEdit {
+		t.Helper()
+		s.Reset()
+		if attachment != nil {
+			options = append(options, commandstest.AttachmentOption("file", attachment.ID))
+		}
+		i := commandstest.SlashCommand(testGuildID, channelID, commandstest.Member("user1"), "battlelog", options...)
+		if attachment != nil {
+			commandstest.ResolveAttachment(i, attachment)
+		}
+		c.HandleSlashCommand(s, i)
+
+		response := s.LastResponse()
+		if response == nil || response.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+			t.Fatalf("response = %+v, want a deferred response", response)
+		}
+		edits := s.ResponseEdits()
+		if len(edits) != 1 {
+			t.Fatalf("got %d response edits, want 1", len(edits))
+		}
+		return edits[0]
+	}
+
+	tests := []struct {
+		name       string
+		channelID  string
+		attachment *discordgo.MessageAttachment
+		text       string
+		title      string
+		summary    string
+		filename   string
+		csv        string
+	}{
+		{
+			name:      "pasted text",
+			channelID: testChannelID,
+			text:      "Turn 1; Alice skill 120,000; Bob ca 900000; Turn 3; Alice auto 80000",
+			title:     "Battle Log",
+			summary:   "3 turns · 1,100,000 damage · 366,666 per turn",
+			filename:  "battle_log.csv",
+			csv:       "Bob,0,0,900000,900000,81.82",
+		},
+		{
+			name:       "file in a recruitment thread",
+			channelID:  "thread1",
+			attachment: &discordgo.MessageAttachment{ID: "att1", Filename: "log.json", URL: "https://cdn/log.json", Size: 100},
+			title:      "Battle Log: Akasha (Hard)",
+			summary:    "2 turns · 400,000 damage · 200,000 per turn",
+			filename:   "battle_log_rec1.csv",
+			csv:        "Alice,0,300000,0,300000,75.00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options []*discordgo.ApplicationCommandInteractionDataOption
+			if tt.text != "" {
+				options = append(options, commandstest.StringOption("text", tt.text))
+			}
+			edit := run(tt.channelID, tt.attachment, options...)
+			if edit.Embeds == nil || len(*edit.Embeds) != 1 {
+				t.Fatalf("edit = %+v, want an embed", edit)
+			}
+			embed := (*edit.Embeds)[0]
+			if embed.Title != tt.title {
+				t.Errorf("title = %q, want %q", embed.Title, tt.title)
+			}
+			if embed.Description != tt.summary {
+				t.Errorf("description = %q, want %q", embed.Description, tt.summary)
+			}
+			if len(edit.Files) != 1 || edit.Files[0].Name != tt.filename {
+				t.Fatalf("files = %+v, want %s", edit.Files, tt.filename)
+			}
+			content, _ := io.ReadAll(edit.Files[0].Reader)
+			if !strings.Contains(string(content), tt.csv) {
+				t.Errorf("csv = %q, want it to contain %q", content, tt.csv)
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name       string
+		attachment *discordgo.MessageAttachment
+		text       string
+		contains   string
+	}{
+		{"no input", nil, "", "Attach a log file"},
+		{"invalid line", nil, "Alice heal 1000", "Line 1: unknown damage type"},
+		{"file too large", &discordgo.MessageAttachment{ID: "att2", URL: "https://cdn/big.txt", Size: maxBattleLogSize + 1}, "", "The file is larger than 1 MB"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options []*discordgo.ApplicationCommandInteractionDataOption
+			if tt.text != "" {
+				options = append(options, commandstest.StringOption("text", tt.text))
+			}
+			edit := run(testChannelID, tt.attachment, options...)
+			if edit.Content == nil || !strings.Contains(*edit.Content, tt.contains) {
+				t.Errorf("edit = %+v, want an error containing %q", edit, tt.contains)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+// ClassCommand shows the proficiencies of main character classes
+type ClassCommand struct {
+	logger  *log.Logger
+	locales *i18n.Resolver
+	classes *gbf.ClassCatalog
+}
+
+// NewClassCommand creates a new class command handler
+func NewClassCommand(logger *log.Logger, locales *i18n.Resolver, classes *gbf.ClassCatalog) *ClassCommand {
+	return &ClassCommand{
+		logger:  logger,
+		locales: locales,
+		classes: classes,
+	}
+}
+
+// HandleSlashCommand handles the slash version of class command (/class)
+func (c *ClassCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("class")
+
+	var name string
+	if len(i.ApplicationCommandData().Options) > 0 {
+		name = i.ApplicationCommandData().Options[0].StringValue()
+	}
+
+	embed := c.buildClassEmbed(c.locales.Interaction(i), name)
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to class slash command")
+		return
+	}
+
+	logger.Info("Class slash command executed successfully", "class", name)
+}
+
+// HandleAutocomplete suggests classes matching what has been typed
+func (c *ClassCommand) HandleAutocomplete(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("class")
+	tr := c.locales.Interaction(i)
+
+	option, _ := focusedOption(i.ApplicationCommandData().Options)
+	classes := c.classes.Search(focusedText(option), maxAutocompleteChoices)
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(classes))
+	for _, class := range classes {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  choiceName(fmt.Sprintf("%s (%s)", classDisplayName(tr, class), class.ID)),
+			Value: class.ID,
+		})
+	}
+
+	respondAutocomplete(s, i, logger, choices)
+}
+
+// GetSlashCommandDefinition returns the slash command definition for class
+func (c *ClassCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	dmPermission := false
+
+	return &discordgo.ApplicationCommand{
+		Name:         "class",
+		Description:  "Shows the weapon proficiencies of a class",
+		DMPermission: &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "name",
+				Description:  "Class ID, name or alias",
+				Required:     true,
+				Autocomplete: true,
+			},
+		},
+	}
+}
+
+// buildClassEmbed builds the class info embed message
+func (c *ClassCommand) buildClassEmbed(tr i18n.Localizer, name string) *discordgo.MessageEmbed {
+	class, err := c.classes.Get(name)
+	if err != nil {
+		return &discordgo.MessageEmbed{
+			Title:       tr.T("class.not_found.title"),
+			Description: tr.T("class.not_found.description", name),
+			Color:       0xe74c3c,
+		}
+	}
+
+	proficiencies := make([]string, len(class.Proficiencies))
+	for n, weaponType := range class.Proficiencies {
+		proficiencies[n] = weaponTypeLabel(tr, weaponType)
+	}
+
+	var modifiers []string
+	for _, modifier := range gbf.ClassModifiers {
+		if value, exists := class.Modifiers[modifier]; exists {
+			modifiers = append(modifiers, tr.T("class.modifier", tr.T("class.modifiers."+string(modifier)), value*100))
+		}
+	}
+	if len(modifiers) == 0 {
+		modifiers = append(modifiers, tr.T("class.no_modifiers"))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: tr.T("class.title", classDisplayName(tr, class)),
+		Color: 0x3498db,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   tr.T("class.id"),
+				Value:  class.ID,
+				Inline: true,
+			},
+			{
+				Name:   tr.T("class.row"),
+				Value:  string(class.Row),
+				Inline: true,
+			},
+			{
+				Name:   tr.T("class.proficiencies"),
+				Value:  strings.Join(proficiencies, " / "),
+				Inline: true,
+			},
+			{
+				Name:   tr.T("class.passives"),
+				Value:  strings.Join(modifiers, "\n"),
+				Inline: false,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: tr.T("class.footer", gbf.ProficiencyMultiplier),
+		},
+	}
+	if len(class.Aliases) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: tr.T("class.aliases"), Value: strings.Join(class.Aliases, ", "), Inline: true})
+	}
+
+	return embed
+}
+
+// weaponTypeLabel returns the name of a weapon type in the locale
+func weaponTypeLabel(tr i18n.Localizer, weaponType gbf.WeaponType) string {
+	if label, exists := tr.Lookup("class.weapons." + string(weaponType)); exists {
+		return label
+	}
+	return string(weaponType)
+}
+
+// classDisplayName returns the name of a class in the locale, falling back to its English name
+func classDisplayName(tr i18n.Localizer, class *gbf.Class) string {
+	if name := class.Names[string(tr.Locale())]; name != "" {
+		return name
+	}
+	return class.Name
+}
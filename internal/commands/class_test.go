@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+func TestClassCommand(t *testing.T) {
+	c := NewClassCommand(log.InitLogger("error"), i18n.NewResolver(nil), gbf.DefaultClasses())
+	s := commandstest.NewSession()
+
+	tests := []struct {
+		name       string
+		locale     discordgo.Locale
+		class      string
+		wantTitle  string
+		wantFields map[string]string
+	}{
+		{
+			name:      "shows proficiencies and passives",
+			class:     "zerk",
+			wantTitle: "Class: Berserker",
+			wantFields: map[string]string{
+				"Row":           "IV",
+				"Proficiencies": "Sword / Axe",
+				"Passives":      "ATK +20%\nDA rate +10%",
+			},
+		},
+		{
+			name:      "shows a class without passives in Japanese",
+			locale:    discordgo.Japanese,
+			class:     "ルーンスレイヤー",
+			wantTitle: "ジョブ: ルーンスレイヤー",
+			wantFields: map[string]string{
+				"得意武器":    "斧 / 杖",
+				"アビリティ効果": "なし",
+			},
+		},
+		{
+			name:      "unknown class",
+			class:     "monk",
+			wantTitle: "Class Not Found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("user1"), "class",
+				commandstest.StringOption("name", tt.class))
+			i.Locale = tt.locale
+
+			c.HandleSlashCommand(s, i)
+
+			response := s.LastResponse()
+			if response == nil || response.Data == nil || len(response.Data.Embeds) != 1 {
+				t.Fatal("no embed response")
+			}
+			embed := response.Data.Embeds[0]
+			if embed.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", embed.Title, tt.wantTitle)
+			}
+			for field, want := range tt.wantFields {
+				if got := commandstest.FieldValue(embed, field); got != want {
+					t.Errorf("field %q = %q, want %q", field, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestClassCommand_Autocomplete(t *testing.T) {
+	c := NewClassCommand(log.InitLogger("error"), i18n.NewResolver(nil), gbf.DefaultClasses())
+	s := commandstest.NewSession()
+
+	i := commandstest.Autocomplete(testGuildID, testChannelID, commandstest.Member("user1"), "class",
+		commandstest.Focused(commandstest.StringOption("name", "chaos")))
+	c.HandleAutocomplete(s, i)
+
+	if got := choiceValues(t, s); len(got) != 1 || got[0] != "chaos_ruler" {
+		t.Errorf("choices = %v, want [chaos_ruler]", got)
+	}
+}
@@ -0,0 +1,209 @@
+package commandstest
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// interactionSeq generates unique interaction IDs
+var interactionSeq atomic.Int64
+
+// nextInteractionID returns a fresh interaction ID
+func nextInteractionID() string {
+	return "interaction-" + strconv.FormatInt(interactionSeq.Add(1), 10)
+}
+
+// Member builds a guild member with the given roles
+func Member(userID string, roleIDs ...string) *discordgo.Member {
+	return &discordgo.Member{
+		User:  &discordgo.User{ID: userID, Username: "user_" + userID},
+		Roles: roleIDs,
+	}
+}
+
+// Message builds a message event as sent by member in a guild channel
+func Message(guildID, channelID string, member *discordgo.Member, content string) *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{
+		Message: &discordgo.Message{
+			ID:        nextInteractionID(),
+			GuildID:   guildID,
+			ChannelID: channelID,
+			Author:    member.User,
+			Member:    member,
+			Content:   content,
+		},
+	}
+}
+
+// SlashCommand builds a slash command interaction invoked by member in a guild channel
+func SlashCommand(guildID, channelID string, member *discordgo.Member, name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:        nextInteractionID(),
+			Type:      discordgo.InteractionApplicationCommand,
+			GuildID:   guildID,
+			ChannelID: channelID,
+			Member:    member,
+			Data: discordgo.ApplicationCommandInteractionData{
+				Name:    name,
+				Options: options,
+			},
+		},
+	}
+}
+
+// Autocomplete builds an autocomplete interaction for a slash command typed by
+// member in a guild channel. Mark the option being typed with Focused.
+func Autocomplete(guildID, channelID string, member *discordgo.Member, name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	i := SlashCommand(guildID, channelID, member, name, options...)
+	i.Type = discordgo.InteractionApplicationCommandAutocomplete
+	return i
+}
+
+// Focused marks an option as the one being typed in an autocomplete interaction
+func Focused(option *discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	option.Focused = true
+	return option
+}
+
+// ButtonClick builds a button interaction clicked by member in a guild channel
+func ButtonClick(guildID, channelID string, member *discordgo.Member, customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:        nextInteractionID(),
+			Type:      discordgo.InteractionMessageComponent,
+			GuildID:   guildID,
+			ChannelID: channelID,
+			Member:    member,
+			Data: discordgo.MessageComponentInteractionData{
+				CustomID:      customID,
+				ComponentType: discordgo.ButtonComponent,
+			},
+		},
+	}
+}
+
+// StringOption builds a string option for a slash command
+func StringOption(name, value string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  name,
+		Type:  discordgo.ApplicationCommandOptionString,
+		Value: value,
+	}
+}
+
+// IntegerOption builds an integer option for a slash command.
+// Discord sends numbers as JSON floats, so the value is stored as float64.
+func IntegerOption(name string, value int) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  name,
+		Type:  discordgo.ApplicationCommandOptionInteger,
+		Value: float64(value),
+	}
+}
+
+// Buttons returns the buttons of a message's action rows.
+// Components built by handlers are values while decoded components are pointers,
+// so both forms are accepted.
+func Buttons(components []discordgo.MessageComponent) []discordgo.Button {
+	var buttons []discordgo.Button
+	for _, component := range components {
+		var children []discordgo.MessageComponent
+		switch row := component.(type) {
+		case discordgo.ActionsRow:
+			children = row.Components
+		case *discordgo.ActionsRow:
+			children = row.Components
+		}
+		for _, child := range children {
+			switch button := child.(type) {
+			case discordgo.Button:
+				buttons = append(buttons, button)
+			case *discordgo.Button:
+				buttons = append(buttons, *button)
+			}
+		}
+	}
+	return buttons
+}
+
+// FieldValue returns the value of the embed field with the given name, or "" if absent
+func FieldValue(embed *discordgo.MessageEmbed, name string) string {
+	for _, field := range embed.Fields {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+// ChannelOption builds a channel option for a slash command
+func ChannelOption(name, channelID string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  name,
+		Type:  discordgo.ApplicationCommandOptionChannel,
+		Value: channelID,
+	}
+}
+
+// UserOption builds a user option for a slash command
+func UserOption(name, userID string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  name,
+		Type:  discordgo.ApplicationCommandOptionUser,
+		Value: userID,
+	}
+}
+
+// Subcommand builds a subcommand option holding the given options
+func Subcommand(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name:    name,
+		Type:    discordgo.ApplicationCommandOptionSubCommand,
+		Options: options,
+	}
+}
+
+// SubcommandGroup builds a subcommand group holding the given subcommand
+func SubcommandGroup(name string, subcommand *discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name:    name,
+		Type:    discordgo.ApplicationCommandOptionSubCommandGroup,
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{subcommand},
+	}
+}
+
+// BooleanOption builds a boolean option for a slash command
+func BooleanOption(name string, value bool) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  name,
+		Type:  discordgo.ApplicationCommandOptionBoolean,
+		Value: value,
+	}
+}
+
+// AttachmentOption builds an attachment option referring to attachmentID.
+// The attachment itself must be added to the interaction with ResolveAttachment.
+func AttachmentOption(name, attachmentID string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  name,
+		Type:  discordgo.ApplicationCommandOptionAttachment,
+		Value: attachmentID,
+	}
+}
+
+// ResolveAttachment adds an uploaded attachment to the resolved data of a slash command interaction
+func ResolveAttachment(i *discordgo.InteractionCreate, attachment *discordgo.MessageAttachment) *discordgo.InteractionCreate {
+	data := i.ApplicationCommandData()
+	if data.Resolved == nil {
+		data.Resolved = &discordgo.ApplicationCommandInteractionDataResolved{}
+	}
+	if data.Resolved.Attachments == nil {
+		data.Resolved.Attachments = make(map[string]*discordgo.MessageAttachment)
+	}
+	data.Resolved.Attachments[attachment.ID] = attachment
+	i.Data = data
+	return i
+}
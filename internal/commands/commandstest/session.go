@@ -0,0 +1,237 @@
+// Package commandstest provides an in-memory Discord session for testing
+// command handlers without a network connection.
+package commandstest
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Response is an interaction response recorded by the fake session
+type Response struct {
+	Interaction *discordgo.Interaction
+	Response    *discordgo.InteractionResponse
+}
+
+// Session is an in-memory implementation of commands.Session.
+// Outgoing messages and interaction responses are recorded instead of being sent.
+type Session struct {
+	// State backs guild, role and member lookups; seed it with GuildAdd and MemberAdd
+	State *discordgo.State
+	// Latency is returned as the gateway heartbeat latency
+	Latency time.Duration
+	// Err, when set, is returned by every call that would reach the API
+	Err error
+
+	mu        sync.Mutex
+	nextID    int
+	messages  []*discordgo.Message
+	responses []Response
+	edits     []*discordgo.WebhookEdit
+}
+
+// NewSession creates an empty fake session
+func NewSession() *Session {
+	return &Session{
+		State:   discordgo.NewState(),
+		Latency: 42 * time.Millisecond,
+	}
+}
+
+// Messages returns every message sent to a channel, in order, with edits applied
+func (s *Session) Messages() []*discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.Message(nil), s.messages...)
+}
+
+// Responses returns every interaction response, in order
+func (s *Session) Responses() []Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Response(nil), s.responses...)
+}
+
+// LastResponse returns the most recent interaction response, or nil if there is none
+func (s *Session) LastResponse() *discordgo.InteractionResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.responses) == 0 {
+		return nil
+	}
+	return s.responses[len(s.responses)-1].Response
+}
+
+// ResponseEdits returns every edit made to interaction responses, in order
+func (s *Session) ResponseEdits() []*discordgo.WebhookEdit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.WebhookEdit(nil), s.edits...)
+}
+
+// Reset forgets all recorded messages and responses
+func (s *Session) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = nil
+	s.responses = nil
+	s.edits = nil
+}
+
+// ChannelMessageSend records a plain text message
+func (s *Session) ChannelMessageSend(channelID, content string) (*discordgo.Message, error) {
+	return s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Content: content})
+}
+
+// ChannelMessageSendEmbed records an embed message
+func (s *Session) ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	return s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}})
+}
+
+// ChannelMessageSendComplex records a message with embeds and components
+func (s *Session) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	message := &discordgo.Message{
+		ID:         strconv.Itoa(s.nextID),
+		ChannelID:  channelID,
+		Content:    data.Content,
+		Embeds:     data.Embeds,
+		Components: data.Components,
+		Timestamp:  time.Now(),
+	}
+	s.messages = append(s.messages, message)
+	return message, nil
+}
+
+// ChannelMessageEdit replaces the content of a recorded message
+func (s *Session) ChannelMessageEdit(channelID, messageID, content string) (*discordgo.Message, error) {
+	return s.ChannelMessageEditComplex(&discordgo.MessageEdit{Channel: channelID, ID: messageID, Content: &content})
+}
+
+// ChannelMessageEditComplex applies an edit to a recorded message
+func (s *Session) ChannelMessageEditComplex(edit *discordgo.MessageEdit) (*discordgo.Message, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, message := range s.messages {
+		if message.ID != edit.ID || message.ChannelID != edit.Channel {
+			continue
+		}
+		if edit.Content != nil {
+			message.Content = *edit.Content
+		}
+		if edit.Embeds != nil {
+			message.Embeds = *edit.Embeds
+		}
+		if edit.Components != nil {
+			message.Components = *edit.Components
+		}
+		now := time.Now()
+		message.EditedTimestamp = &now
+		return message, nil
+	}
+	return nil, fmt.Errorf("unknown message %s in channel %s", edit.ID, edit.Channel)
+}
+
+// InteractionRespond records an interaction response
+func (s *Session) InteractionRespond(interaction *discordgo.Interaction, response *discordgo.InteractionResponse) error {
+	if s.Err != nil {
+		return s.Err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = append(s.responses, Response{Interaction: interaction, Response: response})
+	return nil
+}
+
+// InteractionResponseEdit records an edit to an interaction response
+func (s *Session) InteractionResponseEdit(interaction *discordgo.Interaction, edit *discordgo.WebhookEdit) (*discordgo.Message, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.edits = append(s.edits, edit)
+
+	message := &discordgo.Message{ID: interaction.ID, ChannelID: interaction.ChannelID}
+	if edit.Content != nil {
+		message.Content = *edit.Content
+	}
+	if edit.Embeds != nil {
+		message.Embeds = *edit.Embeds
+	}
+	return message, nil
+}
+
+// InteractionResponse returns the message of a recorded channel message response.
+// The message is added to Messages on first use, so it can then be edited like a
+// message sent to the channel.
+func (s *Session) InteractionResponse(interaction *discordgo.Interaction) (*discordgo.Message, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, message := range s.messages {
+		if message.ID == interaction.ID && message.ChannelID == interaction.ChannelID {
+			return message, nil
+		}
+	}
+	for _, recorded := range s.responses {
+		response := recorded.Response
+		if recorded.Interaction.ID != interaction.ID || response.Data == nil ||
+			response.Type != discordgo.InteractionResponseChannelMessageWithSource {
+			continue
+		}
+		message := &discordgo.Message{
+			ID:         interaction.ID,
+			ChannelID:  interaction.ChannelID,
+			Content:    response.Data.Content,
+			Embeds:     response.Data.Embeds,
+			Components: response.Data.Components,
+			Timestamp:  time.Now(),
+		}
+		s.messages = append(s.messages, message)
+		return message, nil
+	}
+	return nil, fmt.Errorf("no response to interaction %s", interaction.ID)
+}
+
+// HeartbeatLatency returns the configured latency
+func (s *Session) HeartbeatLatency() time.Duration {
+	return s.Latency
+}
+
+// Guild looks up a guild in the fake state
+func (s *Session) Guild(guildID string) (*discordgo.Guild, error) {
+	return s.State.Guild(guildID)
+}
+
+// Role looks up a role in the fake state
+func (s *Session) Role(guildID, roleID string) (*discordgo.Role, error) {
+	return s.State.Role(guildID, roleID)
+}
+
+// Member looks up a member in the fake state
+func (s *Session) Member(guildID, userID string) (*discordgo.Member, error) {
+	return s.State.Member(guildID, userID)
+}
@@ -0,0 +1,343 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/sheets"
+)
+
+const (
+	// maxImportSize limits the size of an uploaded import file
+	maxImportSize = 5 << 20
+	// maxReportErrors limits how many row errors are listed in an import report
+	maxReportErrors = 15
+	// fetchTimeout bounds how long downloading an attachment may take
+	fetchTimeout = 30 * time.Second
+)
+
+// DataCommand imports and exports the battle list, event calendar, custom
+// reminders and message templates as CSV or XLSX files
+type DataCommand struct {
+	logger  *log.Logger
+	locales *i18n.Resolver
+	manager *sheets.Manager
+	// fetch downloads an attachment; replaced in tests
+	fetch func(ctx context.Context, url string) ([]byte, error)
+}
+
+// NewDataCommand creates a new data command handler
+func NewDataCommand(logger *log.Logger, locales *i18n.Resolver, manager *sheets.Manager) *DataCommand {
+	return &DataCommand{
+		logger:  logger,
+		locales: locales,
+		manager: manager,
+		fetch:   fetchAttachment,
+	}
+}
+
+// fetchAttachment downloads an attachment from the Discord CDN
+func fetchAttachment(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImportSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	if len(data) > maxImportSize {
+		return nil, fmt.Errorf("the file is larger than %d MB", maxImportSize>>20)
+	}
+	return data, nil
+}
+
+// HandleSlashCommand handles the slash version of data command (/data).
+// The response is deferred since downloading and validating a file can take a while.
+func (c *DataCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("data")
+	tr := c.locales.Interaction(i)
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	subcommand := data.Options[0]
+
+	var (
+		attachmentID, format, sheet string
+		dryRun                      bool
+	)
+	for _, opt := range subcommand.Options {
+		switch opt.Name {
+		case "file":
+			attachmentID, _ = opt.Value.(string)
+		case "dry_run":
+			dryRun = opt.BoolValue()
+		case "format":
+			format = opt.StringValue()
+		case "sheet":
+			sheet = opt.StringValue()
+		}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to data slash command")
+		return
+	}
+
+	ctx := context.Background()
+	edit := &discordgo.WebhookEdit{}
+	switch subcommand.Name {
+	case "import":
+		var attachment *discordgo.MessageAttachment
+		if data.Resolved != nil {
+			attachment = data.Resolved.Attachments[attachmentID]
+		}
+		var report *sheets.Report
+		report, err = c.importFile(ctx, tr, i.GuildID, i.Member.User.ID, attachment, dryRun)
+		if err == nil {
+			edit.Embeds = &[]*discordgo.MessageEmbed{buildImportReportEmbed(tr, report, dryRun)}
+			logger.Info("Data import checked", "sheets", len(report.Sheets), "errors", len(report.Errors), "applied", report.Applied)
+		}
+	case "export":
+		var files []*discordgo.File
+		// Exported templates show the defaults in the guild's language
+		files, err = c.exportFiles(ctx, i.GuildID, c.locales.Guild(ctx, i.GuildID).Locale(), format, sheet)
+		if err == nil {
+			content := tr.T("data.exported", len(files))
+			edit.Content = &content
+			edit.Files = files
+		}
+	}
+
+	if err != nil {
+		logger.WithError(err).Warn("Data command failed", "subcommand", subcommand.Name)
+		content := errorMessage(tr, err)
+		edit.Content = &content
+		edit.Embeds = nil
+		edit.Files = nil
+	}
+
+	if _, err := s.InteractionResponseEdit(i.Interaction, edit); err != nil {
+		logger.WithError(err).Error("Failed to edit data slash command response")
+		return
+	}
+
+	logger.Info("Data slash command executed successfully", "subcommand", subcommand.Name)
+}
+
+// importFile downloads, decodes and imports an uploaded file
+func (c *DataCommand) importFile(ctx context.Context, tr i18n.Localizer, guildID, userID string, attachment *discordgo.MessageAttachment, dryRun bool) (*sheets.Report, error) {
+	if attachment == nil {
+		return nil, errors.New(tr.T("data.attachment_required"))
+	}
+	if attachment.Size > maxImportSize {
+		return nil, errors.New(tr.T("data.too_large", maxImportSize>>20))
+	}
+	format, err := sheets.FormatFor(attachment.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := c.fetch(ctx, attachment.URL)
+	if err != nil {
+		return nil, err
+	}
+	workbook, err := format.Decode(content, attachment.Filename)
+	if err != nil {
+		return nil, err
+	}
+	return c.manager.Import(ctx, guildID, userID, workbook, dryRun)
+}
+
+// exportFiles exports the current data as one XLSX workbook or one CSV file per sheet
+func (c *DataCommand) exportFiles(ctx context.Context, guildID string, locale i18n.Locale, formatName, sheetName string) ([]*discordgo.File, error) {
+	if formatName == "" {
+		formatName = "xlsx"
+	}
+	format, err := sheets.FormatFor(formatName)
+	if err != nil {
+		return nil, err
+	}
+
+	workbook, err := c.manager.Export(ctx, guildID, locale)
+	if err != nil {
+		return nil, err
+	}
+	if sheetName != "" {
+		sheet := workbook.Sheet(sheetName)
+		if sheet == nil {
+			return nil, fmt.Errorf("unknown sheet %q", sheetName)
+		}
+		workbook = &sheets.Workbook{Sheets: []*sheets.Sheet{sheet}}
+	}
+
+	// CSV files hold a single sheet, so each sheet becomes its own file
+	workbooks := []*sheets.Workbook{workbook}
+	if _, isCSV := format.(sheets.CSV); isCSV {
+		workbooks = nil
+		for _, sheet := range workbook.Sheets {
+			workbooks = append(workbooks, &sheets.Workbook{Sheets: []*sheets.Sheet{sheet}})
+		}
+	}
+
+	var files []*discordgo.File
+	for _, wb := range workbooks {
+		content, err := format.Encode(wb)
+		if err != nil {
+			return nil, err
+		}
+		name := "gbf_data"
+		if len(wb.Sheets) == 1 {
+			name = wb.Sheets[0].Name
+		}
+		files = append(files, &discordgo.File{
+			Name:   name + format.Extension(),
+			Reader: bytes.NewReader(content),
+		})
+	}
+	return files, nil
+}
+
+// buildImportReportEmbed builds the embed describing the result of an import
+func buildImportReportEmbed(tr i18n.Localizer, report *sheets.Report, dryRun bool) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{}
+	switch {
+	case len(report.Errors) > 0:
+		embed.Title = tr.T("data.import.failed.title")
+		embed.Description = tr.T("data.import.failed.description")
+		embed.Color = 0xe74c3c
+	case dryRun:
+		embed.Title = tr.T("data.import.checked.title")
+		embed.Description = tr.T("data.import.checked.description")
+		embed.Color = 0x3498db
+	default:
+		embed.Title = tr.T("data.import.applied.title")
+		embed.Color = 0x27ae60
+	}
+
+	for _, result := range report.Sheets {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: result.Name,
+			Value: tr.T("data.import.sheet",
+				result.Rows, result.Added, result.Updated, result.Removed),
+			Inline: false,
+		})
+	}
+
+	if len(report.Errors) > 0 {
+		// Embed field values are limited to 1024 characters
+		var lines []string
+		length := 0
+		for i, rowErr := range report.Errors {
+			line := rowErr.String()
+			if i == maxReportErrors || length+len(line) > 900 {
+				lines = append(lines, tr.T("data.import.more_errors", len(report.Errors)-i))
+				break
+			}
+			lines = append(lines, line)
+			length += len(line) + 1
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr.T("data.import.errors", len(report.Errors)),
+			Value:  "```\n" + strings.Join(lines, "\n") + "\n```",
+			Inline: false,
+		})
+	}
+
+	if len(report.Ignored) > 0 {
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: tr.T("data.import.ignored", strings.Join(report.Ignored, ", ")),
+		}
+	}
+	return embed
+}
+
+// GetSlashCommandDefinition returns the slash command definition for data
+func (c *DataCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	manageGuild := int64(discordgo.PermissionManageGuild)
+	dmPermission := false
+
+	var sheetChoices []*discordgo.ApplicationCommandOptionChoice
+	for _, name := range sheets.SheetNames {
+		sheetChoices = append(sheetChoices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:                     "data",
+		Description:              "Imports and exports battles, the event calendar, reminders and templates (Admin only)",
+		DefaultMemberPermissions: &manageGuild,
+		DMPermission:             &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "import",
+				Description: "Imports a .csv or .xlsx file; sheets in the file replace the current data",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionAttachment,
+						Name:        "file",
+						Description: "File with battles, calendar, schedules or templates sheets (CSV: name the file after the sheet)",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "dry_run",
+						Description: "Only validate the file without applying it",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "export",
+				Description: "Exports the current data",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "format",
+						Description: "File format (default: xlsx)",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Excel (.xlsx)", Value: "xlsx"},
+							{Name: "CSV (one file per sheet)", Value: "csv"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "sheet",
+						Description: "Only export one sheet",
+						Required:    false,
+						Choices:     sheetChoices,
+					},
+				},
+			},
+		},
+	}
+}
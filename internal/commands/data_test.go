@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/schedule"
+	"github.com/varubogu/gbf_discord_bot_go/internal/settings"
+	"github.com/varubogu/gbf_discord_bot_go/internal/sheets"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+func newTestDataCommand(files map[string]string) *DataCommand {
+	store := storage.NewMemoryStore()
+	battleManager := gbf.NewBattleManager()
+	logger := log.InitLogger("error")
+	templateManager := templates.NewManager(store, logger)
+	engine := schedule.NewEngine(store, settings.NewManager(store), battleManager, templateManager, commandstest.NewSession(), schedule.SystemClock{}, logger)
+	c := NewDataCommand(logger, i18n.NewResolver(nil), sheets.NewManager(store, battleManager, engine, templateManager, func() string { return "" }, func() string { return testGuildID }))
+	c.fetch = func(ctx context.Context, url string) ([]byte, error) {
+		content, ok := files[url]
+		if !ok {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return []byte(content), nil
+	}
+	return c
+}
+
+func TestDataCommand_Import(t *testing.T) {
+	files := map[string]string{
+		"https://cdn/valid/schedules.csv":  "name,repeat,when,channel,message\nCrew support,daily,21:00,<#123>,Support time\n",
+		"https://cdn/broken/schedules.csv": "name,repeat,when,channel,message\nCrew support,hourly,21:00,<#123>,Support time\n",
+	}
+	c := newTestDataCommand(files)
+	s := commandstest.NewSession()
+	admin := commandstest.Member("admin1")
+
+	run := func(url string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.WebhookEdit {
+		t.Helper()
+		s.Reset()
+		options = append([]*discordgo.ApplicationCommandInteractionDataOption{commandstest.AttachmentOption("file", "att1")}, options...)
+		i := commandstest.SlashCommand(testGuildID, testChannelID, admin, "data", commandstest.Subcommand("import", options...))
+		commandstest.ResolveAttachment(i, &discordgo.MessageAttachment{ID: "att1", Filename: url[strings.LastIndex(url, "/")+1:], URL: url, Size: 100})
+		c.HandleSlashCommand(s, i)
+
+		response := s.LastResponse()
+		if response == nil || response.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+			t.Fatalf("response = %+v, want a deferred response", response)
+		}
+		edits := s.ResponseEdits()
+		if len(edits) != 1 {
+			t.Fatalf("got %d response edits, want 1", len(edits))
+		}
+		return edits[0]
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		dryRun   bool
+		title    string
+		field    string
+		contains string
+	}{
+		{"dry run", "https://cdn/valid/schedules.csv", true, "🔍 Import Check Passed", "schedules", "1 rows: 1 added"},
+		{"apply", "https://cdn/valid/schedules.csv", false, "✅ Import Applied", "schedules", "1 rows: 1 added, 0 updated, 0 removed"},
+		{"invalid rows", "https://cdn/broken/schedules.csv", false, "❌ Import Failed", "Errors (1)", "schedules:2:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edit := run(tt.url, commandstest.BooleanOption("dry_run", tt.dryRun))
+			if edit.Embeds == nil || len(*edit.Embeds) != 1 {
+				t.Fatalf("edit = %+v, want an embed", edit)
+			}
+			embed := (*edit.Embeds)[0]
+			if embed.Title != tt.title {
+				t.Errorf("title = %q, want %q", embed.Title, tt.title)
+			}
+			if got := commandstest.FieldValue(embed, tt.field); !strings.Contains(got, tt.contains) {
+				t.Errorf("field %s = %q, want it to contain %q", tt.field, got, tt.contains)
+			}
+		})
+	}
+
+	edit := run("https://cdn/data.ods")
+	if edit.Content == nil || !strings.HasPrefix(*edit.Content, "❌") {
+		t.Errorf("unsupported file: edit = %+v, want an error", edit)
+	}
+}
+
+func TestDataCommand_Export(t *testing.T) {
+	c := newTestDataCommand(nil)
+	s := commandstest.NewSession()
+	admin := commandstest.Member("admin1")
+
+	tests := []struct {
+		name    string
+		options []*discordgo.ApplicationCommandInteractionDataOption
+		files   []string
+	}{
+		{"default xlsx", nil, []string{"gbf_data.xlsx"}},
+		{"csv", []*discordgo.ApplicationCommandInteractionDataOption{commandstest.StringOption("format", "csv")},
+			[]string{"battles.csv", "calendar.csv", "schedules.csv", "templates.csv"}},
+		{"one sheet", []*discordgo.ApplicationCommandInteractionDataOption{commandstest.StringOption("sheet", "calendar")},
+			[]string{"calendar.xlsx"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.Reset()
+			c.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, admin, "data", commandstest.Subcommand("export", tt.options...)))
+			edits := s.ResponseEdits()
+			if len(edits) != 1 {
+				t.Fatalf("got %d response edits, want 1", len(edits))
+			}
+			var names []string
+			for _, file := range edits[0].Files {
+				names = append(names, file.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.files, ",") {
+				t.Errorf("files = %v, want %v", names, tt.files)
+			}
+		})
+	}
+}
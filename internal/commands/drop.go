@@ -0,0 +1,389 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/drops"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/sheets"
+)
+
+const (
+	// maxDropStatsFields limits the battles or items listed in a stats embed
+	maxDropStatsFields = 20
+	// maxDropStatsItems limits the items shown for each battle of the overview
+	maxDropStatsItems = 3
+)
+
+// DropCommand logs farming runs and drops and shows the observed drop rates
+type DropCommand struct {
+	logger        *log.Logger
+	locales       *i18n.Resolver
+	battleManager *gbf.BattleManager
+	drops         *drops.Manager
+}
+
+// NewDropCommand creates a new drop command handler
+func NewDropCommand(logger *log.Logger, locales *i18n.Resolver, battleManager *gbf.BattleManager, dropManager *drops.Manager) *DropCommand {
+	return &DropCommand{
+		logger:        logger,
+		locales:       locales,
+		battleManager: battleManager,
+		drops:         dropManager,
+	}
+}
+
+// HandleSlashCommand handles the slash version of drop command (/drop log, /drop stats, /drop export)
+func (c *DropCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("drop")
+	tr := c.locales.Interaction(i)
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	subcommand := data.Options[0]
+
+	var battleID, item, userID string
+	runs, count := 1, -1
+	for _, opt := range subcommand.Options {
+		switch opt.Name {
+		case "battle":
+			battleID = opt.StringValue()
+		case "item":
+			item = opt.StringValue()
+		case "runs":
+			runs = int(opt.IntValue())
+		case "count":
+			count = int(opt.IntValue())
+		case "user":
+			userID = opt.Value.(string)
+		}
+	}
+
+	var battle *gbf.BattleInfo
+	if battleID != "" {
+		var err error
+		battle, err = c.battleManager.GetBattle(battleID)
+		if err != nil {
+			c.respond(s, i, logger, &discordgo.InteractionResponseData{
+				Content: "❌ " + tr.T("battle.not_found.description", battleID),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			})
+			return
+		}
+	}
+
+	ctx := context.Background()
+	var response *discordgo.InteractionResponseData
+	var err error
+	switch subcommand.Name {
+	case "log":
+		// A named item dropped once unless told otherwise
+		if count < 0 {
+			count = 0
+			if item != "" {
+				count = 1
+			}
+		}
+		response, err = c.log(ctx, tr, i, battle, item, runs, count)
+	case "stats":
+		response, err = c.stats(ctx, tr, i.GuildID, battle, userID)
+	case "export":
+		response, err = c.export(ctx, tr, i.GuildID, battle)
+	}
+	if err != nil {
+		logger.WithError(err).Warn("Drop command failed", "subcommand", subcommand.Name)
+		response = &discordgo.InteractionResponseData{
+			Content: errorMessage(tr, err),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		}
+	}
+
+	c.respond(s, i, logger, response)
+
+	logger.Info("Drop slash command executed successfully", "subcommand", subcommand.Name, "battle_id", battleID)
+}
+
+// log records runs and drops of the member (/drop log)
+func (c *DropCommand) log(ctx context.Context, tr i18n.Localizer, i *discordgo.InteractionCreate, battle *gbf.BattleInfo, item string, runs, count int) (*discordgo.InteractionResponseData, error) {
+	tally, err := c.drops.Log(ctx, i.GuildID, i.Member.User.ID, battle.ID, item, runs, count)
+	if err != nil {
+		return nil, err
+	}
+
+	name := battleDisplayName(tr, battle)
+	content := tr.T("drop.logged_runs", runs, name)
+	if count > 0 {
+		content = tr.T("drop.logged_drops", count, drops.NormalizeItem(item), name, runs)
+	}
+	content += "\n" + tr.T("drop.total", tally.Runs)
+	return &discordgo.InteractionResponseData{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	}, nil
+}
+
+// stats shows the drop rates of one battle, or an overview of every battle (/drop stats)
+func (c *DropCommand) stats(ctx context.Context, tr i18n.Localizer, guildID string, battle *gbf.BattleInfo, userID string) (*discordgo.InteractionResponseData, error) {
+	tallies, err := c.drops.Tallies(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	summary := drops.Summarize(tallies, userID)
+	if battle != nil {
+		summary = filterBattleStats(summary, battle.ID)
+	}
+	if len(summary) == 0 {
+		return &discordgo.InteractionResponseData{
+			Content: tr.T("drop.stats.empty"),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		}, nil
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       tr.T("drop.stats.title"),
+		Description: tr.T("drop.stats.crew"),
+		Color:       0x3498db,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: tr.T("drop.stats.footer"),
+		},
+	}
+	if userID != "" {
+		embed.Description = tr.T("drop.stats.member", userID)
+	}
+
+	if battle != nil {
+		stats := summary[0]
+		embed.Title = tr.T("drop.stats.battle_title", battleDisplayName(tr, battle))
+		embed.Description += "\n" + tr.T("drop.stats.runs", stats.Runs, stats.Members)
+		for _, item := range stats.Items[:min(len(stats.Items), maxDropStatsFields)] {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   item.Item,
+				Value:  tr.T("drop.stats.item", item.Drops, stats.Runs) + "\n" + formatDropRate(tr, item),
+				Inline: true,
+			})
+		}
+		if len(stats.Items) == 0 {
+			embed.Description += "\n" + tr.T("drop.stats.no_drops")
+		}
+	} else {
+		for _, stats := range summary[:min(len(summary), maxDropStatsFields)] {
+			lines := []string{tr.T("drop.stats.runs", stats.Runs, stats.Members)}
+			for _, item := range stats.Items[:min(len(stats.Items), maxDropStatsItems)] {
+				lines = append(lines, fmt.Sprintf("%s: %s", item.Item, formatDropRate(tr, item)))
+			}
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:  c.battleName(tr, stats.BattleID),
+				Value: strings.Join(lines, "\n"),
+			})
+		}
+	}
+
+	return &discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{embed},
+	}, nil
+}
+
+// export attaches the guild's tallies as CSV (/drop export)
+func (c *DropCommand) export(ctx context.Context, tr i18n.Localizer, guildID string, battle *gbf.BattleInfo) (*discordgo.InteractionResponseData, error) {
+	tallies, err := c.drops.Tallies(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	if battle != nil {
+		var filtered []drops.Tally
+		for _, tally := range tallies {
+			if tally.BattleID == battle.ID {
+				filtered = append(filtered, tally)
+			}
+		}
+		tallies = filtered
+	}
+	if len(tallies) == 0 {
+		return &discordgo.InteractionResponseData{
+			Content: tr.T("drop.stats.empty"),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		}, nil
+	}
+
+	rows := drops.ExportRows(tallies)
+	content, err := sheets.CSV{}.Encode(&sheets.Workbook{Sheets: []*sheets.Sheet{{Name: "drops", Rows: rows}}})
+	if err != nil {
+		return nil, err
+	}
+	return &discordgo.InteractionResponseData{
+		Content: tr.T("drop.exported", len(rows)-1),
+		Files: []*discordgo.File{{
+			Name:        "drops.csv",
+			ContentType: "text/csv",
+			Reader:      bytes.NewReader(content),
+		}},
+		Flags: discordgo.MessageFlagsEphemeral,
+	}, nil
+}
+
+// HandleAutocomplete suggests battles, and the items already logged on this server
+func (c *DropCommand) HandleAutocomplete(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("drop")
+	tr := c.locales.Interaction(i)
+
+	option, _ := focusedOption(i.ApplicationCommandData().Options)
+	query := focusedText(option)
+
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	switch {
+	case option == nil:
+	case option.Name == "battle":
+		choices = battleChoices(tr, c.battleManager, query)
+	case option.Name == "item":
+		items, err := c.loggedItems(i.GuildID, drops.NormalizeItem(query))
+		if err != nil {
+			logger.WithError(err).Warn("Failed to load logged items")
+		}
+		for _, item := range items {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: choiceName(item), Value: item})
+		}
+	}
+
+	respondAutocomplete(s, i, logger, choices)
+}
+
+// loggedItems returns the items logged on a server that contain query, by name
+func (c *DropCommand) loggedItems(guildID, query string) ([]string, error) {
+	tallies, err := c.drops.Tallies(context.Background(), guildID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var items []string
+	for _, tally := range tallies {
+		for item := range tally.Drops {
+			if !seen[item] && strings.Contains(item, query) {
+				seen[item] = true
+				items = append(items, item)
+			}
+		}
+	}
+	slices.Sort(items)
+	return items, nil
+}
+
+// battleName returns the display name of a battle ID, or the ID of a battle no longer in the catalog
+func (c *DropCommand) battleName(tr i18n.Localizer, battleID string) string {
+	battle, err := c.battleManager.GetBattle(battleID)
+	if err != nil {
+		return battleID
+	}
+	return battleDisplayName(tr, battle)
+}
+
+// respond sends the command's response
+func (c *DropCommand) respond(s Session, i *discordgo.InteractionCreate, logger *log.Logger, data *discordgo.InteractionResponseData) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to drop slash command")
+	}
+}
+
+// filterBattleStats returns the stats of one battle
+func filterBattleStats(summary []drops.BattleStats, battleID string) []drops.BattleStats {
+	for _, stats := range summary {
+		if stats.BattleID == battleID {
+			return []drops.BattleStats{stats}
+		}
+	}
+	return nil
+}
+
+// formatDropRate formats an observed drop rate with its confidence interval
+func formatDropRate(tr i18n.Localizer, item drops.ItemStats) string {
+	return tr.T("drop.stats.rate", item.Rate*100, item.Low*100, item.High*100)
+}
+
+// GetSlashCommandDefinition returns the slash command definition for drop
+func (c *DropCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	minZero := float64(0)
+	dmPermission := false
+	battleOption := func(required bool, description string) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "battle",
+			Description:  description,
+			Required:     required,
+			Autocomplete: true,
+		}
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:         "drop",
+		Description:  "Tracks farming runs and drop rates",
+		DMPermission: &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "log",
+				Description: "Logs your runs of a battle and what dropped",
+				Options: []*discordgo.ApplicationCommandOption{
+					battleOption(true, "Battle you ran"),
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "item",
+						Description:  "Item that dropped, e.g. gold bar",
+						Required:     false,
+						Autocomplete: true,
+						MaxLength:    drops.MaxItemLength,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "runs",
+						Description: "Runs to add (default 1; 0 to log another drop of the same runs)",
+						Required:    false,
+						MinValue:    &minZero,
+						MaxValue:    1000,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "count",
+						Description: "How many of the item dropped (default 1)",
+						Required:    false,
+						MinValue:    &minZero,
+						MaxValue:    1000,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "stats",
+				Description: "Shows drop rates of this server's crew",
+				Options: []*discordgo.ApplicationCommandOption{
+					battleOption(false, "Battle to show; all battles when omitted"),
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        "user",
+						Description: "Only count this member's runs",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "export",
+				Description: "Exports the logged runs and drops as CSV",
+				Options: []*discordgo.ApplicationCommandOption{
+					battleOption(false, "Battle to export; all battles when omitted"),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/drops"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+func TestDropCommand(t *testing.T) {
+	c := NewDropCommand(log.InitLogger("error"), i18n.NewResolver(nil), gbf.NewBattleManager(), drops.NewManager(storage.NewMemoryStore()))
+	s := commandstest.NewSession()
+
+	drop := func(userID, subcommand string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionResponse {
+		t.Helper()
+		s.Reset()
+		c.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member(userID), "drop",
+			commandstest.Subcommand(subcommand, options...)))
+		response := s.LastResponse()
+		if response == nil {
+			t.Fatalf("/drop %s got no response", subcommand)
+		}
+		return response
+	}
+
+	if response := drop("alice", "stats"); response.Data.Content != "No runs have been logged yet. Use /drop log to add some." {
+		t.Errorf("empty stats = %q", response.Data.Content)
+	}
+
+	logs := []struct {
+		name        string
+		userID      string
+		options     []*discordgo.ApplicationCommandInteractionDataOption
+		wantContent string
+	}{
+		{
+			name:   "runs without drops",
+			userID: "alice",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("battle", "akasha_hl"),
+				commandstest.IntegerOption("runs", 100),
+			},
+			wantContent: "✅ Logged 100 run(s) of Akasha (Hard).\nYour runs so far: 100",
+		},
+		{
+			name:   "drop of runs already logged",
+			userID: "alice",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("battle", "akasha_hl"),
+				commandstest.StringOption("item", "Gold Bar"),
+				commandstest.IntegerOption("runs", 0),
+			},
+			wantContent: "✅ Logged 1 × gold bar from Akasha (Hard) in 0 runs.\nYour runs so far: 100",
+		},
+		{
+			name:   "runs and drops together",
+			userID: "bob",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("battle", "akasha_hl"),
+				commandstest.StringOption("item", "gold bar"),
+				commandstest.IntegerOption("runs", 50),
+				commandstest.IntegerOption("count", 2),
+			},
+			wantContent: "✅ Logged 2 × gold bar from Akasha (Hard) in 50 runs.\nYour runs so far: 50",
+		},
+		{
+			name:   "another battle",
+			userID: "bob",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("battle", "ubaha_hl"),
+			},
+			wantContent: "✅ Logged 1 run(s) of Ultimate Bahamut (Hard).\nYour runs so far: 1",
+		},
+		{
+			name:   "nothing to log",
+			userID: "bob",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("battle", "akasha_hl"),
+				commandstest.IntegerOption("runs", 0),
+			},
+			wantContent: "❌ Log at least one run or drop.",
+		},
+		{
+			name:   "unknown battle",
+			userID: "bob",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("battle", "nope"),
+			},
+			wantContent: "❌ No battle found with ID: `nope`",
+		},
+	}
+	for _, tt := range logs {
+		t.Run(tt.name, func(t *testing.T) {
+			response := drop(tt.userID, "log", tt.options...)
+			if response.Data.Content != tt.wantContent {
+				t.Errorf("content = %q, want %q", response.Data.Content, tt.wantContent)
+			}
+			if response.Data.Flags != discordgo.MessageFlagsEphemeral {
+				t.Error("expected an ephemeral response")
+			}
+		})
+	}
+
+	t.Run("stats of a battle", func(t *testing.T) {
+		response := drop("alice", "stats", commandstest.StringOption("battle", "akasha_hl"))
+		embed := response.Data.Embeds[0]
+		if !strings.Contains(embed.Description, "150 runs · 2 member(s)") {
+			t.Errorf("description = %q, want the crew's runs", embed.Description)
+		}
+		if value := commandstest.FieldValue(embed, "gold bar"); value != "3 drops in 150 runs\n2.00% (95% CI 0.68–5.71%)" {
+			t.Errorf("gold bar = %q", value)
+		}
+	})
+
+	t.Run("stats overview of a member", func(t *testing.T) {
+		response := drop("alice", "stats", commandstest.UserOption("user", "bob"))
+		embed := response.Data.Embeds[0]
+		var names []string
+		for _, field := range embed.Fields {
+			names = append(names, field.Name)
+		}
+		if !slices.Equal(names, []string{"Akasha (Hard)", "Ultimate Bahamut (Hard)"}) {
+			t.Errorf("fields = %v, want bob's battles by runs", names)
+		}
+		if value := commandstest.FieldValue(embed, "Akasha (Hard)"); !strings.HasPrefix(value, "50 runs · 1 member(s)\ngold bar: 4.00%") {
+			t.Errorf("Akasha (Hard) = %q", value)
+		}
+	})
+
+	t.Run("export", func(t *testing.T) {
+		response := drop("alice", "export", commandstest.StringOption("battle", "akasha_hl"))
+		if len(response.Data.Files) != 1 || response.Data.Files[0].Name != "drops.csv" {
+			t.Fatalf("files = %+v, want drops.csv", response.Data.Files)
+		}
+		content, err := io.ReadAll(response.Data.Files[0].Reader)
+		if err != nil {
+			t.Fatalf("reading export: %v", err)
+		}
+		want := "user_id,battle_id,runs,item,drops\nalice,akasha_hl,100,gold bar,1\nbob,akasha_hl,50,gold bar,2\n"
+		if got := strings.TrimPrefix(strings.ReplaceAll(string(content), "\r\n", "\n"), "\ufeff"); got != want {
+			t.Errorf("export = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("autocomplete logged items", func(t *testing.T) {
+		s.Reset()
+		c.HandleAutocomplete(s, commandstest.Autocomplete(testGuildID, testChannelID, commandstest.Member("alice"), "drop",
+			commandstest.Subcommand("log",
+				commandstest.StringOption("battle", "akasha_hl"),
+				commandstest.Focused(commandstest.StringOption("item", "GOLD")))))
+		if values := choiceValues(t, s); !slices.Equal(values, []string{"gold bar"}) {
+			t.Errorf("choices = %v, want [gold bar]", values)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+)
+
+// elementNames are the English names of the elements offered as option choices
+var elementNames = map[gbf.Element]string{
+	gbf.ElementFire:  "Fire",
+	gbf.ElementWater: "Water",
+	gbf.ElementEarth: "Earth",
+	gbf.ElementWind:  "Wind",
+	gbf.ElementLight: "Light",
+	gbf.ElementDark:  "Dark",
+	gbf.ElementAll:   "All elements",
+}
+
+// elementOption returns a string option choosing one of the six elements, or all
+// of them as well when withAll is set
+func elementOption(name, description string, withAll bool) *discordgo.ApplicationCommandOption {
+	elements := gbf.Elements
+	if withAll {
+		elements = append(elements[:len(elements):len(elements)], gbf.ElementAll)
+	}
+
+	option := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        name,
+		Description: description,
+		Required:    false,
+	}
+	for _, element := range elements {
+		option.Choices = append(option.Choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  elementNames[element],
+			Value: string(element),
+		})
+	}
+	return option
+}
+
+// elementLabel returns the name of an element in the locale
+func elementLabel(tr i18n.Localizer, element gbf.Element) string {
+	if label, exists := tr.Lookup("elements." + string(element)); exists {
+		return label
+	}
+	return string(element)
+}
+
+// elementMatchup returns the elements of a hit and the enemy, defaulting to an
+// attack of all elements on an enemy it has advantage on
+func elementMatchup(attacker, defender gbf.Element) (gbf.Element, gbf.Element) {
+	if attacker == "" {
+		attacker = gbf.ElementAll
+	}
+	if defender == "" {
+		defender = attacker.AdvantageOn()
+	}
+	return attacker, defender
+}
+
+// formatMatchup describes how the elements of a hit and the enemy match up, e.g.
+// "Fire vs Wind (advantage)"
+func formatMatchup(tr i18n.Localizer, attacker, defender gbf.Element) string {
+	affinity := attacker.Against(defender)
+	return tr.T("elements.matchup", elementLabel(tr, attacker), elementLabel(tr, defender), tr.T("elements.affinity."+string(affinity)))
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"errors"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/drops"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/schedule"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+// errorKeys are the catalog keys of errors users commonly run into. Other errors
+// are shown with their own text.
+var errorKeys = []struct {
+	err error
+	key string
+}{
+	{gbf.ErrRecruitmentNotOpen, "errors.recruitment_not_open"},
+	{gbf.ErrAlreadyParticipant, "errors.already_participant"},
+	{gbf.ErrRecruitmentFull, "errors.recruitment_full"},
+	{gbf.ErrHostCannotLeave, "errors.host_cannot_leave"},
+	{gbf.ErrNotParticipant, "errors.not_participant"},
+	{schedule.ErrNotScheduled, "errors.gw_not_scheduled"},
+	{schedule.ErrEventNotFound, "errors.event_not_found"},
+	{schedule.ErrCalendarEventNotFound, "errors.calendar_event_not_found"},
+	{schedule.ErrOperatorOnly, "errors.operator_only"},
+	{templates.ErrNotCustomized, "errors.template_not_customized"},
+	{drops.ErrNothingToLog, "errors.nothing_to_log"},
+	{gbf.ErrUnknownRecipe, "errors.unknown_recipe"},
+	{gbf.ErrUnknownMaterial, "errors.unknown_material"},
+	{gbf.ErrEmptyBattleLog, "errors.empty_battle_log"},
+}
+
+// errorMessage formats an error as a reply, translating the errors in errorKeys
+func errorMessage(tr i18n.Localizer, err error) string {
+	for _, known := range errorKeys {
+		if errors.Is(err, known.err) {
+			return "❌ " + tr.T(known.key)
+		}
+	}
+	return "❌ " + capitalize(err.Error())
+}
+
+// capitalize upper-cases the first letter of a message
+func capitalize(message string) string {
+	first, size := utf8.DecodeRuneInString(message)
+	if size == 0 {
+		return message
+	}
+	return string(unicode.ToUpper(first)) + message[size:]
+}
@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+// defaultCharacterAttack is the ATK of a character before weapons and summons
+const defaultCharacterAttack = 10000
+
+// GridCommand calculates and compares weapon grids
+type GridCommand struct {
+	logger     *log.Logger
+	locales    *i18n.Resolver
+	calculator *gbf.AttackCalculator
+}
+
+// NewGridCommand creates a new grid command handler
+func NewGridCommand(logger *log.Logger, locales *i18n.Resolver) *GridCommand {
+	return &GridCommand{
+		logger:     logger,
+		locales:    locales,
+		calculator: gbf.NewAttackCalculator(),
+	}
+}
+
+// gridResult is a grid run through the damage pipeline
+type gridResult struct {
+	modifiers gbf.GridModifiers
+	damage    *gbf.DamageBreakdown
+}
+
+// HandleSlashCommand handles the slash version of grid command (/grid calc)
+func (c *GridCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("grid")
+	tr := c.locales.Interaction(i)
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+
+	var grids []string
+	var attacker, defender gbf.Element
+	attack, hpPercent := defaultCharacterAttack, 100
+	for _, opt := range data.Options[0].Options {
+		switch opt.Name {
+		case "grid", "compare":
+			grids = append(grids, opt.StringValue())
+		case "attack":
+			attack = int(opt.IntValue())
+		case "hp":
+			hpPercent = int(opt.IntValue())
+		case "element":
+			attacker = gbf.Element(opt.StringValue())
+		case "enemy":
+			defender = gbf.Element(opt.StringValue())
+		}
+	}
+	attacker, defender = elementMatchup(attacker, defender)
+
+	embed := &discordgo.MessageEmbed{
+		Title: tr.T("grid.title"),
+		Color: 0x3498db,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: tr.T("grid.footer", formatMatchup(tr, attacker, defender), attack, hpPercent),
+		},
+	}
+
+	var results []gridResult
+	for n, text := range grids {
+		label := gridLabel(tr, n)
+		result, err := c.calculate(text, attack, hpPercent, attacker, defender)
+		if err != nil {
+			logger.WithError(err).Warn("Invalid grid", "grid", text)
+			c.respond(s, i, logger, &discordgo.InteractionResponseData{
+				Content: "❌ " + tr.T("grid.invalid", label, err),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			})
+			return
+		}
+		results = append(results, result)
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  label,
+			Value: formatGridResult(tr, result, hpPercent),
+		})
+	}
+
+	if len(results) == 2 {
+		first, second := results[0].damage.Damage, results[1].damage.Damage
+		var change float64
+		if first > 0 {
+			change = float64(second-first) / float64(first) * 100
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  tr.T("grid.difference"),
+			Value: tr.T("grid.compared", gridLabel(tr, 1), change, gridLabel(tr, 0)),
+		})
+		if change < 0 {
+			embed.Color = 0xe74c3c
+		} else {
+			embed.Color = 0x27ae60
+		}
+	}
+
+	c.respond(s, i, logger, &discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{embed},
+	})
+
+	logger.Info("Grid slash command executed successfully", "grids", len(results))
+}
+
+// calculate runs a grid through the damage pipeline against an enemy of element defender
+func (c *GridCommand) calculate(text string, attack, hpPercent int, attacker, defender gbf.Element) (gridResult, error) {
+	grid, err := gbf.ParseGrid(text)
+	if err != nil {
+		return gridResult{}, err
+	}
+	modifiers, err := grid.Modifiers()
+	if err != nil {
+		return gridResult{}, err
+	}
+
+	input := modifiers.Apply(gbf.DamageInput{
+		BaseAttack: attack,
+		Attacker:   attacker,
+		Defender:   defender,
+		HPRatio:    float64(hpPercent) / 100,
+	})
+	damage, err := c.calculator.Calculate(input)
+	if err != nil {
+		return gridResult{}, err
+	}
+	return gridResult{modifiers: modifiers, damage: damage}, nil
+}
+
+// respond sends the command's response
+func (c *GridCommand) respond(s Session, i *discordgo.InteractionCreate, logger *log.Logger, data *discordgo.InteractionResponseData) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to grid slash command")
+	}
+}
+
+// gridLabel names the nth grid of a comparison
+func gridLabel(tr i18n.Localizer, n int) string {
+	return tr.T("grid.label", string(rune('A'+n)))
+}
+
+// formatGridResult describes the modifiers and damage of a grid
+func formatGridResult(tr i18n.Localizer, result gridResult, hpPercent int) string {
+	m := result.modifiers
+	hpRatio := float64(hpPercent) / 100
+
+	// Chance that at least one crit source triggers
+	noCrit := 1.0
+	for _, crit := range m.Crits {
+		noCrit *= 1 - crit.Rate
+	}
+
+	lines := []string{
+		tr.T("grid.buckets", m.Normal*100, m.Omega*100, m.EX*100),
+		tr.T("grid.hp_skills", gbf.EnmityBoost(m.Enmity, hpRatio)*100, gbf.StaminaBoost(m.Stamina, hpRatio)*100),
+		tr.T("grid.rates", (1-noCrit)*100, m.DoubleAttack*100, m.TripleAttack*100),
+		tr.T("grid.attack", int(math.Round(result.damage.Attack))),
+		tr.T("grid.damage", result.damage.Damage, result.damage.CapLoss()*100),
+	}
+	if m.Elemental > 0 {
+		lines = append(lines, tr.T("grid.elemental", m.Elemental*100))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GetSlashCommandDefinition returns the slash command definition for grid
+func (c *GridCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	minAttack := float64(1)
+	minHP := float64(1)
+	dmPermission := false
+
+	return &discordgo.ApplicationCommand{
+		Name:         "grid",
+		Description:  "Calculates weapon grids",
+		DMPermission: &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "calc",
+				Description: "Shows the modifiers and damage of a grid, or compares two grids",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "grid",
+						Description: "Weapons and summons, e.g. 6x omega might big sl15, 4x normal crit, summon omega 140",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "compare",
+						Description: "Another grid to compare with the first",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "attack",
+						Description: fmt.Sprintf("Character ATK before weapons and summons (default %d)", defaultCharacterAttack),
+						Required:    false,
+						MinValue:    &minAttack,
+						MaxValue:    1000000,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "hp",
+						Description: "HP percentage for enmity and stamina (default 100)",
+						Required:    false,
+						MinValue:    &minHP,
+						MaxValue:    100,
+					},
+					elementOption("element", "Element of the characters (default: all elements)", true),
+					elementOption("enemy", "Element of the enemy (default: the element yours beats)", true),
+				},
+			},
+		},
+	}
+}
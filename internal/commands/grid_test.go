@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+func TestGridCommand(t *testing.T) {
+	c := NewGridCommand(log.InitLogger("error"), i18n.NewResolver(nil))
+	s := commandstest.NewSession()
+
+	tests := []struct {
+		name       string
+		options    []*discordgo.ApplicationCommandInteractionDataOption
+		wantFields map[string]string
+		wantFooter string
+		wantError  string
+	}{
+		{
+			name: "shows the modifiers of a grid",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("grid", "10x normal might big"),
+			},
+			wantFields: map[string]string{
+				"Grid A": "Normal 150.0% / Omega 0.0% / EX 0.0%",
+			},
+			wantFooter: "All elements vs All elements (advantage), ",
+		},
+		{
+			name: "elemental disadvantage",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("grid", "10x normal might big"),
+				commandstest.StringOption("element", "water"),
+				commandstest.StringOption("enemy", "earth"),
+			},
+			wantFields: map[string]string{
+				"Grid A": "ATK 18750",
+			},
+			wantFooter: "Water vs Earth (disadvantage), ",
+		},
+		{
+			name: "enemy defaults to the element yours beats",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("grid", "10x normal might big"),
+				commandstest.StringOption("element", "light"),
+			},
+			wantFields: map[string]string{
+				"Grid A": "ATK 37500",
+			},
+			wantFooter: "Light vs Dark (advantage), ",
+		},
+		{
+			name: "compares two grids",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("grid", "10x normal might big"),
+				commandstest.StringOption("compare", "10x normal might big, summon normal 100"),
+			},
+			wantFields: map[string]string{
+				"Grid B":     "Normal 300.0% / Omega 0.0% / EX 0.0%",
+				"Difference": "Grid B deals +",
+			},
+		},
+		{
+			name: "applies enmity at the given HP",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("grid", "10x normal enmity"),
+				commandstest.IntegerOption("hp", 1),
+			},
+			wantFields: map[string]string{
+				"Grid A": "Enmity ",
+			},
+		},
+		{
+			name: "rejects an invalid comparison grid",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("grid", "10x normal might"),
+				commandstest.StringOption("compare", "3x magic might"),
+			},
+			wantError: "❌ Grid B is invalid: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("user1"), "grid",
+				commandstest.Subcommand("calc", tt.options...))
+
+			c.HandleSlashCommand(s, i)
+
+			response := s.LastResponse()
+			if response == nil || response.Data == nil {
+				t.Fatal("no response")
+			}
+			if tt.wantError != "" {
+				if !strings.HasPrefix(response.Data.Content, tt.wantError) {
+					t.Errorf("content = %q, want prefix %q", response.Data.Content, tt.wantError)
+				}
+				if response.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+					t.Error("error response is not ephemeral")
+				}
+				return
+			}
+			if len(response.Data.Embeds) != 1 {
+				t.Fatalf("embeds = %d, want 1", len(response.Data.Embeds))
+			}
+			for field, want := range tt.wantFields {
+				if got := commandstest.FieldValue(response.Data.Embeds[0], field); !strings.Contains(got, want) {
+					t.Errorf("field %q = %q, want it to contain %q", field, got, want)
+				}
+			}
+			if footer := response.Data.Embeds[0].Footer.Text; !strings.HasPrefix(footer, tt.wantFooter) {
+				t.Errorf("footer = %q, want prefix %q", footer, tt.wantFooter)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+// GuildWarCommand plans Guild War contribution
+type GuildWarCommand struct {
+	logger        *log.Logger
+	locales       *i18n.Resolver
+	battleManager *gbf.BattleManager
+}
+
+// NewGuildWarCommand creates a new Guild War command handler
+func NewGuildWarCommand(logger *log.Logger, locales *i18n.Resolver, battleManager *gbf.BattleManager) *GuildWarCommand {
+	return &GuildWarCommand{
+		logger:        logger,
+		locales:       locales,
+		battleManager: battleManager,
+	}
+}
+
+// HandleSlashCommand handles the slash version of Guild War command (/gw plan)
+func (c *GuildWarCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("gw")
+	tr := c.locales.Interaction(i)
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+
+	var input gbf.GuildWarPlanInput
+	var tier string
+	for _, opt := range data.Options[0].Options {
+		switch opt.Name {
+		case "target":
+			input.Target = int(opt.IntValue())
+		case "tier":
+			tier = opt.StringValue()
+		case "clear_time":
+			input.ClearTime = time.Duration(opt.IntValue()) * time.Second
+		case "budget":
+			input.DailyBudget = time.Duration(opt.IntValue()) * time.Minute
+		case "days":
+			input.Days = int(opt.IntValue())
+		}
+	}
+
+	embed, err := c.buildPlanEmbed(tr, tier, input)
+	if err != nil {
+		logger.WithError(err).Warn("Guild War plan failed", "tier", tier)
+		c.respond(s, i, logger, &discordgo.InteractionResponseData{
+			Content: "❌ " + err.Error(),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		return
+	}
+
+	c.respond(s, i, logger, &discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{embed},
+	})
+
+	logger.Info("Guild War plan slash command executed successfully", "target", input.Target, "tier", tier)
+}
+
+// HandleAutocomplete suggests the nightmare battles with Guild War rewards
+func (c *GuildWarCommand) HandleAutocomplete(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("gw")
+	tr := c.locales.Interaction(i)
+
+	option, _ := focusedOption(i.ApplicationCommandData().Options)
+	query := strings.ToLower(focusedText(option))
+
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, battle := range c.battleManager.GuildWarBattles() {
+		name := battleDisplayName(tr, battle)
+		if strings.Contains(battle.ID, query) || strings.Contains(strings.ToLower(name), query) {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  choiceName(fmt.Sprintf("%s (%s)", name, battle.ID)),
+				Value: battle.ID,
+			})
+		}
+	}
+
+	respondAutocomplete(s, i, logger, choices)
+}
+
+// respond sends the command's response
+func (c *GuildWarCommand) respond(s Session, i *discordgo.InteractionCreate, logger *log.Logger, data *discordgo.InteractionResponseData) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to gw slash command")
+	}
+}
+
+// buildPlanEmbed plans the target with one tier, or compares every tier.
+// Errors are worded for the user.
+func (c *GuildWarCommand) buildPlanEmbed(tr i18n.Localizer, tier string, input gbf.GuildWarPlanInput) (*discordgo.MessageEmbed, error) {
+	battles := c.battleManager.GuildWarBattles()
+	if tier != "" {
+		battle, err := c.battleManager.GetBattle(tier)
+		if err != nil || battle.GuildWar == nil {
+			return nil, errors.New(tr.T("gw.plan.unknown_tier", tier))
+		}
+		battles = []*gbf.BattleInfo{battle}
+	} else if input.ClearTime > 0 {
+		return nil, errors.New(tr.T("gw.plan.clear_time_needs_tier"))
+	}
+	if len(battles) == 0 {
+		return nil, errors.New(tr.T("gw.plan.no_tiers"))
+	}
+
+	var plans []*gbf.GuildWarPlan
+	var best *gbf.GuildWarPlan
+	for _, battle := range battles {
+		plan, err := gbf.PlanGuildWar(battle, input)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+		if plan.FitsBudget && (best == nil || plan.TotalTime < best.TotalTime) {
+			best = plan
+		}
+	}
+
+	days := input.Days
+	if days == 0 {
+		days = gbf.GuildWarDays
+	}
+	description := tr.T("gw.plan.summary", formatNumber(input.Target), days)
+	if input.DailyBudget > 0 {
+		description += "\n" + tr.T("gw.plan.budget", formatDuration(tr, input.DailyBudget))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       tr.T("gw.plan.title"),
+		Description: description,
+		Color:       0x27ae60,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: tr.T("gw.plan.footer"),
+		},
+	}
+	if best == nil {
+		embed.Color = 0xf39c12
+		embed.Description += "\n" + tr.T("gw.plan.over_budget")
+	}
+
+	for _, plan := range plans {
+		name := battleDisplayName(tr, plan.Battle)
+		if plan == best && len(plans) > 1 {
+			name = "⭐ " + name
+		}
+		lines := []string{
+			tr.T("gw.plan.runs", formatNumber(plan.Runs), plan.RunsPerDay),
+			tr.T("gw.plan.cost", formatNumber(plan.Meat), formatNumber(plan.AP)),
+			tr.T("gw.plan.time", formatDuration(tr, plan.TotalTime), formatDuration(tr, plan.DailyTime), formatClearTime(plan.ClearTime)),
+			tr.T("gw.plan.rate", formatNumber(plan.HonorsPerHour())),
+		}
+		if !plan.FitsBudget {
+			lines = append(lines, tr.T("gw.plan.tier_over_budget"))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  name,
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+	return embed, nil
+}
+
+// GetSlashCommandDefinition returns the slash command definition for gw
+func (c *GuildWarCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	minOne := float64(1)
+	dmPermission := false
+
+	return &discordgo.ApplicationCommand{
+		Name:         "gw",
+		Description:  "Guild War tools",
+		DMPermission: &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "plan",
+				Description: "Plans the nightmare runs needed to reach an honors target",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "target",
+						Description: "Honors to reach",
+						Required:    true,
+						MinValue:    &minOne,
+						MaxValue:    1e12,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "tier",
+						Description:  "Nightmare battle to farm; all tiers are compared when omitted",
+						Required:     false,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "clear_time",
+						Description: "Your clear time of the tier in seconds",
+						Required:    false,
+						MinValue:    &minOne,
+						MaxValue:    3600,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "budget",
+						Description: "Minutes you can play each day",
+						Required:    false,
+						MinValue:    &minOne,
+						MaxValue:    1440,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "days",
+						Description: fmt.Sprintf("Days to spread the runs over (default %d)", gbf.GuildWarDays),
+						Required:    false,
+						MinValue:    &minOne,
+						MaxValue:    30,
+					},
+				},
+			},
+		},
+	}
+}
+
+// formatNumber formats an integer with thousands separators, e.g. 1,234,567
+func formatNumber(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return sign + digits
+}
+
+// formatClearTime formats a clear time as minutes and seconds, e.g. 2:30
+func formatClearTime(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+func TestGuildWarCommand_Plan(t *testing.T) {
+	c := NewGuildWarCommand(log.InitLogger("error"), i18n.NewResolver(nil), gbf.NewBattleManager())
+	s := commandstest.NewSession()
+
+	tests := []struct {
+		name       string
+		options    []*discordgo.ApplicationCommandInteractionDataOption
+		wantColor  int
+		wantFields map[string]string
+		wantError  string
+	}{
+		{
+			name: "compares every tier and stars the fastest",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.IntegerOption("target", 20000000),
+			},
+			wantColor: 0x27ae60,
+			wantFields: map[string]string{
+				"Guild War NM90":    "77 runs (16 per day)",
+				"⭐ Guild War NM200": "2 runs (1 per day)\n60 meat / 100 AP\n8m total, 4m per day (4:00 per run)\n150,000,000 honors per hour",
+			},
+		},
+		{
+			name: "daily budget excludes the slower days",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.IntegerOption("target", 20000000),
+				commandstest.IntegerOption("budget", 3),
+			},
+			wantColor: 0x27ae60,
+			wantFields: map[string]string{
+				"⭐ Guild War NM150": "5 runs (1 per day)",
+				"Guild War NM200":   "⚠️ Over the daily budget",
+			},
+		},
+		{
+			name: "own clear time of one tier",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.IntegerOption("target", 20000000),
+				commandstest.StringOption("tier", "nm150"),
+				commandstest.IntegerOption("clear_time", 120),
+				commandstest.IntegerOption("budget", 1),
+			},
+			wantColor: 0xf39c12,
+			wantFields: map[string]string{
+				"Guild War NM150": "10m total, 2m per day (2:00 per run)",
+			},
+		},
+		{
+			name: "clear time without a tier",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.IntegerOption("target", 20000000),
+				commandstest.IntegerOption("clear_time", 120),
+			},
+			wantError: "❌ clear_time needs a tier.",
+		},
+		{
+			name: "tier that is not a nightmare battle",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.IntegerOption("target", 20000000),
+				commandstest.StringOption("tier", "baha_hl"),
+			},
+			wantError: "❌ `baha_hl` is not a Guild War nightmare battle.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("user1"), "gw",
+				commandstest.Subcommand("plan", tt.options...))
+
+			c.HandleSlashCommand(s, i)
+
+			response := s.LastResponse()
+			if response == nil || response.Data == nil {
+				t.Fatal("no response")
+			}
+			if tt.wantError != "" {
+				if response.Data.Content != tt.wantError {
+					t.Errorf("content = %q, want %q", response.Data.Content, tt.wantError)
+				}
+				return
+			}
+			if len(response.Data.Embeds) != 1 {
+				t.Fatalf("embeds = %d, want 1", len(response.Data.Embeds))
+			}
+			embed := response.Data.Embeds[0]
+			if embed.Color != tt.wantColor {
+				t.Errorf("color = %#x, want %#x", embed.Color, tt.wantColor)
+			}
+			for field, want := range tt.wantFields {
+				if got := commandstest.FieldValue(embed, field); !strings.Contains(got, want) {
+					t.Errorf("field %q = %q, want it to contain %q", field, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGuildWarCommand_Autocomplete(t *testing.T) {
+	c := NewGuildWarCommand(log.InitLogger("error"), i18n.NewResolver(nil), gbf.NewBattleManager())
+	s := commandstest.NewSession()
+
+	i := commandstest.Autocomplete(testGuildID, testChannelID, commandstest.Member("user1"), "gw",
+		commandstest.Subcommand("plan", commandstest.Focused(commandstest.StringOption("tier", "nm2"))))
+	c.HandleAutocomplete(s, i)
+
+	if got := choiceValues(t, s); !slices.Equal(got, []string{"gw_nm200", "gw_nm250"}) {
+		t.Errorf("choices = %v, want [gw_nm200 gw_nm250]", got)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1000: "1,000", 17000000: "17,000,000", -1234: "-1,234"}
+	for n, want := range tests {
+		if got := formatNumber(n); got != want {
+			t.Errorf("formatNumber(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
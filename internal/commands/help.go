@@ -5,16 +5,19 @@ import (
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
 	"github.com/varubogu/gbf_discord_bot_go/internal/log"
 )
 
 // HelpCommand handles help command functionality
 type HelpCommand struct {
 	logger   *log.Logger
+	locales  *i18n.Resolver
 	commands []CommandInfo
 }
 
-// CommandInfo represents information about a command
+// CommandInfo represents information about a command.
+// The description is translated by the help.commands.<name> catalog key when it exists.
 type CommandInfo struct {
 	Name        string
 	Description string
@@ -25,9 +28,10 @@ type CommandInfo struct {
 }
 
 // NewHelpCommand creates a new help command handler
-func NewHelpCommand(logger *log.Logger) *HelpCommand {
+func NewHelpCommand(logger *log.Logger, locales *i18n.Resolver) *HelpCommand {
 	return &HelpCommand{
-		logger: logger,
+		logger:  logger,
+		locales: locales,
 		commands: []CommandInfo{
 			{
 				Name:        "ping",
@@ -61,6 +65,46 @@ func NewHelpCommand(logger *log.Logger) *HelpCommand {
 				IsSlash:     true,
 				IsPrefix:    true,
 			},
+			{
+				Name:        "permissions",
+				Description: "Manages who can use bot commands on this server",
+				Usage:       "/permissions <allow|revoke|reset|list> [target] [role] [user]",
+				Category:    "Admin",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
+			{
+				Name:        "schedule",
+				Description: "Manages Guild War notifications, custom reminders and the notification channel",
+				Usage:       "/schedule <add|list|remove|channel> or /schedule gw <set|show|clear>",
+				Category:    "Admin",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
+			{
+				Name:        "data",
+				Description: "Imports and exports battles, the event calendar and reminders as CSV/XLSX",
+				Usage:       "/data import <file> [dry_run] or /data export [format] [sheet]",
+				Category:    "Admin",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
+			{
+				Name:        "template",
+				Description: "Customizes the wording of recruitment, reminder and permission messages",
+				Usage:       "/template list | preview <name> [text] | set <name> <text> | reset <name>",
+				Category:    "Admin",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
+			{
+				Name:        "language",
+				Description: "Sets the language of the bot's messages on this server",
+				Usage:       "/language [locale]",
+				Category:    "Admin",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
 			{
 				Name:        "battles",
 				Description: "Shows list of available battles",
@@ -77,12 +121,76 @@ func NewHelpCommand(logger *log.Logger) *HelpCommand {
 				IsSlash:     true,
 				IsPrefix:    true,
 			},
+			{
+				Name:        "recruit",
+				Description: "Recruits players for a battle, or joins and leaves recruitments",
+				Usage:       "/recruit start <battle> [title] [max_players] | join <recruitment> | leave <recruitment>",
+				Category:    "GBF",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
+			{
+				Name:        "grid",
+				Description: "Calculates the modifiers and damage of weapon grids",
+				Usage:       "/grid calc <grid> [compare] [attack] [hp]",
+				Category:    "GBF",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
+			{
+				Name:        "class",
+				Description: "Shows the weapon proficiencies of a class",
+				Usage:       "/class <name>",
+				Category:    "GBF",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
+			{
+				Name:        "sim",
+				Description: "Simulates party damage per turn, charge attacks and turns to kill",
+				Usage:       "/sim [grid] [attack] [da] [ta] [crit_rate] [echo] [charge_boost] [target_hp] ...",
+				Category:    "GBF",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
+			{
+				Name:        "gw",
+				Description: "Plans the nightmare runs needed to reach a Guild War honors target",
+				Usage:       "/gw plan <target> [tier] [clear_time] [budget] [days]",
+				Category:    "GBF",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
+			{
+				Name:        "drop",
+				Description: "Tracks farming runs and drop rates",
+				Usage:       "/drop log <battle> [item] [runs] [count], /drop stats [battle] [user], /drop export [battle]",
+				Category:    "GBF",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
+			{
+				Name:        "mats",
+				Description: "Plans the materials for crafts and uncaps",
+				Usage:       "/mats plan <recipe> [quantity], /mats set <material> <count>, /mats list",
+				Category:    "GBF",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
+			{
+				Name:        "battlelog",
+				Description: "Analyzes a battle log: damage per player, by source and turns",
+				Usage:       "/battlelog [file] [text]",
+				Category:    "GBF",
+				IsSlash:     true,
+				IsPrefix:    false,
+			},
 		},
 	}
 }
 
 // HandlePrefixCommand handles the prefix version of help command (!help)
-func (h *HelpCommand) HandlePrefixCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
+func (h *HelpCommand) HandlePrefixCommand(s Session, m *discordgo.MessageCreate) {
 	logger := h.logger.WithDiscordContext(m.GuildID, m.ChannelID, m.Author.ID).WithCommand("help")
 
 	// Parse arguments
@@ -92,7 +200,7 @@ func (h *HelpCommand) HandlePrefixCommand(s *discordgo.Session, m *discordgo.Mes
 		commandName = args[1]
 	}
 
-	embed := h.buildHelpEmbed(commandName, false)
+	embed := h.buildHelpEmbed(h.locales.Message(m), commandName, false)
 
 	_, err := s.ChannelMessageSendEmbed(m.ChannelID, embed)
 	if err != nil {
@@ -104,7 +212,7 @@ func (h *HelpCommand) HandlePrefixCommand(s *discordgo.Session, m *discordgo.Mes
 }
 
 // HandleSlashCommand handles the slash version of help command (/help)
-func (h *HelpCommand) HandleSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func (h *HelpCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
 	logger := h.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("help")
 
 	// Parse options
@@ -113,7 +221,7 @@ func (h *HelpCommand) HandleSlashCommand(s *discordgo.Session, i *discordgo.Inte
 		commandName = i.ApplicationCommandData().Options[0].StringValue()
 	}
 
-	embed := h.buildHelpEmbed(commandName, true)
+	embed := h.buildHelpEmbed(h.locales.Interaction(i), commandName, true)
 
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -146,9 +254,9 @@ func (h *HelpCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand
 }
 
 // buildHelpEmbed builds the help embed message
-func (h *HelpCommand) buildHelpEmbed(commandName string, isSlash bool) *discordgo.MessageEmbed {
+func (h *HelpCommand) buildHelpEmbed(tr i18n.Localizer, commandName string, isSlash bool) *discordgo.MessageEmbed {
 	embed := &discordgo.MessageEmbed{
-		Title: "GBF Discord Bot Help",
+		Title: tr.T("help.title"),
 		Color: 0x3498db,
 	}
 
@@ -156,22 +264,22 @@ func (h *HelpCommand) buildHelpEmbed(commandName string, isSlash bool) *discordg
 		// Show help for specific command
 		for _, cmd := range h.commands {
 			if strings.EqualFold(cmd.Name, commandName) {
-				embed.Title = fmt.Sprintf("Help: %s", cmd.Name)
-				embed.Description = cmd.Description
+				embed.Title = tr.T("help.command_title", cmd.Name)
+				embed.Description = describeCommand(tr, cmd)
 				embed.Fields = []*discordgo.MessageEmbedField{
 					{
-						Name:   "Usage",
+						Name:   tr.T("help.usage"),
 						Value:  cmd.Usage,
 						Inline: false,
 					},
 					{
-						Name:   "Category",
-						Value:  cmd.Category,
+						Name:   tr.T("help.category"),
+						Value:  categoryName(tr, cmd.Category),
 						Inline: true,
 					},
 					{
-						Name:   "Available as",
-						Value:  h.getAvailabilityString(cmd),
+						Name:   tr.T("help.available_as"),
+						Value:  h.getAvailabilityString(tr, cmd),
 						Inline: true,
 					},
 				}
@@ -180,8 +288,8 @@ func (h *HelpCommand) buildHelpEmbed(commandName string, isSlash bool) *discordg
 		}
 
 		// Command not found
-		embed.Title = "Command Not Found"
-		embed.Description = fmt.Sprintf("No command found with name: `%s`", commandName)
+		embed.Title = tr.T("help.not_found.title")
+		embed.Description = tr.T("help.not_found.description", commandName)
 		embed.Color = 0xe74c3c
 		return embed
 	}
@@ -192,7 +300,7 @@ func (h *HelpCommand) buildHelpEmbed(commandName string, isSlash bool) *discordg
 		categories[cmd.Category] = append(categories[cmd.Category], cmd)
 	}
 
-	embed.Description = "Available commands grouped by category. Use `!help <command>` or `/help <command>` for detailed information."
+	embed.Description = tr.T("help.description")
 
 	for category, commands := range categories {
 		var commandList []string
@@ -207,13 +315,13 @@ func (h *HelpCommand) buildHelpEmbed(commandName string, isSlash bool) *discordg
 			}
 
 			if availability != "" {
-				commandList = append(commandList, fmt.Sprintf("`%s%s` - %s", availability, cmd.Name, cmd.Description))
+				commandList = append(commandList, fmt.Sprintf("`%s%s` - %s", availability, cmd.Name, describeCommand(tr, cmd)))
 			}
 		}
 
 		if len(commandList) > 0 {
 			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-				Name:   category,
+				Name:   categoryName(tr, category),
 				Value:  strings.Join(commandList, "\n"),
 				Inline: false,
 			})
@@ -228,17 +336,33 @@ func (h *HelpCommand) buildHelpEmbed(commandName string, isSlash bool) *discordg
 }
 
 // getAvailabilityString returns a string describing command availability
-func (h *HelpCommand) getAvailabilityString(cmd CommandInfo) string {
+func (h *HelpCommand) getAvailabilityString(tr i18n.Localizer, cmd CommandInfo) string {
 	var parts []string
 	if cmd.IsPrefix {
-		parts = append(parts, "Prefix (!)")
+		parts = append(parts, tr.T("help.prefix"))
 	}
 	if cmd.IsSlash {
-		parts = append(parts, "Slash (/)")
+		parts = append(parts, tr.T("help.slash"))
 	}
 	return strings.Join(parts, ", ")
 }
 
+// describeCommand returns the translated description of a command, if the catalog has one
+func describeCommand(tr i18n.Localizer, cmd CommandInfo) string {
+	if description, exists := tr.Lookup("help.commands." + cmd.Name); exists {
+		return description
+	}
+	return cmd.Description
+}
+
+// categoryName returns the translated name of a command category, if the catalog has one
+func categoryName(tr i18n.Localizer, category string) string {
+	if name, exists := tr.Lookup("help.categories." + strings.ToLower(category)); exists {
+		return name
+	}
+	return category
+}
+
 // AddCommand adds a new command to the help system
 func (h *HelpCommand) AddCommand(info CommandInfo) {
 	h.commands = append(h.commands, info)
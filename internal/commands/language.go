@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/settings"
+)
+
+// languageAuto is the choice that clears the guild's language so members get their own
+const languageAuto = "auto"
+
+// LanguageCommand shows or changes the language of a guild's messages
+type LanguageCommand struct {
+	logger   *log.Logger
+	locales  *i18n.Resolver
+	settings *settings.Manager
+}
+
+// NewLanguageCommand creates a new language command handler
+func NewLanguageCommand(logger *log.Logger, locales *i18n.Resolver, settingsManager *settings.Manager) *LanguageCommand {
+	return &LanguageCommand{
+		logger:   logger,
+		locales:  locales,
+		settings: settingsManager,
+	}
+}
+
+// HandleSlashCommand handles the slash version of language command (/language)
+func (c *LanguageCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("language")
+	tr := c.locales.Interaction(i)
+
+	var choice string
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Name == "locale" {
+			choice = opt.StringValue()
+		}
+	}
+
+	ctx := context.Background()
+	var (
+		content string
+		err     error
+	)
+	switch choice {
+	case "":
+		var guildSettings settings.GuildSettings
+		guildSettings, err = c.settings.Get(ctx, i.GuildID)
+		content = tr.T("language.current.auto")
+		if guildSettings.Locale != "" {
+			content = tr.T("language.current.set", guildSettings.Locale.Name())
+		}
+	case languageAuto:
+		err = c.settings.Update(ctx, i.GuildID, func(s *settings.GuildSettings) {
+			s.Locale = ""
+		})
+		content = tr.T("language.cleared")
+	default:
+		locale, supported := i18n.Parse(choice)
+		if !supported {
+			content = tr.T("language.unsupported", choice)
+			break
+		}
+		err = c.settings.Update(ctx, i.GuildID, func(s *settings.GuildSettings) {
+			s.Locale = locale
+		})
+		// Confirm in the language just chosen
+		content = i18n.For(locale).T("language.set", locale.Name())
+	}
+
+	if err != nil {
+		logger.WithError(err).Warn("Language update failed", "locale", choice)
+		content = errorMessage(tr, err)
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to language slash command")
+		return
+	}
+
+	logger.Info("Language slash command executed successfully", "locale", choice)
+}
+
+// GetSlashCommandDefinition returns the slash command definition for language
+func (c *LanguageCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	manageGuild := int64(discordgo.PermissionManageGuild)
+	dmPermission := false
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(i18n.Locales)+1)
+	for _, locale := range i18n.Locales {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  locale.Name(),
+			Value: string(locale),
+		})
+	}
+	choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+		Name:  "Auto (each member's Discord language)",
+		Value: languageAuto,
+	})
+
+	return &discordgo.ApplicationCommand{
+		Name:                     "language",
+		Description:              "Shows or changes the language of the bot's messages (Admin only)",
+		DefaultMemberPermissions: &manageGuild,
+		DMPermission:             &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "locale",
+				Description: "Language to use; leave empty to show the current one",
+				Required:    false,
+				Choices:     choices,
+			},
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/settings"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+func TestLanguageCommand(t *testing.T) {
+	settingsManager := settings.NewManager(storage.NewMemoryStore())
+	c := NewLanguageCommand(log.InitLogger("error"), i18n.NewResolver(settingsManager), settingsManager)
+	s := commandstest.NewSession()
+
+	tests := []struct {
+		name       string
+		userLocale discordgo.Locale
+		choice     string
+		want       string
+		wantLocale i18n.Locale
+	}{
+		{
+			name: "shows that members get their own language",
+			want: "🌐 Messages follow each member's Discord language.",
+		},
+		{
+			name:       "confirms in the chosen language",
+			userLocale: discordgo.EnglishUS,
+			choice:     "ja",
+			want:       "✅ このサーバーの言語を 日本語 にしました。",
+			wantLocale: i18n.Japanese,
+		},
+		{
+			name:       "guild setting applies without a user language",
+			want:       "🌐 このサーバーの言語は 日本語 です。",
+			wantLocale: i18n.Japanese,
+		},
+		{
+			name:       "user language wins over the guild setting",
+			userLocale: discordgo.EnglishGB,
+			want:       "🌐 This server's language is 日本語.",
+			wantLocale: i18n.Japanese,
+		},
+		{
+			name:       "auto clears the setting",
+			userLocale: discordgo.EnglishUS,
+			choice:     "auto",
+			want:       "✅ Messages now follow each member's Discord language.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options []*discordgo.ApplicationCommandInteractionDataOption
+			if tt.choice != "" {
+				options = append(options, commandstest.StringOption("locale", tt.choice))
+			}
+			i := commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("admin1"), "language", options...)
+			i.Locale = tt.userLocale
+
+			c.HandleSlashCommand(s, i)
+
+			response := s.LastResponse()
+			if response == nil || response.Data == nil {
+				t.Fatal("no response")
+			}
+			if response.Data.Content != tt.want {
+				t.Errorf("content = %q, want %q", response.Data.Content, tt.want)
+			}
+			locale, err := settingsManager.GuildLocale(context.Background(), testGuildID)
+			if err != nil {
+				t.Fatalf("GuildLocale() unexpected error: %v", err)
+			}
+			if locale != tt.wantLocale {
+				t.Errorf("guild locale = %q, want %q", locale, tt.wantLocale)
+			}
+		})
+	}
+}
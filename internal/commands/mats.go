@@ -0,0 +1,323 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/inventory"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+// MatsCommand plans the materials of crafts and uncaps against what members own
+type MatsCommand struct {
+	logger        *log.Logger
+	locales       *i18n.Resolver
+	battleManager *gbf.BattleManager
+	recipes       *gbf.RecipeCatalog
+	inventory     *inventory.Manager
+}
+
+// NewMatsCommand creates a new mats command handler
+func NewMatsCommand(logger *log.Logger, locales *i18n.Resolver, battleManager *gbf.BattleManager, recipes *gbf.RecipeCatalog, inventoryManager *inventory.Manager) *MatsCommand {
+	return &MatsCommand{
+		logger:        logger,
+		locales:       locales,
+		battleManager: battleManager,
+		recipes:       recipes,
+		inventory:     inventoryManager,
+	}
+}
+
+// HandleSlashCommand handles the slash version of mats command (/mats plan, /mats set, /mats list)
+func (c *MatsCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("mats")
+	tr := c.locales.Interaction(i)
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	subcommand := data.Options[0]
+
+	var recipeName, materialName string
+	quantity, count := 1, 0
+	for _, opt := range subcommand.Options {
+		switch opt.Name {
+		case "recipe":
+			recipeName = opt.StringValue()
+		case "quantity":
+			quantity = int(opt.IntValue())
+		case "material":
+			materialName = opt.StringValue()
+		case "count":
+			count = int(opt.IntValue())
+		}
+	}
+
+	ctx := context.Background()
+	var response *discordgo.InteractionResponseData
+	var err error
+	switch subcommand.Name {
+	case "plan":
+		response, err = c.plan(ctx, tr, i.Member.User.ID, recipeName, quantity)
+	case "set":
+		response, err = c.set(ctx, tr, i.Member.User.ID, materialName, count)
+	case "list":
+		response, err = c.list(ctx, tr, i.Member.User.ID)
+	}
+	if err != nil {
+		logger.WithError(err).Warn("Mats command failed", "subcommand", subcommand.Name)
+		response = &discordgo.InteractionResponseData{
+			Content: errorMessage(tr, err),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		}
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: response,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to mats slash command")
+		return
+	}
+
+	logger.Info("Mats slash command executed successfully", "subcommand", subcommand.Name)
+}
+
+// plan shows the materials a recipe needs and where to farm the missing ones (/mats plan)
+func (c *MatsCommand) plan(ctx context.Context, tr i18n.Localizer, userID, recipeName string, quantity int) (*discordgo.InteractionResponseData, error) {
+	recipe, err := c.recipes.Recipe(recipeName)
+	if err != nil {
+		return nil, err
+	}
+	owned, err := c.inventory.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	needs, err := recipe.Plan(quantity, owned.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: tr.T("mats.plan.title", recipeDisplayName(tr, recipe), quantity),
+		Color: 0x27ae60,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: tr.T("mats.plan.footer"),
+		},
+	}
+
+	missing := 0
+	for _, need := range needs {
+		name := materialDisplayName(tr, need.Material)
+		lines := []string{tr.T("mats.plan.owned", need.Owned, need.Needed)}
+		if need.Missing() == 0 {
+			name = "✅ " + name
+		} else {
+			missing++
+			lines = append(lines, tr.T("mats.plan.missing", need.Missing()), c.sources(tr, need.Material))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  name,
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+
+	embed.Description = tr.T("mats.plan.complete")
+	if missing > 0 {
+		embed.Description = tr.T("mats.plan.summary", missing, len(needs))
+		embed.Color = 0xf39c12
+	}
+
+	return &discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{embed},
+	}, nil
+}
+
+// sources lists the battles that drop a material
+func (c *MatsCommand) sources(tr i18n.Localizer, material *gbf.Material) string {
+	battles := c.battleManager.MaterialSources(material)
+	if len(battles) == 0 {
+		return tr.T("mats.plan.no_sources")
+	}
+	names := make([]string, len(battles))
+	for n, battle := range battles {
+		names[n] = battleDisplayName(tr, battle)
+	}
+	return tr.T("mats.plan.sources", strings.Join(names, ", "))
+}
+
+// set records how many of a material the member owns (/mats set)
+func (c *MatsCommand) set(ctx context.Context, tr i18n.Localizer, userID, materialName string, count int) (*discordgo.InteractionResponseData, error) {
+	material, err := c.recipes.Material(materialName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.inventory.Set(ctx, userID, material.ID, count); err != nil {
+		return nil, err
+	}
+
+	content := tr.T("mats.set.saved", count, materialDisplayName(tr, material))
+	if count == 0 {
+		content = tr.T("mats.set.removed", materialDisplayName(tr, material))
+	}
+	return &discordgo.InteractionResponseData{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	}, nil
+}
+
+// list shows the materials the member owns (/mats list)
+func (c *MatsCommand) list(ctx context.Context, tr i18n.Localizer, userID string) (*discordgo.InteractionResponseData, error) {
+	owned, err := c.inventory.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(owned.Items) == 0 {
+		return &discordgo.InteractionResponseData{
+			Content: tr.T("mats.list.empty"),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		}, nil
+	}
+
+	ids := make([]string, 0, len(owned.Items))
+	for id := range owned.Items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	lines := make([]string, len(ids))
+	for n, id := range ids {
+		// Materials removed from the catalog are listed by ID
+		name := id
+		if material, err := c.recipes.Material(id); err == nil {
+			name = materialDisplayName(tr, material)
+		}
+		lines[n] = fmt.Sprintf("%s: %s", name, formatNumber(owned.Items[id]))
+	}
+
+	return &discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       tr.T("mats.list.title"),
+			Description: strings.Join(lines, "\n"),
+			Color:       0x3498db,
+		}},
+		Flags: discordgo.MessageFlagsEphemeral,
+	}, nil
+}
+
+// HandleAutocomplete suggests recipes and materials matching what has been typed
+func (c *MatsCommand) HandleAutocomplete(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("mats")
+	tr := c.locales.Interaction(i)
+
+	option, _ := focusedOption(i.ApplicationCommandData().Options)
+	query := focusedText(option)
+
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	switch {
+	case option == nil:
+	case option.Name == "recipe":
+		for _, recipe := range c.recipes.SearchRecipes(query, maxAutocompleteChoices) {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  choiceName(fmt.Sprintf("%s (%s)", recipeDisplayName(tr, recipe), recipe.ID)),
+				Value: recipe.ID,
+			})
+		}
+	case option.Name == "material":
+		for _, material := range c.recipes.SearchMaterials(query, maxAutocompleteChoices) {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  choiceName(fmt.Sprintf("%s (%s)", materialDisplayName(tr, material), material.ID)),
+				Value: material.ID,
+			})
+		}
+	}
+
+	respondAutocomplete(s, i, logger, choices)
+}
+
+// recipeDisplayName returns the name of a recipe in the locale
+func recipeDisplayName(tr i18n.Localizer, recipe *gbf.Recipe) string {
+	if name := recipe.Names[string(tr.Locale())]; name != "" {
+		return name
+	}
+	return recipe.Name
+}
+
+// materialDisplayName returns the name of a material in the locale
+func materialDisplayName(tr i18n.Localizer, material *gbf.Material) string {
+	if name := material.Names[string(tr.Locale())]; name != "" {
+		return name
+	}
+	return material.Name
+}
+
+// GetSlashCommandDefinition returns the slash command definition for mats
+func (c *MatsCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	minQuantity := float64(1)
+	minCount := float64(0)
+	dmPermission := false
+
+	return &discordgo.ApplicationCommand{
+		Name:         "mats",
+		Description:  "Plans the materials for crafts and uncaps",
+		DMPermission: &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "plan",
+				Description: "Shows the materials a recipe needs and where to farm the missing ones",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "recipe",
+						Description:  "Craft or uncap, e.g. ultima_weapon",
+						Required:     true,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "quantity",
+						Description: "How many times to craft it (default 1)",
+						Required:    false,
+						MinValue:    &minQuantity,
+						MaxValue:    gbf.MaxRecipeQuantity,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Records how many of a material you own",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "material",
+						Description:  "Material, e.g. hihiirokane",
+						Required:     true,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "count",
+						Description: "How many you own (0 to remove)",
+						Required:    true,
+						MinValue:    &minCount,
+						MaxValue:    inventory.MaxCount,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Shows the materials you have recorded",
+			},
+		},
+	}
+}
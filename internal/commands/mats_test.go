@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/inventory"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+func TestMatsCommand(t *testing.T) {
+	c := NewMatsCommand(log.InitLogger("error"), i18n.NewResolver(nil), gbf.NewBattleManager(), gbf.DefaultRecipes(), inventory.NewManager(storage.NewMemoryStore()))
+	s := commandstest.NewSession()
+
+	mats := func(subcommand string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionResponse {
+		t.Helper()
+		s.Reset()
+		c.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("alice"), "mats",
+			commandstest.Subcommand(subcommand, options...)))
+		response := s.LastResponse()
+		if response == nil {
+			t.Fatalf("/mats %s got no response", subcommand)
+		}
+		return response
+	}
+
+	if response := mats("list"); response.Data.Content != "You have not recorded any materials yet. Use /mats set to add some." {
+		t.Errorf("empty list = %q", response.Data.Content)
+	}
+
+	sets := []struct {
+		material    string
+		count       int
+		wantContent string
+	}{
+		{"ultima_unit", 75, "✅ You now own 75 × Ultima Unit."},
+		{"fragment_of_ultima", 4, "✅ You now own 4 × Fragment of Ultima."},
+		{"hihiirokane", 0, "✅ Removed Hihiirokane from your materials."},
+		{"gold bar", 1, "❌ Unknown material. Pick one from the suggestions."},
+	}
+	for _, tt := range sets {
+		response := mats("set", commandstest.StringOption("material", tt.material), commandstest.IntegerOption("count", tt.count))
+		if response.Data.Content != tt.wantContent {
+			t.Errorf("set %s = %q, want %q", tt.material, response.Data.Content, tt.wantContent)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		options    []*discordgo.ApplicationCommandInteractionDataOption
+		wantTitle  string
+		wantColor  int
+		wantFields map[string]string
+	}{
+		{
+			name: "missing materials list their battles",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("recipe", "ultima_weapon"),
+				commandstest.IntegerOption("quantity", 2),
+			},
+			wantTitle: "Ultima Weapon ×2",
+			wantColor: 0xf39c12,
+			wantFields: map[string]string{
+				"Fragment of Ultima": "4 / 20 owned\n**16 missing**\nDrops in: Ultimate Bahamut (Hard), Super Ultimate Bahamut",
+				"✅ Ultima Unit":      "75 / 60 owned",
+			},
+		},
+		{
+			name: "material without battle drops",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("recipe", "revenant"),
+			},
+			wantTitle: "Revenant Weapon Awakening ×1",
+			wantColor: 0xf39c12,
+			wantFields: map[string]string{
+				"Revenant Weapon Fragment": "0 / 30 owned\n**30 missing**\nNot dropped by any battle",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := mats("plan", tt.options...)
+			if len(response.Data.Embeds) != 1 {
+				t.Fatalf("response = %+v, want an embed", response.Data)
+			}
+			embed := response.Data.Embeds[0]
+			if embed.Title != tt.wantTitle || embed.Color != tt.wantColor {
+				t.Errorf("title, color = %q, %#x, want %q, %#x", embed.Title, embed.Color, tt.wantTitle, tt.wantColor)
+			}
+			for name, want := range tt.wantFields {
+				if got := commandstest.FieldValue(embed, name); got != want {
+					t.Errorf("field %q = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+
+	if response := mats("plan", commandstest.StringOption("recipe", "magna")); response.Data.Content != "❌ Unknown recipe. Pick one from the suggestions." {
+		t.Errorf("unknown recipe = %q", response.Data.Content)
+	}
+	if response := mats("list"); response.Data.Embeds[0].Description != "Fragment of Ultima: 4\nUltima Unit: 75" {
+		t.Errorf("list = %q", response.Data.Embeds[0].Description)
+	}
+
+	s.Reset()
+	c.HandleAutocomplete(s, commandstest.Autocomplete(testGuildID, testChannelID, commandstest.Member("alice"), "mats",
+		commandstest.Subcommand("set", commandstest.Focused(commandstest.StringOption("material", "ultima")))))
+	if values := choiceValues(t, s); !slices.Equal(values, []string{"ultima_unit", "fragment_of_ultima"}) {
+		t.Errorf("material choices = %v", values)
+	}
+}
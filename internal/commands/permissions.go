@@ -0,0 +1,216 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/permissions"
+)
+
+// PermissionsCommand handles management of per-guild command ACLs
+type PermissionsCommand struct {
+	logger  *log.Logger
+	locales *i18n.Resolver
+	manager *permissions.Manager
+}
+
+// NewPermissionsCommand creates a new permissions command handler
+func NewPermissionsCommand(logger *log.Logger, locales *i18n.Resolver, manager *permissions.Manager) *PermissionsCommand {
+	return &PermissionsCommand{
+		logger:  logger,
+		locales: locales,
+		manager: manager,
+	}
+}
+
+// HandleSlashCommand handles the slash version of permissions command (/permissions)
+func (p *PermissionsCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := p.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("permissions")
+	tr := p.locales.Interaction(i)
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	subcommand := data.Options[0]
+
+	var (
+		target, roleID, userID string
+	)
+	for _, opt := range subcommand.Options {
+		switch opt.Name {
+		case "target":
+			target = opt.StringValue()
+		case "role":
+			roleID = opt.Value.(string)
+		case "user":
+			userID = opt.Value.(string)
+		}
+	}
+
+	ctx := context.Background()
+	var (
+		content string
+		embed   *discordgo.MessageEmbed
+		err     error
+	)
+
+	switch subcommand.Name {
+	case "allow":
+		if roleID == "" && userID == "" {
+			content = tr.T("permissions.allow_grantee_required")
+			break
+		}
+		err = p.manager.Grant(ctx, i.GuildID, target, roleID, userID)
+		content = tr.T("permissions.allowed", formatGrantee(tr, roleID, userID), target)
+	case "revoke":
+		if roleID == "" && userID == "" {
+			content = tr.T("permissions.revoke_grantee_required")
+			break
+		}
+		err = p.manager.Revoke(ctx, i.GuildID, target, roleID, userID)
+		content = tr.T("permissions.revoked", formatGrantee(tr, roleID, userID), target)
+	case "reset":
+		err = p.manager.Reset(ctx, i.GuildID, target)
+		content = tr.T("permissions.reset", target)
+	case "list":
+		embed, err = p.buildListEmbed(ctx, tr, i.GuildID)
+	}
+
+	if err != nil {
+		logger.WithError(err).Warn("Permissions update failed", "subcommand", subcommand.Name, "target", target)
+		content = errorMessage(tr, err)
+		embed = nil
+	}
+
+	response := &discordgo.InteractionResponseData{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	}
+	if embed != nil {
+		response.Embeds = []*discordgo.MessageEmbed{embed}
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: response,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to permissions slash command")
+		return
+	}
+
+	logger.Info("Permissions slash command executed successfully", "subcommand", subcommand.Name, "target", target)
+}
+
+// GetSlashCommandDefinition returns the slash command definition for permissions
+func (p *PermissionsCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	manageGuild := int64(discordgo.PermissionManageGuild)
+	dmPermission := false
+	targetOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "target",
+		Description: "Command name or category (General, GBF, Admin)",
+		Required:    true,
+	}
+	roleOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionRole,
+		Name:        "role",
+		Description: "Role to update",
+		Required:    false,
+	}
+	userOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionUser,
+		Name:        "user",
+		Description: "User to update",
+		Required:    false,
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:                     "permissions",
+		Description:              "Manages who can use bot commands on this server (Admin only)",
+		DefaultMemberPermissions: &manageGuild,
+		DMPermission:             &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "allow",
+				Description: "Allows a role or user to use a command or category",
+				Options:     []*discordgo.ApplicationCommandOption{targetOption, roleOption, userOption},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "revoke",
+				Description: "Removes a role or user from a command or category",
+				Options:     []*discordgo.ApplicationCommandOption{targetOption, roleOption, userOption},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Restores default access for a command or category",
+				Options:     []*discordgo.ApplicationCommandOption{targetOption},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Shows the access rules configured on this server",
+			},
+		},
+	}
+}
+
+// buildListEmbed builds the embed listing a guild's access rules
+func (p *PermissionsCommand) buildListEmbed(ctx context.Context, tr i18n.Localizer, guildID string) (*discordgo.MessageEmbed, error) {
+	acl, err := p.manager.ACL(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: tr.T("permissions.list.title"),
+		Color: 0x3498db,
+	}
+
+	targets := acl.Targets()
+	if len(targets) == 0 {
+		embed.Description = tr.T("permissions.list.empty", p.manager.ControlRoleName())
+		return embed, nil
+	}
+
+	for _, target := range targets {
+		rule := acl.Rule(target)
+		var grantees []string
+		for _, roleID := range rule.RoleIDs {
+			grantees = append(grantees, "<@&"+roleID+">")
+		}
+		for _, userID := range rule.UserIDs {
+			grantees = append(grantees, "<@"+userID+">")
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   target,
+			Value:  strings.Join(grantees, ", "),
+			Inline: false,
+		})
+	}
+
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: tr.T("permissions.list.footer"),
+	}
+
+	return embed, nil
+}
+
+// formatGrantee formats a role and/or user mention
+func formatGrantee(tr i18n.Localizer, roleID, userID string) string {
+	var parts []string
+	if roleID != "" {
+		parts = append(parts, "<@&"+roleID+">")
+	}
+	if userID != "" {
+		parts = append(parts, "<@"+userID+">")
+	}
+	return strings.Join(parts, tr.T("permissions.and"))
+}
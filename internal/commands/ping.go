@@ -1,57 +1,119 @@
 package commands
 
 import (
+	"time"
+
 	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
 	"github.com/varubogu/gbf_discord_bot_go/internal/log"
 )
 
 // PingCommand handles ping command functionality
 type PingCommand struct {
-	logger *log.Logger
+	logger  *log.Logger
+	locales *i18n.Resolver
 }
 
 // NewPingCommand creates a new ping command handler
-func NewPingCommand(logger *log.Logger) *PingCommand {
+func NewPingCommand(logger *log.Logger, locales *i18n.Resolver) *PingCommand {
 	return &PingCommand{
-		logger: logger,
+		logger:  logger,
+		locales: locales,
 	}
 }
 
-// HandlePrefixCommand handles the prefix version of ping command (!ping)
-func (p *PingCommand) HandlePrefixCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
+// HandlePrefixCommand handles the prefix version of ping command (!ping).
+// The REST round trip is measured by timing the reply and an edit of it, after
+// which the reply is edited once more to show the results.
+func (p *PingCommand) HandlePrefixCommand(s Session, m *discordgo.MessageCreate) {
 	logger := p.logger.WithDiscordContext(m.GuildID, m.ChannelID, m.Author.ID).WithCommand("ping")
+	tr := p.locales.Message(m)
 
-	_, err := s.ChannelMessageSend(m.ChannelID, "Pong!")
+	start := time.Now()
+	message, err := s.ChannelMessageSend(m.ChannelID, "Pong!")
 	if err != nil {
 		logger.WithError(err).Error("Failed to send ping response")
 		return
 	}
+	sendLatency := time.Since(start)
+
+	start = time.Now()
+	_, err = s.ChannelMessageEdit(m.ChannelID, message.ID, formatPingResult(tr, s.HeartbeatLatency(), sendLatency, 0))
+	if err != nil {
+		logger.WithError(err).Error("Failed to edit ping response")
+		return
+	}
+	editLatency := time.Since(start)
 
-	logger.Info("Ping prefix command executed successfully")
+	_, err = s.ChannelMessageEdit(m.ChannelID, message.ID, formatPingResult(tr, s.HeartbeatLatency(), sendLatency, editLatency))
+	if err != nil {
+		logger.WithError(err).Error("Failed to edit ping response")
+		return
+	}
+
+	logger.Info("Ping prefix command executed successfully",
+		"gateway_latency_ms", s.HeartbeatLatency().Milliseconds(),
+		"send_latency_ms", sendLatency.Milliseconds(),
+		"edit_latency_ms", editLatency.Milliseconds())
 }
 
-// HandleSlashCommand handles the slash version of ping command (/ping)
-func (p *PingCommand) HandleSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+// HandleSlashCommand handles the slash version of ping command (/ping).
+// The REST round trip is measured by timing the deferred response and an edit
+// of it, after which the response is edited once more to show the results.
+func (p *PingCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
 	logger := p.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("ping")
+	tr := p.locales.Interaction(i)
 
+	start := time.Now()
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: "Pong!",
-		},
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 	})
 	if err != nil {
 		logger.WithError(err).Error("Failed to respond to ping slash command")
 		return
 	}
+	sendLatency := time.Since(start)
+
+	measuring := formatPingResult(tr, s.HeartbeatLatency(), sendLatency, 0)
+	start = time.Now()
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &measuring,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to edit ping slash command response")
+		return
+	}
+	editLatency := time.Since(start)
 
-	logger.Info("Ping slash command executed successfully")
+	content := formatPingResult(tr, s.HeartbeatLatency(), sendLatency, editLatency)
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &content,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to edit ping slash command response")
+		return
+	}
+
+	logger.Info("Ping slash command executed successfully",
+		"gateway_latency_ms", s.HeartbeatLatency().Milliseconds(),
+		"send_latency_ms", sendLatency.Milliseconds(),
+		"edit_latency_ms", editLatency.Milliseconds())
 }
 
 // GetSlashCommandDefinition returns the slash command definition for registration
 func (p *PingCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
 		Name:        "ping",
-		Description: "Replies with Pong!",
+		Description: "Shows gateway and API latency",
+	}
+}
+
+// formatPingResult formats the measured latencies. An edit latency of zero is
+// shown as still being measured.
+func formatPingResult(tr i18n.Localizer, gatewayLatency, sendLatency, editLatency time.Duration) string {
+	edit := tr.T("ping.measuring")
+	if editLatency > 0 {
+		edit = formatLatency(tr, editLatency)
 	}
+	return tr.T("ping.result", formatLatency(tr, gatewayLatency), formatLatency(tr, sendLatency), edit)
 }
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+func TestPingCommand_HandlePrefixCommand(t *testing.T) {
+	s := commandstest.NewSession()
+	p := NewPingCommand(log.InitLogger("error"), i18n.NewResolver(nil))
+
+	p.HandlePrefixCommand(s, commandstest.Message(testGuildID, testChannelID, commandstest.Member("u1"), "!ping"))
+
+	messages := s.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("sent %d messages, expected 1", len(messages))
+	}
+	if messages[0].EditedTimestamp == nil {
+		t.Error("ping reply was not edited with the results")
+	}
+	for _, line := range []string{"Gateway (heartbeat): 42ms", "REST send (round trip):", "REST edit (round trip):"} {
+		if !strings.Contains(messages[0].Content, line) {
+			t.Errorf("content = %q, expected %q", messages[0].Content, line)
+		}
+	}
+	if strings.Contains(messages[0].Content, "measuring") {
+		t.Errorf("content = %q, expected the measured edit latency", messages[0].Content)
+	}
+}
+
+func TestPingCommand_HandleSlashCommand(t *testing.T) {
+	s := commandstest.NewSession()
+	p := NewPingCommand(log.InitLogger("error"), i18n.NewResolver(nil))
+
+	p.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("u1"), "ping"))
+
+	edits := s.ResponseEdits()
+	if len(edits) != 2 {
+		t.Fatalf("got %d response edits, expected 2 (timed edit and results)", len(edits))
+	}
+	if content := *edits[0].Content; !strings.Contains(content, "REST edit (round trip): measuring") {
+		t.Errorf("first edit = %q, expected the edit latency to be measured", content)
+	}
+	if content := *edits[1].Content; !strings.Contains(content, "REST edit (round trip):") || strings.Contains(content, "measuring") {
+		t.Errorf("second edit = %q, expected the edit latency", content)
+	}
+}
@@ -0,0 +1,524 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+// RecruitComponentPrefix prefixes the custom IDs of recruitment buttons
+const RecruitComponentPrefix = "recruit:"
+
+// Recruitment button actions, encoded as "recruit:<action>:<recruitment id>"
+const (
+	recruitActionJoin  = "join"
+	recruitActionLeave = "leave"
+	recruitActionClose = "close"
+)
+
+// RecruitCommand handles battle recruitments and their join/leave/close buttons.
+// Recruitment messages are shown to everyone, so they use the guild's language;
+// replies only the user sees use the user's.
+type RecruitCommand struct {
+	logger             *log.Logger
+	locales            *i18n.Resolver
+	battleManager      *gbf.BattleManager
+	recruitmentManager *gbf.RecruitmentManager
+	templates          *templates.Manager
+}
+
+// NewRecruitCommand creates a new recruit command handler
+func NewRecruitCommand(logger *log.Logger, locales *i18n.Resolver, battleManager *gbf.BattleManager, recruitmentManager *gbf.RecruitmentManager, templateManager *templates.Manager) *RecruitCommand {
+	return &RecruitCommand{
+		logger:             logger,
+		locales:            locales,
+		battleManager:      battleManager,
+		recruitmentManager: recruitmentManager,
+		templates:          templateManager,
+	}
+}
+
+// HandleSlashCommand handles the slash version of recruit command (/recruit)
+func (r *RecruitCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	subcommand := data.Options[0]
+
+	switch subcommand.Name {
+	case "start":
+		r.handleStart(s, i, subcommand.Options)
+	case recruitActionJoin, recruitActionLeave:
+		r.handleParticipation(s, i, subcommand.Name, subcommand.Options)
+	}
+}
+
+// handleStart posts a new recruitment (/recruit start)
+func (r *RecruitCommand) handleStart(s Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	logger := r.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("recruit")
+
+	var battleID, title string
+	var maxPlayers int
+	for _, opt := range options {
+		switch opt.Name {
+		case "battle":
+			battleID = opt.StringValue()
+		case "title":
+			title = opt.StringValue()
+		case "max_players":
+			maxPlayers = int(opt.IntValue())
+		}
+	}
+
+	tr := r.locales.Interaction(i)
+	guildTr := r.locales.Guild(context.Background(), i.GuildID)
+
+	battle, err := r.battleManager.GetBattle(battleID)
+	if err != nil {
+		r.respondEphemeral(s, i, logger, "❌ "+tr.T("battle.not_found.description", battleID))
+		return
+	}
+	if title == "" {
+		title = battleDisplayName(guildTr, battle)
+	}
+
+	// The interaction ID is unique, so it doubles as the recruitment ID
+	recruitment := &gbf.Recruitment{
+		ID:         i.ID,
+		ChannelID:  i.ChannelID,
+		GuildID:    i.GuildID,
+		BattleID:   battle.ID,
+		HostUserID: i.Member.User.ID,
+		Title:      title,
+		MaxPlayers: maxPlayers,
+	}
+	if err := r.recruitmentManager.CreateRecruitment(recruitment); err != nil {
+		logger.WithError(err).Error("Failed to create recruitment")
+		r.respondEphemeral(s, i, logger, errorMessage(tr, err))
+		return
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:    r.renderMessage(guildTr, logger, templates.RecruitmentPosted, recruitment, i.Member.User.ID),
+			Embeds:     []*discordgo.MessageEmbed{r.buildRecruitmentEmbed(guildTr, recruitment)},
+			Components: buildRecruitmentComponents(guildTr, recruitment),
+		},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to recruit slash command")
+		return
+	}
+
+	// Joining and leaving with slash commands edit the recruitment message, so remember it
+	message, err := s.InteractionResponse(i.Interaction)
+	if err == nil {
+		err = r.recruitmentManager.SetMessageID(recruitment.ID, message.ID)
+	}
+	if err != nil {
+		logger.WithError(err).Warn("Failed to record recruitment message", "recruitment_id", recruitment.ID)
+	}
+
+	logger.Info("Recruit slash command executed successfully", "recruitment_id", recruitment.ID, "battle_id", battle.ID)
+}
+
+// handleParticipation joins or leaves a recruitment chosen by ID (/recruit join, /recruit leave)
+// and refreshes the recruitment message
+func (r *RecruitCommand) handleParticipation(s Session, i *discordgo.InteractionCreate, action string, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	user := i.Member.User
+	logger := r.logger.WithDiscordContext(i.GuildID, i.ChannelID, user.ID).WithCommand("recruit")
+	tr := r.locales.Interaction(i)
+
+	var recruitmentID string
+	for _, opt := range options {
+		if opt.Name == "recruitment" {
+			recruitmentID = opt.StringValue()
+		}
+	}
+
+	recruitment, err := r.recruitmentManager.GetRecruitment(recruitmentID)
+	if err != nil || recruitment.GuildID != i.GuildID {
+		r.respondEphemeral(s, i, logger, tr.T("recruit.unavailable"))
+		return
+	}
+
+	message, recruitment, err := r.updateParticipation(action, recruitment.ID, user)
+	if err != nil {
+		r.respondEphemeral(s, i, logger, errorMessage(tr, err))
+		return
+	}
+
+	if recruitment.MessageID != "" {
+		guildTr := r.locales.Guild(context.Background(), i.GuildID)
+		content := r.renderMessage(guildTr, logger, message, recruitment, user.ID)
+		embeds := []*discordgo.MessageEmbed{r.buildRecruitmentEmbed(guildTr, recruitment)}
+		components := buildRecruitmentComponents(guildTr, recruitment)
+		_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			Channel:    recruitment.ChannelID,
+			ID:         recruitment.MessageID,
+			Content:    &content,
+			Embeds:     &embeds,
+			Components: &components,
+		})
+		if err != nil {
+			logger.WithError(err).Warn("Failed to update recruitment message", "recruitment_id", recruitment.ID)
+		}
+	}
+
+	reply := tr.T("recruit.joined", recruitment.Title)
+	if action == recruitActionLeave {
+		reply = tr.T("recruit.left", recruitment.Title)
+	}
+	r.respondEphemeral(s, i, logger, reply)
+
+	logger.Info("Recruitment updated", "recruitment_id", recruitment.ID, "action", action,
+		"participants", recruitment.GetParticipantCount())
+}
+
+// HandleAutocomplete suggests battles for /recruit start and the recruitments a
+// member can join or leave for /recruit join and /recruit leave
+func (r *RecruitCommand) HandleAutocomplete(s Session, i *discordgo.InteractionCreate) {
+	userID := i.Member.User.ID
+	logger := r.logger.WithDiscordContext(i.GuildID, i.ChannelID, userID).WithCommand("recruit")
+	tr := r.locales.Interaction(i)
+
+	options := i.ApplicationCommandData().Options
+	option, subcommand := focusedOption(options)
+	query := focusedText(option)
+
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	switch subcommand {
+	case "start":
+		choices = battleChoices(tr, r.battleManager, query)
+	case recruitActionJoin:
+		var element gbf.Element
+		for _, opt := range options[0].Options {
+			if opt.Name == "element" {
+				element = gbf.Element(opt.StringValue())
+			}
+		}
+		joinable := r.recruitmentManager.GetJoinableRecruitments(i.GuildID, userID, element)
+		choices = r.recruitmentChoices(tr, joinable, query)
+	case recruitActionLeave:
+		choices = r.recruitmentChoices(tr, r.recruitmentManager.GetJoinedRecruitments(i.GuildID, userID), query)
+	}
+
+	respondAutocomplete(s, i, logger, choices)
+}
+
+// recruitmentChoices suggests the recruitments whose title or battle matches query
+func (r *RecruitCommand) recruitmentChoices(tr i18n.Localizer, recruitments []gbf.Recruitment, query string) []*discordgo.ApplicationCommandOptionChoice {
+	query = strings.ToLower(query)
+
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, recruitment := range recruitments {
+		battleName := r.battleName(tr, recruitment.BattleID)
+		text := strings.ToLower(recruitment.Title + " " + battleName + " " + recruitment.BattleID)
+		if !strings.Contains(text, query) {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name: choiceName(fmt.Sprintf("%s - %s (%d/%d)", recruitment.Title, battleName,
+				recruitment.GetParticipantCount(), recruitment.MaxPlayers)),
+			Value: recruitment.ID,
+		})
+		if len(choices) == maxAutocompleteChoices {
+			break
+		}
+	}
+	return choices
+}
+
+// HandleComponent handles clicks on the join, leave and close buttons of a recruitment
+func (r *RecruitCommand) HandleComponent(s Session, i *discordgo.InteractionCreate) {
+	userID := i.Member.User.ID
+	logger := r.logger.WithDiscordContext(i.GuildID, i.ChannelID, userID).WithCommand("recruit")
+
+	action, recruitmentID, ok := parseRecruitCustomID(i.MessageComponentData().CustomID)
+	if !ok {
+		logger.Warn("Unknown recruitment component", "custom_id", i.MessageComponentData().CustomID)
+		return
+	}
+
+	tr := r.locales.Interaction(i)
+	recruitment, err := r.recruitmentManager.GetRecruitment(recruitmentID)
+	if err != nil {
+		r.respondEphemeral(s, i, logger, tr.T("recruit.unavailable"))
+		return
+	}
+
+	var message string
+	switch action {
+	case recruitActionJoin, recruitActionLeave:
+		message, recruitment, err = r.updateParticipation(action, recruitmentID, i.Member.User)
+	case recruitActionClose:
+		if recruitment.HostUserID != userID {
+			r.respondEphemeral(s, i, logger, tr.T("recruit.host_only"))
+			return
+		}
+		recruitment, err = r.recruitmentManager.UpdateRecruitmentStatus(recruitmentID, gbf.RecruitmentStatusClosed)
+		message = templates.RecruitmentClosed
+	}
+	if err != nil {
+		r.respondEphemeral(s, i, logger, errorMessage(tr, err))
+		return
+	}
+
+	guildTr := r.locales.Guild(context.Background(), i.GuildID)
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    r.renderMessage(guildTr, logger, message, recruitment, userID),
+			Embeds:     []*discordgo.MessageEmbed{r.buildRecruitmentEmbed(guildTr, recruitment)},
+			Components: buildRecruitmentComponents(guildTr, recruitment),
+		},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to update recruitment message")
+		return
+	}
+
+	logger.Info("Recruitment updated", "recruitment_id", recruitmentID, "action", action,
+		"participants", recruitment.GetParticipantCount())
+}
+
+// updateParticipation adds or removes a member and returns the template announcing
+// the change with a copy of the updated recruitment
+func (r *RecruitCommand) updateParticipation(action, recruitmentID string, user *discordgo.User) (string, *gbf.Recruitment, error) {
+	if action == recruitActionLeave {
+		recruitment, err := r.recruitmentManager.RemoveParticipant(recruitmentID, user.ID)
+		return templates.RecruitmentLeft, recruitment, err
+	}
+
+	recruitment, err := r.recruitmentManager.AddParticipant(recruitmentID, user.ID, user.Username)
+	if err != nil {
+		return "", nil, err
+	}
+	if recruitment.Status == gbf.RecruitmentStatusFull {
+		return templates.RecruitmentFull, recruitment, nil
+	}
+	return templates.RecruitmentJoined, recruitment, nil
+}
+
+// GetSlashCommandDefinition returns the slash command definition for recruit
+func (r *RecruitCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	minPlayers := float64(2)
+	dmPermission := false
+	recruitmentOption := func(description string) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "recruitment",
+			Description:  description,
+			Required:     true,
+			Autocomplete: true,
+		}
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:         "recruit",
+		Description:  "Recruits players for battles",
+		DMPermission: &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "start",
+				Description: "Starts recruiting players for a battle",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "battle",
+						Description:  "Battle to recruit for",
+						Required:     true,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "title",
+						Description: "Recruitment title (defaults to the battle name)",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "max_players",
+						Description: "Number of players including the host (defaults to the battle's limit)",
+						Required:    false,
+						MinValue:    &minPlayers,
+						MaxValue:    30,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        recruitActionJoin,
+				Description: "Joins a recruitment on this server",
+				Options: []*discordgo.ApplicationCommandOption{
+					recruitmentOption("Recruitment to join"),
+					elementOption("element", "Only suggests recruitments for battles of this element", false),
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        recruitActionLeave,
+				Description: "Leaves a recruitment you joined",
+				Options:     []*discordgo.ApplicationCommandOption{recruitmentOption("Recruitment to leave")},
+			},
+		},
+	}
+}
+
+// respondEphemeral replies to the interaction with a message only the user can see
+func (r *RecruitCommand) respondEphemeral(s Session, i *discordgo.InteractionCreate, logger *log.Logger, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to recruit interaction")
+	}
+}
+
+// renderMessage renders a recruitment template with the guild's wording.
+// Rendering failures are logged and leave the message without text.
+func (r *RecruitCommand) renderMessage(tr i18n.Localizer, logger *log.Logger, name string, recruitment *gbf.Recruitment, userID string) string {
+	data := templates.RecruitmentData{
+		Title:      recruitment.Title,
+		BattleID:   recruitment.BattleID,
+		BattleName: r.battleName(tr, recruitment.BattleID),
+		Host:       recruitment.HostUserID,
+		User:       userID,
+		Players:    recruitment.GetParticipantCount(),
+		MaxPlayers: recruitment.MaxPlayers,
+	}
+	for _, participant := range recruitment.Participants {
+		data.Participants = append(data.Participants, participant.UserID)
+	}
+
+	content, err := r.templates.Render(context.Background(), recruitment.GuildID, tr.Locale(), name, data)
+	if err != nil {
+		logger.WithError(err).Error("Failed to render recruitment message", "template", name)
+		return ""
+	}
+	return content
+}
+
+// battleName returns the display name of a battle, or its ID if it no longer exists
+func (r *RecruitCommand) battleName(tr i18n.Localizer, battleID string) string {
+	if battle, err := r.battleManager.GetBattle(battleID); err == nil {
+		return battleDisplayName(tr, battle)
+	}
+	return battleID
+}
+
+// buildRecruitmentEmbed builds the recruitment embed message
+func (r *RecruitCommand) buildRecruitmentEmbed(tr i18n.Localizer, recruitment *gbf.Recruitment) *discordgo.MessageEmbed {
+	color := 0x27ae60
+	status := tr.T("recruit.status.open")
+	switch recruitment.Status {
+	case gbf.RecruitmentStatusFull:
+		color = 0xf39c12
+		status = tr.T("recruit.status.full")
+	case gbf.RecruitmentStatusClosed, gbf.RecruitmentStatusCancelled, gbf.RecruitmentStatusCompleted:
+		color = 0xe74c3c
+		status = tr.T("recruit.status.closed")
+	}
+
+	var participants []string
+	for _, participant := range recruitment.Participants {
+		line := fmt.Sprintf("<@%s>", participant.UserID)
+		if participant.Role == gbf.ParticipantRoleHost {
+			line += tr.T("recruit.host_marker")
+		}
+		participants = append(participants, line)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("📢 %s", recruitment.Title),
+		Description: tr.T("recruit.battle", r.battleName(tr, recruitment.BattleID), recruitment.BattleID),
+		Color:       color,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   tr.T("recruit.players"),
+				Value:  fmt.Sprintf("%d/%d", recruitment.GetParticipantCount(), recruitment.MaxPlayers),
+				Inline: true,
+			},
+			{
+				Name:   tr.T("recruit.status.name"),
+				Value:  status,
+				Inline: true,
+			},
+			{
+				Name:   tr.T("recruit.participants"),
+				Value:  strings.Join(participants, "\n"),
+				Inline: false,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: tr.T("recruit.id", recruitment.ID),
+		},
+	}
+}
+
+// buildRecruitmentComponents builds the join, leave and close buttons.
+// Buttons are disabled once the recruitment is no longer accepting changes.
+func buildRecruitmentComponents(tr i18n.Localizer, recruitment *gbf.Recruitment) []discordgo.MessageComponent {
+	closed := recruitment.Status != gbf.RecruitmentStatusOpen && recruitment.Status != gbf.RecruitmentStatusFull
+	full := recruitment.Status == gbf.RecruitmentStatusFull
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    tr.T("recruit.join"),
+					Style:    discordgo.SuccessButton,
+					CustomID: recruitCustomID(recruitActionJoin, recruitment.ID),
+					Disabled: closed || full,
+				},
+				discordgo.Button{
+					Label:    tr.T("recruit.leave"),
+					Style:    discordgo.SecondaryButton,
+					CustomID: recruitCustomID(recruitActionLeave, recruitment.ID),
+					Disabled: closed,
+				},
+				discordgo.Button{
+					Label:    tr.T("recruit.close"),
+					Style:    discordgo.DangerButton,
+					CustomID: recruitCustomID(recruitActionClose, recruitment.ID),
+					Disabled: closed,
+				},
+			},
+		},
+	}
+}
+
+// recruitCustomID encodes a button action for a recruitment
+func recruitCustomID(action, recruitmentID string) string {
+	return RecruitComponentPrefix + action + ":" + recruitmentID
+}
+
+// parseRecruitCustomID decodes a button custom ID built by recruitCustomID
+func parseRecruitCustomID(customID string) (action, recruitmentID string, ok bool) {
+	rest, found := strings.CutPrefix(customID, RecruitComponentPrefix)
+	if !found {
+		return "", "", false
+	}
+	action, recruitmentID, found = strings.Cut(rest, ":")
+	if !found || recruitmentID == "" {
+		return "", "", false
+	}
+	switch action {
+	case recruitActionJoin, recruitActionLeave, recruitActionClose:
+		return action, recruitmentID, true
+	}
+	return "", "", false
+}
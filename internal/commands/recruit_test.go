@@ -0,0 +1,424 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+const (
+	testGuildID   = "guild1"
+	testChannelID = "channel1"
+)
+
+func newTestRecruitCommand() *RecruitCommand {
+	battleManager := gbf.NewBattleManager()
+	logger := log.InitLogger("error")
+	templateManager := templates.NewManager(storage.NewMemoryStore(), logger)
+	return NewRecruitCommand(logger, i18n.NewResolver(nil), battleManager, gbf.NewRecruitmentManager(battleManager), templateManager)
+}
+
+// clickButton clicks the labelled button of the last recruitment message
+func clickButton(t *testing.T, s *commandstest.Session, r *RecruitCommand, member *discordgo.Member, label string) {
+	t.Helper()
+
+	var components []discordgo.MessageComponent
+	for _, response := range s.Responses() {
+		if response.Response.Data != nil && len(response.Response.Data.Components) > 0 {
+			components = response.Response.Data.Components
+		}
+	}
+
+	for _, button := range commandstest.Buttons(components) {
+		if button.Label == label {
+			r.HandleComponent(s, commandstest.ButtonClick(testGuildID, testChannelID, member, button.CustomID))
+			return
+		}
+	}
+	t.Fatalf("button %q not found", label)
+}
+
+// lastEmbed returns the embed of the last interaction response
+func lastEmbed(t *testing.T, s *commandstest.Session) *discordgo.MessageEmbed {
+	t.Helper()
+
+	response := s.LastResponse()
+	if response == nil || response.Data == nil || len(response.Data.Embeds) == 0 {
+		t.Fatalf("expected an embed response, got %+v", response)
+	}
+	return response.Data.Embeds[0]
+}
+
+func TestRecruitCommand_Scenario(t *testing.T) {
+	s := commandstest.NewSession()
+	r := newTestRecruitCommand()
+
+	host := commandstest.Member("host")
+	alice := commandstest.Member("alice")
+	bob := commandstest.Member("bob")
+
+	// Host starts a recruitment for three players
+	r.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, host, "recruit",
+		commandstest.Subcommand("start",
+			commandstest.StringOption("battle", "faa_hl"),
+			commandstest.IntegerOption("max_players", 3),
+		),
+	))
+
+	response := s.LastResponse()
+	if response.Type != discordgo.InteractionResponseChannelMessageWithSource {
+		t.Fatalf("initial response type = %v, expected ChannelMessageWithSource", response.Type)
+	}
+	embed := lastEmbed(t, s)
+	if !strings.Contains(embed.Title, "Lucilius (Hard)") {
+		t.Errorf("title = %q, expected battle name", embed.Title)
+	}
+	if got := commandstest.FieldValue(embed, "Players"); got != "1/3" {
+		t.Errorf("Players = %q, expected 1/3", got)
+	}
+	if got := response.Data.Content; got != "📢 <@host> is recruiting for **Lucilius (Hard)**! (1/3)" {
+		t.Errorf("content = %q, expected the recruitment.posted message", got)
+	}
+
+	// Two users join, which fills the recruitment
+	clickButton(t, s, r, alice, "Join")
+	if got := commandstest.FieldValue(lastEmbed(t, s), "Players"); got != "2/3" {
+		t.Errorf("Players after first join = %q, expected 2/3", got)
+	}
+	if got := s.LastResponse().Data.Content; !strings.HasPrefix(got, "✅ <@alice> joined") {
+		t.Errorf("content after first join = %q, expected the recruitment.joined message", got)
+	}
+
+	clickButton(t, s, r, bob, "Join")
+	embed = lastEmbed(t, s)
+	if got := commandstest.FieldValue(embed, "Players"); got != "3/3" {
+		t.Errorf("Players after second join = %q, expected 3/3", got)
+	}
+	if got := commandstest.FieldValue(embed, "Status"); !strings.Contains(got, "Full") {
+		t.Errorf("Status = %q, expected Full", got)
+	}
+	if got := s.LastResponse().Data.Content; !strings.Contains(got, "is full! <@host> <@alice> <@bob>") {
+		t.Errorf("content when full = %q, expected the recruitment.full message", got)
+	}
+	participants := commandstest.FieldValue(embed, "Participants")
+	for _, id := range []string{"<@host> (Host)", "<@alice>", "<@bob>"} {
+		if !strings.Contains(participants, id) {
+			t.Errorf("Participants = %q, expected to contain %s", participants, id)
+		}
+	}
+	for _, button := range commandstest.Buttons(s.LastResponse().Data.Components) {
+		if button.Label == "Join" && !button.Disabled {
+			t.Error("Join button enabled on a full recruitment")
+		}
+	}
+
+	// Only the host may close
+	clickButton(t, s, r, alice, "Close")
+	if response := s.LastResponse(); response.Data.Flags&discordgo.MessageFlagsEphemeral == 0 ||
+		!strings.Contains(response.Data.Content, "Only the host") {
+		t.Errorf("non-host close response = %+v, expected ephemeral refusal", response.Data)
+	}
+
+	clickButton(t, s, r, host, "Close")
+	response = s.LastResponse()
+	if response.Type != discordgo.InteractionResponseUpdateMessage {
+		t.Errorf("close response type = %v, expected UpdateMessage", response.Type)
+	}
+	embed = lastEmbed(t, s)
+	if got := commandstest.FieldValue(embed, "Status"); !strings.Contains(got, "Closed") {
+		t.Errorf("Status after close = %q, expected Closed", got)
+	}
+	if embed.Color != 0xe74c3c {
+		t.Errorf("Color after close = %#x, expected 0xe74c3c", embed.Color)
+	}
+	for _, button := range commandstest.Buttons(response.Data.Components) {
+		if !button.Disabled {
+			t.Errorf("button %q enabled after close", button.Label)
+		}
+	}
+}
+
+func TestRecruitCommand_ConcurrentClicks(t *testing.T) {
+	s := commandstest.NewSession()
+	r := newTestRecruitCommand()
+
+	r.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("host"), "recruit",
+		commandstest.Subcommand("start",
+			commandstest.StringOption("battle", "faa_hl"),
+			commandstest.IntegerOption("max_players", 6),
+		),
+	))
+	buttons := commandstest.Buttons(s.LastResponse().Data.Components)
+
+	// Handlers render the recruitment while other clicks change it; run with -race
+	var wg sync.WaitGroup
+	for n := range 10 {
+		member := commandstest.Member(fmt.Sprintf("user%d", n))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, button := range buttons[:2] {
+				r.HandleComponent(s, commandstest.ButtonClick(testGuildID, testChannelID, member, button.CustomID))
+			}
+		}()
+	}
+	wg.Wait()
+
+	recruitment, err := r.recruitmentManager.GetRecruitment(s.Responses()[0].Interaction.ID)
+	if err != nil {
+		t.Fatalf("GetRecruitment() error = %v", err)
+	}
+	if count := recruitment.GetParticipantCount(); count != 1 {
+		t.Errorf("participants = %d, expected only the host after everyone left", count)
+	}
+}
+
+func TestRecruitCommand_GuildTemplate(t *testing.T) {
+	s := commandstest.NewSession()
+	r := newTestRecruitCommand()
+	err := r.templates.Set(context.Background(), testGuildID, templates.RecruitmentPosted,
+		"{{mention .Host}} が {{.BattleName}} の参加者を募集中 ({{.Players}}/{{.MaxPlayers}})", "admin1")
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	r.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("host"), "recruit",
+		commandstest.Subcommand("start",
+			commandstest.StringOption("battle", "baha_hl"),
+			commandstest.IntegerOption("max_players", 6),
+		),
+	))
+	if got := s.LastResponse().Data.Content; got != "<@host> が Proto Bahamut (Hard) の参加者を募集中 (1/6)" {
+		t.Errorf("content = %q, expected the guild's template", got)
+	}
+
+	// Other guilds keep the default wording
+	r.HandleSlashCommand(s, commandstest.SlashCommand("guild2", testChannelID, commandstest.Member("host"), "recruit",
+		commandstest.Subcommand("start", commandstest.StringOption("battle", "baha_hl"))))
+	if got := s.LastResponse().Data.Content; !strings.HasPrefix(got, "📢 <@host> is recruiting") {
+		t.Errorf("content in another guild = %q, expected the default template", got)
+	}
+}
+
+func TestRecruitCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, s *commandstest.Session, r *RecruitCommand)
+		expected string
+	}{
+		{
+			name: "unknown battle",
+			setup: func(t *testing.T, s *commandstest.Session, r *RecruitCommand) {
+				r.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("host"), "recruit",
+					commandstest.Subcommand("start", commandstest.StringOption("battle", "unknown"))))
+			},
+			expected: "No battle found",
+		},
+		{
+			name: "join twice",
+			setup: func(t *testing.T, s *commandstest.Session, r *RecruitCommand) {
+				r.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("host"), "recruit",
+					commandstest.Subcommand("start", commandstest.StringOption("battle", "baha_hl"))))
+				clickButton(t, s, r, commandstest.Member("alice"), "Join")
+				clickButton(t, s, r, commandstest.Member("alice"), "Join")
+			},
+			expected: "already a participant",
+		},
+		{
+			name: "host cannot leave",
+			setup: func(t *testing.T, s *commandstest.Session, r *RecruitCommand) {
+				r.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("host"), "recruit",
+					commandstest.Subcommand("start", commandstest.StringOption("battle", "baha_hl"))))
+				clickButton(t, s, r, commandstest.Member("host"), "Leave")
+			},
+			expected: "Host cannot leave",
+		},
+		{
+			name: "stale button",
+			setup: func(t *testing.T, s *commandstest.Session, r *RecruitCommand) {
+				r.HandleComponent(s, commandstest.ButtonClick(testGuildID, testChannelID, commandstest.Member("alice"),
+					recruitCustomID(recruitActionJoin, "missing")))
+			},
+			expected: "no longer available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := commandstest.NewSession()
+			tt.setup(t, s, newTestRecruitCommand())
+
+			response := s.LastResponse()
+			if response == nil {
+				t.Fatal("expected a response")
+			}
+			if response.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+				t.Error("expected an ephemeral response")
+			}
+			if !strings.Contains(response.Data.Content, tt.expected) {
+				t.Errorf("content = %q, expected to contain %q", response.Data.Content, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRecruitCustomID(t *testing.T) {
+	tests := []struct {
+		customID       string
+		expectedAction string
+		expectedID     string
+		expectedOK     bool
+	}{
+		{recruitCustomID(recruitActionJoin, "123"), recruitActionJoin, "123", true},
+		{recruitCustomID(recruitActionClose, "a:b"), recruitActionClose, "a:b", true},
+		{"recruit:dance:123", "", "", false},
+		{"recruit:join:", "", "", false},
+		{"other:join:123", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.customID, func(t *testing.T) {
+			action, id, ok := parseRecruitCustomID(tt.customID)
+			if action != tt.expectedAction || id != tt.expectedID || ok != tt.expectedOK {
+				t.Errorf("parseRecruitCustomID(%q) = (%q, %q, %t), expected (%q, %q, %t)",
+					tt.customID, action, id, ok, tt.expectedAction, tt.expectedID, tt.expectedOK)
+			}
+		})
+	}
+}
+
+// choiceValues returns the values of the choices in the last autocomplete response
+func choiceValues(t *testing.T, s *commandstest.Session) []string {
+	t.Helper()
+
+	response := s.LastResponse()
+	if response == nil || response.Type != discordgo.InteractionApplicationCommandAutocompleteResult {
+		t.Fatalf("expected an autocomplete response, got %+v", response)
+	}
+	values := make([]string, 0, len(response.Data.Choices))
+	for _, choice := range response.Data.Choices {
+		values = append(values, fmt.Sprint(choice.Value))
+	}
+	return values
+}
+
+func TestRecruitCommand_SlashJoinLeave(t *testing.T) {
+	s := commandstest.NewSession()
+	r := newTestRecruitCommand()
+
+	start := commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("host"), "recruit",
+		commandstest.Subcommand("start",
+			commandstest.StringOption("battle", "faa_hl"),
+			commandstest.StringOption("title", "Morning Faa"),
+			commandstest.IntegerOption("max_players", 3),
+		),
+	)
+	r.HandleSlashCommand(s, start)
+
+	participate := func(member *discordgo.Member, action string) string {
+		r.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, member, "recruit",
+			commandstest.Subcommand(action, commandstest.StringOption("recruitment", start.ID))))
+		response := s.LastResponse()
+		if response.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+			t.Errorf("%s reply is not ephemeral", action)
+		}
+		return response.Data.Content
+	}
+	players := func() string {
+		messages := s.Messages()
+		if len(messages) != 1 || len(messages[0].Embeds) == 0 {
+			t.Fatalf("messages = %+v, expected the recruitment message", messages)
+		}
+		return commandstest.FieldValue(messages[0].Embeds[0], "Players")
+	}
+
+	if got := participate(commandstest.Member("alice"), "join"); got != "✅ Joined **Morning Faa**." {
+		t.Errorf("join reply = %q", got)
+	}
+	if got := players(); got != "2/3" {
+		t.Errorf("Players after join = %q, expected 2/3", got)
+	}
+
+	if got := participate(commandstest.Member("alice"), "join"); !strings.Contains(got, "already a participant") {
+		t.Errorf("second join reply = %q, expected a refusal", got)
+	}
+
+	if got := participate(commandstest.Member("alice"), "leave"); got != "✅ Left **Morning Faa**." {
+		t.Errorf("leave reply = %q", got)
+	}
+	if got := players(); got != "1/3" {
+		t.Errorf("Players after leave = %q, expected 1/3", got)
+	}
+
+	// Recruitments of other guilds cannot be joined
+	r.HandleSlashCommand(s, commandstest.SlashCommand("guild2", testChannelID, commandstest.Member("bob"), "recruit",
+		commandstest.Subcommand("join", commandstest.StringOption("recruitment", start.ID))))
+	if got := s.LastResponse().Data.Content; !strings.Contains(got, "no longer available") {
+		t.Errorf("join from another guild = %q, expected unavailable", got)
+	}
+}
+
+func TestRecruitCommand_Autocomplete(t *testing.T) {
+	s := commandstest.NewSession()
+	r := newTestRecruitCommand()
+
+	start := func(battleID, title string) string {
+		i := commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("host"), "recruit",
+			commandstest.Subcommand("start",
+				commandstest.StringOption("battle", battleID),
+				commandstest.StringOption("title", title),
+			),
+		)
+		r.HandleSlashCommand(s, i)
+		return i.ID
+	}
+	faa := start("faa_hl", "Morning Faa")
+	baha := start("baha_hl", "Evening Baha")
+	clickButton(t, s, r, commandstest.Member("alice"), "Join") // Joins the latest, baha
+
+	tests := []struct {
+		name       string
+		member     string
+		subcommand string
+		option     string
+		query      string
+		element    string
+		want       []string
+	}{
+		{"battles for start", "host", "start", "battle", "akasha", "", []string{"akasha_hl"}},
+		{"joinable, newest first", "bob", "join", "recruitment", "", "", []string{baha, faa}},
+		{"joinable by title", "bob", "join", "recruitment", "morning", "", []string{faa}},
+		{"joinable by battle", "bob", "join", "recruitment", "bahamut", "", []string{baha}},
+		{"joined ones are not joinable", "alice", "join", "recruitment", "", "", []string{faa}},
+		{"joinable of an element", "bob", "join", "recruitment", "", "dark", []string{faa}},
+		{"none joinable of an element", "bob", "join", "recruitment", "", "fire", []string{}},
+		{"joined", "alice", "leave", "recruitment", "", "", []string{baha}},
+		{"hosts cannot leave", "host", "leave", "recruitment", "", "", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := []*discordgo.ApplicationCommandInteractionDataOption{commandstest.Focused(commandstest.StringOption(tt.option, tt.query))}
+			if tt.element != "" {
+				options = append(options, commandstest.StringOption("element", tt.element))
+			}
+			r.HandleAutocomplete(s, commandstest.Autocomplete(testGuildID, testChannelID, commandstest.Member(tt.member), "recruit",
+				commandstest.Subcommand(tt.subcommand, options...)))
+
+			if got := choiceValues(t, s); !slices.Equal(got, tt.want) {
+				t.Errorf("choices = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
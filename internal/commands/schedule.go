@@ -0,0 +1,655 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/schedule"
+	"github.com/varubogu/gbf_discord_bot_go/internal/settings"
+)
+
+// ScheduleCommand handles Guild War and custom event schedules and the notification channel
+type ScheduleCommand struct {
+	logger   *log.Logger
+	locales  *i18n.Resolver
+	engine   *schedule.Engine
+	settings *settings.Manager
+	// operatorGuildID returns the only guild allowed to change the shared calendar
+	operatorGuildID func() string
+}
+
+// NewScheduleCommand creates a new schedule command handler. operatorGuildID
+// returns the configured guild allowed to change the event calendar.
+func NewScheduleCommand(logger *log.Logger, locales *i18n.Resolver, engine *schedule.Engine, settingsManager *settings.Manager, operatorGuildID func() string) *ScheduleCommand {
+	return &ScheduleCommand{
+		logger:          logger,
+		locales:         locales,
+		engine:          engine,
+		settings:        settingsManager,
+		operatorGuildID: operatorGuildID,
+	}
+}
+
+// HandleSlashCommand handles the slash version of schedule command (/schedule)
+func (c *ScheduleCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("schedule")
+	tr := c.locales.Interaction(i)
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+
+	// Subcommands may be nested in a group, e.g. "/schedule gw set"
+	subcommand := data.Options[0]
+	name := subcommand.Name
+	if subcommand.Type == discordgo.ApplicationCommandOptionSubCommandGroup && len(subcommand.Options) > 0 {
+		subcommand = subcommand.Options[0]
+		name += " " + subcommand.Name
+	}
+
+	var (
+		start, end, channelID, roleID string
+		eventName, repeat, when, text string
+		kind, battles                 string
+		finalsDays, eventID           int
+	)
+	for _, opt := range subcommand.Options {
+		switch opt.Name {
+		case "start":
+			start = opt.StringValue()
+		case "end":
+			end = opt.StringValue()
+		case "kind":
+			kind = opt.StringValue()
+		case "battles":
+			battles = opt.StringValue()
+		case "finals_days":
+			finalsDays = int(opt.IntValue())
+		case "channel":
+			channelID = opt.Value.(string)
+		case "role":
+			roleID = opt.Value.(string)
+		case "name":
+			eventName = opt.StringValue()
+		case "repeat":
+			repeat = opt.StringValue()
+		case "when":
+			when = opt.StringValue()
+		case "message":
+			text = opt.StringValue()
+		case "id":
+			eventID = int(opt.IntValue())
+		}
+	}
+
+	ctx := context.Background()
+	var (
+		content string
+		embed   *discordgo.MessageEmbed
+		err     error
+	)
+
+	switch name {
+	case "gw set":
+		embed, err = c.setGuildWar(ctx, tr, i.GuildID, i.Member.User.ID, start, finalsDays)
+	case "gw show":
+		embed, err = c.buildGuildWarEmbed(ctx, tr, i.GuildID)
+	case "gw clear":
+		err = c.engine.ClearGuildWar(ctx, i.GuildID)
+		content = tr.T("schedule.gw.cleared")
+	case "calendar add":
+		// The calendar is shared by every guild
+		if err = schedule.CheckOperator(i.GuildID, c.operatorGuildID()); err == nil {
+			embed, err = c.addCalendarEvent(ctx, tr, i.Member.User.ID, eventName, kind, start, end, battles)
+		}
+	case "calendar list":
+		embed, err = c.buildCalendarEmbed(ctx, tr)
+	case "calendar remove":
+		if err = schedule.CheckOperator(i.GuildID, c.operatorGuildID()); err == nil {
+			err = c.engine.RemoveCalendarEvent(ctx, eventID)
+			content = tr.T("schedule.calendar.removed", eventID)
+		}
+	case "channel":
+		err = c.settings.Update(ctx, i.GuildID, func(s *settings.GuildSettings) {
+			s.NotificationChannelID = channelID
+		})
+		content = tr.T("schedule.channel_set", channelID)
+	case "add":
+		if channelID == "" {
+			channelID = i.ChannelID
+		}
+		embed, err = c.addEvent(ctx, tr, i.GuildID, i.Member.User.ID, schedule.CustomEvent{
+			Name:      eventName,
+			ChannelID: channelID,
+			RoleID:    roleID,
+			Message:   text,
+		}, repeat, when)
+	case "list":
+		embed, err = c.buildEventsEmbed(ctx, tr, i.GuildID)
+	case "remove":
+		err = c.engine.RemoveEvent(ctx, i.GuildID, eventID)
+		content = tr.T("schedule.removed", eventID)
+	}
+
+	if err != nil {
+		logger.WithError(err).Warn("Schedule update failed", "subcommand", name)
+		content = errorMessage(tr, err)
+		embed = nil
+	}
+
+	response := &discordgo.InteractionResponseData{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	}
+	if embed != nil {
+		response.Embeds = []*discordgo.MessageEmbed{embed}
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: response,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to schedule slash command")
+		return
+	}
+
+	logger.Info("Schedule slash command executed successfully", "subcommand", name)
+}
+
+// HandleAutocomplete suggests the events of /schedule remove and /schedule calendar remove
+func (c *ScheduleCommand) HandleAutocomplete(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("schedule")
+	tr := c.locales.Interaction(i)
+
+	option, subcommand := focusedOption(i.ApplicationCommandData().Options)
+	query := strings.ToLower(focusedText(option))
+
+	ctx := context.Background()
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	switch subcommand {
+	case "remove":
+		events, err := c.engine.Events(ctx, i.GuildID)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to load events for autocomplete")
+		}
+		for _, event := range events {
+			choices = appendEventChoice(choices, query, event.ID, event.Name, formatRule(tr, event.Rule))
+		}
+	case "calendar remove":
+		events, err := c.engine.CalendarEvents(ctx)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to load calendar for autocomplete")
+		}
+		for _, event := range events {
+			choices = appendEventChoice(choices, query, event.ID, event.Name,
+				calendarKindLabel(tr, event.Kind)+", "+schedule.FormatJST(event.Start))
+		}
+	}
+
+	respondAutocomplete(s, i, logger, choices)
+}
+
+// appendEventChoice suggests an event if query matches its ID or name
+func appendEventChoice(choices []*discordgo.ApplicationCommandOptionChoice, query string, id int, name, detail string) []*discordgo.ApplicationCommandOptionChoice {
+	label := fmt.Sprintf("#%d %s", id, name)
+	if !strings.Contains(strings.ToLower(label), query) {
+		return choices
+	}
+	return append(choices, &discordgo.ApplicationCommandOptionChoice{
+		Name:  choiceName(label + " (" + detail + ")"),
+		Value: id,
+	})
+}
+
+// GetSlashCommandDefinition returns the slash command definition for schedule
+func (c *ScheduleCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	manageGuild := int64(discordgo.PermissionManageGuild)
+	dmPermission := false
+	minFinalsDays := float64(1)
+
+	var kindChoices []*discordgo.ApplicationCommandOptionChoice
+	for _, kind := range schedule.CalendarKinds {
+		kindChoices = append(kindChoices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  calendarKindLabel(i18n.For(i18n.English), kind),
+			Value: string(kind),
+		})
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:                     "schedule",
+		Description:              "Manages scheduled event notifications (Admin only)",
+		DefaultMemberPermissions: &manageGuild,
+		DMPermission:             &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+				Name:        "gw",
+				Description: "Guild War (古戦場) schedule",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "set",
+						Description: "Schedules the next Guild War",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "start",
+								Description: "Preliminaries start in JST (YYYY-MM-DD defaults to 19:00)",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionInteger,
+								Name:        "finals_days",
+								Description: fmt.Sprintf("Number of finals days (default %d)", schedule.DefaultFinalsDays),
+								Required:    false,
+								MinValue:    &minFinalsDays,
+								MaxValue:    schedule.MaxFinalsDays,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "show",
+						Description: "Shows the scheduled Guild War and its notifications",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "clear",
+						Description: "Cancels the scheduled Guild War",
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+				Name:        "calendar",
+				Description: "Game event calendar that controls which battles are available",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "add",
+						Description: "Adds a game event to the calendar",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "name",
+								Description: "Event name",
+								Required:    true,
+								MaxLength:   100,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "kind",
+								Description: "Kind of event",
+								Required:    true,
+								Choices:     kindChoices,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "start",
+								Description: "Start in JST (YYYY-MM-DD defaults to 19:00)",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "end",
+								Description: "End in JST (YYYY-MM-DD means 23:59)",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "battles",
+								Description: "Comma separated battle IDs available during the event",
+								Required:    false,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "list",
+						Description: "Shows the game event calendar",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "remove",
+						Description: "Removes a game event from the calendar",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:         discordgo.ApplicationCommandOptionInteger,
+								Name:         "id",
+								Description:  "Event ID shown by /schedule calendar list",
+								Required:     true,
+								Autocomplete: true,
+							},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Schedules a custom reminder",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Event name, e.g. 団サポート",
+						Required:    true,
+						MaxLength:   100,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "repeat",
+						Description: "How often the event repeats",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Once (YYYY-MM-DD HH:MM)", Value: string(schedule.RuleOnce)},
+							{Name: "Daily (HH:MM)", Value: string(schedule.RuleDaily)},
+							{Name: "Weekly (sat 21:00)", Value: string(schedule.RuleWeekly)},
+							{Name: "Cron (0 21 * * 1-5)", Value: string(schedule.RuleCron)},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "when",
+						Description: "When the event fires in JST, in the format of the repeat rule",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "message",
+						Description: "Message to post; {{.Name}} and {{jst .At}} are replaced",
+						Required:    true,
+						MaxLength:   1500,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "channel",
+						Description:  "Channel to post in (default: this channel)",
+						Required:     false,
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "role",
+						Description: "Role to mention",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Shows the custom reminders of this server",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Removes a custom reminder",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionInteger,
+						Name:         "id",
+						Description:  "Event ID shown by /schedule list",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "channel",
+				Description: "Sets the channel scheduled notifications are posted in",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "channel",
+						Description:  "Notification channel",
+						Required:     true,
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews},
+					},
+				},
+			},
+		},
+	}
+}
+
+// setGuildWar schedules a Guild War and returns the resulting schedule embed
+func (c *ScheduleCommand) setGuildWar(ctx context.Context, tr i18n.Localizer, guildID, userID, start string, finalsDays int) (*discordgo.MessageEmbed, error) {
+	startTime, err := schedule.ParseGuildWarStart(start)
+	if err != nil {
+		return nil, err
+	}
+	event, err := schedule.NewGuildWar(startTime, finalsDays)
+	if err != nil {
+		return nil, err
+	}
+	if !event.End().After(c.engine.Now()) {
+		return nil, errors.New(tr.T("schedule.gw.ended"))
+	}
+
+	if _, err := c.engine.SetGuildWar(ctx, guildID, event, userID); err != nil {
+		return nil, err
+	}
+	return c.buildGuildWarEmbed(ctx, tr, guildID)
+}
+
+// buildGuildWarEmbed builds the embed showing a guild's Guild War schedule
+func (c *ScheduleCommand) buildGuildWarEmbed(ctx context.Context, tr i18n.Localizer, guildID string) (*discordgo.MessageEmbed, error) {
+	gw, err := c.engine.GuildWar(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, notification := range gw.Event.Notifications() {
+		status := "⏳"
+		if gw.IsFired(notification.Key) {
+			status = "✅"
+		}
+		lines = append(lines, fmt.Sprintf("%s `%s` %s", status, schedule.FormatJST(notification.At), notification.Key))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       tr.T("schedule.gw.title"),
+		Description: strings.Join(lines, "\n"),
+		Color:       0x3498db,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: tr.T("schedule.gw.preliminaries"), Value: schedule.FormatJST(gw.Event.Start), Inline: true},
+			{Name: tr.T("schedule.gw.finals_days"), Value: fmt.Sprintf("%d", gw.Event.FinalsDays), Inline: true},
+			{Name: tr.T("schedule.gw.event_end"), Value: schedule.FormatJST(gw.Event.End()), Inline: true},
+		},
+	}
+
+	guildSettings, err := c.settings.Get(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	if guildSettings.NotificationChannelID == "" {
+		embed.Color = 0xf39c12
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: tr.T("schedule.gw.no_channel"),
+		}
+	} else {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr.T("schedule.channel"),
+			Value:  "<#" + guildSettings.NotificationChannelID + ">",
+			Inline: false,
+		})
+	}
+
+	return embed, nil
+}
+
+// addEvent schedules a custom event and returns an embed describing it
+func (c *ScheduleCommand) addEvent(ctx context.Context, tr i18n.Localizer, guildID, userID string, event schedule.CustomEvent, repeat, when string) (*discordgo.MessageEmbed, error) {
+	rule, err := schedule.ParseRule(schedule.RuleKind(repeat), when)
+	if err != nil {
+		return nil, err
+	}
+	event.Rule = rule
+	event.CreatedBy = userID
+
+	added, err := c.engine.AddEvent(ctx, guildID, event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: tr.T("schedule.added", added.ID, added.Name),
+		Color: 0x27ae60,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: tr.T("schedule.repeat"), Value: formatRule(tr, added.Rule), Inline: true},
+			{Name: tr.T("schedule.next"), Value: schedule.FormatJST(added.NextAt), Inline: true},
+			{Name: tr.T("schedule.channel"), Value: "<#" + added.ChannelID + ">", Inline: true},
+		},
+	}, nil
+}
+
+// buildEventsEmbed builds the embed listing a guild's custom events
+func (c *ScheduleCommand) buildEventsEmbed(ctx context.Context, tr i18n.Localizer, guildID string) (*discordgo.MessageEmbed, error) {
+	events, err := c.engine.Events(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: tr.T("schedule.list.title"),
+		Color: 0x3498db,
+	}
+	if len(events) == 0 {
+		embed.Description = tr.T("schedule.list.empty")
+		return embed, nil
+	}
+
+	for _, event := range events {
+		value := tr.T("schedule.list.event", formatRule(tr, event.Rule), event.ChannelID, schedule.FormatJST(event.NextAt))
+		if event.RoleID != "" {
+			value += "\n" + tr.T("schedule.list.mentions", event.RoleID)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("#%d %s", event.ID, event.Name),
+			Value:  value,
+			Inline: false,
+		})
+	}
+
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: tr.T("schedule.list.footer", len(events), schedule.MaxEventsPerGuild),
+	}
+	return embed, nil
+}
+
+// addCalendarEvent adds a game event to the calendar and returns an embed describing it
+func (c *ScheduleCommand) addCalendarEvent(ctx context.Context, tr i18n.Localizer, userID, name, kind, start, end, battles string) (*discordgo.MessageEmbed, error) {
+	startTime, err := schedule.ParseJST(start, schedule.DefaultEventStartHour)
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := schedule.ParseJSTEnd(end)
+	if err != nil {
+		return nil, err
+	}
+
+	var battleIDs []string
+	for _, battleID := range strings.Split(battles, ",") {
+		if battleID = strings.TrimSpace(battleID); battleID != "" {
+			battleIDs = append(battleIDs, battleID)
+		}
+	}
+
+	event, err := c.engine.AddCalendarEvent(ctx, schedule.CalendarEvent{
+		Name:      name,
+		Kind:      schedule.CalendarKind(kind),
+		Start:     startTime,
+		End:       endTime,
+		BattleIDs: battleIDs,
+		CreatedBy: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: tr.T("schedule.calendar.added", event.ID, event.Name),
+		Color: 0x27ae60,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: tr.T("schedule.calendar.kind"), Value: calendarKindLabel(tr, event.Kind), Inline: true},
+			{Name: tr.T("schedule.calendar.start"), Value: schedule.FormatJST(event.Start), Inline: true},
+			{Name: tr.T("schedule.calendar.end"), Value: schedule.FormatJST(event.End), Inline: true},
+		},
+	}
+	if len(event.BattleIDs) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr.T("schedule.calendar.battles"),
+			Value:  "`" + strings.Join(event.BattleIDs, "`, `") + "`",
+			Inline: false,
+		})
+	}
+	return embed, nil
+}
+
+// buildCalendarEmbed builds the embed listing the game event calendar
+func (c *ScheduleCommand) buildCalendarEmbed(ctx context.Context, tr i18n.Localizer) (*discordgo.MessageEmbed, error) {
+	events, err := c.engine.CalendarEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: tr.T("schedule.calendar.title"),
+		Color: 0x3498db,
+	}
+	if len(events) == 0 {
+		embed.Description = tr.T("schedule.calendar.empty")
+		return embed, nil
+	}
+
+	now := c.engine.Now()
+	for _, event := range events {
+		status := "⏳"
+		switch {
+		case event.IsRunning(now):
+			status = "🟢"
+		case !event.End.After(now):
+			status = "✅"
+		}
+		value := fmt.Sprintf("%s %s\n%s 〜 %s", status, calendarKindLabel(tr, event.Kind),
+			schedule.FormatJST(event.Start), schedule.FormatJST(event.End))
+		if len(event.BattleIDs) > 0 {
+			value += "\n" + tr.T("schedule.calendar.battles_value", strings.Join(event.BattleIDs, "`, `"))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("#%d %s", event.ID, event.Name),
+			Value:  value,
+			Inline: false,
+		})
+	}
+	return embed, nil
+}
+
+// calendarKindLabel returns the display name of a calendar event kind
+func calendarKindLabel(tr i18n.Localizer, kind schedule.CalendarKind) string {
+	return tr.T("schedule.kinds." + string(kind))
+}
+
+// formatRule describes when a custom event repeats
+func formatRule(tr i18n.Localizer, rule schedule.Rule) string {
+	switch rule.Kind {
+	case schedule.RuleOnce:
+		if at, err := schedule.ParseJST(rule.Spec, 0); err == nil {
+			return tr.T("schedule.rule.once", schedule.FormatJST(at))
+		}
+	case schedule.RuleDaily:
+		return tr.T("schedule.rule.daily", rule.Spec)
+	case schedule.RuleWeekly:
+		return tr.T("schedule.rule.weekly", rule.Spec)
+	}
+	return tr.T("schedule.rule.other", rule.Kind, rule.Spec)
+}
@@ -0,0 +1,262 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/schedule"
+	"github.com/varubogu/gbf_discord_bot_go/internal/settings"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+func newTestScheduleCommand() *ScheduleCommand {
+	store := storage.NewMemoryStore()
+	settingsManager := settings.NewManager(store)
+	logger := log.InitLogger("error")
+	engine := schedule.NewEngine(store, settingsManager, gbf.NewBattleManager(), templates.NewManager(store, logger), commandstest.NewSession(), schedule.SystemClock{}, logger)
+	return NewScheduleCommand(logger, i18n.NewResolver(settingsManager), engine, settingsManager, func() string { return testGuildID })
+}
+
+func TestScheduleCommand_GuildWar(t *testing.T) {
+	c := newTestScheduleCommand()
+	s := commandstest.NewSession()
+	admin := commandstest.Member("admin1")
+	start := time.Now().In(schedule.JST).AddDate(0, 0, 7).Format("2006-01-02")
+
+	run := func(options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionResponseData {
+		t.Helper()
+		c.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, admin, "schedule", options...))
+		response := s.LastResponse()
+		if response == nil || response.Data == nil {
+			t.Fatal("no response")
+		}
+		if response.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+			t.Error("schedule responses should be ephemeral")
+		}
+		return response.Data
+	}
+
+	// Before anything is scheduled
+	data := run(commandstest.SubcommandGroup("gw", commandstest.Subcommand("show")))
+	if !strings.HasPrefix(data.Content, "❌") {
+		t.Errorf("show without schedule: got %q, want an error", data.Content)
+	}
+
+	// Schedule a Guild War; without a channel the embed warns about it
+	data = run(commandstest.SubcommandGroup("gw", commandstest.Subcommand("set",
+		commandstest.StringOption("start", start),
+		commandstest.IntegerOption("finals_days", 5),
+	)))
+	if len(data.Embeds) != 1 {
+		t.Fatalf("set: got content %q, want an embed", data.Content)
+	}
+	embed := data.Embeds[0]
+	if got := commandstest.FieldValue(embed, "Preliminaries"); got != start+" 19:00 JST" {
+		t.Errorf("Preliminaries = %q", got)
+	}
+	if got := commandstest.FieldValue(embed, "Finals Days"); got != "5" {
+		t.Errorf("Finals Days = %q, want 5", got)
+	}
+	if embed.Footer == nil {
+		t.Error("expected a footer warning about the missing notification channel")
+	}
+	if !strings.Contains(embed.Description, "⏳") || strings.Contains(embed.Description, "✅") {
+		t.Errorf("all notifications should be pending:\n%s", embed.Description)
+	}
+
+	// Set the notification channel
+	data = run(commandstest.Subcommand("channel", commandstest.ChannelOption("channel", "notify1")))
+	if !strings.Contains(data.Content, "<#notify1>") {
+		t.Errorf("channel: got %q", data.Content)
+	}
+	data = run(commandstest.SubcommandGroup("gw", commandstest.Subcommand("show")))
+	if got := commandstest.FieldValue(data.Embeds[0], "Channel"); got != "<#notify1>" {
+		t.Errorf("Channel = %q, want <#notify1>", got)
+	}
+
+	// Clear the schedule
+	data = run(commandstest.SubcommandGroup("gw", commandstest.Subcommand("clear")))
+	if !strings.HasPrefix(data.Content, "✅") {
+		t.Errorf("clear: got %q", data.Content)
+	}
+	data = run(commandstest.SubcommandGroup("gw", commandstest.Subcommand("clear")))
+	if !strings.HasPrefix(data.Content, "❌") {
+		t.Errorf("second clear: got %q, want an error", data.Content)
+	}
+}
+
+func TestScheduleCommand_InvalidGuildWar(t *testing.T) {
+	tests := []struct {
+		name       string
+		start      string
+		finalsDays int
+		want       string
+	}{
+		{"invalid date", "next week", 0, "Invalid date"},
+		{"too many finals days", "2099-01-01", 9, "Finals days must be between"},
+		{"already ended", "2020-01-01", 0, "already ended"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestScheduleCommand()
+			s := commandstest.NewSession()
+
+			options := []*discordgo.ApplicationCommandInteractionDataOption{commandstest.StringOption("start", tt.start)}
+			if tt.finalsDays != 0 {
+				options = append(options, commandstest.IntegerOption("finals_days", tt.finalsDays))
+			}
+			c.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("admin1"), "schedule",
+				commandstest.SubcommandGroup("gw", commandstest.Subcommand("set", options...))))
+
+			if got := s.LastResponse().Data.Content; !strings.Contains(got, tt.want) {
+				t.Errorf("got %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleCommand_CustomEvents(t *testing.T) {
+	c := newTestScheduleCommand()
+	s := commandstest.NewSession()
+	admin := commandstest.Member("admin1")
+
+	run := func(subcommand *discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionResponseData {
+		t.Helper()
+		c.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, admin, "schedule", subcommand))
+		return s.LastResponse().Data
+	}
+
+	data := run(commandstest.Subcommand("add",
+		commandstest.StringOption("name", "Crew support"),
+		commandstest.StringOption("repeat", "weekly"),
+		commandstest.StringOption("when", "sat 21:00"),
+		commandstest.StringOption("message", "{{.Name}} starts at {{jst .At}}"),
+	))
+	if len(data.Embeds) != 1 {
+		t.Fatalf("add: got content %q, want an embed", data.Content)
+	}
+	if got := commandstest.FieldValue(data.Embeds[0], "Channel"); got != "<#"+testChannelID+">" {
+		t.Errorf("Channel = %q, want the current channel", got)
+	}
+	if got := commandstest.FieldValue(data.Embeds[0], "Repeat"); got != "weekly on sat 21:00 JST" {
+		t.Errorf("Repeat = %q", got)
+	}
+
+	data = run(commandstest.Subcommand("add",
+		commandstest.StringOption("name", "Broken"),
+		commandstest.StringOption("repeat", "cron"),
+		commandstest.StringOption("when", "every day"),
+		commandstest.StringOption("message", "hi"),
+	))
+	if !strings.HasPrefix(data.Content, "❌ Invalid cron expression") {
+		t.Errorf("invalid rule: got %q", data.Content)
+	}
+
+	data = run(commandstest.Subcommand("list"))
+	if len(data.Embeds) != 1 || len(data.Embeds[0].Fields) != 1 || data.Embeds[0].Fields[0].Name != "#1 Crew support" {
+		t.Fatalf("list: got %+v", data.Embeds)
+	}
+
+	c.HandleAutocomplete(s, commandstest.Autocomplete(testGuildID, testChannelID, admin, "schedule",
+		commandstest.Subcommand("remove", commandstest.Focused(commandstest.StringOption("id", "crew")))))
+	if choices := s.LastResponse().Data.Choices; len(choices) != 1 || choices[0].Value != 1 ||
+		choices[0].Name != "#1 Crew support (weekly on sat 21:00 JST)" {
+		t.Errorf("remove autocomplete: got %+v", choices)
+	}
+
+	data = run(commandstest.Subcommand("remove", commandstest.IntegerOption("id", 1)))
+	if !strings.HasPrefix(data.Content, "✅") {
+		t.Errorf("remove: got %q", data.Content)
+	}
+	data = run(commandstest.Subcommand("remove", commandstest.IntegerOption("id", 1)))
+	if !strings.HasPrefix(data.Content, "❌") {
+		t.Errorf("second remove: got %q, want an error", data.Content)
+	}
+}
+
+func TestScheduleCommand_Calendar(t *testing.T) {
+	store := storage.NewMemoryStore()
+	settingsManager := settings.NewManager(store)
+	battleManager := gbf.NewBattleManager()
+	logger := log.InitLogger("error")
+	engine := schedule.NewEngine(store, settingsManager, battleManager, templates.NewManager(store, logger), commandstest.NewSession(), schedule.SystemClock{}, logger)
+	c := NewScheduleCommand(logger, i18n.NewResolver(settingsManager), engine, settingsManager, func() string { return testGuildID })
+	s := commandstest.NewSession()
+	admin := commandstest.Member("admin1")
+
+	run := func(subcommand *discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionResponseData {
+		t.Helper()
+		c.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, admin, "schedule",
+			commandstest.SubcommandGroup("calendar", subcommand)))
+		return s.LastResponse().Data
+	}
+
+	// A running Dread Barrage makes its battle available immediately
+	now := time.Now().In(schedule.JST)
+	data := run(commandstest.Subcommand("add",
+		commandstest.StringOption("name", "Dread Barrage"),
+		commandstest.StringOption("kind", "dread_barrage"),
+		commandstest.StringOption("start", now.AddDate(0, 0, -1).Format("2006-01-02")),
+		commandstest.StringOption("end", now.AddDate(0, 0, 3).Format("2006-01-02")),
+		commandstest.StringOption("battles", "gw_nm150, GW_NM95"),
+	))
+	if len(data.Embeds) != 1 {
+		t.Fatalf("add: got content %q, want an embed", data.Content)
+	}
+	if got := commandstest.FieldValue(data.Embeds[0], "Battles"); got != "`gw_nm150`, `gw_nm95`" {
+		t.Errorf("Battles = %q", got)
+	}
+	if battle, _ := battleManager.GetBattle("gw_nm150"); !battle.IsActive {
+		t.Error("gw_nm150 should be active while the event runs")
+	}
+
+	data = run(commandstest.Subcommand("add",
+		commandstest.StringOption("name", "Typo"),
+		commandstest.StringOption("kind", "other"),
+		commandstest.StringOption("start", "2099-01-01"),
+		commandstest.StringOption("end", "2099-01-02"),
+		commandstest.StringOption("battles", "unknown_raid"),
+	))
+	if !strings.HasPrefix(data.Content, "❌ Battle not found") {
+		t.Errorf("unknown battle: got %q", data.Content)
+	}
+
+	data = run(commandstest.Subcommand("list"))
+	if len(data.Embeds) != 1 || len(data.Embeds[0].Fields) != 1 || !strings.HasPrefix(data.Embeds[0].Fields[0].Value, "🟢") {
+		t.Fatalf("list: got %+v", data.Embeds)
+	}
+
+	c.HandleAutocomplete(s, commandstest.Autocomplete(testGuildID, testChannelID, admin, "schedule",
+		commandstest.SubcommandGroup("calendar", commandstest.Subcommand("remove",
+			commandstest.Focused(commandstest.StringOption("id", ""))))))
+	if choices := s.LastResponse().Data.Choices; len(choices) != 1 || choices[0].Value != 1 ||
+		!strings.HasPrefix(choices[0].Name, "#1 Dread Barrage (") {
+		t.Errorf("calendar remove autocomplete: got %+v", choices)
+	}
+
+	// The calendar is shared, so other guilds cannot change it
+	c.HandleSlashCommand(s, commandstest.SlashCommand("guild2", testChannelID, admin, "schedule",
+		commandstest.SubcommandGroup("calendar", commandstest.Subcommand("remove", commandstest.IntegerOption("id", 1)))))
+	if got := s.LastResponse().Data.Content; !strings.Contains(got, "operator server") {
+		t.Errorf("remove from another guild: got %q", got)
+	}
+	if battle, _ := battleManager.GetBattle("gw_nm150"); !battle.IsActive {
+		t.Error("another guild must not be able to remove the event")
+	}
+
+	data = run(commandstest.Subcommand("remove", commandstest.IntegerOption("id", 1)))
+	if !strings.HasPrefix(data.Content, "✅") {
+		t.Errorf("remove: got %q", data.Content)
+	}
+	if battle, _ := battleManager.GetBattle("gw_nm150"); battle.IsActive {
+		t.Error("gw_nm150 should be inactive once its event is removed")
+	}
+}
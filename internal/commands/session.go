@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Session is the subset of the Discord API used by command handlers.
+// Handlers depend on it instead of *discordgo.Session so they can be driven
+// by an in-memory fake in tests (see the commandstest package).
+type Session interface {
+	// ChannelMessageSend sends a plain text message to a channel
+	ChannelMessageSend(channelID, content string) (*discordgo.Message, error)
+	// ChannelMessageSendEmbed sends an embed to a channel
+	ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed) (*discordgo.Message, error)
+	// ChannelMessageSendComplex sends a message with embeds and components to a channel
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend) (*discordgo.Message, error)
+	// ChannelMessageEdit replaces the content of a message
+	ChannelMessageEdit(channelID, messageID, content string) (*discordgo.Message, error)
+	// ChannelMessageEditComplex edits the content, embeds and components of a message
+	ChannelMessageEditComplex(edit *discordgo.MessageEdit) (*discordgo.Message, error)
+
+	// InteractionRespond sends the initial response to an interaction
+	InteractionRespond(interaction *discordgo.Interaction, response *discordgo.InteractionResponse) error
+	// InteractionResponseEdit edits the initial response to an interaction
+	InteractionResponseEdit(interaction *discordgo.Interaction, edit *discordgo.WebhookEdit) (*discordgo.Message, error)
+	// InteractionResponse returns the message sent as the initial response to an interaction
+	InteractionResponse(interaction *discordgo.Interaction) (*discordgo.Message, error)
+
+	// HeartbeatLatency returns the latency of the gateway heartbeat
+	HeartbeatLatency() time.Duration
+
+	// Guild returns a guild from the state cache
+	Guild(guildID string) (*discordgo.Guild, error)
+	// Role returns a guild role from the state cache
+	Role(guildID, roleID string) (*discordgo.Role, error)
+	// Member returns a guild member from the state cache, falling back to the API
+	Member(guildID, userID string) (*discordgo.Member, error)
+}
+
+// discordSession adapts *discordgo.Session to the Session interface
+type discordSession struct {
+	session *discordgo.Session
+}
+
+// NewSession wraps a discordgo session for use by command handlers
+func NewSession(s *discordgo.Session) Session {
+	return &discordSession{session: s}
+}
+
+func (d *discordSession) ChannelMessageSend(channelID, content string) (*discordgo.Message, error) {
+	return d.session.ChannelMessageSend(channelID, content)
+}
+
+func (d *discordSession) ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	return d.session.ChannelMessageSendEmbed(channelID, embed)
+}
+
+func (d *discordSession) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	return d.session.ChannelMessageSendComplex(channelID, data)
+}
+
+func (d *discordSession) ChannelMessageEdit(channelID, messageID, content string) (*discordgo.Message, error) {
+	return d.session.ChannelMessageEdit(channelID, messageID, content)
+}
+
+func (d *discordSession) ChannelMessageEditComplex(edit *discordgo.MessageEdit) (*discordgo.Message, error) {
+	return d.session.ChannelMessageEditComplex(edit)
+}
+
+func (d *discordSession) InteractionRespond(interaction *discordgo.Interaction, response *discordgo.InteractionResponse) error {
+	return d.session.InteractionRespond(interaction, response)
+}
+
+func (d *discordSession) InteractionResponseEdit(interaction *discordgo.Interaction, edit *discordgo.WebhookEdit) (*discordgo.Message, error) {
+	return d.session.InteractionResponseEdit(interaction, edit)
+}
+
+func (d *discordSession) InteractionResponse(interaction *discordgo.Interaction) (*discordgo.Message, error) {
+	return d.session.InteractionResponse(interaction)
+}
+
+func (d *discordSession) HeartbeatLatency() time.Duration {
+	return d.session.HeartbeatLatency()
+}
+
+func (d *discordSession) Guild(guildID string) (*discordgo.Guild, error) {
+	return d.session.State.Guild(guildID)
+}
+
+func (d *discordSession) Role(guildID, roleID string) (*discordgo.Role, error) {
+	return d.session.State.Role(guildID, roleID)
+}
+
+func (d *discordSession) Member(guildID, userID string) (*discordgo.Member, error) {
+	if member, err := d.session.State.Member(guildID, userID); err == nil {
+		return member, nil
+	}
+	return d.session.GuildMember(guildID, userID)
+}
@@ -0,0 +1,256 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+// defaultSimMembers is the front line of a party
+const defaultSimMembers = 4
+
+// defaultCritMultiplier is the damage bonus of a crit option without a multiplier
+const defaultCritMultiplier = 50
+
+// SimCommand simulates the damage of a party over turns
+type SimCommand struct {
+	logger     *log.Logger
+	locales    *i18n.Resolver
+	calculator *gbf.AttackCalculator
+}
+
+// NewSimCommand creates a new sim command handler
+func NewSimCommand(logger *log.Logger, locales *i18n.Resolver) *SimCommand {
+	return &SimCommand{
+		logger:     logger,
+		locales:    locales,
+		calculator: gbf.NewAttackCalculator(),
+	}
+}
+
+// HandleSlashCommand handles the slash version of sim command (/sim)
+func (c *SimCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("sim")
+	tr := c.locales.Interaction(i)
+
+	input := gbf.SimInput{
+		Attack:  gbf.DamageInput{BaseAttack: defaultCharacterAttack},
+		Members: defaultSimMembers,
+		Trials:  gbf.DefaultSimTrials,
+		Seed:    1,
+	}
+	var grid string
+	var critRate, critMultiplier float64 = 0, defaultCritMultiplier
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "grid":
+			grid = opt.StringValue()
+		case "attack":
+			input.Attack.BaseAttack = int(opt.IntValue())
+		case "da":
+			input.DoubleAttack = float64(opt.IntValue()) / 100
+		case "ta":
+			input.TripleAttack = float64(opt.IntValue()) / 100
+		case "crit_rate":
+			critRate = float64(opt.IntValue()) / 100
+		case "crit_multiplier":
+			critMultiplier = float64(opt.IntValue())
+		case "echo":
+			input.Attack.Echo = float64(opt.IntValue()) / 100
+		case "supplemental":
+			input.Attack.Supplemental = int(opt.IntValue())
+		case "charge_boost":
+			input.ChargeBoost = float64(opt.IntValue()) / 100
+		case "target_hp":
+			input.TargetHP = int(opt.IntValue())
+		case "members":
+			input.Members = int(opt.IntValue())
+		case "seed":
+			input.Seed = uint64(opt.IntValue())
+		case "element":
+			input.Attack.Attacker = gbf.Element(opt.StringValue())
+		case "enemy":
+			input.Attack.Defender = gbf.Element(opt.StringValue())
+		}
+	}
+	input.Attack.Attacker, input.Attack.Defender = elementMatchup(input.Attack.Attacker, input.Attack.Defender)
+
+	if critRate > 0 {
+		input.Attack.Crits = append(input.Attack.Crits, gbf.CritSource{Rate: critRate, Multiplier: critMultiplier / 100})
+	}
+	if grid != "" {
+		parsed, err := gbf.ParseGrid(grid)
+		var modifiers gbf.GridModifiers
+		if err == nil {
+			modifiers, err = parsed.Modifiers()
+		}
+		if err != nil {
+			logger.WithError(err).Warn("Invalid grid", "grid", grid)
+			c.respond(s, i, logger, &discordgo.InteractionResponseData{
+				Content: "❌ " + tr.T("sim.invalid_grid", err),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			})
+			return
+		}
+		input.Attack = modifiers.Apply(input.Attack)
+		input.DoubleAttack = min(input.DoubleAttack+modifiers.DoubleAttack, 1)
+		input.TripleAttack = min(input.TripleAttack+modifiers.TripleAttack, 1)
+	}
+
+	result, err := c.calculator.Simulate(input)
+	if err != nil {
+		logger.WithError(err).Warn("Simulation failed")
+		c.respond(s, i, logger, &discordgo.InteractionResponseData{
+			Content: "❌ " + tr.T("sim.failed", err),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		return
+	}
+
+	c.respond(s, i, logger, &discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{buildSimEmbed(tr, input, result)},
+	})
+
+	logger.Info("Sim slash command executed successfully", "trials", input.Trials, "seed", input.Seed)
+}
+
+// respond sends the command's response
+func (c *SimCommand) respond(s Session, i *discordgo.InteractionCreate, logger *log.Logger, data *discordgo.InteractionResponseData) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to sim slash command")
+	}
+}
+
+// buildSimEmbed shows the calculated and simulated results side by side
+func buildSimEmbed(tr i18n.Localizer, input gbf.SimInput, result *gbf.SimResult) *discordgo.MessageEmbed {
+	sim := result.Simulated
+
+	calculated := tr.T("sim.turn_damage", int(result.TurnDamage)) + "\n" +
+		tr.T("sim.charge_rate", result.ChargeRate*100)
+	simulated := tr.T("sim.turn_damage", int(sim.TurnDamage)) + "\n" +
+		tr.T("sim.charge_rate", sim.ChargeRate*100)
+	if input.TargetHP > 0 {
+		calculated += "\n" + tr.T("sim.turns", result.TurnsToKill)
+		simulated += "\n" + tr.T("sim.turns_range", sim.TurnsToKill, sim.MinTurns, sim.MaxTurns)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: tr.T("sim.title"),
+		Color: 0x3498db,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name: tr.T("sim.per_hit"),
+				Value: tr.T("sim.normal_hit", result.Normal.Damage, result.ExpectedHits) + "\n" +
+					tr.T("sim.charge_hit", result.Charge.Damage),
+				Inline: false,
+			},
+			{
+				Name:   tr.T("sim.calculated"),
+				Value:  calculated,
+				Inline: true,
+			},
+			{
+				Name:   tr.T("sim.simulated"),
+				Value:  simulated,
+				Inline: true,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: tr.T("sim.footer", input.Members, sim.Trials, input.Seed, formatMatchup(tr, input.Attack.Attacker, input.Attack.Defender)),
+		},
+	}
+}
+
+// GetSlashCommandDefinition returns the slash command definition for sim
+func (c *SimCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	minOne := float64(1)
+	minZero := float64(0)
+	dmPermission := false
+
+	percent := func(name, description string) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        name,
+			Description: description,
+			Required:    false,
+			MinValue:    &minZero,
+			MaxValue:    100,
+		}
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:         "sim",
+		Description:  "Simulates party damage per turn, charge attacks and turns to kill",
+		DMPermission: &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "grid",
+				Description: "Weapons and summons, as in /grid calc",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "attack",
+				Description: fmt.Sprintf("Character ATK before weapons and summons (default %d)", defaultCharacterAttack),
+				Required:    false,
+				MinValue:    &minOne,
+				MaxValue:    1000000,
+			},
+			percent("da", "Double attack rate in percent, added to the grid"),
+			percent("ta", "Triple attack rate in percent, added to the grid"),
+			percent("crit_rate", "Critical hit rate in percent"),
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "crit_multiplier",
+				Description: fmt.Sprintf("Critical damage bonus in percent (default %d)", defaultCritMultiplier),
+				Required:    false,
+				MinValue:    &minOne,
+				MaxValue:    500,
+			},
+			percent("echo", "Echo damage in percent of each hit"),
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "supplemental",
+				Description: "Supplemental damage added to each hit",
+				Required:    false,
+				MinValue:    &minZero,
+				MaxValue:    1000000,
+			},
+			percent("charge_boost", "Charge bar gain boost in percent"),
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "target_hp",
+				Description: "Enemy HP for turns to kill",
+				Required:    false,
+				MinValue:    &minOne,
+				MaxValue:    1000000000,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "members",
+				Description: fmt.Sprintf("Party members attacking (default %d)", defaultSimMembers),
+				Required:    false,
+				MinValue:    &minOne,
+				MaxValue:    gbf.MaxSimMembers,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "seed",
+				Description: "Random seed of the simulation (default 1)",
+				Required:    false,
+				MinValue:    &minZero,
+				MaxValue:    1000000,
+			},
+			elementOption("element", "Element of the party (default: all elements)", true),
+			elementOption("enemy", "Element of the enemy (default: the element yours beats)", true),
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+func TestSimCommand(t *testing.T) {
+	c := NewSimCommand(log.InitLogger("error"), i18n.NewResolver(nil))
+	s := commandstest.NewSession()
+
+	tests := []struct {
+		name       string
+		options    []*discordgo.ApplicationCommandInteractionDataOption
+		wantFields map[string]string
+		wantError  string
+	}{
+		{
+			name: "single attacks of one member",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.IntegerOption("members", 1),
+				commandstest.IntegerOption("target_hp", 15000),
+			},
+			wantFields: map[string]string{
+				"Per hit":    "Normal attack 1500 × 1.00 hits\nCharge attack 6750",
+				"Calculated": "1977 damage per turn\nCharge attacks on 9.1% of turns\n8 turns to kill",
+				"Simulated":  "10.0 turns to kill (10-10)",
+			},
+		},
+		{
+			name: "grid multi-attack rates add to the options",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("grid", "2x normal ta massive"),
+				commandstest.IntegerOption("ta", 92),
+			},
+			wantFields: map[string]string{
+				"Per hit": "× 3.00 hits",
+			},
+		},
+		{
+			name: "rejects an invalid grid",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				commandstest.StringOption("grid", "magic might"),
+			},
+			wantError: "❌ The grid is invalid: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("user1"), "sim", tt.options...)
+
+			c.HandleSlashCommand(s, i)
+
+			response := s.LastResponse()
+			if response == nil || response.Data == nil {
+				t.Fatal("no response")
+			}
+			if tt.wantError != "" {
+				if !strings.HasPrefix(response.Data.Content, tt.wantError) {
+					t.Errorf("content = %q, want prefix %q", response.Data.Content, tt.wantError)
+				}
+				return
+			}
+			if len(response.Data.Embeds) != 1 {
+				t.Fatalf("embeds = %d, want 1", len(response.Data.Embeds))
+			}
+			for field, want := range tt.wantFields {
+				if got := commandstest.FieldValue(response.Data.Embeds[0], field); !strings.Contains(got, want) {
+					t.Errorf("field %q = %q, want it to contain %q", field, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+// TemplateCommand lets guilds customize the wording of the bot's messages
+type TemplateCommand struct {
+	logger    *log.Logger
+	locales   *i18n.Resolver
+	templates *templates.Manager
+}
+
+// NewTemplateCommand creates a new template command handler
+func NewTemplateCommand(logger *log.Logger, locales *i18n.Resolver, templateManager *templates.Manager) *TemplateCommand {
+	return &TemplateCommand{
+		logger:    logger,
+		locales:   locales,
+		templates: templateManager,
+	}
+}
+
+// HandleSlashCommand handles the slash version of template command (/template)
+func (c *TemplateCommand) HandleSlashCommand(s Session, i *discordgo.InteractionCreate) {
+	logger := c.logger.WithDiscordContext(i.GuildID, i.ChannelID, i.Member.User.ID).WithCommand("template")
+	tr := c.locales.Interaction(i)
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	subcommand := data.Options[0]
+
+	var name, text string
+	for _, opt := range subcommand.Options {
+		switch opt.Name {
+		case "name":
+			name = opt.StringValue()
+		case "text":
+			// Slash command options are single line, so "\n" stands for a line break
+			text = strings.ReplaceAll(opt.StringValue(), `\n`, "\n")
+		}
+	}
+
+	ctx := context.Background()
+	var (
+		content string
+		embed   *discordgo.MessageEmbed
+		err     error
+	)
+
+	switch subcommand.Name {
+	case "list":
+		embed, err = c.buildListEmbed(ctx, tr, i.GuildID)
+	case "preview":
+		embed, err = c.buildPreviewEmbed(ctx, tr, i.GuildID, name, text, tr.T("template.preview.title"))
+	case "set":
+		err = c.templates.Set(ctx, i.GuildID, name, text, i.Member.User.ID)
+		if err == nil {
+			embed, err = c.buildPreviewEmbed(ctx, tr, i.GuildID, name, "", tr.T("template.saved.title"))
+		}
+	case "reset":
+		err = c.templates.Reset(ctx, i.GuildID, name)
+		content = tr.T("template.reset", name)
+	}
+
+	if err != nil {
+		logger.WithError(err).Warn("Template command failed", "subcommand", subcommand.Name, "template", name)
+		content = errorMessage(tr, err)
+		embed = nil
+	}
+
+	response := &discordgo.InteractionResponseData{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	}
+	if embed != nil {
+		response.Embeds = []*discordgo.MessageEmbed{embed}
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: response,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond to template slash command")
+		return
+	}
+
+	logger.Info("Template slash command executed successfully", "subcommand", subcommand.Name)
+}
+
+// buildListEmbed lists every template, marking the ones the guild customized
+func (c *TemplateCommand) buildListEmbed(ctx context.Context, tr i18n.Localizer, guildID string) (*discordgo.MessageEmbed, error) {
+	overrides, err := c.templates.Overrides(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, definition := range templates.Definitions() {
+		definition = definition.In(tr.Locale())
+		marker := ""
+		if _, custom := overrides[definition.Name]; custom {
+			marker = " ✏️"
+		}
+		lines = append(lines, fmt.Sprintf("`%s`%s - %s", definition.Name, marker, definition.Description))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       tr.T("template.list.title"),
+		Description: strings.Join(lines, "\n"),
+		Color:       0x3498db,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: tr.T("template.list.footer", len(overrides)),
+		},
+	}, nil
+}
+
+// buildPreviewEmbed renders a template with sample data. An empty text previews
+// the template the guild currently uses.
+func (c *TemplateCommand) buildPreviewEmbed(ctx context.Context, tr i18n.Localizer, guildID, name, text, title string) (*discordgo.MessageEmbed, error) {
+	source := tr.T("template.source.unsaved")
+	if text == "" {
+		var (
+			custom bool
+			err    error
+		)
+		text, custom, err = c.templates.Text(ctx, guildID, tr.Locale(), name)
+		if err != nil {
+			return nil, err
+		}
+		source = tr.T("template.source.default")
+		if custom {
+			source = tr.T("template.source.custom")
+		}
+	}
+
+	rendered, err := templates.Preview(name, text)
+	if err != nil {
+		return nil, err
+	}
+
+	// Embed field values are limited to 1024 characters
+	code := strings.ReplaceAll(text, "```", "'''")
+	if runes := []rune(code); len(runes) > 1000 {
+		code = string(runes[:1000]) + "…"
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s: %s", title, name),
+		Description: rendered,
+		Color:       0x27ae60,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   tr.T("template.field.template"),
+				Value:  "```\n" + code + "\n```",
+				Inline: false,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: tr.T("template.preview.footer", source),
+		},
+	}, nil
+}
+
+// GetSlashCommandDefinition returns the slash command definition for template
+func (c *TemplateCommand) GetSlashCommandDefinition() *discordgo.ApplicationCommand {
+	manageGuild := int64(discordgo.PermissionManageGuild)
+	dmPermission := false
+
+	var nameChoices []*discordgo.ApplicationCommandOptionChoice
+	for _, definition := range templates.Definitions() {
+		nameChoices = append(nameChoices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  definition.Name,
+			Value: definition.Name,
+		})
+	}
+	nameOption := func() *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "name",
+			Description: "Template name",
+			Required:    true,
+			Choices:     nameChoices,
+		}
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:                     "template",
+		Description:              "Customizes the bot's messages for this server (Admin only)",
+		DefaultMemberPermissions: &manageGuild,
+		DMPermission:             &dmPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Lists the message templates",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "preview",
+				Description: "Renders a template with sample data",
+				Options: []*discordgo.ApplicationCommandOption{
+					nameOption(),
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "text",
+						Description: "Template text to try instead of the saved one (\\n for a line break)",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Replaces a template for this server",
+				Options: []*discordgo.ApplicationCommandOption{
+					nameOption(),
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "text",
+						Description: "Go text/template, e.g. {{mention .Host}} is recruiting! (\\n for a line break)",
+						Required:    true,
+						MaxLength:   templates.MaxLength,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Restores the built-in default of a template",
+				Options:     []*discordgo.ApplicationCommandOption{nameOption()},
+			},
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+func TestTemplateCommand(t *testing.T) {
+	logger := log.InitLogger("error")
+	c := NewTemplateCommand(logger, i18n.NewResolver(nil), templates.NewManager(storage.NewMemoryStore(), logger))
+	s := commandstest.NewSession()
+	admin := commandstest.Member("admin1")
+
+	run := func(subcommand *discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionResponseData {
+		t.Helper()
+		c.HandleSlashCommand(s, commandstest.SlashCommand(testGuildID, testChannelID, admin, "template", subcommand))
+		response := s.LastResponse()
+		if response == nil || response.Data == nil {
+			t.Fatal("no response")
+		}
+		if response.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+			t.Error("template responses should be ephemeral")
+		}
+		return response.Data
+	}
+
+	// Previewing unsaved text turns "\n" into a line break
+	data := run(commandstest.Subcommand("preview",
+		commandstest.StringOption("name", templates.RecruitmentJoined),
+		commandstest.StringOption("text", `{{mention .User}} が参加\n{{.Players}}/{{.MaxPlayers}}`),
+	))
+	if len(data.Embeds) != 1 {
+		t.Fatalf("preview: got content %q, want an embed", data.Content)
+	}
+	if got := data.Embeds[0].Description; got != "<@100000000000000002> が参加\n2/6" {
+		t.Errorf("preview = %q", got)
+	}
+
+	data = run(commandstest.Subcommand("set",
+		commandstest.StringOption("name", templates.RecruitmentJoined),
+		commandstest.StringOption("text", "{{.Nmae}}"),
+	))
+	if !strings.HasPrefix(data.Content, "❌") || !strings.Contains(data.Content, "Nmae") {
+		t.Errorf("set with an unknown field: got %q, want an error naming the field", data.Content)
+	}
+
+	data = run(commandstest.Subcommand("set",
+		commandstest.StringOption("name", templates.RecruitmentJoined),
+		commandstest.StringOption("text", "{{mention .User}} が参加"),
+	))
+	if len(data.Embeds) != 1 || !strings.Contains(data.Embeds[0].Title, "Saved") {
+		t.Fatalf("set: got %+v, want the saved template", data)
+	}
+	if got := data.Embeds[0].Footer.Text; !strings.HasPrefix(got, "Customized") {
+		t.Errorf("set footer = %q, want it marked as customized", got)
+	}
+
+	data = run(commandstest.Subcommand("list"))
+	if len(data.Embeds) != 1 || !strings.Contains(data.Embeds[0].Description, "`recruitment.joined` ✏️") {
+		t.Errorf("list: got %+v, want recruitment.joined marked as customized", data)
+	}
+
+	data = run(commandstest.Subcommand("reset", commandstest.StringOption("name", templates.RecruitmentJoined)))
+	if !strings.HasPrefix(data.Content, "✅") {
+		t.Errorf("reset: got %q", data.Content)
+	}
+	data = run(commandstest.Subcommand("reset", commandstest.StringOption("name", templates.RecruitmentJoined)))
+	if !strings.HasPrefix(data.Content, "❌") {
+		t.Errorf("second reset: got %q, want an error", data.Content)
+	}
+}
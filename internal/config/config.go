@@ -1,6 +1,7 @@
 package config
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"strings"
@@ -8,12 +9,22 @@ import (
 
 // Config holds all configuration for the bot
 type Config struct {
+	// Config file settings (optional)
+	ConfigFile string
+
 	// Discord settings (required)
 	DiscordToken string
 
 	// Logging settings (optional)
 	LogLevel string
 
+	// Battle catalog file merged over the built-in catalog (optional)
+	BattleCatalogPath string
+
+	// Guild allowed to change data shared by every guild, such as the event
+	// calendar (optional; without it shared data cannot be changed)
+	OperatorGuildID string
+
 	// Database settings (required)
 	DBHost     string
 	DBUser     string
@@ -30,26 +41,49 @@ type Config struct {
 	TestDBPort       string
 }
 
-// Load reads configuration from environment variables and validates required fields
+// Load reads configuration from environment variables and validates required fields.
+// If CONFIG_FILE is set, KEY=VALUE pairs from that file are used for variables
+// that are not set in the environment.
 func Load() (*Config, error) {
+	configFile := os.Getenv("CONFIG_FILE")
+	fileValues, err := readConfigFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	getValue := func(key, defaultValue string) string {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+		if value := fileValues[key]; value != "" {
+			return value
+		}
+		return defaultValue
+	}
+
 	config := &Config{
-		DiscordToken: os.Getenv("DISCORD_TOKEN"),
-		LogLevel:     getEnvWithDefault("LOG_LEVEL", "info"),
+		ConfigFile: configFile,
+
+		DiscordToken: getValue("DISCORD_TOKEN", ""),
+		LogLevel:     getValue("LOG_LEVEL", "info"),
+
+		BattleCatalogPath: getValue("BATTLE_CATALOG", ""),
+		OperatorGuildID:   getValue("OPERATOR_GUILD_ID", ""),
 
 		// Database settings
-		DBHost:     getEnvWithDefault("DB_HOST", "localhost"),
-		DBUser:     getEnvWithDefault("DB_USER", ""),
-		DBPassword: getEnvWithDefault("DB_PASSWORD", ""),
-		DBName:     getEnvWithDefault("DB_NAME", ""),
-		DBPort:     getEnvWithDefault("DB_PORT", "5432"),
+		DBHost:     getValue("DB_HOST", "localhost"),
+		DBUser:     getValue("DB_USER", ""),
+		DBPassword: getValue("DB_PASSWORD", ""),
+		DBName:     getValue("DB_NAME", ""),
+		DBPort:     getValue("DB_PORT", "5432"),
 
 		// Test environment settings
-		TestDiscordToken: os.Getenv("TEST_DISCORD_TOKEN"),
-		TestDBHost:       getEnvWithDefault("TEST_DBHOST", "localhost"),
-		TestDBUser:       getEnvWithDefault("TEST_DBUSER", ""),
-		TestDBPassword:   getEnvWithDefault("TEST_DBPASSWORD", ""),
-		TestDBDatabase:   getEnvWithDefault("TEST_DBDATABASE", ""),
-		TestDBPort:       getEnvWithDefault("TEST_DB_PORT", "5432"),
+		TestDiscordToken: getValue("TEST_DISCORD_TOKEN", ""),
+		TestDBHost:       getValue("TEST_DBHOST", "localhost"),
+		TestDBUser:       getValue("TEST_DBUSER", ""),
+		TestDBPassword:   getValue("TEST_DBPASSWORD", ""),
+		TestDBDatabase:   getValue("TEST_DBDATABASE", ""),
+		TestDBPort:       getValue("TEST_DB_PORT", "5432"),
 	}
 
 	// Validate required fields
@@ -89,10 +123,79 @@ func (c *Config) validate() error {
 	return nil
 }
 
-// getEnvWithDefault returns the environment variable value or a default if not set
-func getEnvWithDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// readConfigFile parses a dotenv style file of KEY=VALUE lines.
+// Blank lines and lines starting with # are ignored.
+func readConfigFile(path string) (map[string]string, error) {
+	values := make(map[string]string)
+	if path == "" {
+		return values, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !found {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNumber)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Change describes a configuration value that differs between two configs
+type Change struct {
+	Key string
+	// RestartRequired is true when the new value only takes effect after a restart
+	RestartRequired bool
+}
+
+// Diff returns the configuration keys whose values differ in other.
+// Secret values are never included, only their keys.
+func (c *Config) Diff(other *Config) []Change {
+	fields := []struct {
+		key             string
+		old, new        string
+		restartRequired bool
+	}{
+		{"CONFIG_FILE", c.ConfigFile, other.ConfigFile, false},
+		{"DISCORD_TOKEN", c.DiscordToken, other.DiscordToken, true},
+		{"LOG_LEVEL", strings.ToLower(c.LogLevel), strings.ToLower(other.LogLevel), false},
+		{"BATTLE_CATALOG", c.BattleCatalogPath, other.BattleCatalogPath, false},
+		{"OPERATOR_GUILD_ID", c.OperatorGuildID, other.OperatorGuildID, false},
+		{"DB_HOST", c.DBHost, other.DBHost, true},
+		{"DB_USER", c.DBUser, other.DBUser, true},
+		{"DB_PASSWORD", c.DBPassword, other.DBPassword, true},
+		{"DB_NAME", c.DBName, other.DBName, true},
+		{"DB_PORT", c.DBPort, other.DBPort, true},
+	}
+
+	var changes []Change
+	for _, field := range fields {
+		if field.old != field.new {
+			changes = append(changes, Change{Key: field.key, RestartRequired: field.restartRequired})
+		}
 	}
-	return defaultValue
+	return changes
 }
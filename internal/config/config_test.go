@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -277,6 +278,90 @@ func TestLoad_TestEnvironmentSettings(t *testing.T) {
 	})
 }
 
+func TestLoad_ConfigFile(t *testing.T) {
+	// Save and restore env vars
+	originalToken := os.Getenv("DISCORD_TOKEN")
+	originalConfigFile := os.Getenv("CONFIG_FILE")
+	originalLogLevel := os.Getenv("LOG_LEVEL")
+	originalDBName := os.Getenv("DB_NAME")
+	defer func() {
+		restoreEnv("DISCORD_TOKEN", originalToken)
+		restoreEnv("CONFIG_FILE", originalConfigFile)
+		restoreEnv("LOG_LEVEL", originalLogLevel)
+		restoreEnv("DB_NAME", originalDBName)
+	}()
+
+	path := filepath.Join(t.TempDir(), "bot.env")
+	content := "# bot settings\n" +
+		"DISCORD_TOKEN=file_token\n" +
+		"LOG_LEVEL=debug\n" +
+		"export DB_NAME=\"file_db\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	_ = os.Setenv("CONFIG_FILE", path)
+	_ = os.Unsetenv("DISCORD_TOKEN")
+	_ = os.Unsetenv("DB_NAME")
+	_ = os.Setenv("LOG_LEVEL", "warn")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.DiscordToken != "file_token" {
+		t.Errorf("Expected DiscordToken from file to be 'file_token', got %q", cfg.DiscordToken)
+	}
+	if cfg.DBName != "file_db" {
+		t.Errorf("Expected quoted DBName from file to be 'file_db', got %q", cfg.DBName)
+	}
+	if cfg.LogLevel != "warn" {
+		t.Errorf("Expected environment LogLevel to override file, got %q", cfg.LogLevel)
+	}
+
+	t.Run("malformed_file", func(t *testing.T) {
+		if err := os.WriteFile(path, []byte("DISCORD_TOKEN\n"), 0o600); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+		if _, err := Load(); err == nil {
+			t.Error("Expected error for malformed config file, got nil")
+		}
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		_ = os.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.env"))
+		if _, err := Load(); err == nil {
+			t.Error("Expected error for missing config file, got nil")
+		}
+	})
+}
+
+func TestConfig_Diff(t *testing.T) {
+	base := &Config{DiscordToken: "token", LogLevel: "info", DBHost: "localhost"}
+
+	changed := *base
+	changed.LogLevel = "DEBUG"
+	changed.DBHost = "db.host"
+
+	changes := base.Diff(&changed)
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Key != "LOG_LEVEL" || changes[0].RestartRequired {
+		t.Errorf("Expected LOG_LEVEL change without restart, got %+v", changes[0])
+	}
+	if changes[1].Key != "DB_HOST" || !changes[1].RestartRequired {
+		t.Errorf("Expected DB_HOST change requiring restart, got %+v", changes[1])
+	}
+
+	same := *base
+	same.LogLevel = "INFO"
+	if changes := base.Diff(&same); len(changes) != 0 {
+		t.Errorf("Expected no changes for case-only log level difference, got %+v", changes)
+	}
+}
+
 // Helper function to restore environment variable
 func restoreEnv(key, originalValue string) {
 	if originalValue != "" {
@@ -3,52 +3,164 @@ package discord
 import (
 	"context"
 	"fmt"
-	"os"
-	"os/signal"
+	"runtime"
+	"sort"
 	"strings"
-	"syscall"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/varubogu/gbf_discord_bot_go/internal/commands"
 	"github.com/varubogu/gbf_discord_bot_go/internal/config"
+	"github.com/varubogu/gbf_discord_bot_go/internal/drops"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/inventory"
+	"github.com/varubogu/gbf_discord_bot_go/internal/lifecycle"
 	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/permissions"
+	"github.com/varubogu/gbf_discord_bot_go/internal/schedule"
+	"github.com/varubogu/gbf_discord_bot_go/internal/settings"
+	"github.com/varubogu/gbf_discord_bot_go/internal/sheets"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+	"github.com/varubogu/gbf_discord_bot_go/internal/version"
 )
 
 // Bot represents the Discord bot instance
 type Bot struct {
-	session       *discordgo.Session
-	config        *config.Config
-	logger        *log.Logger
-	pingCommand   *commands.PingCommand
-	helpCommand   *commands.HelpCommand
-	adminCommand  *commands.AdminCommand
-	battleCommand *commands.BattleCommand
+	session            *discordgo.Session
+	config             atomic.Pointer[config.Config]
+	logger             *log.Logger
+	store              storage.Store
+	lifecycle          *lifecycle.Manager
+	permissions        *permissions.Manager
+	settings           *settings.Manager
+	scheduler          *schedule.Engine
+	sheets             *sheets.Manager
+	templates          *templates.Manager
+	drops              *drops.Manager
+	inventory          *inventory.Manager
+	locales            *i18n.Resolver
+	battleManager      *gbf.BattleManager
+	recruitmentManager *gbf.RecruitmentManager
+	reloadMu           sync.Mutex
+	startedAt          time.Time
+	lastReload         atomic.Pointer[time.Time]
+	registeredCommands atomic.Pointer[[]string]
+	pingCommand        *commands.PingCommand
+	helpCommand        *commands.HelpCommand
+	adminCommand       *commands.AdminCommand
+	battleCommand      *commands.BattleCommand
+	permissionsCommand *commands.PermissionsCommand
+	recruitCommand     *commands.RecruitCommand
+	scheduleCommand    *commands.ScheduleCommand
+	dataCommand        *commands.DataCommand
+	templateCommand    *commands.TemplateCommand
+	languageCommand    *commands.LanguageCommand
+	gridCommand        *commands.GridCommand
+	classCommand       *commands.ClassCommand
+	simCommand         *commands.SimCommand
+	guildWarCommand    *commands.GuildWarCommand
+	dropCommand        *commands.DropCommand
+	matsCommand        *commands.MatsCommand
+	battleLogCommand   *commands.BattleLogCommand
 }
 
-// New creates a new Discord bot instance
-func New(cfg *config.Config, logger *log.Logger) (*Bot, error) {
+// slashCommand pairs a slash command definition with its permission category
+type slashCommand struct {
+	definition *discordgo.ApplicationCommand
+	category   permissions.Category
+}
+
+// recruitmentCleanupInterval is how often expired recruitments are removed
+const recruitmentCleanupInterval = time.Minute
+
+// New creates a new Discord bot instance.
+// The bot registers its event handlers and background workers with lc, which
+// owns shutdown.
+func New(cfg *config.Config, logger *log.Logger, store storage.Store, lc *lifecycle.Manager) (*Bot, error) {
 	// Create Discord session with bot token
 	session, err := discordgo.New("Bot " + cfg.DiscordToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Discord session: %w", err)
 	}
 
-	// Set required intents
-	session.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent
+	// Set required intents (guilds keeps roles in the state cache for permission checks)
+	session.Identify.Intents = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent
+
+	permissionManager := permissions.NewManager(store)
+	battleManager := gbf.NewBattleManager()
+	recruitmentManager := gbf.NewRecruitmentManager(battleManager)
+	settingsManager := settings.NewManager(store)
+	templateManager := templates.NewManager(store, logger)
+	dropManager := drops.NewManager(store)
+	inventoryManager := inventory.NewManager(store)
+	locales := i18n.NewResolver(settingsManager)
+	scheduler := schedule.NewEngine(store, settingsManager, battleManager, templateManager, commands.NewSession(session), schedule.SystemClock{}, logger)
 
 	bot := &Bot{
-		session:       session,
-		config:        cfg,
-		logger:        logger,
-		pingCommand:   commands.NewPingCommand(logger),
-		helpCommand:   commands.NewHelpCommand(logger),
-		adminCommand:  commands.NewAdminCommand(logger),
-		battleCommand: commands.NewBattleCommand(logger),
+		session:            session,
+		logger:             logger,
+		store:              store,
+		lifecycle:          lc,
+		permissions:        permissionManager,
+		settings:           settingsManager,
+		scheduler:          scheduler,
+		templates:          templateManager,
+		drops:              dropManager,
+		inventory:          inventoryManager,
+		battleManager:      battleManager,
+		recruitmentManager: recruitmentManager,
+		startedAt:          time.Now(),
+		locales:            locales,
+		pingCommand:        commands.NewPingCommand(logger, locales),
+		helpCommand:        commands.NewHelpCommand(logger, locales),
+		battleCommand:      commands.NewBattleCommand(logger, locales, battleManager),
+		permissionsCommand: commands.NewPermissionsCommand(logger, locales, permissionManager),
+		recruitCommand:     commands.NewRecruitCommand(logger, locales, battleManager, recruitmentManager, templateManager),
+		templateCommand:    commands.NewTemplateCommand(logger, locales, templateManager),
+		languageCommand:    commands.NewLanguageCommand(logger, locales, settingsManager),
+		gridCommand:        commands.NewGridCommand(logger, locales),
+		classCommand:       commands.NewClassCommand(logger, locales, gbf.DefaultClasses()),
+		simCommand:         commands.NewSimCommand(logger, locales),
+		guildWarCommand:    commands.NewGuildWarCommand(logger, locales, battleManager),
+		dropCommand:        commands.NewDropCommand(logger, locales, battleManager, dropManager),
+		matsCommand:        commands.NewMatsCommand(logger, locales, battleManager, gbf.DefaultRecipes(), inventoryManager),
+		battleLogCommand:   commands.NewBattleLogCommand(logger, locales, battleManager, recruitmentManager),
+	}
+	bot.config.Store(cfg)
+	bot.adminCommand = commands.NewAdminCommand(logger, locales, bot, bot, permissionManager)
+	operatorGuildID := func() string {
+		return bot.config.Load().OperatorGuildID
+	}
+	bot.scheduleCommand = commands.NewScheduleCommand(logger, locales, scheduler, settingsManager, operatorGuildID)
+	bot.sheets = sheets.NewManager(store, battleManager, scheduler, templateManager, func() string {
+		return bot.config.Load().BattleCatalogPath
+	}, operatorGuildID)
+	bot.dataCommand = commands.NewDataCommand(logger, locales, bot.sheets)
+
+	// The catalog file and imported battles are applied on top of the built-in battles
+	battles, err := bot.sheets.Catalog(context.Background(), cfg.BattleCatalogPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load battle catalog: %w", err)
+	}
+	battleManager.Replace(battles)
+
+	// Make every command known to the permission system
+	for _, command := range bot.slashCommands() {
+		permissionManager.RegisterCommand(command.definition, command.category)
 	}
 
 	// Register event handlers
 	bot.setupHandlers()
 
+	// The Discord connection is closed once in-flight handlers have drained
+	lc.OnShutdown("discord session", func(ctx context.Context) error {
+		return bot.Close()
+	})
+
 	return bot, nil
 }
 
@@ -61,35 +173,115 @@ func (b *Bot) setupHandlers() {
 
 // onReady handles the ready event
 func (b *Bot) onReady(s *discordgo.Session, r *discordgo.Ready) {
+	done, ok := b.lifecycle.Track()
+	if !ok {
+		return
+	}
+	defer done()
+
 	b.logger.Info("Bot is ready", "user", r.User.Username, "guilds", len(r.Guilds))
+
+	if _, err := b.syncSlashCommands(); err != nil {
+		b.logger.WithError(err).Error("Failed to register slash commands")
+	}
 }
 
 // onMessageCreate handles new messages (for prefix commands)
 func (b *Bot) onMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	b.handleMessage(commands.NewSession(s), m)
+}
+
+// handleMessage routes a prefix command to its handler
+func (b *Bot) handleMessage(s commands.Session, m *discordgo.MessageCreate) {
 	// Ignore messages from bots
 	if m.Author.Bot {
 		return
 	}
 
+	// Stop accepting new commands once shutdown has begun
+	done, ok := b.lifecycle.Track()
+	if !ok {
+		return
+	}
+	defer done()
+
 	// Handle prefix commands
-	if m.Content == "!ping" {
-		b.pingCommand.HandlePrefixCommand(s, m)
-	} else if strings.HasPrefix(m.Content, "!help") {
-		b.helpCommand.HandlePrefixCommand(s, m)
-	} else if m.Content == "!reload" {
-		b.adminCommand.HandleReloadPrefixCommand(s, m)
-	} else if m.Content == "!status" {
-		b.adminCommand.HandleStatusPrefixCommand(s, m)
-	} else if strings.HasPrefix(m.Content, "!battles") {
-		b.battleCommand.HandleBattlesListPrefixCommand(s, m)
-	} else if strings.HasPrefix(m.Content, "!battle ") {
-		b.battleCommand.HandleBattleInfoPrefixCommand(s, m)
-	}
-}
-
-// onInteractionCreate handles slash command interactions
+	if !strings.HasPrefix(m.Content, "!") {
+		return
+	}
+	fields := strings.Fields(m.Content)
+	if len(fields) == 0 {
+		return
+	}
+	name := strings.TrimPrefix(fields[0], "!")
+
+	var handler func(commands.Session, *discordgo.MessageCreate)
+	switch name {
+	case "ping":
+		handler = b.pingCommand.HandlePrefixCommand
+	case "help":
+		handler = b.helpCommand.HandlePrefixCommand
+	case "reload":
+		handler = b.adminCommand.HandleReloadPrefixCommand
+	case "status":
+		handler = b.adminCommand.HandleStatusPrefixCommand
+	case "battles":
+		handler = b.battleCommand.HandleBattlesListPrefixCommand
+	case "battle":
+		handler = b.battleCommand.HandleBattleInfoPrefixCommand
+	default:
+		return
+	}
+
+	if !b.authorizeMessage(s, m, name) {
+		return
+	}
+	handler(s, m)
+}
+
+// onInteractionCreate handles slash command and component interactions
 func (b *Bot) onInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	switch i.ApplicationCommandData().Name {
+	b.handleInteraction(commands.NewSession(s), i)
+}
+
+// handleInteraction routes a slash command, autocomplete request or button click to its handler
+func (b *Bot) handleInteraction(s commands.Session, i *discordgo.InteractionCreate) {
+	var name string
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionApplicationCommandAutocomplete:
+		name = i.ApplicationCommandData().Name
+	case discordgo.InteractionMessageComponent:
+		// Components are authorized as the command that created them
+		if !strings.HasPrefix(i.MessageComponentData().CustomID, commands.RecruitComponentPrefix) {
+			return
+		}
+		name = "recruit"
+	default:
+		return
+	}
+
+	// Stop accepting new commands once shutdown has begun
+	done, ok := b.lifecycle.Track()
+	if !ok {
+		return
+	}
+	defer done()
+
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		b.handleAutocomplete(s, i, name)
+		return
+	}
+
+	if !b.authorizeInteraction(s, i, name) {
+		return
+	}
+
+	if i.Type == discordgo.InteractionMessageComponent {
+		b.recruitCommand.HandleComponent(s, i)
+		return
+	}
+
+	switch name {
 	case "ping":
 		b.pingCommand.HandleSlashCommand(s, i)
 	case "help":
@@ -102,10 +294,147 @@ func (b *Bot) onInteractionCreate(s *discordgo.Session, i *discordgo.Interaction
 		b.battleCommand.HandleBattlesListSlashCommand(s, i)
 	case "battle":
 		b.battleCommand.HandleBattleInfoSlashCommand(s, i)
+	case "permissions":
+		b.permissionsCommand.HandleSlashCommand(s, i)
+	case "recruit":
+		b.recruitCommand.HandleSlashCommand(s, i)
+	case "schedule":
+		b.scheduleCommand.HandleSlashCommand(s, i)
+	case "data":
+		b.dataCommand.HandleSlashCommand(s, i)
+	case "template":
+		b.templateCommand.HandleSlashCommand(s, i)
+	case "language":
+		b.languageCommand.HandleSlashCommand(s, i)
+	case "grid":
+		b.gridCommand.HandleSlashCommand(s, i)
+	case "class":
+		b.classCommand.HandleSlashCommand(s, i)
+	case "sim":
+		b.simCommand.HandleSlashCommand(s, i)
+	case "gw":
+		b.guildWarCommand.HandleSlashCommand(s, i)
+	case "drop":
+		b.dropCommand.HandleSlashCommand(s, i)
+	case "mats":
+		b.matsCommand.HandleSlashCommand(s, i)
+	case "battlelog":
+		b.battleLogCommand.HandleSlashCommand(s, i)
+	}
+}
+
+// handleAutocomplete routes an autocomplete request to its command. Members who
+// cannot use the command get no suggestions instead of a permission error.
+func (b *Bot) handleAutocomplete(s commands.Session, i *discordgo.InteractionCreate, name string) {
+	subject := permissions.SubjectFromInteraction(i)
+	if !b.permissions.Check(context.Background(), s, subject, name).Allowed {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+			Data: &discordgo.InteractionResponseData{},
+		})
+		if err != nil {
+			b.logger.WithDiscordContext(i.GuildID, i.ChannelID, subject.UserID).WithCommand(name).
+				WithError(err).Error("Failed to respond to autocomplete")
+		}
+		return
+	}
+
+	switch name {
+	case "battles", "battle":
+		b.battleCommand.HandleAutocomplete(s, i)
+	case "recruit":
+		b.recruitCommand.HandleAutocomplete(s, i)
+	case "schedule":
+		b.scheduleCommand.HandleAutocomplete(s, i)
+	case "class":
+		b.classCommand.HandleAutocomplete(s, i)
+	case "gw":
+		b.guildWarCommand.HandleAutocomplete(s, i)
+	case "drop":
+		b.dropCommand.HandleAutocomplete(s, i)
+	case "mats":
+		b.matsCommand.HandleAutocomplete(s, i)
+	}
+}
+
+// authorizeMessage checks command access for a prefix command and reports denials
+func (b *Bot) authorizeMessage(s commands.Session, m *discordgo.MessageCreate, command string) bool {
+	subject := permissions.SubjectFromMessage(s, m)
+	decision := b.permissions.Check(context.Background(), s, subject, command)
+	if decision.Allowed {
+		return true
+	}
+
+	logger := b.logger.WithDiscordContext(m.GuildID, m.ChannelID, m.Author.ID).WithCommand(command)
+	if decision.Err != nil {
+		logger.WithError(decision.Err).Error("Failed to check permissions")
+	} else {
+		logger.Warn("Unauthorized access attempt", "reason", decision.Reason)
+	}
+
+	content, err := b.permissionDeniedMessage(b.locales.Message(m), m.GuildID, command, decision)
+	if err != nil {
+		logger.WithError(err).Error("Failed to render permission denied message")
+		return false
+	}
+	_, err = s.ChannelMessageSend(m.ChannelID, content)
+	if err != nil {
+		logger.WithError(err).Error("Failed to send permission denied message")
 	}
+	return false
 }
 
-// Start starts the Discord bot
+// authorizeInteraction checks command access for a slash command and reports denials
+func (b *Bot) authorizeInteraction(s commands.Session, i *discordgo.InteractionCreate, command string) bool {
+	subject := permissions.SubjectFromInteraction(i)
+	decision := b.permissions.Check(context.Background(), s, subject, command)
+	if decision.Allowed {
+		return true
+	}
+
+	logger := b.logger.WithDiscordContext(i.GuildID, i.ChannelID, subject.UserID).WithCommand(command)
+	if decision.Err != nil {
+		logger.WithError(decision.Err).Error("Failed to check permissions")
+	} else {
+		logger.Warn("Unauthorized access attempt", "reason", decision.Reason)
+	}
+
+	content, err := b.permissionDeniedMessage(b.locales.Interaction(i), i.GuildID, command, decision)
+	if err != nil {
+		logger.WithError(err).Error("Failed to render permission denied message")
+		return false
+	}
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to respond with permission denied")
+	}
+	return false
+}
+
+// permissionDeniedMessage renders the reply to a denied command with the guild's wording
+func (b *Bot) permissionDeniedMessage(tr i18n.Localizer, guildID, command string, decision permissions.Decision) (string, error) {
+	reason := decision.Reason
+	switch decision.Denial {
+	case permissions.DenialControlRole:
+		reason = tr.T("permissions.reason.control_role", decision.Role)
+	case permissions.DenialDiscordPermissions, permissions.DenialRestricted, permissions.DenialUnavailable:
+		reason = tr.T("permissions.reason." + string(decision.Denial))
+	}
+
+	return b.templates.Render(context.Background(), guildID, tr.Locale(), templates.PermissionDenied, templates.PermissionData{
+		Command: command,
+		Reason:  reason,
+	})
+}
+
+// Start opens the Discord connection and starts background workers.
+// It blocks until ctx is cancelled; cleanup is left to the lifecycle manager.
 func (b *Bot) Start(ctx context.Context) error {
 	// Open connection to Discord
 	err := b.session.Open()
@@ -113,20 +442,32 @@ func (b *Bot) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to open Discord connection: %w", err)
 	}
 
+	b.lifecycle.Go("recruitment cleanup", b.runRecruitmentCleanup)
+	b.lifecycle.Go("scheduled notifications", b.scheduler.Run)
+
 	b.logger.Info("Bot started successfully")
 
-	// Wait for context cancellation or interrupt signal
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	b.logger.Info("Bot stopping due to context cancellation")
 
-	select {
-	case <-ctx.Done():
-		b.logger.Info("Bot stopping due to context cancellation")
-	case <-stop:
-		b.logger.Info("Bot stopping due to interrupt signal")
-	}
+	return nil
+}
+
+// runRecruitmentCleanup periodically removes expired recruitments
+func (b *Bot) runRecruitmentCleanup(ctx context.Context) {
+	ticker := time.NewTicker(recruitmentCleanupInterval)
+	defer ticker.Stop()
 
-	return b.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, recruitment := range b.recruitmentManager.CleanupExpiredRecruitments() {
+				b.logger.Info("Recruitment expired", "recruitment_id", recruitment.ID, "guild_id", recruitment.GuildID)
+			}
+		}
+	}
 }
 
 // Close closes the Discord connection
@@ -135,24 +476,181 @@ func (b *Bot) Close() error {
 	return b.session.Close()
 }
 
-// registerSlashCommands registers slash commands for the bot
-func (b *Bot) registerSlashCommands() error {
-	commands := []*discordgo.ApplicationCommand{
-		b.pingCommand.GetSlashCommandDefinition(),
-		b.helpCommand.GetSlashCommandDefinition(),
-		b.adminCommand.GetReloadSlashCommandDefinition(),
-		b.adminCommand.GetStatusSlashCommandDefinition(),
-		b.battleCommand.GetBattlesListSlashCommandDefinition(),
-		b.battleCommand.GetBattleInfoSlashCommandDefinition(),
+// slashCommands returns every slash command definition with its permission category
+func (b *Bot) slashCommands() []slashCommand {
+	return []slashCommand{
+		{b.pingCommand.GetSlashCommandDefinition(), permissions.CategoryGeneral},
+		{b.helpCommand.GetSlashCommandDefinition(), permissions.CategoryGeneral},
+		{b.adminCommand.GetReloadSlashCommandDefinition(), permissions.CategoryAdmin},
+		{b.adminCommand.GetStatusSlashCommandDefinition(), permissions.CategoryGeneral},
+		{b.battleCommand.GetBattlesListSlashCommandDefinition(), permissions.CategoryGBF},
+		{b.battleCommand.GetBattleInfoSlashCommandDefinition(), permissions.CategoryGBF},
+		{b.permissionsCommand.GetSlashCommandDefinition(), permissions.CategoryAdmin},
+		{b.recruitCommand.GetSlashCommandDefinition(), permissions.CategoryGBF},
+		{b.scheduleCommand.GetSlashCommandDefinition(), permissions.CategoryAdmin},
+		{b.dataCommand.GetSlashCommandDefinition(), permissions.CategoryAdmin},
+		{b.templateCommand.GetSlashCommandDefinition(), permissions.CategoryAdmin},
+		{b.languageCommand.GetSlashCommandDefinition(), permissions.CategoryAdmin},
+		{b.gridCommand.GetSlashCommandDefinition(), permissions.CategoryGBF},
+		{b.classCommand.GetSlashCommandDefinition(), permissions.CategoryGBF},
+		{b.simCommand.GetSlashCommandDefinition(), permissions.CategoryGBF},
+		{b.guildWarCommand.GetSlashCommandDefinition(), permissions.CategoryGBF},
+		{b.dropCommand.GetSlashCommandDefinition(), permissions.CategoryGBF},
+		{b.matsCommand.GetSlashCommandDefinition(), permissions.CategoryGBF},
+		{b.battleLogCommand.GetSlashCommandDefinition(), permissions.CategoryGBF},
 	}
+}
 
-	for _, command := range commands {
-		_, err := b.session.ApplicationCommandCreate(b.session.State.User.ID, "", command)
-		if err != nil {
-			return fmt.Errorf("failed to create slash command %s: %w", command.Name, err)
+// syncSlashCommands overwrites the registered global slash commands with the
+// current definitions and returns how many were registered
+func (b *Bot) syncSlashCommands() (int, error) {
+	if b.session.State.User == nil {
+		return 0, fmt.Errorf("session is not ready")
+	}
+
+	var definitions []*discordgo.ApplicationCommand
+	for _, slash := range b.slashCommands() {
+		if missing := i18n.LocalizeCommand(slash.definition); len(missing) > 0 {
+			b.logger.Warn("Slash command is missing translations", "command", slash.definition.Name, "keys", missing)
 		}
+		definitions = append(definitions, slash.definition)
+	}
+
+	registered, err := b.session.ApplicationCommandBulkOverwrite(b.session.State.User.ID, "", definitions)
+	if err != nil {
+		return 0, fmt.Errorf("failed to register slash commands: %w", err)
+	}
+
+	names := make([]string, 0, len(registered))
+	for _, command := range registered {
+		names = append(names, command.Name)
 		b.logger.Info("Registered slash command", "command", command.Name, "global", true)
 	}
+	b.registeredCommands.Store(&names)
 
-	return nil
+	return len(registered), nil
+}
+
+// Reload re-reads configuration, rebuilds the battle catalog and refreshes guild
+// settings, then re-syncs slash commands.
+// Everything is loaded before anything is swapped in, so a failed load leaves the
+// running state untouched and in-flight handlers keep the values they started with.
+func (b *Bot) Reload(ctx context.Context) (*commands.ReloadReport, error) {
+	b.reloadMu.Lock()
+	defer b.reloadMu.Unlock()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload configuration: %w", err)
+	}
+
+	battles, err := b.sheets.Catalog(ctx, cfg.BattleCatalogPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload battle catalog: %w", err)
+	}
+
+	acls, err := b.permissions.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload guild permissions: %w", err)
+	}
+	guildSettings, err := b.settings.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload guild settings: %w", err)
+	}
+
+	// Swap in the catalog with the event calendar already applied. This is the
+	// last step that can fail, and it fails before anything is swapped.
+	battleDiff, err := b.scheduler.ReplaceBattles(ctx, battles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply event calendar: %w", err)
+	}
+
+	// Everything is loaded; from here on nothing can fail
+	report := &commands.ReloadReport{
+		ConfigChanges:   b.config.Load().Diff(cfg),
+		Battles:         battleDiff,
+		ChangedGuildIDs: mergeGuildIDs(b.permissions.Apply(acls), b.settings.Apply(guildSettings)),
+	}
+	b.templates.Reload()
+	b.drops.Reload()
+	b.inventory.Reload()
+	b.config.Store(cfg)
+	log.SetLevel(cfg.LogLevel)
+
+	now := time.Now()
+	b.lastReload.Store(&now)
+
+	report.SyncedCommandsCount, err = b.syncSlashCommands()
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// Status returns a snapshot of the bot's runtime state.
+// Storage health is only checked when includeStorage is true.
+func (b *Bot) Status(ctx context.Context, includeStorage bool) *commands.StatusInfo {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	info := &commands.StatusInfo{
+		Version:            version.Version,
+		Commit:             version.GetCommit(),
+		BuildDate:          version.BuildDate,
+		Uptime:             time.Since(b.startedAt),
+		HeartbeatLatency:   commands.NewSession(b.session).HeartbeatLatency(),
+		Goroutines:         runtime.NumGoroutine(),
+		HeapAlloc:          memStats.HeapAlloc,
+		SysMemory:          memStats.Sys,
+		ActiveRecruitments: len(b.recruitmentManager.GetActiveRecruitments()),
+		Commands:           b.commandNames(),
+	}
+
+	if b.session.State != nil {
+		b.session.State.RLock()
+		info.GuildCount = len(b.session.State.Guilds)
+		b.session.State.RUnlock()
+	}
+
+	if lastReload := b.lastReload.Load(); lastReload != nil {
+		info.LastReload = *lastReload
+	}
+
+	if includeStorage {
+		start := time.Now()
+		info.StorageChecked = true
+		info.StorageError = b.store.Ping(ctx)
+		info.StorageLatency = time.Since(start)
+	}
+
+	return info
+}
+
+// commandNames returns the names of the registered slash commands, falling back
+// to the local definitions before the first successful registration
+func (b *Bot) commandNames() []string {
+	if registered := b.registeredCommands.Load(); registered != nil {
+		return *registered
+	}
+
+	var names []string
+	for _, slash := range b.slashCommands() {
+		names = append(names, slash.definition.Name)
+	}
+	return names
+}
+
+// mergeGuildIDs returns the sorted union of two lists of guild IDs
+func mergeGuildIDs(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var merged []string
+	for _, guildID := range append(append([]string(nil), a...), b...) {
+		if !seen[guildID] {
+			seen[guildID] = true
+			merged = append(merged, guildID)
+		}
+	}
+	sort.Strings(merged)
+	return merged
 }
@@ -0,0 +1,266 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/config"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/lifecycle"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/permissions"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+const (
+	testGuildID   = "guild1"
+	testChannelID = "channel1"
+)
+
+func newTestBot(t *testing.T) (*Bot, *commandstest.Session, *lifecycle.Manager) {
+	t.Helper()
+
+	logger := log.InitLogger("error")
+	lc := lifecycle.New(logger)
+	bot, err := New(&config.Config{DiscordToken: "test"}, logger, storage.NewMemoryStore(), lc)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	s := commandstest.NewSession()
+	err = s.State.GuildAdd(&discordgo.Guild{
+		ID: testGuildID,
+		Roles: []*discordgo.Role{
+			{ID: testGuildID, Name: "@everyone"},
+			{ID: "role_control", Name: permissions.DefaultControlRoleName},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to seed state: %v", err)
+	}
+	return bot, s, lc
+}
+
+func TestBot_HandleMessage_Authorization(t *testing.T) {
+	tests := []struct {
+		name          string
+		member        *discordgo.Member
+		content       string
+		expectDenied  bool
+		expectMessage bool
+	}{
+		{
+			name:          "general command",
+			member:        commandstest.Member("u1"),
+			content:       "!ping",
+			expectMessage: true,
+		},
+		{
+			name:          "admin command without control role",
+			member:        commandstest.Member("u1"),
+			content:       "!reload",
+			expectDenied:  true,
+			expectMessage: true,
+		},
+		{
+			name:    "plain chat is ignored",
+			member:  commandstest.Member("u1"),
+			content: "hello",
+		},
+		{
+			name:    "unknown command is ignored",
+			member:  commandstest.Member("u1"),
+			content: "!unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, s, _ := newTestBot(t)
+
+			bot.handleMessage(s, commandstest.Message(testGuildID, testChannelID, tt.member, tt.content))
+
+			messages := s.Messages()
+			if tt.expectMessage != (len(messages) > 0) {
+				t.Fatalf("sent %d messages, expected message = %t", len(messages), tt.expectMessage)
+			}
+			if !tt.expectMessage {
+				return
+			}
+			denied := strings.Contains(messages[0].Content, "don't have permission")
+			if denied != tt.expectDenied {
+				t.Errorf("denied = %t, expected %t (content: %q)", denied, tt.expectDenied, messages[0].Content)
+			}
+		})
+	}
+}
+
+func TestBot_SlashCommandsLocalized(t *testing.T) {
+	bot, _, _ := newTestBot(t)
+
+	for _, slash := range bot.slashCommands() {
+		definition := slash.definition
+		if missing := i18n.LocalizeCommand(definition); len(missing) > 0 {
+			t.Errorf("/%s is missing translations: %v", definition.Name, missing)
+			continue
+		}
+		// The English catalog must match the definitions registered as the base language
+		if got := (*definition.NameLocalizations)[discordgo.EnglishUS]; got != definition.Name {
+			t.Errorf("/%s English name = %q", definition.Name, got)
+		}
+		if got := (*definition.DescriptionLocalizations)[discordgo.EnglishUS]; got != definition.Description {
+			t.Errorf("/%s English description = %q, want %q", definition.Name, got, definition.Description)
+		}
+		checkEnglishOptions(t, "/"+definition.Name, definition.Options)
+	}
+}
+
+func TestBot_GuildOnlyCommands(t *testing.T) {
+	bot, _, _ := newTestBot(t)
+
+	// These handlers read i.Member, which is nil for interactions in DMs
+	guildOnly := map[string]bool{
+		"battlelog": true, "class": true, "data": true, "drop": true, "grid": true, "gw": true, "language": true,
+		"mats": true, "permissions": true, "recruit": true, "schedule": true, "sim": true, "template": true,
+	}
+	for _, slash := range bot.slashCommands() {
+		definition := slash.definition
+		if !guildOnly[definition.Name] {
+			continue
+		}
+		delete(guildOnly, definition.Name)
+		if definition.DMPermission == nil || *definition.DMPermission {
+			t.Errorf("/%s is allowed in DMs", definition.Name)
+		}
+	}
+	for name := range guildOnly {
+		t.Errorf("/%s is not registered", name)
+	}
+}
+
+// checkEnglishOptions compares the English localizations of options and choices with their base texts
+func checkEnglishOptions(t *testing.T, path string, options []*discordgo.ApplicationCommandOption) {
+	t.Helper()
+	for _, option := range options {
+		optionPath := path + " " + option.Name
+		if got := option.NameLocalizations[discordgo.EnglishUS]; got != option.Name {
+			t.Errorf("%s English name = %q", optionPath, got)
+		}
+		if got := option.DescriptionLocalizations[discordgo.EnglishUS]; got != option.Description {
+			t.Errorf("%s English description = %q, want %q", optionPath, got, option.Description)
+		}
+		for _, choice := range option.Choices {
+			if choice.NameLocalizations == nil {
+				continue
+			}
+			if got := choice.NameLocalizations[discordgo.EnglishUS]; got != choice.Name {
+				t.Errorf("%s choice %v English name = %q, want %q", optionPath, choice.Value, got, choice.Name)
+			}
+		}
+		checkEnglishOptions(t, optionPath, option.Options)
+	}
+}
+
+func TestBot_PermissionDeniedLocalized(t *testing.T) {
+	bot, s, _ := newTestBot(t)
+
+	i := commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("u1"), "reload")
+	i.Locale = discordgo.Japanese
+	bot.handleInteraction(s, i)
+
+	response := s.LastResponse()
+	if response == nil || response.Data == nil {
+		t.Fatal("no response")
+	}
+	if !strings.Contains(response.Data.Content, "必要なロール: `"+permissions.DefaultControlRoleName+"`") {
+		t.Errorf("content = %q, want the reason in Japanese", response.Data.Content)
+	}
+}
+
+func TestBot_HandleInteraction_Autocomplete(t *testing.T) {
+	bot, s, _ := newTestBot(t)
+
+	tests := []struct {
+		name        string
+		command     string
+		option      *discordgo.ApplicationCommandInteractionDataOption
+		wantChoices bool
+	}{
+		{
+			name:        "battle",
+			command:     "battle",
+			option:      commandstest.Focused(commandstest.StringOption("id", "pbhl")),
+			wantChoices: true,
+		},
+		{
+			name:        "recruit start",
+			command:     "recruit",
+			option:      commandstest.Subcommand("start", commandstest.Focused(commandstest.StringOption("battle", "baha"))),
+			wantChoices: true,
+		},
+		{
+			name:    "command the member cannot use",
+			command: "schedule",
+			option:  commandstest.Subcommand("remove", commandstest.Focused(commandstest.StringOption("id", ""))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot.handleInteraction(s, commandstest.Autocomplete(testGuildID, testChannelID, commandstest.Member("u1"), tt.command, tt.option))
+
+			response := s.LastResponse()
+			if response == nil || response.Type != discordgo.InteractionApplicationCommandAutocompleteResult {
+				t.Fatalf("response = %+v, want an autocomplete result", response)
+			}
+			if got := len(response.Data.Choices) > 0; got != tt.wantChoices {
+				t.Errorf("choices = %+v, want choices %t", response.Data.Choices, tt.wantChoices)
+			}
+		})
+	}
+}
+
+func TestBot_HandleInteraction_Recruit(t *testing.T) {
+	bot, s, _ := newTestBot(t)
+
+	bot.handleInteraction(s, commandstest.SlashCommand(testGuildID, testChannelID, commandstest.Member("host"), "recruit",
+		commandstest.Subcommand("start", commandstest.StringOption("battle", "baha_hl"))))
+
+	response := s.LastResponse()
+	if response == nil || len(response.Data.Components) == 0 {
+		t.Fatalf("recruit response = %+v, expected buttons", response)
+	}
+
+	join := commandstest.Buttons(response.Data.Components)[0]
+	bot.handleInteraction(s, commandstest.ButtonClick(testGuildID, testChannelID, commandstest.Member("alice"), join.CustomID))
+
+	response = s.LastResponse()
+	if response.Type != discordgo.InteractionResponseUpdateMessage {
+		t.Fatalf("join response type = %v, expected UpdateMessage", response.Type)
+	}
+	if got := commandstest.FieldValue(response.Data.Embeds[0], "Players"); got != "2/18" {
+		t.Errorf("Players = %q, expected 2/18", got)
+	}
+
+	// Unrelated components are ignored
+	bot.handleInteraction(s, commandstest.ButtonClick(testGuildID, testChannelID, commandstest.Member("alice"), "other:button"))
+	if got := len(s.Responses()); got != 2 {
+		t.Errorf("responses = %d, expected 2", got)
+	}
+}
+
+func TestBot_RefusesEventsDuringShutdown(t *testing.T) {
+	bot, s, lc := newTestBot(t)
+
+	if err := lc.Shutdown(time.Second); err != nil {
+		t.Fatalf("Shutdown() unexpected error: %v", err)
+	}
+
+	bot.handleMessage(s, commandstest.Message(testGuildID, testChannelID, commandstest.Member("u1"), "!ping"))
+	if got := len(s.Messages()); got != 0 {
+		t.Errorf("sent %d messages during shutdown, expected 0", got)
+	}
+}
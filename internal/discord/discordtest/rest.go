@@ -0,0 +1,262 @@
+package discordtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleREST routes REST API requests by method and path
+func (s *Server) handleREST(w http.ResponseWriter, r *http.Request) {
+	prefix := "/api/v" + discordgo.APIVersion + "/"
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/"), "/")
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	// GET /gateway, GET /gateway/bot
+	case path[0] == "gateway" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{
+			"url":    "ws" + strings.TrimPrefix(s.http.URL, "http") + "/gateway",
+			"shards": 1,
+		})
+
+	// POST /channels/{channel}/messages
+	case len(path) == 3 && path[0] == "channels" && path[2] == "messages" && r.Method == http.MethodPost:
+		s.createMessage(w, path[1], body)
+
+	// PATCH /channels/{channel}/messages/{message}
+	case len(path) == 4 && path[0] == "channels" && path[2] == "messages" && r.Method == http.MethodPatch:
+		s.editMessage(w, path[1], path[3], body)
+
+	// POST /interactions/{interaction}/{token}/callback
+	case len(path) == 4 && path[0] == "interactions" && path[3] == "callback" && r.Method == http.MethodPost:
+		s.respondInteraction(w, path[1], body)
+
+	// GET /webhooks/{application}/{token}/messages/@original
+	case len(path) == 5 && path[0] == "webhooks" && path[3] == "messages" && r.Method == http.MethodGet:
+		s.getInteractionResponse(w, path[2])
+
+	// PATCH /webhooks/{application}/{token}/messages/@original
+	case len(path) == 5 && path[0] == "webhooks" && path[3] == "messages" && r.Method == http.MethodPatch:
+		s.editInteractionResponse(w, path[2], body)
+
+	// PUT, GET, POST /applications/{application}/commands
+	case len(path) == 3 && path[0] == "applications" && path[2] == "commands":
+		s.handleCommands(w, r.Method, path[1], body)
+
+	// DELETE /applications/{application}/commands/{command}
+	case len(path) == 4 && path[0] == "applications" && path[2] == "commands" && r.Method == http.MethodDelete:
+		s.deleteCommand(w, path[3])
+
+	default:
+		s.mu.Lock()
+		s.unhandled = append(s.unhandled, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		writeJSON(w, http.StatusNotFound, map[string]any{"code": 0, "message": "404: Not Found"})
+	}
+}
+
+// createMessage stores a new channel message
+func (s *Server) createMessage(w http.ResponseWriter, channelID string, body []byte) {
+	var message discordgo.Message
+	if err := json.Unmarshal(body, &message); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"message": err.Error()})
+		return
+	}
+	message.ID = s.newID()
+	message.ChannelID = channelID
+	message.Author = &discordgo.User{ID: BotUserID, Bot: true}
+	message.Timestamp = time.Now()
+
+	s.mu.Lock()
+	s.messages = append(s.messages, &message)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, &message)
+}
+
+// editMessage applies an edit to a stored channel message
+func (s *Server) editMessage(w http.ResponseWriter, channelID, messageID string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, message := range s.messages {
+		if message.ID == messageID && message.ChannelID == channelID {
+			if err := applyEdit(message, body); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"message": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, message)
+			return
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]any{"code": 10008, "message": "Unknown Message"})
+}
+
+// respondInteraction stores an interaction callback
+func (s *Server) respondInteraction(w http.ResponseWriter, interactionID string, body []byte) {
+	var callback struct {
+		Type discordgo.InteractionResponseType `json:"type"`
+		Data json.RawMessage                   `json:"data"`
+	}
+	if err := json.Unmarshal(body, &callback); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"message": err.Error()})
+		return
+	}
+
+	message := &discordgo.Message{ID: s.newID(), Author: &discordgo.User{ID: BotUserID, Bot: true}}
+	if len(callback.Data) > 0 && string(callback.Data) != "null" {
+		if err := json.Unmarshal(callback.Data, message); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"message": err.Error()})
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.interactionResponses = append(s.interactionResponses, &InteractionResponse{
+		InteractionID: interactionID,
+		Type:          callback.Type,
+		Message:       message,
+	})
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getInteractionResponse returns the original response of an interaction
+func (s *Server) getInteractionResponse(w http.ResponseWriter, token string) {
+	interactionID := strings.TrimPrefix(token, "token-")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, response := range s.interactionResponses {
+		if response.InteractionID == interactionID {
+			writeJSON(w, http.StatusOK, response.Message)
+			return
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]any{"code": 10008, "message": "Unknown Message"})
+}
+
+// editInteractionResponse applies an edit to the original response of an interaction.
+// Tokens are issued as "token-<interaction id>" by SendInteraction.
+func (s *Server) editInteractionResponse(w http.ResponseWriter, token string, body []byte) {
+	interactionID := strings.TrimPrefix(token, "token-")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, response := range s.interactionResponses {
+		if response.InteractionID == interactionID {
+			if err := applyEdit(response.Message, body); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"message": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, response.Message)
+			return
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]any{"code": 10015, "message": "Unknown Webhook"})
+}
+
+// handleCommands lists, creates or bulk overwrites global application commands
+func (s *Server) handleCommands(w http.ResponseWriter, method, applicationID string, body []byte) {
+	switch method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.Commands())
+
+	case http.MethodPut:
+		var commands []*discordgo.ApplicationCommand
+		if err := json.Unmarshal(body, &commands); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"message": err.Error()})
+			return
+		}
+		for _, command := range commands {
+			command.ID = s.newID()
+			command.ApplicationID = applicationID
+		}
+
+		s.mu.Lock()
+		s.commands = commands
+		s.commandSyncs++
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, commands)
+
+	case http.MethodPost:
+		var command discordgo.ApplicationCommand
+		if err := json.Unmarshal(body, &command); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"message": err.Error()})
+			return
+		}
+		command.ID = s.newID()
+		command.ApplicationID = applicationID
+
+		s.mu.Lock()
+		s.commands = append(s.commands, &command)
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusCreated, &command)
+
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"message": "405: Method Not Allowed"})
+	}
+}
+
+// deleteCommand removes a global application command
+func (s *Server) deleteCommand(w http.ResponseWriter, commandID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, command := range s.commands {
+		if command.ID == commandID {
+			s.commands = append(s.commands[:i], s.commands[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]any{"code": 10063, "message": "Unknown application command"})
+}
+
+// applyEdit updates the fields of message that are present in an edit body
+func applyEdit(message *discordgo.Message, body []byte) error {
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(body, &present); err != nil {
+		return fmt.Errorf("invalid edit: %w", err)
+	}
+	var edit discordgo.Message
+	if err := json.Unmarshal(body, &edit); err != nil {
+		return fmt.Errorf("invalid edit: %w", err)
+	}
+
+	if _, ok := present["content"]; ok {
+		message.Content = edit.Content
+	}
+	if _, ok := present["embeds"]; ok {
+		message.Embeds = edit.Embeds
+	}
+	if _, ok := present["components"]; ok {
+		message.Components = edit.Components
+	}
+	now := time.Now()
+	message.EditedTimestamp = &now
+	return nil
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,443 @@
+// Package discordtest runs a local stand-in for the Discord gateway and REST API
+// so that a real discordgo session, and the bot built on it, can be exercised
+// end to end without network access.
+//
+// The server implements only what the bot uses: the gateway handshake
+// (hello, identify, resume, heartbeats and dispatches), channel message
+// create/edit, interaction callbacks and response edits, and application
+// command registration.
+package discordtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+// heartbeatInterval is announced in the hello packet, in milliseconds
+const heartbeatInterval = 45000
+
+// BotUserID is the user and application ID of the bot on the test server
+const BotUserID = "100000000000000001"
+
+// InteractionResponse is an interaction callback received by the server.
+// The response data is decoded as a message so that components survive the round trip.
+type InteractionResponse struct {
+	InteractionID string
+	Type          discordgo.InteractionResponseType
+	Message       *discordgo.Message
+}
+
+// Identify is the part of an identify payload the server records.
+// Presence is left out since discordgo encodes activity timestamps differently
+// from how it decodes them.
+type Identify struct {
+	Token      string                       `json:"token"`
+	Intents    discordgo.Intent             `json:"intents"`
+	Properties discordgo.IdentifyProperties `json:"properties"`
+	Compress   bool                         `json:"compress"`
+	Shard      *[2]int                      `json:"shard,omitempty"`
+}
+
+// Server is a local Discord gateway and REST API.
+// Create it with NewServer, which also points discordgo at it for the duration of the test.
+type Server struct {
+	t    testing.TB
+	http *httptest.Server
+
+	nextID    atomic.Int64
+	sessionID string
+
+	mu                   sync.Mutex
+	conns                map[*gatewayConn]struct{}
+	sequence             int64
+	guilds               []*discordgo.Guild
+	identifies           []Identify
+	resumes              int
+	commands             []*discordgo.ApplicationCommand
+	commandSyncs         int
+	messages             []*discordgo.Message
+	interactionResponses []*InteractionResponse
+	unhandled            []string
+}
+
+// gatewayConn is one websocket connection from a client
+type gatewayConn struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+}
+
+// NewServer starts a local Discord stand-in and redirects discordgo's endpoints to it.
+// The server is closed and the endpoints restored when the test finishes.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		t:         t,
+		sessionID: "test-session",
+		conns:     make(map[*gatewayConn]struct{}),
+	}
+	s.nextID.Store(200000000000000000)
+
+	mux := http.NewServeMux()
+	// discordgo appends a trailing slash to the gateway URL
+	mux.HandleFunc("/gateway/", s.handleGateway)
+	mux.HandleFunc("/api/", s.handleREST)
+	s.http = httptest.NewServer(mux)
+
+	restore := redirectEndpoints(s.http.URL + "/")
+	t.Cleanup(func() {
+		s.DropConnections()
+		s.http.Close()
+		restore()
+	})
+
+	return s
+}
+
+// redirectEndpoints points discordgo's base endpoints at base and returns a
+// function that restores the previous values.
+// The endpoint helper functions build URLs from these variables on each call,
+// so overriding the bases is enough.
+func redirectEndpoints(base string) func() {
+	endpoints := []*string{
+		&discordgo.EndpointDiscord,
+		&discordgo.EndpointAPI,
+		&discordgo.EndpointGuilds,
+		&discordgo.EndpointChannels,
+		&discordgo.EndpointUsers,
+		&discordgo.EndpointGateway,
+		&discordgo.EndpointGatewayBot,
+		&discordgo.EndpointWebhooks,
+		&discordgo.EndpointApplications,
+	}
+	saved := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		saved[i] = *endpoint
+	}
+
+	api := base + "api/v" + discordgo.APIVersion + "/"
+	discordgo.EndpointDiscord = base
+	discordgo.EndpointAPI = api
+	discordgo.EndpointGuilds = api + "guilds/"
+	discordgo.EndpointChannels = api + "channels/"
+	discordgo.EndpointUsers = api + "users/"
+	discordgo.EndpointGateway = api + "gateway"
+	discordgo.EndpointGatewayBot = discordgo.EndpointGateway + "/bot"
+	discordgo.EndpointWebhooks = api + "webhooks/"
+	discordgo.EndpointApplications = api + "applications"
+
+	return func() {
+		for i, endpoint := range endpoints {
+			*endpoint = saved[i]
+		}
+	}
+}
+
+// newID returns a fresh snowflake-like ID
+func (s *Server) newID() string {
+	return strconv.FormatInt(s.nextID.Add(1), 10)
+}
+
+// AddGuild makes a guild available to clients.
+// Guilds are listed in READY and sent as GUILD_CREATE after it, so add them before connecting.
+func (s *Server) AddGuild(guild *discordgo.Guild) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.guilds = append(s.guilds, guild)
+}
+
+// Identifies returns the identify payloads received, one per new session
+func (s *Server) Identifies() []Identify {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Identify(nil), s.identifies...)
+}
+
+// Resumes returns how many times a client resumed its session
+func (s *Server) Resumes() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resumes
+}
+
+// Connections returns the number of open gateway connections
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// Commands returns the currently registered global application commands
+func (s *Server) Commands() []*discordgo.ApplicationCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.ApplicationCommand(nil), s.commands...)
+}
+
+// CommandSyncs returns how many times the global commands were bulk overwritten
+func (s *Server) CommandSyncs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commandSyncs
+}
+
+// Messages returns the channel messages created through the REST API, with edits applied
+func (s *Server) Messages() []*discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Copy the messages so that later edits do not race with the caller
+	messages := make([]*discordgo.Message, len(s.messages))
+	for i, message := range s.messages {
+		copied := *message
+		messages[i] = &copied
+	}
+	return messages
+}
+
+// InteractionResponses returns the interaction callbacks received, with edits applied
+func (s *Server) InteractionResponses() []*InteractionResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Copy the responses so that later edits do not race with the caller
+	responses := make([]*InteractionResponse, len(s.interactionResponses))
+	for i, response := range s.interactionResponses {
+		copied := *response
+		if response.Message != nil {
+			message := *response.Message
+			copied.Message = &message
+		}
+		responses[i] = &copied
+	}
+	return responses
+}
+
+// Unhandled returns the REST requests the server did not recognize, as "METHOD path"
+func (s *Server) Unhandled() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.unhandled...)
+}
+
+// SendMessage dispatches MESSAGE_CREATE for a message from member in a guild channel
+func (s *Server) SendMessage(guildID, channelID string, member *discordgo.Member, content string) *discordgo.Message {
+	message := &discordgo.Message{
+		ID:        s.newID(),
+		GuildID:   guildID,
+		ChannelID: channelID,
+		Author:    member.User,
+		Member:    member,
+		Content:   content,
+		Timestamp: time.Now(),
+	}
+	s.Dispatch("MESSAGE_CREATE", message)
+	return message
+}
+
+// SendInteraction dispatches INTERACTION_CREATE.
+// The interaction ID, token and application ID are filled in when empty.
+func (s *Server) SendInteraction(interaction *discordgo.Interaction) *discordgo.Interaction {
+	if interaction.ID == "" {
+		interaction.ID = s.newID()
+	}
+	if interaction.Token == "" {
+		interaction.Token = "token-" + interaction.ID
+	}
+	if interaction.AppID == "" {
+		interaction.AppID = BotUserID
+	}
+	interaction.Version = 1
+	s.Dispatch("INTERACTION_CREATE", interaction)
+	return interaction
+}
+
+// Dispatch sends a gateway event to every connected client
+func (s *Server) Dispatch(eventType string, data any) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		s.t.Errorf("discordtest: failed to encode %s: %v", eventType, err)
+		return
+	}
+
+	s.mu.Lock()
+	conns := make([]*gatewayConn, 0, len(s.conns))
+	for conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		if err := s.dispatch(conn, eventType, raw); err != nil {
+			s.t.Logf("discordtest: failed to dispatch %s: %v", eventType, err)
+		}
+	}
+}
+
+// DropConnections closes every gateway connection without a close handshake,
+// as a network failure would
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = make(map[*gatewayConn]struct{})
+	s.mu.Unlock()
+
+	for conn := range conns {
+		conn.conn.UnderlyingConn().Close()
+	}
+}
+
+// WaitFor polls cond until it holds, failing t after timeout
+func WaitFor(t testing.TB, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("discordtest: timed out after %s waiting for %s", timeout, what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// gatewayPayload is a gateway packet
+type gatewayPayload struct {
+	Op       int             `json:"op"`
+	Data     json.RawMessage `json:"d"`
+	Sequence *int64          `json:"s,omitempty"`
+	Type     string          `json:"t,omitempty"`
+}
+
+// upgrader accepts websocket connections from any origin
+var upgrader = websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+
+// handleGateway runs the gateway protocol for one connection
+func (s *Server) handleGateway(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.t.Logf("discordtest: websocket upgrade failed: %v", err)
+		return
+	}
+	conn := &gatewayConn{conn: ws}
+	defer ws.Close()
+
+	hello, _ := json.Marshal(map[string]int{"heartbeat_interval": heartbeatInterval})
+	if err := conn.write(gatewayPayload{Op: 10, Data: hello}); err != nil {
+		return
+	}
+
+	for {
+		var payload gatewayPayload
+		if err := ws.ReadJSON(&payload); err != nil {
+			s.removeConn(conn)
+			return
+		}
+
+		switch payload.Op {
+		case 1: // Heartbeat
+			err = conn.write(gatewayPayload{Op: 11})
+		case 2: // Identify
+			err = s.handleIdentify(conn, payload.Data)
+		case 6: // Resume
+			err = s.handleResume(conn)
+		}
+		if err != nil {
+			s.t.Logf("discordtest: closing gateway connection: %v", err)
+			s.removeConn(conn)
+			return
+		}
+	}
+}
+
+// handleIdentify records the identify payload and starts a new session
+func (s *Server) handleIdentify(conn *gatewayConn, data json.RawMessage) error {
+	var identify Identify
+	if err := json.Unmarshal(data, &identify); err != nil {
+		return fmt.Errorf("invalid identify payload: %w", err)
+	}
+
+	s.mu.Lock()
+	s.identifies = append(s.identifies, identify)
+	guilds := append([]*discordgo.Guild(nil), s.guilds...)
+	s.mu.Unlock()
+
+	unavailable := make([]map[string]any, 0, len(guilds))
+	for _, guild := range guilds {
+		unavailable = append(unavailable, map[string]any{"id": guild.ID, "unavailable": true})
+	}
+	ready, _ := json.Marshal(map[string]any{
+		"v":          9,
+		"session_id": s.sessionID,
+		"user":       &discordgo.User{ID: BotUserID, Username: "gbf-bot", Bot: true},
+		"guilds":     unavailable,
+		"application": map[string]string{
+			"id": BotUserID,
+		},
+	})
+
+	// The connection only receives dispatches once READY has been sent
+	if err := s.dispatch(conn, "READY", ready); err != nil {
+		return err
+	}
+	s.addConn(conn)
+
+	for _, guild := range guilds {
+		raw, _ := json.Marshal(guild)
+		if err := s.dispatch(conn, "GUILD_CREATE", raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleResume acknowledges a resumed session
+func (s *Server) handleResume(conn *gatewayConn) error {
+	s.mu.Lock()
+	s.resumes++
+	s.mu.Unlock()
+
+	if err := s.dispatch(conn, "RESUMED", json.RawMessage(`{}`)); err != nil {
+		return err
+	}
+	s.addConn(conn)
+	return nil
+}
+
+// dispatch sends an event with the next sequence number to one connection
+func (s *Server) dispatch(conn *gatewayConn, eventType string, data json.RawMessage) error {
+	s.mu.Lock()
+	s.sequence++
+	sequence := s.sequence
+	s.mu.Unlock()
+
+	return conn.write(gatewayPayload{Op: 0, Type: eventType, Sequence: &sequence, Data: data})
+}
+
+func (s *Server) addConn(conn *gatewayConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conns[conn] = struct{}{}
+}
+
+func (s *Server) removeConn(conn *gatewayConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+}
+
+// write sends a packet, serializing writers as gorilla/websocket requires
+func (c *gatewayConn) write(payload gatewayPayload) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.conn.WriteJSON(payload)
+}
@@ -0,0 +1,184 @@
+package discord
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/commands/commandstest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/config"
+	"github.com/varubogu/gbf_discord_bot_go/internal/discord/discordtest"
+	"github.com/varubogu/gbf_discord_bot_go/internal/lifecycle"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/permissions"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+// waitTimeout bounds how long the integration test waits for each step
+const waitTimeout = 5 * time.Second
+
+// startTestBot runs the real bot against a local Discord stand-in until the test ends
+func startTestBot(t *testing.T) (*Bot, *discordtest.Server) {
+	t.Helper()
+
+	server := discordtest.NewServer(t)
+	server.AddGuild(&discordgo.Guild{
+		ID:   testGuildID,
+		Name: "Test Guild",
+		Roles: []*discordgo.Role{
+			{ID: testGuildID, Name: "@everyone"},
+			{ID: "role_control", Name: permissions.DefaultControlRoleName},
+		},
+	})
+
+	logger := log.InitLogger("error")
+	lc := lifecycle.New(logger)
+	bot, err := New(&config.Config{DiscordToken: "test-token"}, logger, storage.NewMemoryStore(), lc)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan error, 1)
+	go func() {
+		started <- bot.Start(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		if err := <-started; err != nil {
+			t.Errorf("Start() unexpected error: %v", err)
+		}
+		if err := lc.Shutdown(waitTimeout); err != nil {
+			t.Errorf("Shutdown() unexpected error: %v", err)
+		}
+	})
+
+	discordtest.WaitFor(t, waitTimeout, "slash command registration", func() bool {
+		return server.CommandSyncs() > 0
+	})
+	return bot, server
+}
+
+func TestBot_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping gateway integration test in short mode")
+	}
+
+	bot, server := startTestBot(t)
+
+	t.Run("identify requests the intents prefix commands need", func(t *testing.T) {
+		identifies := server.Identifies()
+		if len(identifies) != 1 {
+			t.Fatalf("identifies = %d, expected 1", len(identifies))
+		}
+		if identifies[0].Token != "Bot test-token" {
+			t.Errorf("token = %q, expected %q", identifies[0].Token, "Bot test-token")
+		}
+		required := discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent
+		if identifies[0].Intents&required != required {
+			t.Errorf("intents = %d, missing some of %d", identifies[0].Intents, required)
+		}
+	})
+
+	t.Run("slash commands are registered", func(t *testing.T) {
+		registered := make(map[string]bool)
+		for _, command := range server.Commands() {
+			registered[command.Name] = true
+		}
+		for _, slash := range bot.slashCommands() {
+			if !registered[slash.definition.Name] {
+				t.Errorf("command %q was not registered", slash.definition.Name)
+			}
+		}
+	})
+
+	t.Run("prefix command round trip", func(t *testing.T) {
+		server.SendMessage(testGuildID, testChannelID, commandstest.Member("u1"), "!ping")
+
+		discordtest.WaitFor(t, waitTimeout, "edited ping reply", func() bool {
+			messages := server.Messages()
+			return len(messages) == 1 && strings.Contains(messages[0].Content, "Gateway")
+		})
+	})
+
+	t.Run("admin command denied without control role", func(t *testing.T) {
+		before := len(server.Messages())
+		server.SendMessage(testGuildID, testChannelID, commandstest.Member("u1"), "!reload")
+
+		discordtest.WaitFor(t, waitTimeout, "permission denied reply", func() bool {
+			messages := server.Messages()
+			return len(messages) > before && strings.Contains(messages[len(messages)-1].Content, "don't have permission")
+		})
+	})
+
+	t.Run("slash command and button round trip", func(t *testing.T) {
+		server.SendInteraction(&discordgo.Interaction{
+			Type:      discordgo.InteractionApplicationCommand,
+			GuildID:   testGuildID,
+			ChannelID: testChannelID,
+			Member:    commandstest.Member("host"),
+			Data: discordgo.ApplicationCommandInteractionData{
+				Name: "recruit",
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					commandstest.Subcommand("start", commandstest.StringOption("battle", "baha_hl")),
+				},
+			},
+		})
+
+		var buttons []discordgo.Button
+		discordtest.WaitFor(t, waitTimeout, "recruitment message", func() bool {
+			responses := server.InteractionResponses()
+			if len(responses) == 0 {
+				return false
+			}
+			buttons = commandstest.Buttons(responses[0].Message.Components)
+			return len(buttons) > 0
+		})
+
+		server.SendInteraction(&discordgo.Interaction{
+			Type:      discordgo.InteractionMessageComponent,
+			GuildID:   testGuildID,
+			ChannelID: testChannelID,
+			Member:    commandstest.Member("alice"),
+			Data: discordgo.MessageComponentInteractionData{
+				CustomID:      buttons[0].CustomID,
+				ComponentType: discordgo.ButtonComponent,
+			},
+		})
+
+		discordtest.WaitFor(t, waitTimeout, "updated recruitment message", func() bool {
+			responses := server.InteractionResponses()
+			if len(responses) < 2 {
+				return false
+			}
+			last := responses[len(responses)-1]
+			return last.Type == discordgo.InteractionResponseUpdateMessage &&
+				len(last.Message.Embeds) > 0 &&
+				commandstest.FieldValue(last.Message.Embeds[0], "Players") == "2/18"
+		})
+	})
+
+	t.Run("session resumes after connection loss", func(t *testing.T) {
+		server.DropConnections()
+
+		discordtest.WaitFor(t, waitTimeout, "resumed session", func() bool {
+			return server.Resumes() == 1 && server.Connections() == 1
+		})
+		if got := len(server.Identifies()); got != 1 {
+			t.Errorf("identifies after reconnect = %d, expected 1 (resume, not a new session)", got)
+		}
+
+		before := len(server.Messages())
+		server.SendMessage(testGuildID, testChannelID, commandstest.Member("u1"), "!ping")
+		discordtest.WaitFor(t, waitTimeout, "ping reply after reconnect", func() bool {
+			return len(server.Messages()) > before
+		})
+	})
+
+	if unhandled := server.Unhandled(); len(unhandled) > 0 {
+		t.Errorf("unhandled REST requests: %v", unhandled)
+	}
+}
@@ -0,0 +1,308 @@
+package drops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+// dropsCollection is the storage collection holding each guild's drop tallies
+const dropsCollection = "drop_logs"
+
+const (
+	// MaxItemLength limits the length of an item name
+	MaxItemLength = 50
+	// maxItemsPerTally limits the different items logged for a member's battle
+	maxItemsPerTally = 50
+	// ConfidenceZ is the z-score of the 95% confidence intervals of drop rates
+	ConfidenceZ = 1.96
+)
+
+// ErrNothingToLog is returned when a log entry has neither runs nor drops
+var ErrNothingToLog = errors.New("log at least one run or drop")
+
+// ExportColumns are the columns of the CSV export, one row per member, battle and item
+var ExportColumns = []string{"user_id", "battle_id", "runs", "item", "drops"}
+
+// Tally is a member's runs of a battle and the drops logged in them
+type Tally struct {
+	UserID   string `json:"user_id"`
+	BattleID string `json:"battle_id"`
+	Runs     int    `json:"runs"`
+	// Drops counts the drops of each item by normalized name
+	Drops     map[string]int `json:"drops,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// GuildDrops is the stored set of a guild's tallies
+type GuildDrops struct {
+	GuildID string  `json:"guild_id"`
+	Tallies []Tally `json:"tallies"`
+}
+
+// ItemStats is the observed drop rate of an item with its 95% confidence interval
+type ItemStats struct {
+	Item  string
+	Drops int
+	// Rate is drops per run; Low and High bound the chance that a run drops the item
+	Rate, Low, High float64
+}
+
+// BattleStats aggregates the tallies of one battle
+type BattleStats struct {
+	BattleID string
+	Runs     int
+	// Members is the number of members who logged runs or drops
+	Members int
+	// Items are ordered by drops, most first
+	Items []ItemStats
+}
+
+// Manager records drops per guild, caching tallies loaded from storage
+type Manager struct {
+	store storage.Store
+
+	// writeMu serializes changes so that concurrent logs are not lost
+	writeMu sync.Mutex
+
+	mu     sync.RWMutex
+	guilds map[string]*GuildDrops
+}
+
+// NewManager creates a new drop manager
+func NewManager(store storage.Store) *Manager {
+	return &Manager{
+		store:  store,
+		guilds: make(map[string]*GuildDrops),
+	}
+}
+
+// NormalizeItem returns the name items are counted under, e.g. "gold bar" for " Gold  Bar"
+func NormalizeItem(item string) string {
+	return strings.ToLower(strings.Join(strings.Fields(item), " "))
+}
+
+// load returns the cached tallies of a guild, loading them from storage on first use.
+// The returned value must not be modified.
+func (m *Manager) load(ctx context.Context, guildID string) (*GuildDrops, error) {
+	m.mu.RLock()
+	guild, cached := m.guilds[guildID]
+	m.mu.RUnlock()
+	if cached {
+		return guild, nil
+	}
+
+	guild = &GuildDrops{GuildID: guildID}
+	err := m.store.Get(ctx, dropsCollection, guildID, guild)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load drops for guild %s: %w", guildID, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.guilds[guildID] = guild
+	return guild, nil
+}
+
+// Log adds runs of a battle and count drops of item to a member's tally and
+// returns the updated tally. Runs and drops are logged separately so that several
+// items dropped in the same runs can be logged with runs of 0.
+func (m *Manager) Log(ctx context.Context, guildID, userID, battleID, item string, runs, count int) (Tally, error) {
+	item = NormalizeItem(item)
+	switch {
+	case runs < 0 || count < 0:
+		return Tally{}, errors.New("runs and drops cannot be negative")
+	case runs == 0 && count == 0:
+		return Tally{}, ErrNothingToLog
+	case count > 0 && item == "":
+		return Tally{}, errors.New("name the item that dropped")
+	case utf8.RuneCountInString(item) > MaxItemLength:
+		return Tally{}, fmt.Errorf("item names are limited to %d characters", MaxItemLength)
+	}
+
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	guild, err := m.load(ctx, guildID)
+	if err != nil {
+		return Tally{}, err
+	}
+	next := &GuildDrops{GuildID: guildID, Tallies: make([]Tally, len(guild.Tallies))}
+	copy(next.Tallies, guild.Tallies)
+
+	index := -1
+	for n, tally := range next.Tallies {
+		if tally.UserID == userID && tally.BattleID == battleID {
+			index = n
+			break
+		}
+	}
+	if index < 0 {
+		next.Tallies = append(next.Tallies, Tally{UserID: userID, BattleID: battleID})
+		index = len(next.Tallies) - 1
+	}
+
+	tally := &next.Tallies[index]
+	tally.Drops = maps.Clone(tally.Drops)
+	if count > 0 {
+		if tally.Drops == nil {
+			tally.Drops = make(map[string]int)
+		}
+		if _, exists := tally.Drops[item]; !exists && len(tally.Drops) >= maxItemsPerTally {
+			return Tally{}, fmt.Errorf("up to %d different items can be logged for a battle", maxItemsPerTally)
+		}
+		tally.Drops[item] += count
+	}
+	tally.Runs += runs
+	tally.UpdatedAt = time.Now()
+
+	if err := m.store.Put(ctx, dropsCollection, guildID, next); err != nil {
+		return Tally{}, fmt.Errorf("failed to save drops for guild %s: %w", guildID, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.guilds[guildID] = next
+	return *tally, nil
+}
+
+// Tallies returns copies of a guild's tallies ordered by battle and member
+func (m *Manager) Tallies(ctx context.Context, guildID string) ([]Tally, error) {
+	guild, err := m.load(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	tallies := make([]Tally, len(guild.Tallies))
+	for n, tally := range guild.Tallies {
+		tally.Drops = maps.Clone(tally.Drops)
+		tallies[n] = tally
+	}
+	sort.Slice(tallies, func(i, j int) bool {
+		if tallies[i].BattleID != tallies[j].BattleID {
+			return tallies[i].BattleID < tallies[j].BattleID
+		}
+		return tallies[i].UserID < tallies[j].UserID
+	})
+	return tallies, nil
+}
+
+// Reload drops the cached tallies so that they are read from storage again
+func (m *Manager) Reload() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.guilds = make(map[string]*GuildDrops)
+}
+
+// Summarize aggregates tallies per battle, ordered by runs, most first. A
+// non-empty userID only counts that member's tallies.
+func Summarize(tallies []Tally, userID string) []BattleStats {
+	battles := make(map[string]*BattleStats)
+	drops := make(map[string]map[string]int)
+	for _, tally := range tallies {
+		if userID != "" && tally.UserID != userID {
+			continue
+		}
+		stats, exists := battles[tally.BattleID]
+		if !exists {
+			stats = &BattleStats{BattleID: tally.BattleID}
+			battles[tally.BattleID] = stats
+			drops[tally.BattleID] = make(map[string]int)
+		}
+		stats.Runs += tally.Runs
+		stats.Members++
+		for item, count := range tally.Drops {
+			drops[tally.BattleID][item] += count
+		}
+	}
+
+	summary := make([]BattleStats, 0, len(battles))
+	for battleID, stats := range battles {
+		for item, count := range drops[battleID] {
+			stats.Items = append(stats.Items, itemStats(item, count, stats.Runs))
+		}
+		sort.Slice(stats.Items, func(i, j int) bool {
+			if stats.Items[i].Drops != stats.Items[j].Drops {
+				return stats.Items[i].Drops > stats.Items[j].Drops
+			}
+			return stats.Items[i].Item < stats.Items[j].Item
+		})
+		summary = append(summary, *stats)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Runs != summary[j].Runs {
+			return summary[i].Runs > summary[j].Runs
+		}
+		return summary[i].BattleID < summary[j].BattleID
+	})
+	return summary
+}
+
+// itemStats computes the drop rate of an item over runs. The interval treats
+// each run as dropping the item or not, so extra drops in a run do not narrow it.
+func itemStats(item string, drops, runs int) ItemStats {
+	stats := ItemStats{Item: item, Drops: drops}
+	if runs > 0 {
+		stats.Rate = float64(drops) / float64(runs)
+		stats.Low, stats.High = WilsonInterval(min(drops, runs), runs, ConfidenceZ)
+	}
+	return stats
+}
+
+// WilsonInterval returns the Wilson score interval of a proportion of successes
+// in trials. Unlike the normal approximation it stays within [0, 1] and suits
+// rare drops seen a few times.
+func WilsonInterval(successes, trials int, z float64) (low, high float64) {
+	if trials <= 0 {
+		return 0, 1
+	}
+	n := float64(trials)
+	p := float64(successes) / n
+	z2 := z * z
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := z / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	return max(0, center-margin), min(1, center+margin)
+}
+
+// ExportRows returns the tallies as CSV rows with ExportColumns as the header.
+// Runs without drops have a row with an empty item. Text cells are escaped
+// against formula injection.
+func ExportRows(tallies []Tally) [][]string {
+	rows := [][]string{ExportColumns}
+	for _, tally := range tallies {
+		runs := strconv.Itoa(tally.Runs)
+		userID, battleID := exportCell(tally.UserID), exportCell(tally.BattleID)
+		if len(tally.Drops) == 0 {
+			rows = append(rows, []string{userID, battleID, runs, "", "0"})
+			continue
+		}
+		items := make([]string, 0, len(tally.Drops))
+		for item := range tally.Drops {
+			items = append(items, item)
+		}
+		sort.Strings(items)
+		for _, item := range items {
+			rows = append(rows, []string{userID, battleID, runs, exportCell(item), strconv.Itoa(tally.Drops[item])})
+		}
+	}
+	return rows
+}
+
+// exportCell prefixes text that a spreadsheet would run as a formula with an
+// apostrophe, so that an item name such as "=HYPERLINK(...)" is shown as typed
+func exportCell(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,150 @@
+package drops
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+func TestManager_Log(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	m := NewManager(store)
+
+	if _, err := m.Log(ctx, "guild1", "alice", "akasha_hl", "", 100, 0); err != nil {
+		t.Fatalf("Log() runs: %v", err)
+	}
+	tally, err := m.Log(ctx, "guild1", "alice", "akasha_hl", " Gold  Bar", 0, 1)
+	if err != nil {
+		t.Fatalf("Log() drop: %v", err)
+	}
+	if tally.Runs != 100 || tally.Drops["gold bar"] != 1 {
+		t.Errorf("Log() = %+v, expected 100 runs and a gold bar", tally)
+	}
+	if _, err := m.Log(ctx, "guild1", "bob", "akasha_hl", "gold bar", 50, 1); err != nil {
+		t.Fatalf("Log() bob: %v", err)
+	}
+	if _, err := m.Log(ctx, "guild2", "carol", "ubaha_hl", "", 1, 0); err != nil {
+		t.Fatalf("Log() other guild: %v", err)
+	}
+
+	errorCases := []struct {
+		name       string
+		item       string
+		runs, drop int
+	}{
+		{"nothing", "", 0, 0},
+		{"negative runs", "", -1, 0},
+		{"drop without an item", "", 1, 1},
+		{"item name too long", "a very long item name that nobody would ever type in", 1, 1},
+	}
+	for _, tt := range errorCases {
+		if _, err := m.Log(ctx, "guild1", "alice", "akasha_hl", tt.item, tt.runs, tt.drop); err == nil {
+			t.Errorf("Log() %s expected error", tt.name)
+		}
+	}
+	if _, err := m.Log(ctx, "guild1", "alice", "akasha_hl", "", 0, 0); !errors.Is(err, ErrNothingToLog) {
+		t.Errorf("Log() without runs or drops error = %v, expected ErrNothingToLog", err)
+	}
+
+	// Tallies are persisted
+	tallies, err := NewManager(store).Tallies(ctx, "guild1")
+	if err != nil {
+		t.Fatalf("Tallies(): %v", err)
+	}
+	var users []string
+	for _, tally := range tallies {
+		users = append(users, tally.UserID)
+	}
+	if !slices.Equal(users, []string{"alice", "bob"}) {
+		t.Errorf("Tallies() users = %v, expected [alice bob]", users)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tallies := []Tally{
+		{UserID: "alice", BattleID: "akasha_hl", Runs: 100, Drops: map[string]int{"gold bar": 1, "sand": 2}},
+		{UserID: "bob", BattleID: "akasha_hl", Runs: 50, Drops: map[string]int{"gold bar": 2}},
+		{UserID: "bob", BattleID: "ubaha_hl", Runs: 200},
+	}
+
+	summary := Summarize(tallies, "")
+	if len(summary) != 2 || summary[0].BattleID != "ubaha_hl" || summary[1].BattleID != "akasha_hl" {
+		t.Fatalf("Summarize() = %+v, expected ubaha_hl then akasha_hl", summary)
+	}
+	akasha := summary[1]
+	if akasha.Runs != 150 || akasha.Members != 2 || len(akasha.Items) != 2 {
+		t.Fatalf("akasha_hl = %+v, expected 150 runs by 2 members with 2 items", akasha)
+	}
+	if bar := akasha.Items[0]; bar.Item != "gold bar" || bar.Drops != 3 || bar.Rate != 0.02 {
+		t.Errorf("first item = %+v, expected 3 gold bars at 2%%", bar)
+	}
+
+	mine := Summarize(tallies, "alice")
+	if len(mine) != 1 || mine[0].Runs != 100 || mine[0].Members != 1 {
+		t.Errorf("Summarize(alice) = %+v, expected alice's 100 runs", mine)
+	}
+}
+
+func TestWilsonInterval(t *testing.T) {
+	tests := []struct {
+		successes, trials int
+		low, high         float64
+	}{
+		{1, 100, 0.0018, 0.0545},
+		{0, 100, 0, 0.0370},
+		{50, 100, 0.4038, 0.5962},
+		{10, 10, 0.7225, 1},
+		{0, 0, 0, 1},
+	}
+
+	for _, tt := range tests {
+		low, high := WilsonInterval(tt.successes, tt.trials, ConfidenceZ)
+		if math.Abs(low-tt.low) > 1e-4 || math.Abs(high-tt.high) > 1e-4 {
+			t.Errorf("WilsonInterval(%d, %d) = %.4f-%.4f, expected %.4f-%.4f",
+				tt.successes, tt.trials, low, high, tt.low, tt.high)
+		}
+	}
+}
+
+func TestExportRows(t *testing.T) {
+	rows := ExportRows([]Tally{
+		{UserID: "alice", BattleID: "akasha_hl", Runs: 100, Drops: map[string]int{"sand": 2, "gold bar": 1}},
+		{UserID: "bob", BattleID: "ubaha_hl", Runs: 3},
+	})
+
+	expected := [][]string{
+		ExportColumns,
+		{"alice", "akasha_hl", "100", "gold bar", "1"},
+		{"alice", "akasha_hl", "100", "sand", "2"},
+		{"bob", "ubaha_hl", "3", "", "0"},
+	}
+	if !slices.EqualFunc(rows, expected, slices.Equal) {
+		t.Errorf("ExportRows() = %v, expected %v", rows, expected)
+	}
+}
+
+func TestExportRows_Formulas(t *testing.T) {
+	rows := ExportRows([]Tally{
+		{UserID: "alice", BattleID: "akasha_hl", Runs: 1, Drops: map[string]int{
+			`=HYPERLINK("http://example.com","x")`: 1,
+			"+1":                                   1,
+			"-1":                                   1,
+			"@SUM(A1)":                             1,
+			"gold bar":                             1,
+		}},
+	})
+
+	var items []string
+	for _, row := range rows[1:] {
+		items = append(items, row[3])
+	}
+	expected := []string{"'+1", "'-1", `'=HYPERLINK("http://example.com","x")`, "'@SUM(A1)", "gold bar"}
+	if !slices.Equal(items, expected) {
+		t.Errorf("exported items = %q, expected %q", items, expected)
+	}
+}
@@ -2,7 +2,11 @@ package gbf
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -31,14 +35,29 @@ const (
 	BattleTypeCustom BattleType = "custom" // Custom battles
 )
 
+// BattleTypes are the valid battle types in display order
+var BattleTypes = []BattleType{
+	BattleTypeHL, BattleTypeFaaHL, BattleTypeBahaHL, BattleTypeUBahaHL,
+	BattleTypeAkashaHL, BattleTypeLuciHL, BattleTypeGW, BattleTypeGWNM,
+	BattleTypeEvent, BattleTypeEventHL, BattleTypeTrain, BattleTypeCustom,
+}
+
 // BattleInfo represents information about a specific battle
 type BattleInfo struct {
-	ID          string
-	Name        string
-	Type        BattleType
-	Level       int
-	MinRank     int
-	MaxPlayers  int
+	ID   string
+	Name string
+	// Names are localized display names keyed by language, e.g. "ja"
+	Names map[string]string
+	// Aliases are other IDs that resolve to this battle
+	Aliases    []string
+	Type       BattleType
+	Level      int
+	MinRank    int
+	MaxPlayers int
+	Element    Element
+	RaidCode   string
+	// GuildWar is set for Guild War nightmare battles with known rewards and costs
+	GuildWar    *GuildWarInfo
 	Description string
 	IsActive    bool
 	CreatedAt   time.Time
@@ -46,113 +65,147 @@ type BattleInfo struct {
 
 // BattleManager manages battle types and information
 type BattleManager struct {
+	mu      sync.RWMutex
 	battles map[string]*BattleInfo
+	// aliases maps battle aliases to battle IDs
+	aliases map[string]string
+}
+
+// CatalogDiff describes how a battle catalog changed on replacement
+type CatalogDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// IsEmpty returns true if nothing changed
+func (d CatalogDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
 }
 
 // NewBattleManager creates a new battle manager
 func NewBattleManager() *BattleManager {
 	bm := &BattleManager{
 		battles: make(map[string]*BattleInfo),
+		aliases: make(map[string]string),
 	}
 
 	// Initialize default battles
-	bm.initializeDefaultBattles()
+	bm.Replace(DefaultBattles())
 
 	return bm
 }
 
-// initializeDefaultBattles sets up default battle configurations
-func (bm *BattleManager) initializeDefaultBattles() {
-	defaultBattles := []*BattleInfo{
-		{
-			ID:          "faa_hl",
-			Name:        "Lucilius (Hard)",
-			Type:        BattleTypeFaaHL,
-			Level:       200,
-			MinRank:     150,
-			MaxPlayers:  6,
-			Description: "Dark Rapture Hard mode raid",
-			IsActive:    true,
-		},
-		{
-			ID:          "baha_hl",
-			Name:        "Proto Bahamut (Hard)",
-			Type:        BattleTypeBahaHL,
-			Level:       150,
-			MinRank:     101,
-			MaxPlayers:  18,
-			Description: "Proto Bahamut Hard mode raid",
-			IsActive:    true,
-		},
-		{
-			ID:          "ubaha_hl",
-			Name:        "Ultimate Bahamut (Hard)",
-			Type:        BattleTypeUBahaHL,
-			Level:       200,
-			MinRank:     120,
-			MaxPlayers:  30,
-			Description: "Ultimate Bahamut Hard mode raid",
-			IsActive:    true,
-		},
-		{
-			ID:          "akasha_hl",
-			Name:        "Akasha (Hard)",
-			Type:        BattleTypeAkashaHL,
-			Level:       200,
-			MinRank:     120,
-			MaxPlayers:  30,
-			Description: "Akasha Hard mode raid",
-			IsActive:    true,
-		},
-		{
-			ID:          "luci_hl",
-			Name:        "Lucifer (Hard)",
-			Type:        BattleTypeEventHL,
-			Level:       200,
-			MinRank:     120,
-			MaxPlayers:  30,
-			Description: "Lucifer Hard mode raid",
-			IsActive:    true,
-		},
-		{
-			ID:          "gw_nm95",
-			Name:        "Guild War NM95",
-			Type:        BattleTypeGWNM,
-			Level:       95,
-			MinRank:     80,
-			MaxPlayers:  30,
-			Description: "Guild War Nightmare 95 raid",
-			IsActive:    false, // Activated during GW periods
-		},
-		{
-			ID:          "gw_nm150",
-			Name:        "Guild War NM150",
-			Type:        BattleTypeGWNM,
-			Level:       150,
-			MinRank:     120,
-			MaxPlayers:  30,
-			Description: "Guild War Nightmare 150 raid",
-			IsActive:    false, // Activated during GW periods
-		},
-	}
-
-	for _, battle := range defaultBattles {
-		battle.CreatedAt = time.Now()
-		bm.battles[battle.ID] = battle
-	}
-}
-
-// GetBattle retrieves battle information by ID
+// Replace swaps the whole catalog for battles and reports what changed.
+// Readers see either the old or the new catalog, never a mix of both.
+func (bm *BattleManager) Replace(battles []*BattleInfo) CatalogDiff {
+	now := time.Now()
+	next := make(map[string]*BattleInfo, len(battles))
+	for _, battle := range battles {
+		battle.ID = strings.ToLower(battle.ID)
+		if battle.CreatedAt.IsZero() {
+			battle.CreatedAt = now
+		}
+		next[battle.ID] = battle
+	}
+	aliases := make(map[string]string)
+	for _, battle := range battles {
+		for _, alias := range battle.Aliases {
+			alias = strings.ToLower(alias)
+			if _, exists := next[alias]; !exists {
+				aliases[alias] = battle.ID
+			}
+		}
+	}
+
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	var diff CatalogDiff
+	for id, battle := range next {
+		old, exists := bm.battles[id]
+		if !exists {
+			diff.Added = append(diff.Added, id)
+			continue
+		}
+		battle.CreatedAt = old.CreatedAt
+		if !old.sameAs(battle) {
+			diff.Changed = append(diff.Changed, id)
+		}
+	}
+	for id := range bm.battles {
+		if _, exists := next[id]; !exists {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+
+	bm.battles = next
+	bm.aliases = aliases
+	return diff
+}
+
+// sameAs compares the catalog fields of two battles, ignoring timestamps
+func (b *BattleInfo) sameAs(other *BattleInfo) bool {
+	return b.ID == other.ID &&
+		b.Name == other.Name &&
+		maps.Equal(b.Names, other.Names) &&
+		slices.Equal(b.Aliases, other.Aliases) &&
+		b.Type == other.Type &&
+		b.Level == other.Level &&
+		b.MinRank == other.MinRank &&
+		b.MaxPlayers == other.MaxPlayers &&
+		b.Element == other.Element &&
+		b.RaidCode == other.RaidCode &&
+		(b.GuildWar == nil) == (other.GuildWar == nil) &&
+		(b.GuildWar == nil || *b.GuildWar == *other.GuildWar) &&
+		b.Description == other.Description &&
+		b.IsActive == other.IsActive
+}
+
+// resolveID returns the battle ID for an ID or alias. The caller must hold bm.mu.
+func (bm *BattleManager) resolveID(id string) string {
+	id = strings.ToLower(id)
+	if battleID, exists := bm.aliases[id]; exists {
+		return battleID
+	}
+	return id
+}
+
+// GetBattle retrieves battle information by ID or alias
 func (bm *BattleManager) GetBattle(id string) (*BattleInfo, error) {
-	battle, exists := bm.battles[strings.ToLower(id)]
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+
+	battle, exists := bm.battles[bm.resolveID(id)]
 	if !exists {
 		return nil, fmt.Errorf("battle not found: %s", id)
 	}
 	return battle, nil
 }
 
+// GetAllBattles returns every battle ordered by ID
+func (bm *BattleManager) GetAllBattles() []*BattleInfo {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+
+	battles := make([]*BattleInfo, 0, len(bm.battles))
+	for _, battle := range bm.battles {
+		battles = append(battles, battle)
+	}
+	sort.Slice(battles, func(i, j int) bool {
+		return battles[i].ID < battles[j].ID
+	})
+	return battles
+}
+
 // GetActiveBattles returns all currently active battles
 func (bm *BattleManager) GetActiveBattles() []*BattleInfo {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+
 	var activeBattles []*BattleInfo
 	for _, battle := range bm.battles {
 		if battle.IsActive {
@@ -164,6 +217,9 @@ func (bm *BattleManager) GetActiveBattles() []*BattleInfo {
 
 // GetBattlesByType returns battles of a specific type
 func (bm *BattleManager) GetBattlesByType(battleType BattleType) []*BattleInfo {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+
 	var typeBattles []*BattleInfo
 	for _, battle := range bm.battles {
 		if battle.Type == battleType {
@@ -173,63 +229,162 @@ func (bm *BattleManager) GetBattlesByType(battleType BattleType) []*BattleInfo {
 	return typeBattles
 }
 
+// Types returns the battle types in the catalog, in the order of BattleTypes
+func (bm *BattleManager) Types() []BattleType {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+
+	present := make(map[BattleType]bool)
+	for _, battle := range bm.battles {
+		present[battle.Type] = true
+	}
+	var types []BattleType
+	for _, battleType := range BattleTypes {
+		if present[battleType] {
+			types = append(types, battleType)
+		}
+	}
+	return types
+}
+
+// Search returns up to limit battles whose ID, alias or name in any language
+// contains query, ignoring case. Exact matches come first, then prefixes, then
+// substrings, with active battles before inactive ones and ties ordered by ID.
+// An empty query matches every battle and a limit of 0 or less returns all matches.
+func (bm *BattleManager) Search(query string, limit int) []*BattleInfo {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	type match struct {
+		battle *BattleInfo
+		rank   int
+	}
+	var matches []match
+	for _, battle := range bm.GetAllBattles() {
+		if rank, ok := battle.matchRank(query); ok {
+			if !battle.IsActive {
+				rank++
+			}
+			matches = append(matches, match{battle: battle, rank: rank})
+		}
+	}
+	// GetAllBattles orders by ID, which the stable sort keeps within a rank
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].rank < matches[j].rank
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	battles := make([]*BattleInfo, len(matches))
+	for i, m := range matches {
+		battles[i] = m.battle
+	}
+	return battles
+}
+
+// matchRank ranks how well a lower-case query matches the battle: 0 for an exact
+// match, 2 for a prefix and 4 for a substring, leaving odd ranks for inactive battles
+func (b *BattleInfo) matchRank(query string) (int, bool) {
+	terms := append([]string{b.ID, b.Name}, b.Aliases...)
+	for _, name := range b.Names {
+		terms = append(terms, name)
+	}
+
+	best := -1
+	for _, term := range terms {
+		term = strings.ToLower(term)
+		rank := -1
+		switch {
+		case term == query:
+			rank = 0
+		case strings.HasPrefix(term, query):
+			rank = 2
+		case strings.Contains(term, query):
+			rank = 4
+		}
+		if rank >= 0 && (best < 0 || rank < best) {
+			best = rank
+		}
+	}
+	return best, best >= 0
+}
+
 // AddBattle adds a new battle type
 func (bm *BattleManager) AddBattle(battle *BattleInfo) error {
 	if battle.ID == "" {
 		return fmt.Errorf("battle ID cannot be empty")
 	}
 
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	battle.ID = strings.ToLower(battle.ID)
 	battle.CreatedAt = time.Now()
-	bm.battles[strings.ToLower(battle.ID)] = battle
+	bm.battles[battle.ID] = battle
+	for _, alias := range battle.Aliases {
+		alias = strings.ToLower(alias)
+		if _, exists := bm.battles[alias]; !exists {
+			bm.aliases[alias] = battle.ID
+		}
+	}
 	return nil
 }
 
 // UpdateBattle updates an existing battle
 func (bm *BattleManager) UpdateBattle(id string, battle *BattleInfo) error {
-	if _, exists := bm.battles[strings.ToLower(id)]; !exists {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	id = bm.resolveID(id)
+	if _, exists := bm.battles[id]; !exists {
 		return fmt.Errorf("battle not found: %s", id)
 	}
 
-	battle.ID = strings.ToLower(id)
+	battle.ID = id
 	bm.battles[battle.ID] = battle
 	return nil
 }
 
 // RemoveBattle removes a battle type
 func (bm *BattleManager) RemoveBattle(id string) error {
-	if _, exists := bm.battles[strings.ToLower(id)]; !exists {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	id = bm.resolveID(id)
+	if _, exists := bm.battles[id]; !exists {
 		return fmt.Errorf("battle not found: %s", id)
 	}
 
-	delete(bm.battles, strings.ToLower(id))
+	delete(bm.battles, id)
+	for alias, battleID := range bm.aliases {
+		if battleID == id {
+			delete(bm.aliases, alias)
+		}
+	}
 	return nil
 }
 
-// SetBattleActive sets the active status of a battle
+// SetBattleActive sets the active status of a battle.
+// The battle is replaced by an updated copy because readers hold on to the
+// battles they got without the lock.
 func (bm *BattleManager) SetBattleActive(id string, active bool) error {
-	battle, exists := bm.battles[strings.ToLower(id)]
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	battle, exists := bm.battles[bm.resolveID(id)]
 	if !exists {
 		return fmt.Errorf("battle not found: %s", id)
 	}
 
-	battle.IsActive = active
+	updated := *battle
+	updated.IsActive = active
+	bm.battles[updated.ID] = &updated
 	return nil
 }
 
 // IsValidBattleType checks if a battle type is valid
 func IsValidBattleType(battleType BattleType) bool {
-	validTypes := []BattleType{
-		BattleTypeHL, BattleTypeFaaHL, BattleTypeBahaHL, BattleTypeUBahaHL,
-		BattleTypeAkashaHL, BattleTypeLuciHL, BattleTypeGW, BattleTypeGWNM,
-		BattleTypeEvent, BattleTypeEventHL, BattleTypeTrain, BattleTypeCustom,
-	}
-
-	for _, validType := range validTypes {
-		if battleType == validType {
-			return true
-		}
-	}
-	return false
+	return slices.Contains(BattleTypes, battleType)
 }
 
 // FormatBattleInfo formats battle information for display
@@ -239,10 +394,26 @@ func FormatBattleInfo(battle *BattleInfo) string {
 		status = "🟢 Active"
 	}
 
-	return fmt.Sprintf("**%s** (Level %d)\n"+
-		"Type: %s | Min Rank: %d | Max Players: %d\n"+
+	name := battle.Name
+	if localized := battle.Names["ja"]; localized != "" {
+		name = fmt.Sprintf("%s / %s", battle.Name, localized)
+	}
+	element := string(battle.Element)
+	if element == "" {
+		element = "-"
+	}
+
+	text := fmt.Sprintf("**%s** (Level %d)\n"+
+		"Type: %s | Element: %s | Min Rank: %d | Max Players: %d\n"+
 		"Status: %s\n"+
 		"Description: %s",
-		battle.Name, battle.Level, battle.Type, battle.MinRank,
+		name, battle.Level, battle.Type, element, battle.MinRank,
 		battle.MaxPlayers, status, battle.Description)
+	if len(battle.Aliases) > 0 {
+		text += "\nAliases: " + strings.Join(battle.Aliases, ", ")
+	}
+	if battle.RaidCode != "" {
+		text += "\nRaid: " + battle.RaidCode
+	}
+	return text
 }
@@ -0,0 +1,118 @@
+package gbf
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBattleManager_Replace(t *testing.T) {
+	bm := NewBattleManager()
+
+	battles := DefaultBattles()
+	// Drop the first battle, change the second and add a new one
+	removed := battles[0].ID
+	battles = battles[1:]
+	changed := battles[0].ID
+	battles[0].MaxPlayers++
+	battles = append(battles, &BattleInfo{ID: "New_Battle", Name: "New Battle", Type: BattleTypeCustom})
+
+	diff := bm.Replace(battles)
+
+	if !slices.Equal(diff.Added, []string{"new_battle"}) {
+		t.Errorf("Added = %v, expected [new_battle]", diff.Added)
+	}
+	if !slices.Equal(diff.Removed, []string{removed}) {
+		t.Errorf("Removed = %v, expected [%s]", diff.Removed, removed)
+	}
+	if !slices.Equal(diff.Changed, []string{changed}) {
+		t.Errorf("Changed = %v, expected [%s]", diff.Changed, changed)
+	}
+
+	if _, err := bm.GetBattle(removed); err == nil {
+		t.Errorf("GetBattle(%s) expected error after removal", removed)
+	}
+	if _, err := bm.GetBattle("new_battle"); err != nil {
+		t.Errorf("GetBattle(new_battle) unexpected error: %v", err)
+	}
+
+	if diff := bm.Replace(battles); !diff.IsEmpty() {
+		t.Errorf("Replace with identical catalog = %+v, expected empty diff", diff)
+	}
+}
+
+func TestBattleManager_Search(t *testing.T) {
+	bm := NewBattleManager()
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []string
+	}{
+		{"alias matches exactly", "PBHL", 0, []string{"baha_hl"}},
+		{"localized name", "バハムート", 0, []string{"baha_hl", "subaha", "ubaha_hl"}},
+		{"prefixes before substrings", "baha", 0, []string{"baha_hl", "subaha", "ubaha_hl"}},
+		{"inactive battles last", "nm", 0, []string{"gw_nm100", "gw_nm150", "gw_nm200", "gw_nm250", "gw_nm90", "gw_nm95"}},
+		{"limit", "baha", 1, []string{"baha_hl"}},
+		{"no match", "zzz", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, battle := range bm.Search(tt.query, tt.limit) {
+				got = append(got, battle.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q, %d) = %v, want %v", tt.query, tt.limit, got, tt.want)
+			}
+		})
+	}
+
+	if got := len(bm.Search("", 0)); got != len(bm.GetAllBattles()) {
+		t.Errorf("Search(\"\") returned %d battles, want all %d", got, len(bm.GetAllBattles()))
+	}
+}
+
+func TestBattleManager_Types(t *testing.T) {
+	bm := NewBattleManager()
+	bm.Replace([]*BattleInfo{
+		{ID: "custom", Name: "Custom", Type: BattleTypeCustom},
+		{ID: "faa", Name: "Faa", Type: BattleTypeFaaHL},
+		{ID: "faa2", Name: "Faa 2", Type: BattleTypeFaaHL},
+	})
+
+	want := []BattleType{BattleTypeFaaHL, BattleTypeCustom}
+	if got := bm.Types(); !slices.Equal(got, want) {
+		t.Errorf("Types() = %v, want %v", got, want)
+	}
+}
+
+func TestBattleManager_SetBattleActive(t *testing.T) {
+	bm := NewBattleManager()
+	before, err := bm.GetBattle("gw_nm150")
+	if err != nil {
+		t.Fatalf("GetBattle() error = %v", err)
+	}
+
+	// Battles handed out earlier are read without the lock while the scheduler toggles them
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for n := 0; n < 100; n++ {
+			_ = before.IsActive
+		}
+	}()
+	if err := bm.SetBattleActive("GW_NM150", !before.IsActive); err != nil {
+		t.Fatalf("SetBattleActive() error = %v", err)
+	}
+	<-done
+
+	after, _ := bm.GetBattle("gw_nm150")
+	if after.IsActive == before.IsActive {
+		t.Errorf("IsActive = %t, expected it to be toggled", after.IsActive)
+	}
+	if err := bm.SetBattleActive("unknown_raid", true); err == nil {
+		t.Error("SetBattleActive(unknown_raid) expected an error")
+	}
+}
@@ -0,0 +1,270 @@
+package gbf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Battle log limits
+const (
+	maxLogActions    = 20000
+	maxLogTurn       = 999
+	maxLogPlayerName = 64
+)
+
+// BattleLogColumns are the columns of the CSV export of a battle log summary
+var BattleLogColumns = []string{"player", "normal", "skill", "charge_attack", "total", "share"}
+
+// ErrEmptyBattleLog is returned for logs without any damage
+var ErrEmptyBattleLog = errors.New("the battle log has no damage")
+
+// LogAction is a hit of a player in a battle log
+type LogAction struct {
+	Turn   int
+	Player string
+	Kind   DamageKind
+	Damage int64
+}
+
+// PlayerContribution is the damage a player dealt in a battle
+type PlayerContribution struct {
+	Player string
+	Damage int64
+	// ByKind splits the damage into normal attacks, skills and charge attacks
+	ByKind map[DamageKind]int64
+	// Share is the player's part of the total damage, from 0 to 1
+	Share float64
+}
+
+// BattleLogSummary aggregates a battle log
+type BattleLogSummary struct {
+	// Turns is the last turn of the log
+	Turns  int
+	Damage int64
+	ByKind map[DamageKind]int64
+	// Players are ordered by damage, most first
+	Players []PlayerContribution
+}
+
+// logKindNames maps the damage kinds written in logs to damage kinds
+var logKindNames = map[string]DamageKind{
+	"normal": DamageKindNormal, "auto": DamageKindNormal, "attack": DamageKindNormal, "通常攻撃": DamageKindNormal,
+	"skill": DamageKindSkill, "ability": DamageKindSkill, "アビリティ": DamageKindSkill,
+	"charge_attack": DamageKindCharge, "charge": DamageKindCharge, "ca": DamageKindCharge, "ougi": DamageKindCharge, "奥義": DamageKindCharge,
+}
+
+// parseLogKind returns the damage kind of a name written in a log, e.g. "CA"
+func parseLogKind(name string) (DamageKind, error) {
+	if kind, exists := logKindNames[strings.ToLower(strings.TrimSpace(name))]; exists {
+		return kind, nil
+	}
+	return "", fmt.Errorf("unknown damage type %q: use auto, skill or ca", name)
+}
+
+// ParseBattleLog parses a battle log as JSON when it starts with { or [, and as
+// text otherwise.
+//
+// JSON logs are a list of hits, or an object with the hits in "actions" or
+// grouped by turn in "turns":
+//
+//	{"turns": [{"turn": 1, "actions": [{"player": "Alice", "type": "skill", "damage": 120000}]}]}
+//
+// Text logs have one hit per line after "Turn N" headers, with the player name
+// followed by the damage type and the damage. Lines can also be separated by ;
+// as in text pasted into a single line.
+//
+//	Turn 1
+//	Alice skill 120,000
+func ParseBattleLog(data []byte) ([]LogAction, error) {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	var actions []LogAction
+	var err error
+	if len(data) > 0 && (data[0] == '{' || data[0] == '[') {
+		actions, err = parseJSONLog(data)
+	} else {
+		actions, err = parseTextLog(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(actions) == 0 {
+		return nil, ErrEmptyBattleLog
+	}
+	if len(actions) > maxLogActions {
+		return nil, fmt.Errorf("battle logs are limited to %d hits", maxLogActions)
+	}
+	return actions, nil
+}
+
+// jsonLogAction is a hit as written in a JSON log
+type jsonLogAction struct {
+	Turn   int    `json:"turn"`
+	Player string `json:"player"`
+	Type   string `json:"type"`
+	Damage int64  `json:"damage"`
+}
+
+// parseJSONLog parses the hits of a JSON log
+func parseJSONLog(data []byte) ([]LogAction, error) {
+	var file struct {
+		Actions []jsonLogAction `json:"actions"`
+		Turns   []struct {
+			Turn    int             `json:"turn"`
+			Actions []jsonLogAction `json:"actions"`
+		} `json:"turns"`
+	}
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &file.Actions); err != nil {
+			return nil, fmt.Errorf("invalid JSON battle log: %w", err)
+		}
+	} else if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid JSON battle log: %w", err)
+	}
+
+	records := file.Actions
+	for _, turn := range file.Turns {
+		for _, record := range turn.Actions {
+			if record.Turn == 0 {
+				record.Turn = turn.Turn
+			}
+			records = append(records, record)
+		}
+	}
+
+	actions := make([]LogAction, 0, len(records))
+	for n, record := range records {
+		kind, err := parseLogKind(record.Type)
+		if err == nil {
+			err = checkLogAction(record.Turn, record.Player, record.Damage)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("hit %d: %w", n+1, err)
+		}
+		actions = append(actions, LogAction{Turn: record.Turn, Player: strings.TrimSpace(record.Player), Kind: kind, Damage: record.Damage})
+	}
+	return actions, nil
+}
+
+// turnPattern matches the turn headers of text logs, e.g. "Turn 3" or "ターン3"
+var turnPattern = regexp.MustCompile(`(?i)^(?:turn|ターン)\s*(\d+)\s*:?$`)
+
+// parseTextLog parses the hits of a text log. Blank lines and lines starting
+// with # are skipped; hits before the first turn header are in turn 1.
+func parseTextLog(data []byte) ([]LogAction, error) {
+	var actions []LogAction
+	turn := 1
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.ReplaceAll(data, []byte(";"), []byte("\n"))))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if match := turnPattern.FindStringSubmatch(text); match != nil {
+			turn, _ = strconv.Atoi(match[1])
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("line %d: expected a player, a damage type and the damage", line)
+		}
+		player := strings.TrimSuffix(strings.Join(fields[:len(fields)-2], " "), ":")
+		kind, err := parseLogKind(fields[len(fields)-2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		damage, err := strconv.ParseInt(strings.ReplaceAll(fields[len(fields)-1], ",", ""), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid damage %q", line, fields[len(fields)-1])
+		}
+		if err := checkLogAction(turn, player, damage); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		actions = append(actions, LogAction{Turn: turn, Player: player, Kind: kind, Damage: damage})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid battle log: %w", err)
+	}
+	return actions, nil
+}
+
+// checkLogAction validates the fields of a hit
+func checkLogAction(turn int, player string, damage int64) error {
+	switch {
+	case turn < 1 || turn > maxLogTurn:
+		return fmt.Errorf("turn must be between 1 and %d", maxLogTurn)
+	case strings.TrimSpace(player) == "":
+		return errors.New("player is required")
+	case len([]rune(player)) > maxLogPlayerName:
+		return fmt.Errorf("player names are limited to %d characters", maxLogPlayerName)
+	case damage < 0:
+		return errors.New("damage cannot be negative")
+	}
+	return nil
+}
+
+// SummarizeBattleLog adds up the damage of the hits per player and damage kind
+func SummarizeBattleLog(actions []LogAction) *BattleLogSummary {
+	summary := &BattleLogSummary{ByKind: make(map[DamageKind]int64)}
+	players := make(map[string]*PlayerContribution)
+	var order []string
+	for _, action := range actions {
+		player, exists := players[action.Player]
+		if !exists {
+			player = &PlayerContribution{Player: action.Player, ByKind: make(map[DamageKind]int64)}
+			players[action.Player] = player
+			order = append(order, action.Player)
+		}
+		player.Damage += action.Damage
+		player.ByKind[action.Kind] += action.Damage
+		summary.Damage += action.Damage
+		summary.ByKind[action.Kind] += action.Damage
+		summary.Turns = max(summary.Turns, action.Turn)
+	}
+
+	for _, name := range order {
+		player := players[name]
+		if summary.Damage > 0 {
+			player.Share = float64(player.Damage) / float64(summary.Damage)
+		}
+		summary.Players = append(summary.Players, *player)
+	}
+	// Players with the same damage stay in the order they first appear
+	sort.SliceStable(summary.Players, func(i, j int) bool {
+		return summary.Players[i].Damage > summary.Players[j].Damage
+	})
+	return summary
+}
+
+// ExportRows returns the players of the summary as CSV rows with
+// BattleLogColumns as the header
+func (s *BattleLogSummary) ExportRows() [][]string {
+	rows := [][]string{BattleLogColumns}
+	for _, player := range s.Players {
+		rows = append(rows, []string{
+			exportPlayer(player.Player),
+			strconv.FormatInt(player.ByKind[DamageKindNormal], 10),
+			strconv.FormatInt(player.ByKind[DamageKindSkill], 10),
+			strconv.FormatInt(player.ByKind[DamageKindCharge], 10),
+			strconv.FormatInt(player.Damage, 10),
+			strconv.FormatFloat(player.Share*100, 'f', 2, 64),
+		})
+	}
+	return rows
+}
+
+// exportPlayer keeps a pasted player name such as "=1+1" from running as a
+// formula when the CSV is opened in a spreadsheet
+func exportPlayer(name string) string {
+	if name != "" && strings.ContainsRune("=+-@\t\r", rune(name[0])) {
+		return "'" + name
+	}
+	return name
+}
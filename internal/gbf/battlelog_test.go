@@ -0,0 +1,122 @@
+package gbf
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseBattleLog(t *testing.T) {
+	expected := []LogAction{
+		{Turn: 1, Player: "Alice", Kind: DamageKindNormal, Damage: 100000},
+		{Turn: 1, Player: "Bob Smith", Kind: DamageKindSkill, Damage: 250000},
+		{Turn: 2, Player: "Alice", Kind: DamageKindCharge, Damage: 1200000},
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "text",
+			data: "# raid log\nTurn 1\nAlice auto 100,000\nBob Smith: skill 250000\n\nターン2\nAlice CA 1,200,000\n",
+		},
+		{
+			name: "single line",
+			data: "Turn 1; Alice normal 100000; Bob Smith skill 250,000; Turn 2; Alice ca 1200000",
+		},
+		{
+			name: "json list",
+			data: `[{"turn": 1, "player": "Alice", "type": "auto", "damage": 100000},
+				{"turn": 1, "player": "Bob Smith", "type": "ability", "damage": 250000},
+				{"turn": 2, "player": "Alice", "type": "ougi", "damage": 1200000}]`,
+		},
+		{
+			name: "json turns",
+			data: `{"turns": [
+				{"turn": 1, "actions": [{"player": "Alice", "type": "normal", "damage": 100000}, {"player": "Bob Smith", "type": "skill", "damage": 250000}]},
+				{"turn": 2, "actions": [{"player": "Alice", "type": "charge_attack", "damage": 1200000}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions, err := ParseBattleLog([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseBattleLog() unexpected error: %v", err)
+			}
+			if !slices.Equal(actions, expected) {
+				t.Errorf("ParseBattleLog() = %+v, expected %+v", actions, expected)
+			}
+		})
+	}
+}
+
+func TestParseBattleLog_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"empty", "  \n# nothing\n", "no damage"},
+		{"missing damage", "Alice auto", "line 1: expected a player"},
+		{"unknown type", "Turn 1\nAlice heal 100", `line 2: unknown damage type "heal"`},
+		{"invalid damage", "Alice auto lots", `line 1: invalid damage "lots"`},
+		{"turn 0", "Turn 0\nAlice auto 100", "line 2: turn must be between 1 and 999"},
+		{"invalid json", `{"turns": [}`, "invalid JSON battle log"},
+		{"json hit without a turn", `[{"player": "Alice", "type": "auto", "damage": 1}]`, "hit 1: turn must be"},
+		{"negative damage", `[{"turn": 1, "player": "Alice", "type": "auto", "damage": -1}]`, "hit 1: damage cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBattleLog([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseBattleLog() error = %v, expected %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := ParseBattleLog(nil); !errors.Is(err, ErrEmptyBattleLog) {
+		t.Errorf("ParseBattleLog(nil) error = %v, expected ErrEmptyBattleLog", err)
+	}
+}
+
+func TestSummarizeBattleLog(t *testing.T) {
+	summary := SummarizeBattleLog([]LogAction{
+		{Turn: 1, Player: "Alice", Kind: DamageKindNormal, Damage: 100},
+		{Turn: 1, Player: "Bob", Kind: DamageKindSkill, Damage: 300},
+		{Turn: 3, Player: "Alice", Kind: DamageKindCharge, Damage: 500},
+		{Turn: 3, Player: "Carol", Kind: DamageKindNormal, Damage: 100},
+	})
+
+	if summary.Turns != 3 || summary.Damage != 1000 {
+		t.Errorf("Turns, Damage = %d, %d, expected 3, 1000", summary.Turns, summary.Damage)
+	}
+	if summary.ByKind[DamageKindNormal] != 200 || summary.ByKind[DamageKindSkill] != 300 || summary.ByKind[DamageKindCharge] != 500 {
+		t.Errorf("ByKind = %v, expected 200 normal, 300 skill, 500 charge", summary.ByKind)
+	}
+
+	expected := [][]string{
+		BattleLogColumns,
+		{"Alice", "100", "0", "500", "600", "60.00"},
+		{"Bob", "0", "300", "0", "300", "30.00"},
+		{"Carol", "100", "0", "0", "100", "10.00"},
+	}
+	if rows := summary.ExportRows(); !slices.EqualFunc(rows, expected, slices.Equal) {
+		t.Errorf("ExportRows() = %v, expected %v", rows, expected)
+	}
+}
+
+func TestBattleLogSummary_ExportRows_Formulas(t *testing.T) {
+	summary := SummarizeBattleLog([]LogAction{
+		{Turn: 1, Player: "=1+1", Kind: DamageKindNormal, Damage: 200},
+		{Turn: 1, Player: "@Alice", Kind: DamageKindNormal, Damage: 100},
+	})
+
+	rows := summary.ExportRows()
+	if rows[1][0] != "'=1+1" || rows[2][0] != "'@Alice" {
+		t.Errorf("exported players = %q, %q, expected them escaped", rows[1][0], rows[2][0])
+	}
+}
@@ -14,7 +14,7 @@ func NewAttackCalculator() *AttackCalculator {
 }
 
 // CalculateBaseDamage calculates basic attack damage
-// This is a simplified example for testing purposes
+// This is a simplified example for testing purposes; Calculate runs the full damage pipeline
 func (c *AttackCalculator) CalculateBaseDamage(attack int, defense int) int {
 	if attack <= 0 {
 		return 0
@@ -40,13 +40,14 @@ func (c *AttackCalculator) CalculateCriticalDamage(baseDamage int, critMultiplie
 	return int(math.Round(float64(baseDamage) * critMultiplier))
 }
 
-// CalculateElementalDamage applies elemental advantage/disadvantage
-func (c *AttackCalculator) CalculateElementalDamage(baseDamage int, elementalModifier float64) int {
+// CalculateElementalDamage applies the elemental advantage or disadvantage of an
+// attacker's element against a defender's
+func (c *AttackCalculator) CalculateElementalDamage(baseDamage int, attacker, defender Element) int {
 	if baseDamage <= 0 {
 		return 0
 	}
 
-	return int(math.Round(float64(baseDamage) * elementalModifier))
+	return int(math.Round(float64(baseDamage) * (1 + attacker.Against(defender).Modifier())))
 }
 
 // WeaponType represents different weapon types in GBF
@@ -81,31 +82,17 @@ func IsValidWeaponType(weaponType WeaponType) bool {
 	return false
 }
 
-// GetWeaponTypeMultiplier returns the weapon type multiplier for specific classes
-// This is a simplified example
+// GetWeaponTypeMultiplier returns the ATK multiplier of a weapon type for a class
+// of the built-in catalog: ProficiencyMultiplier when the class is proficient with
+// it and 1 otherwise
 func GetWeaponTypeMultiplier(weaponType WeaponType, className string) (float64, error) {
 	if !IsValidWeaponType(weaponType) {
 		return 0, fmt.Errorf("invalid weapon type: %s", weaponType)
 	}
 
-	// Simplified multiplier system - in real GBF this would be much more complex
-	baseMultipliers := map[WeaponType]float64{
-		WeaponTypeSword:  1.2,
-		WeaponTypeDagger: 1.1,
-		WeaponTypeSpear:  1.15,
-		WeaponTypeAxe:    1.25,
-		WeaponTypeStaff:  1.0,
-		WeaponTypeGun:    1.1,
-		WeaponTypeMelee:  1.3,
-		WeaponTypeBow:    1.05,
-		WeaponTypeHarp:   1.0,
-		WeaponTypeKatana: 1.2,
+	class, err := DefaultClasses().Get(className)
+	if err != nil {
+		return 0, err
 	}
-
-	multiplier, exists := baseMultipliers[weaponType]
-	if !exists {
-		return 1.0, nil
-	}
-
-	return multiplier, nil
+	return class.WeaponMultiplier(weaponType)
 }
@@ -124,49 +124,55 @@ func TestAttackCalculator_CalculateElementalDamage(t *testing.T) {
 	calc := NewAttackCalculator()
 
 	tests := []struct {
-		name              string
-		baseDamage        int
-		elementalModifier float64
-		expected          int
+		name       string
+		baseDamage int
+		attacker   Element
+		defender   Element
+		expected   int
 	}{
 		{
-			name:              "elemental advantage",
-			baseDamage:        1000,
-			elementalModifier: 1.5,
-			expected:          1500,
+			name:       "elemental advantage",
+			baseDamage: 1000,
+			attacker:   ElementFire,
+			defender:   ElementWind,
+			expected:   1500,
 		},
 		{
-			name:              "elemental disadvantage",
-			baseDamage:        1000,
-			elementalModifier: 0.5,
-			expected:          500,
+			name:       "elemental disadvantage",
+			baseDamage: 1000,
+			attacker:   ElementFire,
+			defender:   ElementWater,
+			expected:   750,
 		},
 		{
-			name:              "neutral element",
-			baseDamage:        1000,
-			elementalModifier: 1.0,
-			expected:          1000,
+			name:       "neutral element",
+			baseDamage: 1000,
+			attacker:   ElementFire,
+			defender:   ElementLight,
+			expected:   1000,
 		},
 		{
-			name:              "zero base damage",
-			baseDamage:        0,
-			elementalModifier: 1.5,
-			expected:          0,
+			name:       "zero base damage",
+			baseDamage: 0,
+			attacker:   ElementFire,
+			defender:   ElementWind,
+			expected:   0,
 		},
 		{
-			name:              "negative base damage",
-			baseDamage:        -100,
-			elementalModifier: 1.5,
-			expected:          0,
+			name:       "negative base damage",
+			baseDamage: -100,
+			attacker:   ElementFire,
+			defender:   ElementWind,
+			expected:   0,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := calc.CalculateElementalDamage(tt.baseDamage, tt.elementalModifier)
+			result := calc.CalculateElementalDamage(tt.baseDamage, tt.attacker, tt.defender)
 			if result != tt.expected {
-				t.Errorf("CalculateElementalDamage(%d, %f) = %d, expected %d",
-					tt.baseDamage, tt.elementalModifier, result, tt.expected)
+				t.Errorf("CalculateElementalDamage(%d, %s, %s) = %d, expected %d",
+					tt.baseDamage, tt.attacker, tt.defender, result, tt.expected)
 			}
 		})
 	}
@@ -225,24 +231,31 @@ func TestGetWeaponTypeMultiplier(t *testing.T) {
 		expectError bool
 	}{
 		{
-			name:        "valid sword",
+			name:        "proficient sword",
 			weaponType:  WeaponTypeSword,
 			className:   "fighter",
-			expected:    1.2,
+			expected:    ProficiencyMultiplier,
 			expectError: false,
 		},
 		{
-			name:        "valid axe",
+			name:        "proficient axe",
 			weaponType:  WeaponTypeAxe,
 			className:   "berserker",
-			expected:    1.25,
+			expected:    ProficiencyMultiplier,
 			expectError: false,
 		},
 		{
-			name:        "valid melee",
+			name:        "class looked up by name",
 			weaponType:  WeaponTypeMelee,
-			className:   "monk",
-			expected:    1.3,
+			className:   "Superstar",
+			expected:    ProficiencyMultiplier,
+			expectError: false,
+		},
+		{
+			name:        "weapon without proficiency",
+			weaponType:  WeaponTypeStaff,
+			className:   "fighter",
+			expected:    1.0,
 			expectError: false,
 		},
 		{
@@ -252,6 +265,13 @@ func TestGetWeaponTypeMultiplier(t *testing.T) {
 			expected:    0,
 			expectError: true,
 		},
+		{
+			name:        "unknown class",
+			weaponType:  WeaponTypeSword,
+			className:   "monk",
+			expected:    0,
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -302,3 +322,30 @@ func BenchmarkIsValidWeaponType(b *testing.B) {
 		IsValidWeaponType(WeaponTypeSword)
 	}
 }
+
+func BenchmarkSimulate(b *testing.B) {
+	calc := NewAttackCalculator()
+	input := SimInput{
+		Attack: DamageInput{
+			BaseAttack: 10000,
+			Normal:     1.5,
+			Attacker:   ElementFire,
+			Defender:   ElementWind,
+			Crits:      []CritSource{{Rate: 0.3, Multiplier: 0.5}},
+			Echo:       0.2,
+		},
+		DoubleAttack: 0.5,
+		TripleAttack: 0.2,
+		Members:      4,
+		TargetHP:     10000000,
+		Trials:       100,
+		Seed:         1,
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := calc.Simulate(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,683 @@
+package gbf
+
+import (
+	"bytes"
+	"embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed data/battles.yaml
+var catalogFS embed.FS
+
+// defaultCatalogSource is the name of the embedded catalog in error messages
+const defaultCatalogSource = "data/battles.yaml"
+
+// Battle catalog limits
+const (
+	maxBattleLevel   = 300
+	maxBattlePlayers = 30
+)
+
+// catalogIDPattern is the allowed format of battle IDs and aliases
+var catalogIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_]*$`)
+
+// CatalogError is a problem at a specific line of a catalog file
+type CatalogError struct {
+	Source  string
+	Line    int
+	Message string
+}
+
+func (e *CatalogError) Error() string {
+	if e.Source == "" {
+		return e.Message
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Source, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Source, e.Message)
+}
+
+// CatalogErrors collects every problem found while loading a catalog
+type CatalogErrors []*CatalogError
+
+func (e CatalogErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// catalogRecord is a battle as written in a catalog file
+type catalogRecord struct {
+	ID          string            `yaml:"id" json:"id"`
+	Name        string            `yaml:"name" json:"name"`
+	Names       map[string]string `yaml:"names" json:"names"`
+	Type        string            `yaml:"type" json:"type"`
+	Level       int               `yaml:"level" json:"level"`
+	MinRank     int               `yaml:"min_rank" json:"min_rank"`
+	MaxPlayers  int               `yaml:"max_players" json:"max_players"`
+	Element     string            `yaml:"element" json:"element"`
+	RaidCode    string            `yaml:"raid_code" json:"raid_code"`
+	Honors      int               `yaml:"honors" json:"honors"`
+	APCost      int               `yaml:"ap_cost" json:"ap_cost"`
+	MeatCost    int               `yaml:"meat_cost" json:"meat_cost"`
+	ClearTime   int               `yaml:"clear_time" json:"clear_time"`
+	Aliases     []string          `yaml:"aliases" json:"aliases"`
+	Active      *bool             `yaml:"active" json:"active"`
+	Description string            `yaml:"description" json:"description"`
+}
+
+// catalogFields are the field names of a catalog record
+var catalogFields = []string{
+	"id", "name", "names", "type", "level", "min_rank", "max_players",
+	"element", "raid_code", "honors", "ap_cost", "meat_cost", "clear_time",
+	"aliases", "active", "description",
+}
+
+// catalogEntry is a parsed battle and where it was defined
+type catalogEntry struct {
+	battle *BattleInfo
+	source string
+	line   int
+}
+
+// DefaultBattles returns the built-in battle catalog
+func DefaultBattles() []*BattleInfo {
+	entries, err := defaultCatalog()
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in battle catalog: %v", err))
+	}
+	return catalogBattles(entries)
+}
+
+// LoadCatalog returns the built-in battle catalog merged with the file at
+// overridePath. Battles in the file replace built-in battles with the same ID
+// and new IDs are added. An empty path returns the built-in catalog.
+func LoadCatalog(overridePath string) ([]*BattleInfo, error) {
+	entries, err := defaultCatalog()
+	if err != nil {
+		return nil, err
+	}
+	if overridePath == "" {
+		return catalogBattles(entries), nil
+	}
+
+	data, err := os.ReadFile(overridePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read battle catalog: %w", err)
+	}
+	overrides, err := parseCatalog(data, filepath.Ext(overridePath), overridePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeCatalog(entries, overrides)
+}
+
+// MergeCatalog returns base with battles of overrides replacing battles with the
+// same ID and new IDs added, e.g. to apply an imported sheet
+func MergeCatalog(base, overrides []*BattleInfo) ([]*BattleInfo, error) {
+	entries := make([]catalogEntry, len(base))
+	for i, battle := range base {
+		entries[i] = catalogEntry{battle: battle}
+	}
+	overrideEntries := make([]catalogEntry, len(overrides))
+	for i, battle := range overrides {
+		overrideEntries[i] = catalogEntry{battle: battle}
+	}
+	return mergeCatalog(entries, overrideEntries)
+}
+
+// mergeCatalog applies overrides to entries by battle ID and checks that aliases stay unique
+func mergeCatalog(entries, overrides []catalogEntry) ([]*BattleInfo, error) {
+	index := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		index[entry.battle.ID] = i
+	}
+	for _, entry := range overrides {
+		if i, exists := index[entry.battle.ID]; exists {
+			entries[i] = entry
+			continue
+		}
+		index[entry.battle.ID] = len(entries)
+		entries = append(entries, entry)
+	}
+
+	if errs := checkAliases(entries); len(errs) > 0 {
+		return nil, errs
+	}
+	return catalogBattles(entries), nil
+}
+
+// ParseCatalog parses and validates a battle catalog. format is a file
+// extension such as ".yaml", ".json" or ".csv"; source names the data in errors.
+func ParseCatalog(data []byte, format, source string) ([]*BattleInfo, error) {
+	entries, err := parseCatalog(data, format, source)
+	if err != nil {
+		return nil, err
+	}
+	return catalogBattles(entries), nil
+}
+
+// defaultCatalog parses the embedded catalog
+func defaultCatalog() ([]catalogEntry, error) {
+	data, err := catalogFS.ReadFile(defaultCatalogSource)
+	if err != nil {
+		return nil, err
+	}
+	return parseCatalog(data, ".yaml", defaultCatalogSource)
+}
+
+// parseCatalog decodes a catalog in the given format and validates every battle
+func parseCatalog(data []byte, format, source string) ([]catalogEntry, error) {
+	var records []catalogRecordAt
+	var errs CatalogErrors
+	switch strings.ToLower(format) {
+	case ".yaml", ".yml":
+		records, errs = decodeYAMLCatalog(data, source)
+	case ".json":
+		records, errs = decodeJSONCatalog(data, source)
+	case ".csv":
+		records, errs = decodeCSVCatalog(data, source)
+	default:
+		return nil, fmt.Errorf("unsupported battle catalog format %q: use .yaml, .yml, .json or .csv", format)
+	}
+
+	entries := make([]catalogEntry, 0, len(records))
+	lines := make(map[string]int, len(records))
+	for _, record := range records {
+		battle, messages := record.toBattle()
+		for _, message := range messages {
+			errs = append(errs, &CatalogError{Source: source, Line: record.line, Message: message})
+		}
+		if battle == nil {
+			continue
+		}
+		if line, exists := lines[battle.ID]; exists {
+			errs = append(errs, &CatalogError{Source: source, Line: record.line,
+				Message: fmt.Sprintf("duplicate battle ID %q (first defined at line %d)", battle.ID, line)})
+			continue
+		}
+		lines[battle.ID] = record.line
+		entries = append(entries, catalogEntry{battle: battle, source: source, line: record.line})
+	}
+	errs = append(errs, checkAliases(entries)...)
+
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+		return nil, errs
+	}
+	return entries, nil
+}
+
+// catalogRecordAt is a decoded record and the line it starts on
+type catalogRecordAt struct {
+	catalogRecord
+	line int
+}
+
+// toBattle validates a record and converts it to battle information.
+// The battle is nil when the record is unusable.
+func (r *catalogRecordAt) toBattle() (*BattleInfo, []string) {
+	var messages []string
+	id := strings.ToLower(strings.TrimSpace(r.ID))
+	switch {
+	case id == "":
+		messages = append(messages, "id is required")
+	case !catalogIDPattern.MatchString(id):
+		messages = append(messages, fmt.Sprintf("invalid id %q: use lowercase letters, digits and underscores", r.ID))
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		messages = append(messages, "name is required")
+	}
+	if !IsValidBattleType(BattleType(r.Type)) {
+		messages = append(messages, fmt.Sprintf("invalid type %q", r.Type))
+	}
+	if r.Level < 1 || r.Level > maxBattleLevel {
+		messages = append(messages, fmt.Sprintf("level must be between 1 and %d", maxBattleLevel))
+	}
+	if r.MinRank < 0 {
+		messages = append(messages, "min_rank cannot be negative")
+	}
+	if r.MaxPlayers < 1 || r.MaxPlayers > maxBattlePlayers {
+		messages = append(messages, fmt.Sprintf("max_players must be between 1 and %d", maxBattlePlayers))
+	}
+	element, err := ParseElement(r.Element)
+	if err != nil {
+		messages = append(messages, err.Error())
+	}
+	guildWar, guildWarMessages := r.guildWar()
+	messages = append(messages, guildWarMessages...)
+
+	var aliases []string
+	for _, alias := range r.Aliases {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		if !catalogIDPattern.MatchString(alias) {
+			messages = append(messages, fmt.Sprintf("invalid alias %q: use lowercase letters, digits and underscores", alias))
+			continue
+		}
+		if alias == id || slices.Contains(aliases, alias) {
+			messages = append(messages, fmt.Sprintf("duplicate alias %q", alias))
+			continue
+		}
+		aliases = append(aliases, alias)
+	}
+
+	var names map[string]string
+	for locale, name := range r.Names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		if names == nil {
+			names = make(map[string]string, len(r.Names))
+		}
+		names[strings.ToLower(locale)] = name
+	}
+
+	if len(messages) > 0 {
+		return nil, messages
+	}
+
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return &BattleInfo{
+		ID:          id,
+		Name:        r.Name,
+		Names:       names,
+		Type:        BattleType(r.Type),
+		Level:       r.Level,
+		MinRank:     r.MinRank,
+		MaxPlayers:  r.MaxPlayers,
+		Element:     element,
+		RaidCode:    r.RaidCode,
+		GuildWar:    guildWar,
+		Aliases:     aliases,
+		Description: r.Description,
+		IsActive:    active,
+	}, nil
+}
+
+// guildWar validates the Guild War fields of a record, which only nightmare
+// battles have. The info is nil when the record has none.
+func (r *catalogRecordAt) guildWar() (*GuildWarInfo, []string) {
+	if r.Honors == 0 && r.APCost == 0 && r.MeatCost == 0 && r.ClearTime == 0 {
+		return nil, nil
+	}
+	if BattleType(r.Type) != BattleTypeGWNM {
+		return nil, []string{fmt.Sprintf("honors, ap_cost, meat_cost and clear_time are only for %s battles", BattleTypeGWNM)}
+	}
+
+	var messages []string
+	if r.Honors <= 0 {
+		messages = append(messages, "honors must be positive")
+	}
+	if r.APCost < 0 || r.MeatCost < 0 || r.ClearTime < 0 {
+		messages = append(messages, "ap_cost, meat_cost and clear_time cannot be negative")
+	}
+	if len(messages) > 0 {
+		return nil, messages
+	}
+	return &GuildWarInfo{
+		Honors:    r.Honors,
+		APCost:    r.APCost,
+		MeatCost:  r.MeatCost,
+		ClearTime: time.Duration(r.ClearTime) * time.Second,
+	}, nil
+}
+
+// checkAliases reports aliases that collide with a battle ID or another battle's alias
+func checkAliases(entries []catalogEntry) CatalogErrors {
+	owners := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		owners[entry.battle.ID] = entry.battle.ID
+	}
+
+	var errs CatalogErrors
+	for _, entry := range entries {
+		for _, alias := range entry.battle.Aliases {
+			if owner, exists := owners[alias]; exists && owner != entry.battle.ID {
+				errs = append(errs, &CatalogError{Source: entry.source, Line: entry.line,
+					Message: fmt.Sprintf("alias %q is already used by battle %q", alias, owner)})
+				continue
+			}
+			owners[alias] = entry.battle.ID
+		}
+	}
+	return errs
+}
+
+// catalogBattles returns the battles of catalog entries
+func catalogBattles(entries []catalogEntry) []*BattleInfo {
+	battles := make([]*BattleInfo, len(entries))
+	for i, entry := range entries {
+		battles[i] = entry.battle
+	}
+	return battles
+}
+
+// decodeYAMLCatalog decodes a YAML catalog of the form "battles: [...]"
+func decodeYAMLCatalog(data []byte, source string) ([]catalogRecordAt, CatalogErrors) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, CatalogErrors{yamlError(err, source)}
+	}
+	if len(document.Content) == 0 {
+		return nil, CatalogErrors{{Source: source, Message: "catalog is empty"}}
+	}
+
+	root := document.Content[0]
+	battles, errs := yamlBattlesNode(root, source)
+	if battles == nil {
+		return nil, errs
+	}
+
+	records := make([]catalogRecordAt, 0, len(battles.Content))
+	for _, node := range battles.Content {
+		if node.Kind != yaml.MappingNode {
+			errs = append(errs, &CatalogError{Source: source, Line: node.Line, Message: "battle must be a mapping"})
+			continue
+		}
+		known := true
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if !slices.Contains(catalogFields, key.Value) {
+				errs = append(errs, &CatalogError{Source: source, Line: key.Line, Message: fmt.Sprintf("unknown field %q", key.Value)})
+				known = false
+			}
+		}
+		if !known {
+			continue
+		}
+
+		record := catalogRecordAt{line: node.Line}
+		if err := node.Decode(&record.catalogRecord); err != nil {
+			errs = append(errs, yamlError(err, source))
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, errs
+}
+
+// yamlBattlesNode returns the sequence node under the top-level "battles" key
+func yamlBattlesNode(root *yaml.Node, source string) (*yaml.Node, CatalogErrors) {
+	if root.Kind != yaml.MappingNode {
+		return nil, CatalogErrors{{Source: source, Line: root.Line, Message: `catalog must be a mapping with a "battles" list`}}
+	}
+
+	var battles *yaml.Node
+	var errs CatalogErrors
+	for i := 0; i < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != "battles" {
+			errs = append(errs, &CatalogError{Source: source, Line: key.Line, Message: fmt.Sprintf("unknown field %q", key.Value)})
+			continue
+		}
+		if value.Kind != yaml.SequenceNode {
+			errs = append(errs, &CatalogError{Source: source, Line: value.Line, Message: `"battles" must be a list`})
+			continue
+		}
+		battles = value
+	}
+	if battles == nil && len(errs) == 0 {
+		errs = append(errs, &CatalogError{Source: source, Line: root.Line, Message: `missing "battles" list`})
+	}
+	return battles, errs
+}
+
+// yamlLinePattern extracts the line number from yaml.v3 error messages
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlError converts a yaml.v3 error to catalog errors with line numbers
+func yamlError(err error, source string) *CatalogError {
+	message := err.Error()
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		message = typeErr.Errors[0]
+	}
+	if match := yamlLinePattern.FindStringSubmatch(message); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return &CatalogError{Source: source, Line: line, Message: match[2]}
+	}
+	return &CatalogError{Source: source, Message: strings.TrimPrefix(message, "yaml: ")}
+}
+
+// decodeJSONCatalog decodes a JSON catalog of the form {"battles": [...]}
+func decodeJSONCatalog(data []byte, source string) ([]catalogRecordAt, CatalogErrors) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	records, err := readJSONCatalog(decoder, data)
+	if err != nil {
+		err.Source = source
+		return nil, CatalogErrors{err}
+	}
+	return records, nil
+}
+
+// readJSONCatalog reads the battles of a JSON catalog. JSON errors stop decoding
+// since the decoder cannot resume after them.
+func readJSONCatalog(decoder *json.Decoder, data []byte) ([]catalogRecordAt, *CatalogError) {
+	fail := func(offset int64, message string) *CatalogError {
+		return &CatalogError{Line: lineAt(data, offset), Message: message}
+	}
+	jsonFail := func(err error) *CatalogError {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return fail(syntaxErr.Offset, syntaxErr.Error())
+		case errors.As(err, &typeErr):
+			return fail(typeErr.Offset, fmt.Sprintf("%s must be of type %s", typeErr.Field, typeErr.Type))
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return fail(int64(len(data)), "unexpected end of JSON input")
+		}
+		return fail(decoder.InputOffset(), strings.TrimPrefix(err.Error(), "json: "))
+	}
+	expect := func(want json.Delim, message string) *CatalogError {
+		token, err := decoder.Token()
+		if err != nil {
+			return jsonFail(err)
+		}
+		if token != want {
+			return fail(decoder.InputOffset(), message)
+		}
+		return nil
+	}
+
+	if err := expect('{', `catalog must be an object with a "battles" list`); err != nil {
+		return nil, err
+	}
+
+	var records []catalogRecordAt
+	found := false
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, jsonFail(err)
+		}
+		if token != "battles" {
+			return nil, fail(decoder.InputOffset(), fmt.Sprintf("unknown field %q", token))
+		}
+		found = true
+		if err := expect('[', `"battles" must be a list`); err != nil {
+			return nil, err
+		}
+		for decoder.More() {
+			record := catalogRecordAt{line: lineAt(data, nextValueOffset(data, decoder.InputOffset()))}
+			if err := decoder.Decode(&record.catalogRecord); err != nil {
+				return nil, jsonFail(err)
+			}
+			records = append(records, record)
+		}
+		if err := expect(']', `"battles" must be a list`); err != nil {
+			return nil, err
+		}
+	}
+	if !found {
+		return nil, fail(0, `missing "battles" list`)
+	}
+	return records, nil
+}
+
+// nextValueOffset skips whitespace and separators to the start of the next JSON value
+func nextValueOffset(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// lineAt returns the 1-based line number of a byte offset
+func lineAt(data []byte, offset int64) int {
+	offset = min(max(offset, 0), int64(len(data)))
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// decodeCSVCatalog decodes a CSV catalog with a header row. Aliases are separated
+// by "|" and localized names use name_<locale> columns, e.g. name_ja.
+func decodeCSVCatalog(data []byte, source string) ([]catalogRecordAt, CatalogErrors) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	csvFail := func(err error) ([]catalogRecordAt, CatalogErrors) {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, CatalogErrors{{Source: source, Line: parseErr.StartLine, Message: parseErr.Err.Error()}}
+		}
+		return nil, CatalogErrors{{Source: source, Message: err.Error()}}
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, CatalogErrors{{Source: source, Message: "catalog is empty"}}
+	}
+	if err != nil {
+		return csvFail(err)
+	}
+	headerLine, _ := reader.FieldPos(0)
+
+	var errs CatalogErrors
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		header[i] = column
+		if column == "names" || (!slices.Contains(catalogFields, column) && !strings.HasPrefix(column, "name_")) {
+			errs = append(errs, &CatalogError{Source: source, Line: headerLine, Message: fmt.Sprintf("unknown column %q", column)})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var records []catalogRecordAt
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return csvFail(err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		record := catalogRecordAt{line: line}
+		valid := true
+		for i, value := range row {
+			value = strings.TrimSpace(value)
+			if message := setCSVField(&record.catalogRecord, header[i], value); message != "" {
+				errs = append(errs, &CatalogError{Source: source, Line: line, Message: message})
+				valid = false
+			}
+		}
+		if valid {
+			records = append(records, record)
+		}
+	}
+	return records, errs
+}
+
+// setCSVField assigns a CSV cell to a record and returns a message if the value is invalid
+func setCSVField(record *catalogRecord, column, value string) string {
+	number := func(target *int) string {
+		if value == "" {
+			return ""
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Sprintf("%s must be a number, got %q", column, value)
+		}
+		*target = n
+		return ""
+	}
+
+	switch column {
+	case "id":
+		record.ID = value
+	case "name":
+		record.Name = value
+	case "type":
+		record.Type = value
+	case "level":
+		return number(&record.Level)
+	case "min_rank":
+		return number(&record.MinRank)
+	case "max_players":
+		return number(&record.MaxPlayers)
+	case "element":
+		record.Element = value
+	case "raid_code":
+		record.RaidCode = value
+	case "honors":
+		return number(&record.Honors)
+	case "ap_cost":
+		return number(&record.APCost)
+	case "meat_cost":
+		return number(&record.MeatCost)
+	case "clear_time":
+		return number(&record.ClearTime)
+	case "aliases":
+		for _, alias := range strings.Split(value, "|") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				record.Aliases = append(record.Aliases, alias)
+			}
+		}
+	case "active":
+		if value == "" {
+			return ""
+		}
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Sprintf("active must be true or false, got %q", value)
+		}
+		record.Active = &active
+	case "description":
+		record.Description = value
+	default:
+		if locale, found := strings.CutPrefix(column, "name_"); found && value != "" {
+			if record.Names == nil {
+				record.Names = make(map[string]string)
+			}
+			record.Names[locale] = value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,349 @@
+package gbf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDefaultBattles(t *testing.T) {
+	battles := DefaultBattles()
+
+	byID := make(map[string]*BattleInfo, len(battles))
+	for _, battle := range battles {
+		byID[battle.ID] = battle
+	}
+
+	for _, id := range []string{"subaha", "beelzebub_hl", "belial_hl", "wilnas", "fediel", "hexachromatic"} {
+		if _, exists := byID[id]; !exists {
+			t.Errorf("built-in catalog is missing %s", id)
+		}
+	}
+	if luci := byID["luci_hl"]; luci == nil || luci.Type != BattleTypeLuciHL {
+		t.Errorf("luci_hl = %+v, expected type %s", luci, BattleTypeLuciHL)
+	}
+	if nm := byID["gw_nm95"]; nm == nil || nm.IsActive {
+		t.Errorf("gw_nm95 = %+v, expected an inactive battle", nm)
+	}
+	if nm := byID["gw_nm200"]; nm == nil || nm.GuildWar == nil || nm.GuildWar.Honors == 0 || nm.GuildWar.ClearTime == 0 {
+		t.Errorf("gw_nm200 = %+v, expected Guild War rewards and clear time", nm)
+	}
+	if faa := byID["faa_hl"]; faa == nil || faa.Names["ja"] == "" || faa.Element != "dark" || faa.RaidCode == "" {
+		t.Errorf("faa_hl = %+v, expected localized name, element and raid code", faa)
+	}
+}
+
+func TestBattleManager_GetBattleByAlias(t *testing.T) {
+	bm := NewBattleManager()
+
+	battle, err := bm.GetBattle("PBHL")
+	if err != nil {
+		t.Fatalf("GetBattle(PBHL) unexpected error: %v", err)
+	}
+	if battle.ID != "baha_hl" {
+		t.Errorf("GetBattle(PBHL) = %s, expected baha_hl", battle.ID)
+	}
+
+	if err := bm.SetBattleActive("nm150", true); err != nil {
+		t.Fatalf("SetBattleActive(nm150) unexpected error: %v", err)
+	}
+	if battle, _ := bm.GetBattle("gw_nm150"); !battle.IsActive {
+		t.Error("SetBattleActive by alias did not update gw_nm150")
+	}
+
+	if err := bm.RemoveBattle("pbhl"); err != nil {
+		t.Fatalf("RemoveBattle(pbhl) unexpected error: %v", err)
+	}
+	if _, err := bm.GetBattle("pbhl"); err == nil {
+		t.Error("GetBattle(pbhl) expected error after removing the battle")
+	}
+}
+
+func TestParseCatalog(t *testing.T) {
+	yamlCatalog := `battles:
+  - id: test_hl
+    name: Test (Hard)
+    names: {ja: テストHL}
+    type: hl
+    level: 150
+    max_players: 6
+    element: Fire
+    aliases: [test]
+  - id: test_event
+    name: Test Event
+    type: event
+    level: 100
+    max_players: 30
+    active: false
+`
+	jsonCatalog := `{
+  "battles": [
+    {"id": "test_hl", "name": "Test (Hard)", "names": {"ja": "テストHL"}, "type": "hl",
+     "level": 150, "max_players": 6, "element": "fire", "aliases": ["test"]},
+    {"id": "test_event", "name": "Test Event", "type": "event", "level": 100,
+     "max_players": 30, "active": false}
+  ]
+}`
+	csvCatalog := `id,name,name_ja,type,level,min_rank,max_players,element,raid_code,active,aliases,description
+test_hl,Test (Hard),テストHL,hl,150,,6,fire,,,test,
+test_event,Test Event,,event,100,,30,,,false,,
+`
+
+	for _, tt := range []struct {
+		format string
+		data   string
+	}{
+		{".yaml", yamlCatalog},
+		{".json", jsonCatalog},
+		{".csv", csvCatalog},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			battles, err := ParseCatalog([]byte(tt.data), tt.format, "test"+tt.format)
+			if err != nil {
+				t.Fatalf("ParseCatalog unexpected error: %v", err)
+			}
+			if len(battles) != 2 {
+				t.Fatalf("ParseCatalog returned %d battles, expected 2", len(battles))
+			}
+
+			hl, event := battles[0], battles[1]
+			if hl.ID != "test_hl" || hl.Names["ja"] != "テストHL" || hl.Element != "fire" ||
+				!slices.Equal(hl.Aliases, []string{"test"}) || !hl.IsActive {
+				t.Errorf("first battle = %+v", hl)
+			}
+			if event.ID != "test_event" || event.IsActive || event.Names != nil {
+				t.Errorf("second battle = %+v", event)
+			}
+		})
+	}
+}
+
+func TestParseCatalog_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		data     string
+		expected []string
+	}{
+		{
+			name:   "yaml validation",
+			format: ".yaml",
+			data: `battles:
+  - id: ok
+    name: OK
+    type: hl
+    level: 100
+    max_players: 6
+  - id: Bad ID
+    name: ""
+    type: raid
+    level: 0
+    max_players: 31
+    element: void
+  - id: ok
+    name: Again
+    type: hl
+    level: 100
+    max_players: 6
+`,
+			expected: []string{
+				`test.yaml:7: invalid id "Bad ID": use lowercase letters, digits and underscores`,
+				"test.yaml:7: name is required",
+				`test.yaml:7: invalid type "raid"`,
+				"test.yaml:7: level must be between 1 and 300",
+				"test.yaml:7: max_players must be between 1 and 30",
+				`test.yaml:7: invalid element "void": must be one of fire, water, earth, wind, light, dark, all`,
+				`test.yaml:13: duplicate battle ID "ok" (first defined at line 2)`,
+			},
+		},
+		{
+			name:   "yaml unknown field",
+			format: ".yaml",
+			data: `battles:
+  - id: ok
+    name: OK
+    type: hl
+    level: 100
+    max_player: 6
+`,
+			expected: []string{`test.yaml:6: unknown field "max_player"`},
+		},
+		{
+			name:   "yaml wrong type",
+			format: ".yaml",
+			data: `battles:
+  - id: ok
+    name: OK
+    type: hl
+    level: high
+    max_players: 6
+`,
+			expected: []string{"test.yaml:5: cannot unmarshal !!str `high` into int"},
+		},
+		{
+			name:     "yaml syntax",
+			format:   ".yml",
+			data:     "battles:\n  - id: ok\n    name: [unclosed\n",
+			expected: []string{"test.yml:2: did not find expected ',' or ']'"},
+		},
+		{
+			name:   "alias conflict",
+			format: ".yaml",
+			data: `battles:
+  - id: first
+    name: First
+    type: hl
+    level: 100
+    max_players: 6
+    aliases: [second]
+  - id: second
+    name: Second
+    type: hl
+    level: 100
+    max_players: 6
+`,
+			expected: []string{`test.yaml:2: alias "second" is already used by battle "second"`},
+		},
+		{
+			name:   "guild war fields",
+			format: ".yaml",
+			data: `battles:
+  - id: nm
+    name: NM
+    type: gw_nm
+    level: 100
+    max_players: 30
+    ap_cost: 50
+  - id: hl
+    name: HL
+    type: hl
+    level: 100
+    max_players: 6
+    honors: 1000
+`,
+			expected: []string{
+				"test.yaml:2: honors must be positive",
+				"test.yaml:8: honors, ap_cost, meat_cost and clear_time are only for gw_nm battles",
+			},
+		},
+		{
+			name:   "json unknown field",
+			format: ".json",
+			data: `{"battles": [
+  {"id": "ok", "name": "OK", "type": "hl", "level": 100, "max_players": 6},
+  {"id": "other", "nmae": "Other"}
+]}`,
+			expected: []string{`test.json:3: unknown field "nmae"`},
+		},
+		{
+			name:   "json wrong type",
+			format: ".json",
+			data: `{"battles": [
+  {"id": "ok", "name": "OK", "type": "hl",
+   "level": "100", "max_players": 6}
+]}`,
+			expected: []string{"test.json:3: level must be of type int"},
+		},
+		{
+			name:     "json syntax",
+			format:   ".json",
+			data:     "{\"battles\": [\n  {\"id\": \"ok\",,}\n]}",
+			expected: []string{"test.json:2: invalid character ',' looking for beginning of object key string"},
+		},
+		{
+			name:   "json validation",
+			format: ".json",
+			data: `{"battles": [
+  {"id": "ok", "name": "OK", "type": "hl", "level": 100, "max_players": 6},
+  {"id": "bad", "name": "Bad", "type": "hl", "level": 100, "max_players": 0}
+]}`,
+			expected: []string{"test.json:3: max_players must be between 1 and 30"},
+		},
+		{
+			name:     "csv unknown column",
+			format:   ".csv",
+			data:     "id,name,typ\nok,OK,hl\n",
+			expected: []string{`test.csv:1: unknown column "typ"`},
+		},
+		{
+			name:   "csv validation",
+			format: ".csv",
+			data: `# Comment lines are skipped
+id,name,type,level,max_players,active
+ok,OK,hl,100,6,
+bad,Bad,hl,ten,6,maybe
+`,
+			expected: []string{
+				`test.csv:4: level must be a number, got "ten"`,
+				`test.csv:4: active must be true or false, got "maybe"`,
+			},
+		},
+		{
+			name:     "csv syntax",
+			format:   ".csv",
+			data:     "id,name\nok,\"OK\nbad,Bad\n",
+			expected: []string{`test.csv:2: extraneous or missing " in quoted-field`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCatalog([]byte(tt.data), tt.format, "test"+tt.format)
+			var errs CatalogErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("ParseCatalog error = %v, expected CatalogErrors", err)
+			}
+
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("ParseCatalog errors =\n%s\nexpected\n%s", strings.Join(got, "\n"), strings.Join(tt.expected, "\n"))
+			}
+		})
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "battles.csv")
+	data := `id,name,type,level,min_rank,max_players,aliases
+baha_hl,Proto Bahamut (Hard),baha_hl,150,101,30,pbhl
+custom_raid,Custom Raid,custom,120,50,6,cr
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	battles, err := LoadCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadCatalog unexpected error: %v", err)
+	}
+	if len(battles) != len(DefaultBattles())+1 {
+		t.Errorf("LoadCatalog returned %d battles, expected %d", len(battles), len(DefaultBattles())+1)
+	}
+
+	bm := NewBattleManager()
+	bm.Replace(battles)
+	if baha, _ := bm.GetBattle("baha_hl"); baha.MaxPlayers != 30 {
+		t.Errorf("baha_hl max players = %d, expected the override 30", baha.MaxPlayers)
+	}
+	if _, err := bm.GetBattle("cr"); err != nil {
+		t.Errorf("GetBattle(cr) unexpected error: %v", err)
+	}
+
+	// An override alias may not take over a built-in battle's alias
+	if err := os.WriteFile(path, []byte("id,name,type,level,max_players,aliases\nmine,Mine,custom,100,6,ubhl\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCatalog(path); err == nil || !strings.Contains(err.Error(), `alias "ubhl" is already used by battle "ubaha_hl"`) {
+		t.Errorf("LoadCatalog error = %v, expected alias conflict", err)
+	}
+
+	if _, err := LoadCatalog(filepath.Join(t.TempDir(), "battles.txt")); err == nil {
+		t.Error("LoadCatalog expected error for a missing file")
+	}
+}
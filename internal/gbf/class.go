@@ -0,0 +1,336 @@
+package gbf
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed data/classes.yaml
+var classFS embed.FS
+
+// defaultClassSource is the name of the embedded class catalog in error messages
+const defaultClassSource = "data/classes.yaml"
+
+// ProficiencyMultiplier is the ATK multiplier of a weapon the class is proficient with
+const ProficiencyMultiplier = 1.2
+
+// ErrUnknownClass is returned for class names that are not in the catalog
+var ErrUnknownClass = errors.New("unknown class")
+
+// ClassRow is the row of a class, from the starting classes to the extra classes
+type ClassRow string
+
+const (
+	ClassRowI   ClassRow = "I"
+	ClassRowII  ClassRow = "II"
+	ClassRowIII ClassRow = "III"
+	ClassRowIV  ClassRow = "IV"
+	ClassRowV   ClassRow = "V"
+	ClassRowEX  ClassRow = "EX"
+)
+
+// ClassRows are the valid class rows
+var ClassRows = []ClassRow{ClassRowI, ClassRowII, ClassRowIII, ClassRowIV, ClassRowV, ClassRowEX}
+
+// ClassModifier is a passive effect of a class
+type ClassModifier string
+
+const (
+	ClassModifierAttack       ClassModifier = "attack"
+	ClassModifierDoubleAttack ClassModifier = "double_attack"
+	ClassModifierTripleAttack ClassModifier = "triple_attack"
+	ClassModifierCritRate     ClassModifier = "crit_rate"
+	ClassModifierCapUp        ClassModifier = "cap_up"
+)
+
+// ClassModifiers are the valid passive effects in display order
+var ClassModifiers = []ClassModifier{
+	ClassModifierAttack, ClassModifierDoubleAttack, ClassModifierTripleAttack,
+	ClassModifierCritRate, ClassModifierCapUp,
+}
+
+// classProficiencies is the number of proficient weapon types of every class
+const classProficiencies = 2
+
+// Class is a main character class and its weapon proficiencies
+type Class struct {
+	ID    string
+	Name  string
+	Names map[string]string
+	Row   ClassRow
+	// Proficiencies are the weapon types the class gets ProficiencyMultiplier with
+	Proficiencies []WeaponType
+	// Modifiers are passive effects, e.g. 0.1 for +10%
+	Modifiers map[ClassModifier]float64
+	Aliases   []string
+}
+
+// IsProficient reports whether the class is proficient with the weapon type
+func (c *Class) IsProficient(weaponType WeaponType) bool {
+	return slices.Contains(c.Proficiencies, weaponType)
+}
+
+// WeaponMultiplier returns the ATK multiplier of a weapon type for the class
+func (c *Class) WeaponMultiplier(weaponType WeaponType) (float64, error) {
+	if !IsValidWeaponType(weaponType) {
+		return 0, fmt.Errorf("invalid weapon type: %s", weaponType)
+	}
+	if c.IsProficient(weaponType) {
+		return ProficiencyMultiplier, nil
+	}
+	return 1.0, nil
+}
+
+// ClassCatalog is a read-only set of classes looked up by ID, name or alias
+type ClassCatalog struct {
+	classes []*Class
+	byKey   map[string]*Class
+}
+
+// defaultClasses parses the embedded class catalog once
+var defaultClasses = sync.OnceValue(func() *ClassCatalog {
+	data, err := classFS.ReadFile(defaultClassSource)
+	if err == nil {
+		var catalog *ClassCatalog
+		if catalog, err = ParseClassCatalog(data, defaultClassSource); err == nil {
+			return catalog
+		}
+	}
+	panic(fmt.Sprintf("invalid built-in class catalog: %v", err))
+})
+
+// DefaultClasses returns the built-in class catalog
+func DefaultClasses() *ClassCatalog {
+	return defaultClasses()
+}
+
+// classKey normalizes a class name for lookups, e.g. "Weapon Master" to "weapon_master"
+func classKey(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+// Get returns the class with the given ID, name in any language or alias, ignoring case
+func (c *ClassCatalog) Get(name string) (*Class, error) {
+	if class, exists := c.byKey[classKey(name)]; exists {
+		return class, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownClass, name)
+}
+
+// All returns every class ordered by ID
+func (c *ClassCatalog) All() []*Class {
+	return slices.Clone(c.classes)
+}
+
+// Search returns up to limit classes whose ID, alias or name in any language
+// contains query, ignoring case. Exact matches come first, then prefixes, then
+// substrings, with ties ordered by ID. A limit of 0 or less returns all matches.
+func (c *ClassCatalog) Search(query string, limit int) []*Class {
+	return searchCatalog(c.classes, (*Class).terms, query, limit)
+}
+
+// searchCatalog returns up to limit items with a term containing query, compared
+// as catalog keys. Exact matches come first, then prefixes, then substrings, with
+// ties in the order of items. A limit of 0 or less returns all matches.
+func searchCatalog[T any](items []T, terms func(T) []string, query string, limit int) []T {
+	query = classKey(query)
+
+	type match struct {
+		item T
+		rank int
+	}
+	var matches []match
+	for _, item := range items {
+		best := -1
+		for _, term := range terms(item) {
+			term = classKey(term)
+			rank := -1
+			switch {
+			case term == query:
+				rank = 0
+			case strings.HasPrefix(term, query):
+				rank = 1
+			case strings.Contains(term, query):
+				rank = 2
+			}
+			if rank >= 0 && (best < 0 || rank < best) {
+				best = rank
+			}
+		}
+		if best >= 0 {
+			matches = append(matches, match{item: item, rank: best})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].rank < matches[j].rank
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	found := make([]T, len(matches))
+	for i, m := range matches {
+		found[i] = m.item
+	}
+	return found
+}
+
+// terms are the names the class can be looked up by
+func (c *Class) terms() []string {
+	terms := append([]string{c.ID, c.Name}, c.Aliases...)
+	for _, name := range c.Names {
+		terms = append(terms, name)
+	}
+	return terms
+}
+
+// classFields are the fields of a class in a catalog file
+var classFields = []string{"id", "name", "names", "row", "proficiencies", "modifiers", "aliases"}
+
+// classRecord is a class as written in a catalog file
+type classRecord struct {
+	ID            string             `yaml:"id"`
+	Name          string             `yaml:"name"`
+	Names         map[string]string  `yaml:"names"`
+	Row           string             `yaml:"row"`
+	Proficiencies []string           `yaml:"proficiencies"`
+	Modifiers     map[string]float64 `yaml:"modifiers"`
+	Aliases       []string           `yaml:"aliases"`
+}
+
+// classRecordAt is a decoded class and the line it starts on
+type classRecordAt struct {
+	classRecord
+	line int
+}
+
+// UnmarshalYAML records the line of the class and rejects unknown fields
+func (r *classRecordAt) UnmarshalYAML(node *yaml.Node) error {
+	r.line = node.Line
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i < len(node.Content); i += 2 {
+			if key := node.Content[i]; !slices.Contains(classFields, key.Value) {
+				return fmt.Errorf("line %d: unknown field %q", key.Line, key.Value)
+			}
+		}
+	}
+	return node.Decode(&r.classRecord)
+}
+
+// ParseClassCatalog decodes a YAML class catalog of the form "classes: [...]"
+// and validates every class
+func ParseClassCatalog(data []byte, source string) (*ClassCatalog, error) {
+	var file struct {
+		Classes []classRecordAt `yaml:"classes"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, CatalogErrors{yamlError(err, source)}
+	}
+	if len(file.Classes) == 0 {
+		return nil, CatalogErrors{{Source: source, Message: "catalog has no classes"}}
+	}
+
+	catalog := &ClassCatalog{byKey: make(map[string]*Class)}
+	var errs CatalogErrors
+	for _, record := range file.Classes {
+		class, messages := record.toClass()
+		for _, message := range messages {
+			errs = append(errs, &CatalogError{Source: source, Line: record.line, Message: message})
+		}
+		if class == nil {
+			continue
+		}
+		for _, term := range class.terms() {
+			key := classKey(term)
+			if other, exists := catalog.byKey[key]; exists && other != class {
+				errs = append(errs, &CatalogError{Source: source, Line: record.line,
+					Message: fmt.Sprintf("%q is already used by class %q", term, other.ID)})
+				continue
+			}
+			catalog.byKey[key] = class
+		}
+		catalog.classes = append(catalog.classes, class)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	sort.Slice(catalog.classes, func(i, j int) bool {
+		return catalog.classes[i].ID < catalog.classes[j].ID
+	})
+	return catalog, nil
+}
+
+// toClass validates a record and converts it to a class.
+// The class is nil when the record is unusable.
+func (r *classRecordAt) toClass() (*Class, []string) {
+	var messages []string
+	id := strings.ToLower(strings.TrimSpace(r.ID))
+	switch {
+	case id == "":
+		messages = append(messages, "id is required")
+	case !catalogIDPattern.MatchString(id):
+		messages = append(messages, fmt.Sprintf("invalid id %q: use lowercase letters, digits and underscores", r.ID))
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		messages = append(messages, "name is required")
+	}
+
+	row := ClassRow(strings.ToUpper(strings.TrimSpace(r.Row)))
+	if !slices.Contains(ClassRows, row) {
+		messages = append(messages, fmt.Sprintf("invalid row %q", r.Row))
+	}
+
+	proficiencies := make([]WeaponType, 0, len(r.Proficiencies))
+	for _, value := range r.Proficiencies {
+		weaponType := WeaponType(strings.ToLower(strings.TrimSpace(value)))
+		switch {
+		case !IsValidWeaponType(weaponType):
+			messages = append(messages, fmt.Sprintf("invalid weapon type %q", value))
+		case slices.Contains(proficiencies, weaponType):
+			messages = append(messages, fmt.Sprintf("duplicate proficiency %q", value))
+		default:
+			proficiencies = append(proficiencies, weaponType)
+		}
+	}
+	if len(r.Proficiencies) != classProficiencies {
+		messages = append(messages, fmt.Sprintf("a class needs %d proficiencies, got %d", classProficiencies, len(r.Proficiencies)))
+	}
+
+	modifiers := make(map[ClassModifier]float64, len(r.Modifiers))
+	for name, value := range r.Modifiers {
+		modifier := ClassModifier(name)
+		switch {
+		case !slices.Contains(ClassModifiers, modifier):
+			messages = append(messages, fmt.Sprintf("unknown modifier %q", name))
+		case value <= 0 || value > 1:
+			messages = append(messages, fmt.Sprintf("modifier %s must be greater than 0 and at most 1", name))
+		default:
+			modifiers[modifier] = value
+		}
+	}
+
+	if len(messages) > 0 {
+		return nil, messages
+	}
+	return &Class{
+		ID:            id,
+		Name:          strings.TrimSpace(r.Name),
+		Names:         r.Names,
+		Row:           row,
+		Proficiencies: proficiencies,
+		Modifiers:     modifiers,
+		Aliases:       r.Aliases,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package gbf
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDefaultClasses(t *testing.T) {
+	classes := DefaultClasses()
+
+	for _, id := range []string{"fighter", "berserker", "sage", "kengo", "chaos_ruler", "lumberjack"} {
+		class, err := classes.Get(id)
+		if err != nil {
+			t.Errorf("built-in catalog is missing %s", id)
+			continue
+		}
+		if len(class.Proficiencies) != 2 || class.Names["ja"] == "" {
+			t.Errorf("%s = %+v, expected two proficiencies and a Japanese name", id, class)
+		}
+	}
+	if !slices.IsSortedFunc(classes.All(), func(a, b *Class) int { return strings.Compare(a.ID, b.ID) }) {
+		t.Error("All() is not ordered by ID")
+	}
+}
+
+func TestClassCatalog_Get(t *testing.T) {
+	classes := DefaultClasses()
+
+	tests := []struct {
+		name   string
+		query  string
+		wantID string
+	}{
+		{name: "id", query: "weapon_master", wantID: "weapon_master"},
+		{name: "name with spaces", query: "Weapon Master", wantID: "weapon_master"},
+		{name: "alias", query: "ZERK", wantID: "berserker"},
+		{name: "japanese name", query: "ケンセイ", wantID: "kengo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, err := classes.Get(tt.query)
+			if err != nil {
+				t.Fatalf("Get(%q) unexpected error: %v", tt.query, err)
+			}
+			if class.ID != tt.wantID {
+				t.Errorf("Get(%q) = %s, expected %s", tt.query, class.ID, tt.wantID)
+			}
+		})
+	}
+
+	if _, err := classes.Get("monk"); !errors.Is(err, ErrUnknownClass) {
+		t.Errorf("Get(monk) error = %v, expected ErrUnknownClass", err)
+	}
+}
+
+func TestClassCatalog_Search(t *testing.T) {
+	classes := DefaultClasses()
+
+	var ids []string
+	for _, class := range classes.Search("s", 3) {
+		ids = append(ids, class.ID)
+	}
+	// Prefix matches come before substrings
+	if want := []string{"sage", "samurai", "sorcerer"}; !slices.Equal(ids, want) {
+		t.Errorf("Search(s) = %v, expected %v", ids, want)
+	}
+	if got := classes.Search("", 0); len(got) != len(classes.All()) {
+		t.Errorf("Search(\"\") returned %d classes, expected all %d", len(got), len(classes.All()))
+	}
+}
+
+func TestClass_WeaponMultiplier(t *testing.T) {
+	class, err := DefaultClasses().Get("runeslayer")
+	if err != nil {
+		t.Fatalf("Get(runeslayer) unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		weaponType WeaponType
+		want       float64
+	}{
+		{WeaponTypeAxe, ProficiencyMultiplier},
+		{WeaponTypeStaff, ProficiencyMultiplier},
+		{WeaponTypeSword, 1.0},
+	}
+	for _, tt := range tests {
+		got, err := class.WeaponMultiplier(tt.weaponType)
+		if err != nil {
+			t.Fatalf("WeaponMultiplier(%s) unexpected error: %v", tt.weaponType, err)
+		}
+		if got != tt.want {
+			t.Errorf("WeaponMultiplier(%s) = %f, expected %f", tt.weaponType, got, tt.want)
+		}
+	}
+	if _, err := class.WeaponMultiplier("lance"); err == nil {
+		t.Error("WeaponMultiplier(lance) expected error")
+	}
+}
+
+func TestParseClassCatalog_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "unknown field",
+			data: "classes:\n  - id: fighter\n    name: Fighter\n    row: I\n    proficiencies: [sword, axe]\n    job: true\n",
+			want: `test.yaml:6: unknown field "job"`,
+		},
+		{
+			name: "one proficiency",
+			data: "classes:\n  - id: fighter\n    name: Fighter\n    row: I\n    proficiencies: [sword]\n",
+			want: "test.yaml:2: a class needs 2 proficiencies, got 1",
+		},
+		{
+			name: "invalid weapon type and row",
+			data: "classes:\n  - id: fighter\n    name: Fighter\n    row: VI\n    proficiencies: [sword, lance]\n",
+			want: "test.yaml:2: invalid row \"VI\"\ntest.yaml:2: invalid weapon type \"lance\"",
+		},
+		{
+			name: "unknown modifier",
+			data: "classes:\n  - id: fighter\n    name: Fighter\n    row: I\n    proficiencies: [sword, axe]\n    modifiers: {echo: 0.1}\n",
+			want: `test.yaml:2: unknown modifier "echo"`,
+		},
+		{
+			name: "duplicate alias",
+			data: "classes:\n  - id: fighter\n    name: Fighter\n    row: I\n    proficiencies: [sword, axe]\n" +
+				"  - id: warrior\n    name: Warrior\n    row: II\n    proficiencies: [sword, axe]\n    aliases: [fighter]\n",
+			want: `test.yaml:6: "fighter" is already used by class "fighter"`,
+		},
+		{
+			name: "empty catalog",
+			data: "classes: []\n",
+			want: "catalog has no classes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseClassCatalog([]byte(tt.data), "test.yaml")
+			if err == nil {
+				t.Fatal("ParseClassCatalog() expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseClassCatalog() error = %q, expected it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
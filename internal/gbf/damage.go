@@ -0,0 +1,366 @@
+package gbf
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Defaults applied to zero fields of DamageInput
+const (
+	// DefaultEnemyDefense is the defense of most raid bosses
+	DefaultEnemyDefense = 10.0
+	// DefaultDefenseDownCap is the usual limit of stacked defense down
+	DefaultDefenseDownCap = 0.5
+)
+
+// maxCritSources bounds the crit sources of one attack, which keeps the outcome
+// distribution small
+const maxCritSources = 10
+
+// Damage step names, in the order Calculate applies them
+const (
+	DamageStepBaseAttack   = "base_attack"
+	DamageStepNormal       = "normal"
+	DamageStepOmega        = "omega"
+	DamageStepEX           = "ex"
+	DamageStepExLike       = "ex_like"
+	DamageStepElemental    = "elemental"
+	DamageStepStamina      = "stamina"
+	DamageStepEnmity       = "enmity"
+	DamageStepHit          = "hit_multiplier"
+	DamageStepDefense      = "defense"
+	DamageStepCritical     = "critical"
+	DamageStepCap          = "damage_cap"
+	DamageStepEcho         = "echo"
+	DamageStepSupplemental = "supplemental"
+)
+
+// DamageInput describes one hit for the damage pipeline. Boosts are fractions,
+// e.g. 0.8 for +80%. Each boost field is a modifier bucket: boosts within a
+// bucket add up, and buckets multiply with each other.
+type DamageInput struct {
+	// BaseAttack is the party member's ATK before any modifier
+	BaseAttack int
+
+	// Normal holds normal weapon skills (after summon auras) and normal ATK buffs
+	Normal float64
+	// Omega holds omega weapon skills after omega summon auras
+	Omega float64
+	// EX holds EX weapon skills such as Ultima and Opus
+	EX float64
+	// ExLike holds unique boosts that form their own bucket, e.g. character passives
+	ExLike float64
+	// Elemental holds elemental ATK boosts and elemental summon auras
+	Elemental float64
+	// Attacker and Defender are the elements of the hit and the enemy; their
+	// affinity adds the elemental modifier. Critical hits need advantage.
+	Attacker, Defender Element
+
+	// Stamina is the stamina boost at full HP
+	Stamina float64
+	// Enmity is the enmity boost at 0 HP
+	Enmity float64
+	// HPRatio is the current HP over max HP; 0 is treated as full HP
+	HPRatio float64
+
+	// HitMultiplier scales the hit, e.g. 4.5 for a charge attack; 0 is treated as 1
+	HitMultiplier float64
+
+	// Defense is the enemy's defense; 0 is treated as DefaultEnemyDefense
+	Defense float64
+	// DefenseDown is the total defense down on the enemy
+	DefenseDown float64
+	// DefenseDownCap limits DefenseDown; 0 is treated as DefaultDefenseDownCap
+	DefenseDownCap float64
+
+	// Crits are the independent critical hit sources
+	Crits []CritSource
+
+	// Kind decides the soft cap of the hit; empty is treated as DamageKindNormal
+	Kind DamageKind
+	// Cap overrides the soft cap of Kind
+	Cap SoftCap
+	// CapUp raises the soft cap, e.g. 0.2 for +20%
+	CapUp float64
+
+	// Echo is extra damage as a fraction of the capped hit, e.g. 0.3 for a 30% echo
+	Echo float64
+	// Supplemental is flat damage added to the hit, unaffected by modifiers and caps
+	Supplemental int
+}
+
+// CritSource is a chance of a critical hit. Multipliers of the sources that
+// trigger together add up.
+type CritSource struct {
+	// Rate is the chance of triggering, from 0 to 1
+	Rate float64
+	// Multiplier is the damage bonus, e.g. 0.5 for +50%
+	Multiplier float64
+}
+
+// CritOutcome is one possible result of the crit rolls of a hit
+type CritOutcome struct {
+	Probability float64
+	// Multiplier is the damage multiplier, 1 when nothing triggers
+	Multiplier float64
+	// Damage is the damage of the hit with this outcome, after the cap
+	Damage float64
+}
+
+// DamageStep is one step of a damage calculation
+type DamageStep struct {
+	// Name is one of the DamageStep constants
+	Name string
+	// Multiplier is what the step multiplied by; for defense it is the divisor
+	Multiplier float64
+	// Value is the running result after the step
+	Value float64
+}
+
+// DamageBreakdown is the result of a damage calculation
+type DamageBreakdown struct {
+	// Steps lists every modifier in the order applied
+	Steps []DamageStep
+	// Attack is the effective ATK after all modifier buckets
+	Attack float64
+	// EffectiveDefense is the enemy defense after defense down
+	EffectiveDefense float64
+	// Raw is the damage without crits or the cap
+	Raw float64
+	// Cap is the soft cap applied, after cap up
+	Cap SoftCap
+	// Crits are the crit outcomes, ordered by multiplier
+	Crits []CritOutcome
+	// Hit is the expected damage of the hit after the cap, before echo and supplemental damage
+	Hit int
+	// Min and Max are the per-hit damage of the weakest and strongest crit outcomes
+	Min, Max int
+	// Damage is the expected per-hit damage, including echo and supplemental damage
+	Damage int
+}
+
+// CapLoss returns the share of damage the soft cap takes away, from 0 to 1.
+// Near 1, more ATK barely raises the damage.
+func (b *DamageBreakdown) CapLoss() float64 {
+	for _, step := range b.Steps {
+		if step.Name == DamageStepCap {
+			return 1 - step.Multiplier
+		}
+	}
+	return 0
+}
+
+// StaminaBoost returns the stamina boost at hpRatio. The boost peaks at full HP
+// and fades quickly as HP drops.
+func StaminaBoost(maxBoost, hpRatio float64) float64 {
+	hpRatio = clamp(hpRatio, 0, 1)
+	return maxBoost * math.Pow(hpRatio, 2.9)
+}
+
+// EnmityBoost returns the enmity boost at hpRatio. The boost is 0 at full HP
+// and grows faster the lower HP gets, peaking at 0 HP.
+func EnmityBoost(maxBoost, hpRatio float64) float64 {
+	missing := 1 - clamp(hpRatio, 0, 1)
+	return maxBoost * missing * (1 + 2*missing) / 3
+}
+
+// Calculate runs a hit through the damage pipeline and explains each step
+func (c *AttackCalculator) Calculate(input DamageInput) (*DamageBreakdown, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+
+	hpRatio := input.HPRatio
+	if hpRatio == 0 {
+		hpRatio = 1
+	}
+	hitMultiplier := input.HitMultiplier
+	if hitMultiplier == 0 {
+		hitMultiplier = 1
+	}
+	defense := input.Defense
+	if defense == 0 {
+		defense = DefaultEnemyDefense
+	}
+	defenseDownCap := input.DefenseDownCap
+	if defenseDownCap == 0 {
+		defenseDownCap = DefaultDefenseDownCap
+	}
+	softCap := input.Cap
+	if softCap == nil {
+		softCap = CapFor(input.Kind)
+	}
+	softCap = softCap.Raise(input.CapUp)
+
+	breakdown := &DamageBreakdown{Cap: softCap}
+	value := float64(input.BaseAttack)
+	breakdown.Steps = append(breakdown.Steps, DamageStep{Name: DamageStepBaseAttack, Multiplier: 1, Value: value})
+	apply := func(name string, multiplier float64) {
+		value *= multiplier
+		breakdown.Steps = append(breakdown.Steps, DamageStep{Name: name, Multiplier: multiplier, Value: value})
+	}
+
+	apply(DamageStepNormal, 1+input.Normal)
+	apply(DamageStepOmega, 1+input.Omega)
+	apply(DamageStepEX, 1+input.EX)
+	apply(DamageStepExLike, 1+input.ExLike)
+	advantage := input.Attacker.Against(input.Defender)
+	apply(DamageStepElemental, 1+input.Elemental+advantage.Modifier())
+	apply(DamageStepStamina, 1+StaminaBoost(input.Stamina, hpRatio))
+	apply(DamageStepEnmity, 1+EnmityBoost(input.Enmity, hpRatio))
+	breakdown.Attack = value
+
+	apply(DamageStepHit, hitMultiplier)
+
+	breakdown.EffectiveDefense = defense * (1 - min(input.DefenseDown, defenseDownCap))
+	value /= breakdown.EffectiveDefense
+	breakdown.Steps = append(breakdown.Steps, DamageStep{Name: DamageStepDefense, Multiplier: breakdown.EffectiveDefense, Value: value})
+	breakdown.Raw = value
+
+	var crits []CritSource
+	if advantage == AffinityAdvantage {
+		crits = input.Crits
+	}
+	breakdown.Crits = critOutcomes(crits)
+
+	var expected, expectedMultiplier float64
+	for i := range breakdown.Crits {
+		outcome := &breakdown.Crits[i]
+		outcome.Damage = softCap.Apply(breakdown.Raw * outcome.Multiplier)
+		expected += outcome.Probability * outcome.Damage
+		expectedMultiplier += outcome.Probability * outcome.Multiplier
+	}
+	apply(DamageStepCritical, expectedMultiplier)
+	// The cap applies per crit outcome, so its step shows the combined reduction
+	breakdown.Steps = append(breakdown.Steps, DamageStep{Name: DamageStepCap, Multiplier: ratio(expected, value), Value: expected})
+	breakdown.Hit = int(math.Round(expected))
+
+	value = expected
+	apply(DamageStepEcho, 1+input.Echo)
+	supplemental := float64(input.Supplemental)
+	value += supplemental
+	breakdown.Steps = append(breakdown.Steps, DamageStep{Name: DamageStepSupplemental, Multiplier: ratio(value, value-supplemental), Value: value})
+
+	perHit := func(hit float64) int {
+		return int(math.Round(hit*(1+input.Echo) + supplemental))
+	}
+	breakdown.Min = perHit(breakdown.Crits[0].Damage)
+	breakdown.Max = perHit(breakdown.Crits[len(breakdown.Crits)-1].Damage)
+	breakdown.Damage = int(math.Round(value))
+	return breakdown, nil
+}
+
+// validate rejects inputs the pipeline cannot make sense of
+func (input DamageInput) validate() error {
+	if input.BaseAttack < 0 {
+		return fmt.Errorf("base attack cannot be negative: %d", input.BaseAttack)
+	}
+	if input.HPRatio < 0 || input.HPRatio > 1 {
+		return fmt.Errorf("HP ratio must be between 0 and 1: %g", input.HPRatio)
+	}
+	if input.HitMultiplier < 0 {
+		return fmt.Errorf("hit multiplier cannot be negative: %g", input.HitMultiplier)
+	}
+	if input.Defense < 0 {
+		return fmt.Errorf("enemy defense cannot be negative: %g", input.Defense)
+	}
+	if !input.Attacker.IsValid() || !input.Defender.IsValid() {
+		return fmt.Errorf("invalid elements: %q against %q", input.Attacker, input.Defender)
+	}
+	if input.DefenseDown < 0 || input.DefenseDownCap < 0 || input.DefenseDownCap >= 1 {
+		return fmt.Errorf("defense down cannot be negative and must be capped below 100%%: %g (cap %g)", input.DefenseDown, input.DefenseDownCap)
+	}
+	boosts := []struct {
+		name  string
+		boost float64
+	}{
+		{DamageStepNormal, input.Normal},
+		{DamageStepOmega, input.Omega},
+		{DamageStepEX, input.EX},
+		{DamageStepExLike, input.ExLike},
+		{DamageStepElemental, input.Elemental + input.Attacker.Against(input.Defender).Modifier()},
+		{DamageStepStamina, input.Stamina},
+		{DamageStepEnmity, input.Enmity},
+	}
+	for _, b := range boosts {
+		if b.boost <= -1 {
+			return fmt.Errorf("%s modifier cannot reduce damage by 100%% or more: %g", b.name, b.boost)
+		}
+	}
+	if len(input.Crits) > maxCritSources {
+		return fmt.Errorf("too many crit sources: %d (max %d)", len(input.Crits), maxCritSources)
+	}
+	for _, crit := range input.Crits {
+		if crit.Rate < 0 || crit.Rate > 1 || crit.Multiplier < 0 {
+			return fmt.Errorf("invalid crit source: rate %g, multiplier %g", crit.Rate, crit.Multiplier)
+		}
+	}
+	if !IsValidDamageKind(input.Kind) {
+		return fmt.Errorf("invalid damage kind: %s", input.Kind)
+	}
+	if input.CapUp < 0 || input.Echo < 0 || input.Supplemental < 0 {
+		return fmt.Errorf("cap up, echo and supplemental damage cannot be negative: %g, %g, %d", input.CapUp, input.Echo, input.Supplemental)
+	}
+	for i, tier := range input.Cap {
+		if tier.Threshold <= 0 || tier.Rate < 0 || tier.Rate > 1 || (i > 0 && tier.Threshold <= input.Cap[i-1].Threshold) {
+			return fmt.Errorf("invalid soft cap tier %d: threshold %g, rate %g", i+1, tier.Threshold, tier.Rate)
+		}
+	}
+	return nil
+}
+
+// critOutcomes returns the distribution of crit multipliers, merging outcomes
+// with the same multiplier
+func critOutcomes(sources []CritSource) []CritOutcome {
+	outcomes := []CritOutcome{{Probability: 1, Multiplier: 1}}
+	for _, source := range sources {
+		if source.Rate == 0 || source.Multiplier == 0 {
+			continue
+		}
+		next := make([]CritOutcome, 0, len(outcomes)*2)
+		for _, outcome := range outcomes {
+			next = addOutcome(next, outcome.Multiplier, outcome.Probability*(1-source.Rate))
+			next = addOutcome(next, outcome.Multiplier+source.Multiplier, outcome.Probability*source.Rate)
+		}
+		outcomes = next
+	}
+
+	slices.SortFunc(outcomes, func(a, b CritOutcome) int {
+		switch {
+		case a.Multiplier < b.Multiplier:
+			return -1
+		case a.Multiplier > b.Multiplier:
+			return 1
+		}
+		return 0
+	})
+	return outcomes
+}
+
+// addOutcome adds probability to the outcome with multiplier, creating it if needed
+func addOutcome(outcomes []CritOutcome, multiplier, probability float64) []CritOutcome {
+	if probability == 0 {
+		return outcomes
+	}
+	for i := range outcomes {
+		if math.Abs(outcomes[i].Multiplier-multiplier) < 1e-9 {
+			outcomes[i].Probability += probability
+			return outcomes
+		}
+	}
+	return append(outcomes, CritOutcome{Probability: probability, Multiplier: multiplier})
+}
+
+// clamp limits v to the range [lo, hi]
+func clamp(v, lo, hi float64) float64 {
+	return max(lo, min(v, hi))
+}
+
+// ratio divides a by b, returning 1 when b is 0
+func ratio(a, b float64) float64 {
+	if b == 0 {
+		return 1
+	}
+	return a / b
+}
@@ -0,0 +1,281 @@
+package gbf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAttackCalculator_Calculate(t *testing.T) {
+	calc := NewAttackCalculator()
+
+	tests := []struct {
+		name     string
+		input    DamageInput
+		expected int
+	}{
+		{
+			name:     "no modifiers",
+			input:    DamageInput{BaseAttack: 10000},
+			expected: 1000, // 10000 / defense 10
+		},
+		{
+			name: "buckets multiply",
+			input: DamageInput{
+				BaseAttack: 10000,
+				Normal:     0.8,
+				Omega:      0.5,
+				EX:         0.2,
+				ExLike:     0.1,
+				Elemental:  0.2,
+				Attacker:   ElementFire,
+				Defender:   ElementWind,
+			},
+			expected: 6059, // 10000 * 1.8 * 1.5 * 1.2 * 1.1 * 1.7 / 10
+		},
+		{
+			name:     "defense down is capped",
+			input:    DamageInput{BaseAttack: 10000, DefenseDown: 0.8},
+			expected: 2000, // defense 10 * (1 - 0.5)
+		},
+		{
+			name:     "enemy with a lower defense down cap",
+			input:    DamageInput{BaseAttack: 10000, DefenseDown: 0.3, DefenseDownCap: 0.25},
+			expected: 1333, // defense 10 * (1 - 0.25)
+		},
+		{
+			name:     "hit multiplier and enemy defense",
+			input:    DamageInput{BaseAttack: 10000, HitMultiplier: 4.5, Defense: 15},
+			expected: 3000,
+		},
+		{
+			name:     "stamina at full HP",
+			input:    DamageInput{BaseAttack: 10000, Stamina: 0.3},
+			expected: 1300,
+		},
+		{
+			name:     "stamina at half HP",
+			input:    DamageInput{BaseAttack: 10000, Stamina: 0.3, HPRatio: 0.5},
+			expected: 1040, // 0.3 * 0.5^2.9
+		},
+		{
+			name:     "enmity at half HP",
+			input:    DamageInput{BaseAttack: 10000, Enmity: 0.6, HPRatio: 0.5},
+			expected: 1200, // 0.6 * 0.5 * (1 + 1) / 3
+		},
+		{
+			name: "expected crit damage",
+			input: DamageInput{
+				BaseAttack: 10000,
+				Attacker:   ElementFire,
+				Defender:   ElementWind,
+				Crits:      []CritSource{{Rate: 0.5, Multiplier: 0.5}},
+			},
+			expected: 1875, // 1500 * (0.5 * 1 + 0.5 * 1.5)
+		},
+		{
+			name:     "elemental disadvantage",
+			input:    DamageInput{BaseAttack: 10000, Attacker: ElementWater, Defender: ElementEarth},
+			expected: 750,
+		},
+		{
+			name: "crits need elemental advantage",
+			input: DamageInput{
+				BaseAttack: 10000,
+				Crits:      []CritSource{{Rate: 1, Multiplier: 0.5}},
+			},
+			expected: 1000,
+		},
+		{
+			name:     "normal attack soft cap",
+			input:    DamageInput{BaseAttack: 4000000},
+			expected: 380000, // 300000 + 100000 * 0.8
+		},
+		{
+			name: "cap applies to each crit outcome",
+			input: DamageInput{
+				BaseAttack: 2000000,
+				Attacker:   ElementFire,
+				Defender:   ElementWind,
+				Crits:      []CritSource{{Rate: 0.5, Multiplier: 0.5}},
+			},
+			expected: 355000, // (300000 + 410000) / 2, not 360000, the cap of the average
+		},
+		{
+			name:     "skill soft cap",
+			input:    DamageInput{BaseAttack: 4500000, Kind: DamageKindSkill},
+			expected: 440000, // 400000 + 50000 * 0.8
+		},
+		{
+			name:     "charge attack soft cap",
+			input:    DamageInput{BaseAttack: 4000000, Kind: DamageKindCharge, HitMultiplier: 4.5},
+			expected: 1525000, // 1000000 + 400000 + 120000 + 5000
+		},
+		{
+			name:     "cap up raises the thresholds",
+			input:    DamageInput{BaseAttack: 4000000, CapUp: 0.2},
+			expected: 392000, // 360000 + 40000 * 0.8
+		},
+		{
+			name:     "explicit cap overrides the kind",
+			input:    DamageInput{BaseAttack: 4000000, Kind: DamageKindCharge, Cap: SoftCap{{Threshold: 100000, Rate: 0}}},
+			expected: 100000,
+		},
+		{
+			name:     "echo",
+			input:    DamageInput{BaseAttack: 10000, Echo: 0.3},
+			expected: 1300,
+		},
+		{
+			name:     "supplemental damage",
+			input:    DamageInput{BaseAttack: 10000, Supplemental: 50000},
+			expected: 51000,
+		},
+		{
+			name:     "echo follows the cap, supplemental damage ignores it",
+			input:    DamageInput{BaseAttack: 4000000, Echo: 0.2, Supplemental: 100000},
+			expected: 556000, // 380000 * 1.2 + 100000
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(tt.input)
+			if err != nil {
+				t.Fatalf("Calculate() unexpected error: %v", err)
+			}
+			if result.Damage != tt.expected {
+				t.Errorf("Calculate() damage = %d, expected %d", result.Damage, tt.expected)
+			}
+			if last := result.Steps[len(result.Steps)-1]; math.Round(last.Value) != float64(result.Damage) {
+				t.Errorf("last step = %+v, expected to end at the damage", last)
+			}
+		})
+	}
+}
+
+func TestAttackCalculator_Calculate_Breakdown(t *testing.T) {
+	result, err := NewAttackCalculator().Calculate(DamageInput{
+		BaseAttack:   10000,
+		Normal:       1,
+		Attacker:     ElementFire,
+		Defender:     ElementWind,
+		DefenseDown:  0.25,
+		Crits:        []CritSource{{Rate: 1, Multiplier: 0.25}},
+		Echo:         0.2,
+		Supplemental: 1000,
+	})
+	if err != nil {
+		t.Fatalf("Calculate() unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		name  string
+		value float64
+	}{
+		{DamageStepBaseAttack, 10000},
+		{DamageStepNormal, 20000},
+		{DamageStepOmega, 20000},
+		{DamageStepEX, 20000},
+		{DamageStepExLike, 20000},
+		{DamageStepElemental, 30000},
+		{DamageStepStamina, 30000},
+		{DamageStepEnmity, 30000},
+		{DamageStepHit, 30000},
+		{DamageStepDefense, 4000},
+		{DamageStepCritical, 5000},
+		{DamageStepCap, 5000},
+		{DamageStepEcho, 6000},
+		{DamageStepSupplemental, 7000},
+	}
+	if len(result.Steps) != len(expected) {
+		t.Fatalf("Steps = %+v, expected %d steps", result.Steps, len(expected))
+	}
+	for i, step := range result.Steps {
+		if step.Name != expected[i].name || math.Abs(step.Value-expected[i].value) > 1e-6 {
+			t.Errorf("step %d = %s %g, expected %s %g", i, step.Name, step.Value, expected[i].name, expected[i].value)
+		}
+	}
+	if result.Attack != 30000 || result.EffectiveDefense != 7.5 || result.Raw != 4000 {
+		t.Errorf("Attack, EffectiveDefense, Raw = %g, %g, %g, expected 30000, 7.5, 4000",
+			result.Attack, result.EffectiveDefense, result.Raw)
+	}
+	if result.Hit != 5000 || result.Damage != 7000 {
+		t.Errorf("Hit, Damage = %d, %d, expected 5000, 7000", result.Hit, result.Damage)
+	}
+	if result.Min != 7000 || result.Max != 7000 {
+		t.Errorf("Min, Max = %d, %d, expected a guaranteed crit of 7000", result.Min, result.Max)
+	}
+	if loss := result.CapLoss(); loss != 0 {
+		t.Errorf("CapLoss() = %g, expected 0 below the cap", loss)
+	}
+}
+
+func TestAttackCalculator_Calculate_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input DamageInput
+	}{
+		{"negative attack", DamageInput{BaseAttack: -1}},
+		{"HP above max", DamageInput{BaseAttack: 1000, HPRatio: 1.5}},
+		{"negative defense", DamageInput{BaseAttack: 1000, Defense: -10}},
+		{"defense down cap of 100%", DamageInput{BaseAttack: 1000, DefenseDownCap: 1}},
+		{"bucket removing all damage", DamageInput{BaseAttack: 1000, Omega: -1}},
+		{"crit rate above 100%", DamageInput{BaseAttack: 1000, Crits: []CritSource{{Rate: 1.5, Multiplier: 0.5}}}},
+		{"too many crit sources", DamageInput{BaseAttack: 1000, Crits: make([]CritSource, maxCritSources+1)}},
+		{"unknown damage kind", DamageInput{BaseAttack: 1000, Kind: "ougi"}},
+		{"negative cap up", DamageInput{BaseAttack: 1000, CapUp: -0.1}},
+		{"negative echo", DamageInput{BaseAttack: 1000, Echo: -0.1}},
+		{"unknown element", DamageInput{BaseAttack: 1000, Attacker: "plasma", Defender: ElementFire}},
+		{"unordered cap tiers", DamageInput{BaseAttack: 1000, Cap: SoftCap{{Threshold: 200, Rate: 0.5}, {Threshold: 100, Rate: 0.1}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAttackCalculator().Calculate(tt.input); err == nil {
+				t.Error("Calculate() expected error but got none")
+			}
+		})
+	}
+}
+
+func TestCritOutcomes(t *testing.T) {
+	outcomes := critOutcomes([]CritSource{
+		{Rate: 0.5, Multiplier: 0.5},
+		{Rate: 0.5, Multiplier: 0.5},
+		{Rate: 0, Multiplier: 1},
+	})
+
+	expected := []CritOutcome{
+		{Probability: 0.25, Multiplier: 1},
+		{Probability: 0.5, Multiplier: 1.5},
+		{Probability: 0.25, Multiplier: 2},
+	}
+	if len(outcomes) != len(expected) {
+		t.Fatalf("critOutcomes() = %+v, expected %+v", outcomes, expected)
+	}
+	for i, outcome := range outcomes {
+		if math.Abs(outcome.Probability-expected[i].Probability) > 1e-9 || outcome.Multiplier != expected[i].Multiplier {
+			t.Errorf("outcome %d = %+v, expected %+v", i, outcome, expected[i])
+		}
+	}
+}
+
+func BenchmarkCalculate(b *testing.B) {
+	calc := NewAttackCalculator()
+	input := DamageInput{
+		BaseAttack: 150000,
+		Normal:     1.2,
+		Omega:      2.4,
+		EX:         0.6,
+		Attacker:   ElementFire,
+		Defender:   ElementWind,
+		Enmity:     0.5,
+		HPRatio:    0.3,
+		Crits:      []CritSource{{Rate: 0.3, Multiplier: 0.5}, {Rate: 0.2, Multiplier: 0.2}},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = calc.Calculate(input)
+	}
+}
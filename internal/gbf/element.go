@@ -0,0 +1,102 @@
+package gbf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Element is the element of a character, weapon, summon or enemy
+type Element string
+
+const (
+	ElementFire  Element = "fire"
+	ElementWater Element = "water"
+	ElementEarth Element = "earth"
+	ElementWind  Element = "wind"
+	ElementLight Element = "light"
+	ElementDark  Element = "dark"
+	// ElementAll is every element at once: an attack of all elements has
+	// advantage on any enemy, and an enemy of all elements is weak to any attack
+	ElementAll Element = "all"
+)
+
+// Elements lists the six elements of the element wheel
+var Elements = []Element{ElementFire, ElementWater, ElementEarth, ElementWind, ElementLight, ElementDark}
+
+// advantages maps each element to the element it has advantage on
+var advantages = map[Element]Element{
+	ElementFire:  ElementWind,
+	ElementWind:  ElementEarth,
+	ElementEarth: ElementWater,
+	ElementWater: ElementFire,
+	ElementLight: ElementDark,
+	ElementDark:  ElementLight,
+}
+
+// Affinity is how an attack's element matches up against the enemy's
+type Affinity string
+
+const (
+	AffinityAdvantage    Affinity = "advantage"
+	AffinityNeutral      Affinity = "neutral"
+	AffinityDisadvantage Affinity = "disadvantage"
+)
+
+// Elemental modifiers added to the elemental bucket of the damage pipeline
+const (
+	AdvantageModifier    = 0.5
+	DisadvantageModifier = -0.25
+)
+
+// ParseElement returns the element of a name such as "Fire"; empty means no element
+func ParseElement(name string) (Element, error) {
+	element := Element(strings.ToLower(strings.TrimSpace(name)))
+	if !element.IsValid() {
+		return "", fmt.Errorf("invalid element %q: must be one of fire, water, earth, wind, light, dark, all", name)
+	}
+	return element, nil
+}
+
+// IsValid reports whether e is an element, all elements or empty for no element
+func (e Element) IsValid() bool {
+	_, wheel := advantages[e]
+	return wheel || e == ElementAll || e == ""
+}
+
+// Against returns the affinity of an attack of element e on an enemy of element
+// defender. Attacks and enemies without an element are neutral; all elements
+// have advantage on, and are weak to, every element. Fire, wind, earth and water
+// each beat the next and water beats fire, while light and dark beat each other.
+func (e Element) Against(defender Element) Affinity {
+	switch {
+	case e == "" || defender == "":
+		return AffinityNeutral
+	case e == ElementAll || defender == ElementAll:
+		return AffinityAdvantage
+	case advantages[e] == defender:
+		return AffinityAdvantage
+	case advantages[defender] == e:
+		return AffinityDisadvantage
+	}
+	return AffinityNeutral
+}
+
+// AdvantageOn returns the element e has advantage on; all elements have
+// advantage on all elements, and no element on none
+func (e Element) AdvantageOn() Element {
+	if e == ElementAll {
+		return ElementAll
+	}
+	return advantages[e]
+}
+
+// Modifier returns the elemental modifier of the affinity
+func (a Affinity) Modifier() float64 {
+	switch a {
+	case AffinityAdvantage:
+		return AdvantageModifier
+	case AffinityDisadvantage:
+		return DisadvantageModifier
+	}
+	return 0
+}
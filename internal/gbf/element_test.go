@@ -0,0 +1,80 @@
+package gbf
+
+import "testing"
+
+func TestElement_Against(t *testing.T) {
+	tests := []struct {
+		attacker Element
+		defender Element
+		want     Affinity
+	}{
+		{ElementFire, ElementWind, AffinityAdvantage},
+		{ElementWind, ElementEarth, AffinityAdvantage},
+		{ElementEarth, ElementWater, AffinityAdvantage},
+		{ElementWater, ElementFire, AffinityAdvantage},
+		{ElementFire, ElementWater, AffinityDisadvantage},
+		{ElementWind, ElementFire, AffinityDisadvantage},
+		{ElementFire, ElementEarth, AffinityNeutral},
+		{ElementFire, ElementFire, AffinityNeutral},
+		{ElementLight, ElementDark, AffinityAdvantage},
+		{ElementDark, ElementLight, AffinityAdvantage},
+		{ElementLight, ElementLight, AffinityNeutral},
+		{ElementDark, ElementFire, AffinityNeutral},
+		{ElementAll, ElementWater, AffinityAdvantage},
+		{ElementEarth, ElementAll, AffinityAdvantage},
+		{ElementAll, ElementAll, AffinityAdvantage},
+		{"", ElementWind, AffinityNeutral},
+		{ElementAll, "", AffinityNeutral},
+	}
+
+	for _, tt := range tests {
+		if got := tt.attacker.Against(tt.defender); got != tt.want {
+			t.Errorf("%q.Against(%q) = %s, expected %s", tt.attacker, tt.defender, got, tt.want)
+		}
+	}
+}
+
+func TestElement_AdvantageOn(t *testing.T) {
+	for _, element := range append(Elements, ElementAll) {
+		if got := element.Against(element.AdvantageOn()); got != AffinityAdvantage {
+			t.Errorf("%q.Against(%q) = %s, expected advantage", element, element.AdvantageOn(), got)
+		}
+	}
+	if got := Element("").AdvantageOn(); got != "" {
+		t.Errorf("no element AdvantageOn() = %q, expected no element", got)
+	}
+}
+
+func TestParseElement(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Element
+		wantErr bool
+	}{
+		{"Fire", ElementFire, false},
+		{" dark ", ElementDark, false},
+		{"ALL", ElementAll, false},
+		{"", "", false},
+		{"plasma", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseElement(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseElement(%q) = %q, %v, expected %q (error %v)", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestAffinity_Modifier(t *testing.T) {
+	tests := map[Affinity]float64{
+		AffinityAdvantage:    0.5,
+		AffinityNeutral:      0,
+		AffinityDisadvantage: -0.25,
+	}
+	for affinity, want := range tests {
+		if got := affinity.Modifier(); got != want {
+			t.Errorf("%s.Modifier() = %g, expected %g", affinity, got, want)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package gbf
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Grid limits
+const (
+	GridSize       = 10 // Weapons in a grid, including the main hand
+	MaxGridSummons = 2  // The main summon and the friend summon
+	MaxSkillLevel  = 20
+	gridCritBonus  = 0.5 // Damage bonus of weapon skill crits
+)
+
+// WeaponSeries decides which modifier bucket a weapon skill boosts
+type WeaponSeries string
+
+const (
+	WeaponSeriesNormal WeaponSeries = "normal" // Boosted by normal summon auras
+	WeaponSeriesOmega  WeaponSeries = "omega"  // Boosted by omega summon auras
+	WeaponSeriesEX     WeaponSeries = "ex"     // Not boosted by auras
+	WeaponSeriesUltima WeaponSeries = "ultima" // Ultima weapons, which share the EX bucket
+)
+
+// WeaponSeriesList are the valid weapon series
+var WeaponSeriesList = []WeaponSeries{WeaponSeriesNormal, WeaponSeriesOmega, WeaponSeriesEX, WeaponSeriesUltima}
+
+// WeaponSkill is the effect of a weapon skill
+type WeaponSkill string
+
+const (
+	WeaponSkillMight        WeaponSkill = "might"   // ATK up
+	WeaponSkillEnmity       WeaponSkill = "enmity"  // ATK up as HP drops
+	WeaponSkillStamina      WeaponSkill = "stamina" // ATK up at high HP
+	WeaponSkillCritical     WeaponSkill = "crit"    // Chance of critical hits
+	WeaponSkillDoubleAttack WeaponSkill = "da"      // Double attack rate
+	WeaponSkillTripleAttack WeaponSkill = "ta"      // Triple attack rate
+)
+
+// WeaponSkills are the valid weapon skills
+var WeaponSkills = []WeaponSkill{
+	WeaponSkillMight, WeaponSkillEnmity, WeaponSkillStamina,
+	WeaponSkillCritical, WeaponSkillDoubleAttack, WeaponSkillTripleAttack,
+}
+
+// SkillSize is the size of a weapon skill, e.g. Might (Big)
+type SkillSize string
+
+const (
+	SkillSizeSmall   SkillSize = "small"
+	SkillSizeMedium  SkillSize = "medium"
+	SkillSizeBig     SkillSize = "big"
+	SkillSizeMassive SkillSize = "massive"
+)
+
+// SkillSizes are the valid skill sizes
+var SkillSizes = []SkillSize{SkillSizeSmall, SkillSizeMedium, SkillSizeBig, SkillSizeMassive}
+
+// skillValues are the boosts of weapon skills at skill level 10
+var skillValues = map[WeaponSkill]map[SkillSize]float64{
+	WeaponSkillMight:        {SkillSizeSmall: 0.06, SkillSizeMedium: 0.10, SkillSizeBig: 0.15, SkillSizeMassive: 0.20},
+	WeaponSkillEnmity:       {SkillSizeSmall: 0.10, SkillSizeMedium: 0.18, SkillSizeBig: 0.30, SkillSizeMassive: 0.40},
+	WeaponSkillStamina:      {SkillSizeSmall: 0.04, SkillSizeMedium: 0.08, SkillSizeBig: 0.12, SkillSizeMassive: 0.15},
+	WeaponSkillCritical:     {SkillSizeSmall: 0.03, SkillSizeMedium: 0.06, SkillSizeBig: 0.09, SkillSizeMassive: 0.12},
+	WeaponSkillDoubleAttack: {SkillSizeSmall: 0.02, SkillSizeMedium: 0.04, SkillSizeBig: 0.06, SkillSizeMassive: 0.08},
+	WeaponSkillTripleAttack: {SkillSizeSmall: 0.01, SkillSizeMedium: 0.02, SkillSizeBig: 0.03, SkillSizeMassive: 0.04},
+}
+
+// SkillValue returns the boost of a weapon skill at a skill level. Skills reach
+// their listed value at level 10 and keep growing more slowly up to level 20.
+func SkillValue(skill WeaponSkill, size SkillSize, level int) (float64, error) {
+	sizes, exists := skillValues[skill]
+	if !exists {
+		return 0, fmt.Errorf("invalid weapon skill: %s", skill)
+	}
+	value, exists := sizes[size]
+	if !exists {
+		return 0, fmt.Errorf("invalid skill size: %s", size)
+	}
+	if level < 1 || level > MaxSkillLevel {
+		return 0, fmt.Errorf("skill level must be between 1 and %d: %d", MaxSkillLevel, level)
+	}
+
+	scale := 0.5 + 0.05*float64(level)
+	if level > 10 {
+		scale = 1 + 0.04*float64(level-10)
+	}
+	return value * scale, nil
+}
+
+// Weapon is a weapon in a grid
+type Weapon struct {
+	Name string
+	// Element limits the skill to characters of the same element; empty matches all
+	Element    Element
+	Type       WeaponType
+	Attack     int
+	Series     WeaponSeries
+	Skill      WeaponSkill
+	Size       SkillSize
+	SkillLevel int
+}
+
+// AuraType is the kind of weapon skills a summon aura boosts
+type AuraType string
+
+const (
+	AuraNormal    AuraType = "normal"    // Boosts normal weapon skills
+	AuraOmega     AuraType = "omega"     // Boosts omega weapon skills
+	AuraElemental AuraType = "elemental" // Boosts elemental ATK directly
+)
+
+// AuraTypes are the valid aura types
+var AuraTypes = []AuraType{AuraNormal, AuraOmega, AuraElemental}
+
+// Summon supplies an aura to a grid
+type Summon struct {
+	Name string
+	// Element limits the aura to characters of the same element; empty matches all
+	Element Element
+	Aura    AuraType
+	// Boost is the aura strength, e.g. 1.4 for 140%
+	Boost  float64
+	Attack int
+}
+
+// Grid is a party's weapons and summons for one element
+type Grid struct {
+	// Element is the element of the characters; empty counts every weapon and summon
+	Element Element
+	// Weapons are up to GridSize weapons; the first is the main hand
+	Weapons []Weapon
+	Summons []Summon
+}
+
+// GridModifiers are the totals of a grid, ready for the damage pipeline
+type GridModifiers struct {
+	// Attack is the ATK of the weapons and summons
+	Attack       int
+	Normal       float64
+	Omega        float64
+	EX           float64
+	Elemental    float64
+	Stamina      float64
+	Enmity       float64
+	Crits        []CritSource
+	DoubleAttack float64
+	TripleAttack float64
+}
+
+// Validate checks the size of the grid and every weapon and summon
+func (g *Grid) Validate() error {
+	if len(g.Weapons) == 0 || len(g.Weapons) > GridSize {
+		return fmt.Errorf("a grid needs 1 to %d weapons: %d", GridSize, len(g.Weapons))
+	}
+	if len(g.Summons) > MaxGridSummons {
+		return fmt.Errorf("a grid has at most %d summons: %d", MaxGridSummons, len(g.Summons))
+	}
+	for i, weapon := range g.Weapons {
+		if weapon.Type != "" && !IsValidWeaponType(weapon.Type) {
+			return fmt.Errorf("weapon %d: invalid weapon type: %s", i+1, weapon.Type)
+		}
+		if !slices.Contains(WeaponSeriesList, weapon.Series) {
+			return fmt.Errorf("weapon %d: invalid weapon series: %s", i+1, weapon.Series)
+		}
+		if weapon.Attack < 0 {
+			return fmt.Errorf("weapon %d: attack cannot be negative: %d", i+1, weapon.Attack)
+		}
+		if _, err := SkillValue(weapon.Skill, weapon.Size, weapon.SkillLevel); err != nil {
+			return fmt.Errorf("weapon %d: %w", i+1, err)
+		}
+	}
+	for i, summon := range g.Summons {
+		if !slices.Contains(AuraTypes, summon.Aura) {
+			return fmt.Errorf("summon %d: invalid aura: %s", i+1, summon.Aura)
+		}
+		if summon.Boost < 0 || summon.Attack < 0 {
+			return fmt.Errorf("summon %d: aura and attack cannot be negative", i+1)
+		}
+	}
+	return nil
+}
+
+// Modifiers totals the weapon skills of the grid, boosted by the summon auras
+func (g *Grid) Modifiers() (GridModifiers, error) {
+	if err := g.Validate(); err != nil {
+		return GridModifiers{}, err
+	}
+
+	var modifiers GridModifiers
+	auras := make(map[AuraType]float64)
+	for _, summon := range g.Summons {
+		modifiers.Attack += summon.Attack
+		if g.matches(summon.Element) {
+			auras[summon.Aura] += summon.Boost
+		}
+	}
+	modifiers.Elemental = auras[AuraElemental]
+
+	critRates := make(map[WeaponSeries]float64)
+	for _, weapon := range g.Weapons {
+		modifiers.Attack += weapon.Attack
+		if !g.matches(weapon.Element) {
+			continue
+		}
+
+		// Validate has checked the skill
+		value, _ := SkillValue(weapon.Skill, weapon.Size, weapon.SkillLevel)
+		series := weapon.Series
+		switch series {
+		case WeaponSeriesNormal:
+			value *= 1 + auras[AuraNormal]
+		case WeaponSeriesOmega:
+			value *= 1 + auras[AuraOmega]
+		case WeaponSeriesUltima:
+			series = WeaponSeriesEX
+		}
+
+		switch weapon.Skill {
+		case WeaponSkillMight:
+			switch series {
+			case WeaponSeriesNormal:
+				modifiers.Normal += value
+			case WeaponSeriesOmega:
+				modifiers.Omega += value
+			default:
+				modifiers.EX += value
+			}
+		case WeaponSkillEnmity:
+			modifiers.Enmity += value
+		case WeaponSkillStamina:
+			modifiers.Stamina += value
+		case WeaponSkillCritical:
+			critRates[series] += value
+		case WeaponSkillDoubleAttack:
+			modifiers.DoubleAttack += value
+		case WeaponSkillTripleAttack:
+			modifiers.TripleAttack += value
+		}
+	}
+
+	// Crit rates add up within a series, and each series rolls separately
+	for _, series := range []WeaponSeries{WeaponSeriesNormal, WeaponSeriesOmega, WeaponSeriesEX} {
+		if rate := critRates[series]; rate > 0 {
+			modifiers.Crits = append(modifiers.Crits, CritSource{Rate: min(rate, 1), Multiplier: gridCritBonus})
+		}
+	}
+	return modifiers, nil
+}
+
+// matches reports whether something of element applies to the grid's characters
+func (g *Grid) matches(element Element) bool {
+	return g.Element == "" || element == "" || element == g.Element
+}
+
+// Apply adds the grid to a damage input
+func (m GridModifiers) Apply(input DamageInput) DamageInput {
+	input.BaseAttack += m.Attack
+	input.Normal += m.Normal
+	input.Omega += m.Omega
+	input.EX += m.EX
+	input.Elemental += m.Elemental
+	input.Stamina += m.Stamina
+	input.Enmity += m.Enmity
+	input.Crits = append(slices.Clone(input.Crits), m.Crits...)
+	return input
+}
+
+// ParseGrid reads a grid from a comma separated list of weapons and summons:
+//
+//	[<count>x] <series> <skill> [<size>] [sl<level>] [atk<attack>]
+//	summon <aura> <percent> [atk<attack>]
+//
+// e.g. "6x omega might big sl15, 3x normal crit medium, ultima might, summon omega 140".
+// Sizes default to big and skill levels to 10.
+func ParseGrid(text string) (*Grid, error) {
+	grid := &Grid{}
+	for n, entry := range strings.Split(text, ",") {
+		fields := strings.Fields(strings.ToLower(entry))
+		if len(fields) == 0 {
+			continue
+		}
+
+		var err error
+		if fields[0] == "summon" {
+			var summon Summon
+			summon, err = parseSummon(fields[1:])
+			grid.Summons = append(grid.Summons, summon)
+		} else {
+			var weapon Weapon
+			var count int
+			weapon, count, err = parseWeapon(fields)
+			for range count {
+				grid.Weapons = append(grid.Weapons, weapon)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("entry %d (%q): %w", n+1, strings.TrimSpace(entry), err)
+		}
+	}
+
+	if err := grid.Validate(); err != nil {
+		return nil, err
+	}
+	return grid, nil
+}
+
+// parseWeapon reads the fields of a weapon entry of ParseGrid
+func parseWeapon(fields []string) (Weapon, int, error) {
+	count := 1
+	// "ex" is a series, not a count
+	if copies, found := strings.CutSuffix(fields[0], "x"); found && !slices.Contains(WeaponSeriesList, WeaponSeries(fields[0])) {
+		n, err := strconv.Atoi(copies)
+		if err != nil || n < 1 || n > GridSize {
+			return Weapon{}, 0, fmt.Errorf("invalid count %q", fields[0])
+		}
+		count = n
+		fields = fields[1:]
+	}
+	if len(fields) < 2 {
+		return Weapon{}, 0, fmt.Errorf("expected a series and a skill")
+	}
+
+	weapon := Weapon{
+		Series:     WeaponSeries(fields[0]),
+		Skill:      WeaponSkill(fields[1]),
+		Size:       SkillSizeBig,
+		SkillLevel: 10,
+	}
+	if !slices.Contains(WeaponSeriesList, weapon.Series) {
+		return Weapon{}, 0, fmt.Errorf("unknown series %q", fields[0])
+	}
+	if !slices.Contains(WeaponSkills, weapon.Skill) {
+		return Weapon{}, 0, fmt.Errorf("unknown skill %q", fields[1])
+	}
+
+	for _, field := range fields[2:] {
+		switch {
+		case slices.Contains(SkillSizes, SkillSize(field)):
+			weapon.Size = SkillSize(field)
+		case strings.HasPrefix(field, "sl"):
+			level, err := strconv.Atoi(field[2:])
+			if err != nil || level < 1 || level > MaxSkillLevel {
+				return Weapon{}, 0, fmt.Errorf("invalid skill level %q", field)
+			}
+			weapon.SkillLevel = level
+		case strings.HasPrefix(field, "atk"):
+			attack, err := strconv.Atoi(field[3:])
+			if err != nil || attack < 0 {
+				return Weapon{}, 0, fmt.Errorf("invalid attack %q", field)
+			}
+			weapon.Attack = attack
+		default:
+			return Weapon{}, 0, fmt.Errorf("unknown field %q", field)
+		}
+	}
+	return weapon, count, nil
+}
+
+// parseSummon reads the fields after "summon" of a summon entry of ParseGrid
+func parseSummon(fields []string) (Summon, error) {
+	if len(fields) < 2 {
+		return Summon{}, fmt.Errorf("expected an aura and a percentage")
+	}
+	summon := Summon{Aura: AuraType(fields[0])}
+	if !slices.Contains(AuraTypes, summon.Aura) {
+		return Summon{}, fmt.Errorf("unknown aura %q", fields[0])
+	}
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "%"), 64)
+	if err != nil || percent < 0 {
+		return Summon{}, fmt.Errorf("invalid aura percentage %q", fields[1])
+	}
+	summon.Boost = percent / 100
+
+	for _, field := range fields[2:] {
+		attack, err := strconv.Atoi(strings.TrimPrefix(field, "atk"))
+		if !strings.HasPrefix(field, "atk") || err != nil || attack < 0 {
+			return Summon{}, fmt.Errorf("unknown field %q", field)
+		}
+		summon.Attack = attack
+	}
+	return summon, nil
+}
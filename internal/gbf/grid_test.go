@@ -0,0 +1,229 @@
+package gbf
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSkillValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		skill       WeaponSkill
+		size        SkillSize
+		level       int
+		expected    float64
+		expectError bool
+	}{
+		{"might at level 1", WeaponSkillMight, SkillSizeBig, 1, 0.0825, false},
+		{"might at level 10", WeaponSkillMight, SkillSizeBig, 10, 0.15, false},
+		{"might at level 15", WeaponSkillMight, SkillSizeBig, 15, 0.18, false},
+		{"might at level 20", WeaponSkillMight, SkillSizeBig, 20, 0.21, false},
+		{"small triple attack", WeaponSkillTripleAttack, SkillSizeSmall, 10, 0.01, false},
+		{"level 0", WeaponSkillMight, SkillSizeBig, 0, 0, true},
+		{"level 21", WeaponSkillMight, SkillSizeBig, 21, 0, true},
+		{"unknown skill", WeaponSkill("haste"), SkillSizeBig, 10, 0, true},
+		{"unknown size", WeaponSkillMight, SkillSize("huge"), 10, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := SkillValue(tt.skill, tt.size, tt.level)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("SkillValue() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SkillValue() unexpected error: %v", err)
+			}
+			if math.Abs(value-tt.expected) > 1e-9 {
+				t.Errorf("SkillValue() = %g, expected %g", value, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGrid_Modifiers(t *testing.T) {
+	omegaMight := Weapon{Series: WeaponSeriesOmega, Skill: WeaponSkillMight, Size: SkillSizeBig, SkillLevel: 10, Attack: 3000}
+	grid := &Grid{
+		Element: "fire",
+		Weapons: []Weapon{
+			omegaMight,
+			omegaMight,
+			{Series: WeaponSeriesNormal, Skill: WeaponSkillMight, Size: SkillSizeMedium, SkillLevel: 10, Attack: 2000},
+			{Series: WeaponSeriesUltima, Skill: WeaponSkillMight, Size: SkillSizeBig, SkillLevel: 20},
+			{Series: WeaponSeriesOmega, Skill: WeaponSkillEnmity, Size: SkillSizeBig, SkillLevel: 10},
+			{Series: WeaponSeriesNormal, Skill: WeaponSkillCritical, Size: SkillSizeBig, SkillLevel: 10},
+			{Series: WeaponSeriesEX, Skill: WeaponSkillDoubleAttack, Size: SkillSizeBig, SkillLevel: 10},
+			// Water weapons only add their ATK to a fire grid
+			{Element: "water", Series: WeaponSeriesOmega, Skill: WeaponSkillMight, Size: SkillSizeMassive, SkillLevel: 20, Attack: 1000},
+		},
+		Summons: []Summon{
+			{Element: "fire", Aura: AuraOmega, Boost: 1.4, Attack: 1500},
+			{Aura: AuraOmega, Boost: 1.4},
+		},
+	}
+
+	modifiers, err := grid.Modifiers()
+	if err != nil {
+		t.Fatalf("Modifiers() unexpected error: %v", err)
+	}
+
+	checks := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"Attack", float64(modifiers.Attack), 10500},
+		{"Normal", modifiers.Normal, 0.10},
+		{"Omega", modifiers.Omega, 1.14},   // 0.3 * (1 + 2.8)
+		{"EX", modifiers.EX, 0.21},         // Ultima shares the EX bucket
+		{"Enmity", modifiers.Enmity, 1.14}, // 0.3 * (1 + 2.8)
+		{"DoubleAttack", modifiers.DoubleAttack, 0.06},
+		{"TripleAttack", modifiers.TripleAttack, 0},
+	}
+	for _, check := range checks {
+		if math.Abs(check.got-check.expected) > 1e-9 {
+			t.Errorf("%s = %g, expected %g", check.name, check.got, check.expected)
+		}
+	}
+	if len(modifiers.Crits) != 1 || math.Abs(modifiers.Crits[0].Rate-0.09) > 1e-9 || modifiers.Crits[0].Multiplier != gridCritBonus {
+		t.Errorf("Crits = %+v, expected one normal crit source at 9%%", modifiers.Crits)
+	}
+}
+
+func TestGrid_Modifiers_Summons(t *testing.T) {
+	grid := &Grid{
+		Element: "dark",
+		Weapons: []Weapon{{Series: WeaponSeriesNormal, Skill: WeaponSkillMight, Size: SkillSizeBig, SkillLevel: 10}},
+		Summons: []Summon{
+			{Element: "dark", Aura: AuraNormal, Boost: 1.5},
+			{Element: "dark", Aura: AuraElemental, Boost: 1.2},
+		},
+	}
+
+	modifiers, err := grid.Modifiers()
+	if err != nil {
+		t.Fatalf("Modifiers() unexpected error: %v", err)
+	}
+	if math.Abs(modifiers.Normal-0.375) > 1e-9 { // 0.15 * (1 + 1.5)
+		t.Errorf("Normal = %g, expected 0.375", modifiers.Normal)
+	}
+	if modifiers.Elemental != 1.2 {
+		t.Errorf("Elemental = %g, expected 1.2", modifiers.Elemental)
+	}
+
+	// Auras of another element do nothing
+	grid.Element = "light"
+	modifiers, _ = grid.Modifiers()
+	if modifiers.Normal != 0.15 || modifiers.Elemental != 0 {
+		t.Errorf("Normal, Elemental = %g, %g, expected the dark auras to be ignored", modifiers.Normal, modifiers.Elemental)
+	}
+}
+
+func TestGrid_Validate(t *testing.T) {
+	might := Weapon{Series: WeaponSeriesNormal, Skill: WeaponSkillMight, Size: SkillSizeBig, SkillLevel: 10}
+
+	tests := []struct {
+		name string
+		grid Grid
+	}{
+		{"no weapons", Grid{}},
+		{"too many weapons", Grid{Weapons: []Weapon{might, might, might, might, might, might, might, might, might, might, might}}},
+		{"too many summons", Grid{Weapons: []Weapon{might}, Summons: []Summon{{Aura: AuraOmega}, {Aura: AuraOmega}, {Aura: AuraOmega}}}},
+		{"unknown weapon type", Grid{Weapons: []Weapon{{Type: "whip", Series: WeaponSeriesNormal, Skill: WeaponSkillMight, Size: SkillSizeBig, SkillLevel: 10}}}},
+		{"unknown series", Grid{Weapons: []Weapon{{Series: "magna", Skill: WeaponSkillMight, Size: SkillSizeBig, SkillLevel: 10}}}},
+		{"skill level 0", Grid{Weapons: []Weapon{{Series: WeaponSeriesNormal, Skill: WeaponSkillMight, Size: SkillSizeBig}}}},
+		{"unknown aura", Grid{Weapons: []Weapon{might}, Summons: []Summon{{Aura: "primal"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.grid.Validate(); err == nil {
+				t.Error("Validate() expected error but got none")
+			}
+		})
+	}
+}
+
+func TestGridModifiers_Apply(t *testing.T) {
+	grid, err := ParseGrid("4x omega might big atk2500, summon omega 100, summon omega 100")
+	if err != nil {
+		t.Fatalf("ParseGrid() unexpected error: %v", err)
+	}
+	modifiers, err := grid.Modifiers()
+	if err != nil {
+		t.Fatalf("Modifiers() unexpected error: %v", err)
+	}
+
+	base := DamageInput{BaseAttack: 10000, Omega: 0.5, Attacker: ElementFire, Defender: ElementWind}
+	input := modifiers.Apply(base)
+	if input.BaseAttack != 20000 || math.Abs(input.Omega-2.3) > 1e-9 { // 0.5 + 0.6 * 3
+		t.Errorf("BaseAttack, Omega = %d, %g, expected 20000, 2.3", input.BaseAttack, input.Omega)
+	}
+	if base.BaseAttack != 10000 {
+		t.Error("Apply() modified the original input")
+	}
+
+	result, err := NewAttackCalculator().Calculate(input)
+	if err != nil {
+		t.Fatalf("Calculate() unexpected error: %v", err)
+	}
+	if result.Damage != 9900 { // 20000 * 3.3 * 1.5 / 10
+		t.Errorf("Calculate() damage = %d, expected 9900", result.Damage)
+	}
+}
+
+func TestParseGrid(t *testing.T) {
+	grid, err := ParseGrid("6x Omega Might big SL15, 2x normal crit medium, ultima might massive sl20 atk3500, ex ta, summon omega 140%, summon elemental 100 atk1200")
+	if err != nil {
+		t.Fatalf("ParseGrid() unexpected error: %v", err)
+	}
+	if len(grid.Weapons) != 10 || len(grid.Summons) != 2 {
+		t.Fatalf("ParseGrid() = %d weapons, %d summons, expected 10 and 2", len(grid.Weapons), len(grid.Summons))
+	}
+
+	expected := []Weapon{
+		{Series: WeaponSeriesOmega, Skill: WeaponSkillMight, Size: SkillSizeBig, SkillLevel: 15},
+		{Series: WeaponSeriesNormal, Skill: WeaponSkillCritical, Size: SkillSizeMedium, SkillLevel: 10},
+		{Series: WeaponSeriesUltima, Skill: WeaponSkillMight, Size: SkillSizeMassive, SkillLevel: 20, Attack: 3500},
+		{Series: WeaponSeriesEX, Skill: WeaponSkillTripleAttack, Size: SkillSizeBig, SkillLevel: 10},
+	}
+	for i, index := range []int{0, 6, 8, 9} {
+		if grid.Weapons[index] != expected[i] {
+			t.Errorf("weapon %d = %+v, expected %+v", index+1, grid.Weapons[index], expected[i])
+		}
+	}
+	if grid.Summons[0] != (Summon{Aura: AuraOmega, Boost: 1.4}) || grid.Summons[1] != (Summon{Aura: AuraElemental, Boost: 1, Attack: 1200}) {
+		t.Errorf("Summons = %+v", grid.Summons)
+	}
+}
+
+func TestParseGrid_Errors(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"", "1 to 10 weapons"},
+		{"11x omega might", "invalid count"},
+		{"6x omega might, 5x normal might", "1 to 10 weapons"},
+		{"magna might", "unknown series"},
+		{"omega haste", "unknown skill"},
+		{"omega", "expected a series and a skill"},
+		{"omega might sl21", "invalid skill level"},
+		{"omega might huge", "unknown field"},
+		{"omega might, summon primal 140", "unknown aura"},
+		{"omega might, summon omega lots", "invalid aura percentage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, err := ParseGrid(tt.text)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("ParseGrid(%q) error = %v, expected to contain %q", tt.text, err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package gbf
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// GuildWarDays is the number of days of a Guild War with nightmare battles
+const GuildWarDays = 5
+
+// GuildWarInfo is the reward and cost of one clear of a Guild War nightmare battle
+type GuildWarInfo struct {
+	// Honors are the honors of a solo clear
+	Honors int
+	// APCost and MeatCost are the cost of starting the battle
+	APCost, MeatCost int
+	// ClearTime is a typical clear time of a farming team
+	ClearTime time.Duration
+}
+
+// GuildWarPlanInput is a contribution target and the time a player can spend
+type GuildWarPlanInput struct {
+	// Target is the honors to reach
+	Target int
+	// Days spreads the runs over days; 0 is treated as GuildWarDays
+	Days int
+	// DailyBudget is the time available each day; 0 means no limit
+	DailyBudget time.Duration
+	// ClearTime overrides the battle's clear time
+	ClearTime time.Duration
+}
+
+// GuildWarPlan is how to reach a contribution target with one nightmare battle
+type GuildWarPlan struct {
+	Battle *BattleInfo
+	// Runs is the number of clears reaching the target, spread as RunsPerDay
+	Runs, RunsPerDay int
+	// Honors are the honors of all runs, at least the target
+	Honors int
+	// AP and Meat are the total cost of all runs
+	AP, Meat int
+	// ClearTime is the time of one run
+	ClearTime time.Duration
+	// TotalTime and DailyTime are the time of all runs and of the busiest day
+	TotalTime, DailyTime time.Duration
+	// FitsBudget reports whether DailyTime is within the daily budget
+	FitsBudget bool
+}
+
+// HonorsPerHour returns the honors of an hour of runs
+func (p *GuildWarPlan) HonorsPerHour() int {
+	if p.ClearTime <= 0 {
+		return 0
+	}
+	return int(float64(p.Battle.GuildWar.Honors) / p.ClearTime.Hours())
+}
+
+// PlanGuildWar returns the runs of a nightmare battle needed to reach a
+// contribution target and whether they fit in the daily time budget
+func PlanGuildWar(battle *BattleInfo, input GuildWarPlanInput) (*GuildWarPlan, error) {
+	if battle.GuildWar == nil {
+		return nil, fmt.Errorf("battle %s has no Guild War rewards", battle.ID)
+	}
+	if input.Target <= 0 {
+		return nil, errors.New("target honors must be positive")
+	}
+	if input.Days < 0 || input.DailyBudget < 0 || input.ClearTime < 0 {
+		return nil, errors.New("days, daily budget and clear time cannot be negative")
+	}
+	days := input.Days
+	if days == 0 {
+		days = GuildWarDays
+	}
+	clearTime := input.ClearTime
+	if clearTime == 0 {
+		clearTime = battle.GuildWar.ClearTime
+	}
+
+	info := battle.GuildWar
+	runs := (input.Target + info.Honors - 1) / info.Honors
+	runsPerDay := (runs + days - 1) / days
+	plan := &GuildWarPlan{
+		Battle:     battle,
+		Runs:       runs,
+		RunsPerDay: runsPerDay,
+		Honors:     runs * info.Honors,
+		AP:         runs * info.APCost,
+		Meat:       runs * info.MeatCost,
+		ClearTime:  clearTime,
+		TotalTime:  time.Duration(runs) * clearTime,
+		DailyTime:  time.Duration(runsPerDay) * clearTime,
+	}
+	plan.FitsBudget = input.DailyBudget == 0 || plan.DailyTime <= input.DailyBudget
+	return plan, nil
+}
+
+// GuildWarBattles returns the nightmare battles with Guild War rewards, ordered by level
+func (bm *BattleManager) GuildWarBattles() []*BattleInfo {
+	var battles []*BattleInfo
+	for _, battle := range bm.GetBattlesByType(BattleTypeGWNM) {
+		if battle.GuildWar != nil {
+			battles = append(battles, battle)
+		}
+	}
+	sort.Slice(battles, func(i, j int) bool {
+		if battles[i].Level != battles[j].Level {
+			return battles[i].Level < battles[j].Level
+		}
+		return battles[i].ID < battles[j].ID
+	})
+	return battles
+}
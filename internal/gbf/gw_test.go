@@ -0,0 +1,75 @@
+package gbf
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPlanGuildWar(t *testing.T) {
+	nm := &BattleInfo{
+		ID:       "gw_nm150",
+		Type:     BattleTypeGWNM,
+		Level:    150,
+		GuildWar: &GuildWarInfo{Honors: 4000000, APCost: 50, MeatCost: 20, ClearTime: 3 * time.Minute},
+	}
+
+	tests := []struct {
+		name  string
+		input GuildWarPlanInput
+		want  GuildWarPlan
+	}{
+		{
+			name:  "runs are rounded up and spread over the Guild War",
+			input: GuildWarPlanInput{Target: 41000000},
+			want: GuildWarPlan{
+				Runs: 11, RunsPerDay: 3, Honors: 44000000, AP: 550, Meat: 220,
+				ClearTime: 3 * time.Minute, TotalTime: 33 * time.Minute, DailyTime: 9 * time.Minute,
+				FitsBudget: true,
+			},
+		},
+		{
+			name:  "own clear time over a budget",
+			input: GuildWarPlanInput{Target: 40000000, Days: 2, DailyBudget: 20 * time.Minute, ClearTime: 5 * time.Minute},
+			want: GuildWarPlan{
+				Runs: 10, RunsPerDay: 5, Honors: 40000000, AP: 500, Meat: 200,
+				ClearTime: 5 * time.Minute, TotalTime: 50 * time.Minute, DailyTime: 25 * time.Minute,
+				FitsBudget: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := PlanGuildWar(nm, tt.input)
+			if err != nil {
+				t.Fatalf("PlanGuildWar() unexpected error: %v", err)
+			}
+			tt.want.Battle = nm
+			if *plan != tt.want {
+				t.Errorf("PlanGuildWar() = %+v, expected %+v", *plan, tt.want)
+			}
+		})
+	}
+
+	if plan, _ := PlanGuildWar(nm, GuildWarPlanInput{Target: 1}); plan.HonorsPerHour() != 80000000 {
+		t.Errorf("HonorsPerHour() = %d, expected 80000000", plan.HonorsPerHour())
+	}
+	if _, err := PlanGuildWar(nm, GuildWarPlanInput{}); err == nil {
+		t.Error("PlanGuildWar() without a target expected error")
+	}
+	if _, err := PlanGuildWar(&BattleInfo{ID: "baha_hl"}, GuildWarPlanInput{Target: 1}); err == nil {
+		t.Error("PlanGuildWar() for a battle without rewards expected error")
+	}
+}
+
+func TestBattleManager_GuildWarBattles(t *testing.T) {
+	var ids []string
+	for _, battle := range NewBattleManager().GuildWarBattles() {
+		ids = append(ids, battle.ID)
+	}
+	want := []string{"gw_nm90", "gw_nm95", "gw_nm100", "gw_nm150", "gw_nm200", "gw_nm250"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("GuildWarBattles() = %v, expected %v", ids, want)
+	}
+}
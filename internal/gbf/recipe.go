@@ -0,0 +1,431 @@
+package gbf
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed data/recipes.yaml
+var recipeFS embed.FS
+
+// defaultRecipeSource is the name of the embedded recipe catalog in error messages
+const defaultRecipeSource = "data/recipes.yaml"
+
+// MaxRecipeQuantity limits how many crafts of a recipe are planned at once
+const MaxRecipeQuantity = 20
+
+var (
+	// ErrUnknownRecipe is returned for recipe names that are not in the catalog
+	ErrUnknownRecipe = errors.New("unknown recipe")
+	// ErrUnknownMaterial is returned for material names that are not in the catalog
+	ErrUnknownMaterial = errors.New("unknown material")
+)
+
+// RecipeCategory groups recipes by what they craft or uncap
+type RecipeCategory string
+
+const (
+	RecipeCategoryRevenant RecipeCategory = "revenant"
+	RecipeCategoryEternal  RecipeCategory = "eternal"
+	RecipeCategoryUltima   RecipeCategory = "ultima"
+)
+
+// RecipeCategories are the valid recipe categories in display order
+var RecipeCategories = []RecipeCategory{RecipeCategoryRevenant, RecipeCategoryEternal, RecipeCategoryUltima}
+
+// Material is an item used in recipes
+type Material struct {
+	ID    string
+	Name  string
+	Names map[string]string
+	// Sources are the IDs of the battles that drop the material
+	Sources []string
+	Aliases []string
+}
+
+// Ingredient is a material and the number a recipe needs
+type Ingredient struct {
+	Material *Material
+	Count    int
+}
+
+// Recipe is a craft or uncap step and the materials it needs
+type Recipe struct {
+	ID       string
+	Name     string
+	Names    map[string]string
+	Category RecipeCategory
+	// Ingredients are ordered by material ID
+	Ingredients []Ingredient
+	Aliases     []string
+}
+
+// MaterialNeed is a material of a plan, how many are needed and how many are owned
+type MaterialNeed struct {
+	Material *Material
+	Needed   int
+	Owned    int
+}
+
+// Missing returns how many more of the material are needed
+func (n MaterialNeed) Missing() int {
+	return max(0, n.Needed-n.Owned)
+}
+
+// Plan returns the materials needed to craft the recipe quantity times along
+// with how many are owned, given owned counts keyed by material ID
+func (r *Recipe) Plan(quantity int, owned map[string]int) ([]MaterialNeed, error) {
+	if quantity < 1 || quantity > MaxRecipeQuantity {
+		return nil, fmt.Errorf("quantity must be between 1 and %d", MaxRecipeQuantity)
+	}
+
+	needs := make([]MaterialNeed, len(r.Ingredients))
+	for i, ingredient := range r.Ingredients {
+		needs[i] = MaterialNeed{
+			Material: ingredient.Material,
+			Needed:   ingredient.Count * quantity,
+			Owned:    owned[ingredient.Material.ID],
+		}
+	}
+	return needs, nil
+}
+
+// MaterialSources returns the battles that drop a material. Sources missing from
+// the catalog, e.g. removed by an override, are skipped.
+func (m *BattleManager) MaterialSources(material *Material) []*BattleInfo {
+	var battles []*BattleInfo
+	for _, id := range material.Sources {
+		if battle, err := m.GetBattle(id); err == nil {
+			battles = append(battles, battle)
+		}
+	}
+	return battles
+}
+
+// RecipeCatalog is a read-only set of recipes and materials looked up by ID, name or alias
+type RecipeCatalog struct {
+	recipes      []*Recipe
+	materials    []*Material
+	recipeKeys   map[string]*Recipe
+	materialKeys map[string]*Material
+}
+
+// defaultRecipes parses the embedded recipe catalog once
+var defaultRecipes = sync.OnceValue(func() *RecipeCatalog {
+	data, err := recipeFS.ReadFile(defaultRecipeSource)
+	if err == nil {
+		var catalog *RecipeCatalog
+		if catalog, err = ParseRecipeCatalog(data, defaultRecipeSource); err == nil {
+			return catalog
+		}
+	}
+	panic(fmt.Sprintf("invalid built-in recipe catalog: %v", err))
+})
+
+// DefaultRecipes returns the built-in recipe catalog
+func DefaultRecipes() *RecipeCatalog {
+	return defaultRecipes()
+}
+
+// Recipe returns the recipe with the given ID, name in any language or alias, ignoring case
+func (c *RecipeCatalog) Recipe(name string) (*Recipe, error) {
+	if recipe, exists := c.recipeKeys[classKey(name)]; exists {
+		return recipe, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownRecipe, name)
+}
+
+// Material returns the material with the given ID, name in any language or alias, ignoring case
+func (c *RecipeCatalog) Material(name string) (*Material, error) {
+	if material, exists := c.materialKeys[classKey(name)]; exists {
+		return material, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownMaterial, name)
+}
+
+// Recipes returns every recipe ordered by category and ID
+func (c *RecipeCatalog) Recipes() []*Recipe {
+	return slices.Clone(c.recipes)
+}
+
+// Materials returns every material ordered by ID
+func (c *RecipeCatalog) Materials() []*Material {
+	return slices.Clone(c.materials)
+}
+
+// SearchRecipes returns up to limit recipes matching query, best matches first
+// as ClassCatalog.Search orders classes
+func (c *RecipeCatalog) SearchRecipes(query string, limit int) []*Recipe {
+	return searchCatalog(c.recipes, (*Recipe).terms, query, limit)
+}
+
+// SearchMaterials returns up to limit materials matching query, best matches first
+func (c *RecipeCatalog) SearchMaterials(query string, limit int) []*Material {
+	return searchCatalog(c.materials, (*Material).terms, query, limit)
+}
+
+// terms are the names the recipe can be looked up by
+func (r *Recipe) terms() []string {
+	return catalogTerms(r.ID, r.Name, r.Names, r.Aliases)
+}
+
+// terms are the names the material can be looked up by
+func (m *Material) terms() []string {
+	return catalogTerms(m.ID, m.Name, m.Names, m.Aliases)
+}
+
+// catalogTerms lists an entry's ID, names and aliases
+func catalogTerms(id, name string, names map[string]string, aliases []string) []string {
+	terms := append([]string{id, name}, aliases...)
+	for _, name := range names {
+		terms = append(terms, name)
+	}
+	return terms
+}
+
+// materialFields and recipeFields are the fields of entries in a recipe catalog file
+var (
+	materialFields = []string{"id", "name", "names", "sources", "aliases"}
+	recipeFields   = []string{"id", "name", "names", "category", "materials", "aliases"}
+)
+
+// materialRecord is a material as written in a recipe catalog file
+type materialRecord struct {
+	ID      string            `yaml:"id"`
+	Name    string            `yaml:"name"`
+	Names   map[string]string `yaml:"names"`
+	Sources []string          `yaml:"sources"`
+	Aliases []string          `yaml:"aliases"`
+}
+
+// recipeRecord is a recipe as written in a recipe catalog file
+type recipeRecord struct {
+	ID        string            `yaml:"id"`
+	Name      string            `yaml:"name"`
+	Names     map[string]string `yaml:"names"`
+	Category  string            `yaml:"category"`
+	Materials map[string]int    `yaml:"materials"`
+	Aliases   []string          `yaml:"aliases"`
+}
+
+// materialRecordAt is a decoded material and the line it starts on
+type materialRecordAt struct {
+	materialRecord
+	line int
+}
+
+// UnmarshalYAML records the line of the material and rejects unknown fields
+func (r *materialRecordAt) UnmarshalYAML(node *yaml.Node) error {
+	r.line = node.Line
+	if err := checkFields(node, materialFields); err != nil {
+		return err
+	}
+	return node.Decode(&r.materialRecord)
+}
+
+// recipeRecordAt is a decoded recipe and the line it starts on
+type recipeRecordAt struct {
+	recipeRecord
+	line int
+}
+
+// UnmarshalYAML records the line of the recipe and rejects unknown fields
+func (r *recipeRecordAt) UnmarshalYAML(node *yaml.Node) error {
+	r.line = node.Line
+	if err := checkFields(node, recipeFields); err != nil {
+		return err
+	}
+	return node.Decode(&r.recipeRecord)
+}
+
+// checkFields rejects fields of a catalog entry that are not in fields
+func checkFields(node *yaml.Node, fields []string) error {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		if key := node.Content[i]; !slices.Contains(fields, key.Value) {
+			return fmt.Errorf("line %d: unknown field %q", key.Line, key.Value)
+		}
+	}
+	return nil
+}
+
+// ParseRecipeCatalog decodes a YAML recipe catalog of the form
+// "materials: [...]" and "recipes: [...]" and validates every entry
+func ParseRecipeCatalog(data []byte, source string) (*RecipeCatalog, error) {
+	var file struct {
+		Materials []materialRecordAt `yaml:"materials"`
+		Recipes   []recipeRecordAt   `yaml:"recipes"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, CatalogErrors{yamlError(err, source)}
+	}
+	if len(file.Recipes) == 0 {
+		return nil, CatalogErrors{{Source: source, Message: "catalog has no recipes"}}
+	}
+
+	catalog := &RecipeCatalog{
+		recipeKeys:   make(map[string]*Recipe),
+		materialKeys: make(map[string]*Material),
+	}
+	var errs CatalogErrors
+	addError := func(line int, message string) {
+		errs = append(errs, &CatalogError{Source: source, Line: line, Message: message})
+	}
+
+	for _, entry := range file.Materials {
+		material, messages := entry.toMaterial()
+		for _, message := range messages {
+			addError(entry.line, message)
+		}
+		if material == nil {
+			continue
+		}
+		for _, term := range material.terms() {
+			key := classKey(term)
+			if other, exists := catalog.materialKeys[key]; exists && other != material {
+				addError(entry.line, fmt.Sprintf("%q is already used by material %q", term, other.ID))
+				continue
+			}
+			catalog.materialKeys[key] = material
+		}
+		catalog.materials = append(catalog.materials, material)
+	}
+
+	for _, entry := range file.Recipes {
+		recipe, messages := entry.toRecipe(catalog.materialKeys)
+		for _, message := range messages {
+			addError(entry.line, message)
+		}
+		if recipe == nil {
+			continue
+		}
+		for _, term := range recipe.terms() {
+			key := classKey(term)
+			if other, exists := catalog.recipeKeys[key]; exists && other != recipe {
+				addError(entry.line, fmt.Sprintf("%q is already used by recipe %q", term, other.ID))
+				continue
+			}
+			catalog.recipeKeys[key] = recipe
+		}
+		catalog.recipes = append(catalog.recipes, recipe)
+	}
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+		return nil, errs
+	}
+
+	sort.Slice(catalog.materials, func(i, j int) bool {
+		return catalog.materials[i].ID < catalog.materials[j].ID
+	})
+	sort.Slice(catalog.recipes, func(i, j int) bool {
+		a, b := catalog.recipes[i], catalog.recipes[j]
+		if a.Category != b.Category {
+			return slices.Index(RecipeCategories, a.Category) < slices.Index(RecipeCategories, b.Category)
+		}
+		return a.ID < b.ID
+	})
+	return catalog, nil
+}
+
+// catalogID validates and normalizes the ID of a catalog entry
+func catalogID(value string) (string, []string) {
+	id := strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case id == "":
+		return "", []string{"id is required"}
+	case !catalogIDPattern.MatchString(id):
+		return "", []string{fmt.Sprintf("invalid id %q: use lowercase letters, digits and underscores", value)}
+	}
+	return id, nil
+}
+
+// toMaterial validates a record and converts it to a material.
+// The material is nil when the record is unusable.
+func (r materialRecord) toMaterial() (*Material, []string) {
+	id, messages := catalogID(r.ID)
+	if strings.TrimSpace(r.Name) == "" {
+		messages = append(messages, "name is required")
+	}
+
+	var sources []string
+	for _, source := range r.Sources {
+		source = strings.ToLower(strings.TrimSpace(source))
+		switch {
+		case !catalogIDPattern.MatchString(source):
+			messages = append(messages, fmt.Sprintf("invalid battle ID %q in sources", source))
+		case slices.Contains(sources, source):
+			messages = append(messages, fmt.Sprintf("duplicate source %q", source))
+		default:
+			sources = append(sources, source)
+		}
+	}
+
+	if len(messages) > 0 {
+		return nil, messages
+	}
+	return &Material{
+		ID:      id,
+		Name:    strings.TrimSpace(r.Name),
+		Names:   r.Names,
+		Sources: sources,
+		Aliases: r.Aliases,
+	}, nil
+}
+
+// toRecipe validates a record against the known materials and converts it to
+// a recipe. The recipe is nil when the record is unusable.
+func (r recipeRecord) toRecipe(materials map[string]*Material) (*Recipe, []string) {
+	id, messages := catalogID(r.ID)
+	if strings.TrimSpace(r.Name) == "" {
+		messages = append(messages, "name is required")
+	}
+	category := RecipeCategory(strings.ToLower(strings.TrimSpace(r.Category)))
+	if !slices.Contains(RecipeCategories, category) {
+		messages = append(messages, fmt.Sprintf("invalid category %q", r.Category))
+	}
+	if len(r.Materials) == 0 {
+		messages = append(messages, "a recipe needs at least one material")
+	}
+
+	ingredients := make([]Ingredient, 0, len(r.Materials))
+	for materialID, count := range r.Materials {
+		// Recipes name materials by ID, not by the names and aliases of lookups
+		material, exists := materials[materialID]
+		switch {
+		case !exists || material.ID != materialID:
+			messages = append(messages, fmt.Sprintf("unknown material %q", materialID))
+		case count <= 0:
+			messages = append(messages, fmt.Sprintf("material %s needs a positive count", materialID))
+		default:
+			ingredients = append(ingredients, Ingredient{Material: material, Count: count})
+		}
+	}
+
+	if len(messages) > 0 {
+		sort.Strings(messages)
+		return nil, messages
+	}
+	sort.Slice(ingredients, func(i, j int) bool {
+		return ingredients[i].Material.ID < ingredients[j].Material.ID
+	})
+	return &Recipe{
+		ID:          id,
+		Name:        strings.TrimSpace(r.Name),
+		Names:       r.Names,
+		Category:    category,
+		Ingredients: ingredients,
+		Aliases:     r.Aliases,
+	}, nil
+}
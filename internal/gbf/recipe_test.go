@@ -0,0 +1,152 @@
+package gbf
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDefaultRecipes(t *testing.T) {
+	recipes := DefaultRecipes()
+	battles := NewBattleManager()
+
+	for _, category := range RecipeCategories {
+		found := false
+		for _, recipe := range recipes.Recipes() {
+			found = found || recipe.Category == category
+		}
+		if !found {
+			t.Errorf("built-in catalog has no %s recipe", category)
+		}
+	}
+	for _, material := range recipes.Materials() {
+		if got := battles.MaterialSources(material); len(got) != len(material.Sources) {
+			t.Errorf("%s drops in %v, expected every source to be a built-in battle", material.ID, material.Sources)
+		}
+	}
+}
+
+func TestRecipeCatalog_Lookup(t *testing.T) {
+	recipes := DefaultRecipes()
+
+	tests := []struct {
+		query  string
+		wantID string
+	}{
+		{"ultima_weapon", "ultima_weapon"},
+		{"Eternal 5★ Uncap", "eternal_5star"},
+		{"ETERNAL_FLB", "eternal_5star"},
+		{"オメガウェポン", "ultima_weapon"},
+	}
+	for _, tt := range tests {
+		recipe, err := recipes.Recipe(tt.query)
+		if err != nil {
+			t.Errorf("Recipe(%q) unexpected error: %v", tt.query, err)
+			continue
+		}
+		if recipe.ID != tt.wantID {
+			t.Errorf("Recipe(%q) = %s, expected %s", tt.query, recipe.ID, tt.wantID)
+		}
+	}
+
+	if material, err := recipes.Material("hihi"); err != nil || material.ID != "hihiirokane" {
+		t.Errorf("Material(hihi) = %v, %v, expected hihiirokane", material, err)
+	}
+	if _, err := recipes.Recipe("hihiirokane"); !errors.Is(err, ErrUnknownRecipe) {
+		t.Errorf("Recipe(hihiirokane) error = %v, expected ErrUnknownRecipe", err)
+	}
+	if _, err := recipes.Material("bar"); !errors.Is(err, ErrUnknownMaterial) {
+		t.Errorf("Material(bar) error = %v, expected ErrUnknownMaterial", err)
+	}
+
+	var ids []string
+	for _, material := range recipes.SearchMaterials("u", 2) {
+		ids = append(ids, material.ID)
+	}
+	if want := []string{"ultima_unit", "damascus_crystal"}; !slices.Equal(ids, want) {
+		t.Errorf("SearchMaterials(u) = %v, expected %v", ids, want)
+	}
+}
+
+func TestRecipe_Plan(t *testing.T) {
+	recipe, err := DefaultRecipes().Recipe("ultima_weapon")
+	if err != nil {
+		t.Fatalf("Recipe(ultima_weapon) unexpected error: %v", err)
+	}
+
+	needs, err := recipe.Plan(2, map[string]int{"ultima_unit": 75, "fragment_of_ultima": 4, "hihiirokane": 3})
+	if err != nil {
+		t.Fatalf("Plan() unexpected error: %v", err)
+	}
+	expected := []struct {
+		id                     string
+		needed, owned, missing int
+	}{
+		{"fragment_of_ultima", 20, 4, 16},
+		{"ultima_unit", 60, 75, 0},
+	}
+	if len(needs) != len(expected) {
+		t.Fatalf("Plan() = %+v, expected %d materials", needs, len(expected))
+	}
+	for i, need := range needs {
+		want := expected[i]
+		if need.Material.ID != want.id || need.Needed != want.needed || need.Owned != want.owned || need.Missing() != want.missing {
+			t.Errorf("need %d = %s %d/%d missing %d, expected %+v", i, need.Material.ID, need.Owned, need.Needed, need.Missing(), want)
+		}
+	}
+
+	for _, quantity := range []int{0, MaxRecipeQuantity + 1} {
+		if _, err := recipe.Plan(quantity, nil); err == nil {
+			t.Errorf("Plan(%d) expected error", quantity)
+		}
+	}
+}
+
+func TestParseRecipeCatalog_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "no recipes",
+			data:    "materials: [{id: ore, name: Ore}]",
+			wantErr: "catalog has no recipes",
+		},
+		{
+			name:    "unknown field",
+			data:    "recipes: [{id: a, name: A, category: ultima, materials: {ore: 1}, cost: 3}]",
+			wantErr: `unknown field "cost"`,
+		},
+		{
+			name:    "unknown material",
+			data:    "materials: [{id: ore, name: Ore}]\nrecipes: [{id: a, name: A, category: ultima, materials: {iron: 1}}]",
+			wantErr: `unknown material "iron"`,
+		},
+		{
+			name:    "invalid category and count",
+			data:    "materials: [{id: ore, name: Ore}]\nrecipes: [{id: a, name: A, category: magna, materials: {ore: 0}}]",
+			wantErr: `invalid category "magna"`,
+		},
+		{
+			name:    "duplicate material",
+			data:    "materials: [{id: ore, name: Ore}, {id: rock, name: Rock, aliases: [ore]}]\nrecipes: [{id: a, name: A, category: ultima, materials: {ore: 1}}]",
+			wantErr: `"ore" is already used by material "ore"`,
+		},
+		{
+			name:    "invalid source",
+			data:    "materials: [{id: ore, name: Ore, sources: [Akasha HL]}]\nrecipes: [{id: a, name: A, category: ultima, materials: {ore: 1}}]",
+			wantErr: `invalid battle ID "akasha hl" in sources`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRecipeCatalog([]byte(tt.data), "recipes.yaml")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseRecipeCatalog() error = %v, expected %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package gbf
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -16,6 +20,15 @@ const (
 	RecruitmentStatusCancelled RecruitmentStatus = "cancelled" // Cancelled
 )
 
+// Errors returned when a participant cannot join or leave a recruitment
+var (
+	ErrRecruitmentNotOpen = errors.New("recruitment is not open for new participants")
+	ErrAlreadyParticipant = errors.New("user is already a participant")
+	ErrRecruitmentFull    = errors.New("recruitment is full")
+	ErrHostCannotLeave    = errors.New("host cannot leave recruitment")
+	ErrNotParticipant     = errors.New("participant not found")
+)
+
 // ParticipantRole represents the role of a participant
 type ParticipantRole string
 
@@ -62,6 +75,7 @@ type Recruitment struct {
 
 // RecruitmentManager manages battle recruitments
 type RecruitmentManager struct {
+	mu            sync.RWMutex
 	recruitments  map[string]*Recruitment
 	battleManager *BattleManager
 }
@@ -74,7 +88,8 @@ func NewRecruitmentManager(battleManager *BattleManager) *RecruitmentManager {
 	}
 }
 
-// CreateRecruitment creates a new recruitment
+// CreateRecruitment creates a new recruitment. The manager keeps its own copy,
+// so req remains the caller's.
 func (rm *RecruitmentManager) CreateRecruitment(req *Recruitment) error {
 	if req.ID == "" {
 		return fmt.Errorf("recruitment ID cannot be empty")
@@ -85,6 +100,7 @@ func (rm *RecruitmentManager) CreateRecruitment(req *Recruitment) error {
 	if err != nil {
 		return fmt.Errorf("invalid battle ID: %w", err)
 	}
+	req.BattleID = battle.ID
 
 	// Set defaults
 	now := time.Now()
@@ -118,73 +134,161 @@ func (rm *RecruitmentManager) CreateRecruitment(req *Recruitment) error {
 		req.Participants = []Participant{host}
 	}
 
-	rm.recruitments[req.ID] = req
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if _, exists := rm.recruitments[req.ID]; exists {
+		return fmt.Errorf("recruitment already exists: %s", req.ID)
+	}
+	rm.recruitments[req.ID] = req.clone()
 	return nil
 }
 
-// GetRecruitment retrieves a recruitment by ID
+// GetRecruitment returns a copy of a recruitment by ID
 func (rm *RecruitmentManager) GetRecruitment(id string) (*Recruitment, error) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
 	recruitment, exists := rm.recruitments[id]
 	if !exists {
 		return nil, fmt.Errorf("recruitment not found: %s", id)
 	}
-	return recruitment, nil
+	return recruitment.clone(), nil
 }
 
-// GetRecruitmentByMessage retrieves a recruitment by message ID
+// GetRecruitmentByMessage returns a copy of a recruitment by message ID
 func (rm *RecruitmentManager) GetRecruitmentByMessage(messageID string) (*Recruitment, error) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
 	for _, recruitment := range rm.recruitments {
 		if recruitment.MessageID == messageID {
-			return recruitment, nil
+			return recruitment.clone(), nil
 		}
 	}
 	return nil, fmt.Errorf("recruitment not found for message: %s", messageID)
 }
 
-// GetActiveRecruitments returns all active recruitments
+// GetActiveRecruitments returns copies of all active recruitments
 func (rm *RecruitmentManager) GetActiveRecruitments() []*Recruitment {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
 	var activeRecruitments []*Recruitment
 	for _, recruitment := range rm.recruitments {
 		if recruitment.Status == RecruitmentStatusOpen || recruitment.Status == RecruitmentStatusFull {
-			activeRecruitments = append(activeRecruitments, recruitment)
+			activeRecruitments = append(activeRecruitments, recruitment.clone())
 		}
 	}
 	return activeRecruitments
 }
 
-// GetRecruitmentsByChannel returns recruitments for a specific channel
+// GetJoinableRecruitments returns copies of the recruitments of a guild a user can
+// join, newest first. A non-empty element keeps the recruitments for battles of
+// that element or of all elements.
+func (rm *RecruitmentManager) GetJoinableRecruitments(guildID, userID string, element Element) []Recruitment {
+	return rm.guildRecruitments(guildID, func(r *Recruitment) bool {
+		return r.CanJoin(userID) && (element == "" || rm.battleMatches(r.BattleID, element))
+	})
+}
+
+// battleMatches reports whether a battle is of element or of all elements
+func (rm *RecruitmentManager) battleMatches(battleID string, element Element) bool {
+	battle, err := rm.battleManager.GetBattle(battleID)
+	if err != nil {
+		return false
+	}
+	return battle.Element == element || battle.Element == ElementAll
+}
+
+// GetJoinedRecruitments returns copies of the active recruitments of a guild a user
+// has joined without hosting, newest first
+func (rm *RecruitmentManager) GetJoinedRecruitments(guildID, userID string) []Recruitment {
+	return rm.guildRecruitments(guildID, func(r *Recruitment) bool {
+		if r.Status != RecruitmentStatusOpen && r.Status != RecruitmentStatusFull {
+			return false
+		}
+		for _, participant := range r.Participants {
+			if participant.UserID == userID {
+				return participant.Role != ParticipantRoleHost
+			}
+		}
+		return false
+	})
+}
+
+// guildRecruitments returns copies of the recruitments of a guild that match, newest first.
+// Copies can be read without holding the lock.
+func (rm *RecruitmentManager) guildRecruitments(guildID string, matches func(r *Recruitment) bool) []Recruitment {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	var found []Recruitment
+	for _, recruitment := range rm.recruitments {
+		if recruitment.GuildID != guildID || !matches(recruitment) {
+			continue
+		}
+		found = append(found, *recruitment.clone())
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].CreatedAt.After(found[j].CreatedAt)
+	})
+	return found
+}
+
+// SetMessageID records the message that shows a recruitment
+func (rm *RecruitmentManager) SetMessageID(recruitmentID, messageID string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	recruitment, exists := rm.recruitments[recruitmentID]
+	if !exists {
+		return fmt.Errorf("recruitment not found: %s", recruitmentID)
+	}
+	recruitment.MessageID = messageID
+	return nil
+}
+
+// GetRecruitmentsByChannel returns copies of the recruitments for a specific channel
 func (rm *RecruitmentManager) GetRecruitmentsByChannel(channelID string) []*Recruitment {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
 	var channelRecruitments []*Recruitment
 	for _, recruitment := range rm.recruitments {
 		if recruitment.ChannelID == channelID {
-			channelRecruitments = append(channelRecruitments, recruitment)
+			channelRecruitments = append(channelRecruitments, recruitment.clone())
 		}
 	}
 	return channelRecruitments
 }
 
-// AddParticipant adds a participant to a recruitment
-func (rm *RecruitmentManager) AddParticipant(recruitmentID, userID, username string) error {
+// AddParticipant adds a participant to a recruitment and returns a copy of the
+// updated recruitment
+func (rm *RecruitmentManager) AddParticipant(recruitmentID, userID, username string) (*Recruitment, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
 	recruitment, exists := rm.recruitments[recruitmentID]
 	if !exists {
-		return fmt.Errorf("recruitment not found: %s", recruitmentID)
+		return nil, fmt.Errorf("recruitment not found: %s", recruitmentID)
 	}
 
 	// Check if recruitment is open
 	if recruitment.Status != RecruitmentStatusOpen {
-		return fmt.Errorf("recruitment is not open for new participants")
+		return nil, ErrRecruitmentNotOpen
 	}
 
 	// Check if user is already a participant
 	for _, participant := range recruitment.Participants {
 		if participant.UserID == userID {
-			return fmt.Errorf("user is already a participant")
+			return nil, ErrAlreadyParticipant
 		}
 	}
 
 	// Check if recruitment is full
 	if len(recruitment.Participants) >= recruitment.MaxPlayers {
-		return fmt.Errorf("recruitment is full")
+		return nil, ErrRecruitmentFull
 	}
 
 	// Add participant
@@ -204,14 +308,18 @@ func (rm *RecruitmentManager) AddParticipant(recruitmentID, userID, username str
 		recruitment.Status = RecruitmentStatusFull
 	}
 
-	return nil
+	return recruitment.clone(), nil
 }
 
-// RemoveParticipant removes a participant from a recruitment
-func (rm *RecruitmentManager) RemoveParticipant(recruitmentID, userID string) error {
+// RemoveParticipant removes a participant from a recruitment and returns a copy
+// of the updated recruitment
+func (rm *RecruitmentManager) RemoveParticipant(recruitmentID, userID string) (*Recruitment, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
 	recruitment, exists := rm.recruitments[recruitmentID]
 	if !exists {
-		return fmt.Errorf("recruitment not found: %s", recruitmentID)
+		return nil, fmt.Errorf("recruitment not found: %s", recruitmentID)
 	}
 
 	// Find and remove participant
@@ -219,7 +327,7 @@ func (rm *RecruitmentManager) RemoveParticipant(recruitmentID, userID string) er
 		if participant.UserID == userID {
 			// Don't allow host to leave
 			if participant.Role == ParticipantRoleHost {
-				return fmt.Errorf("host cannot leave recruitment")
+				return nil, ErrHostCannotLeave
 			}
 
 			// Remove participant
@@ -231,27 +339,34 @@ func (rm *RecruitmentManager) RemoveParticipant(recruitmentID, userID string) er
 				recruitment.Status = RecruitmentStatusOpen
 			}
 
-			return nil
+			return recruitment.clone(), nil
 		}
 	}
 
-	return fmt.Errorf("participant not found: %s", userID)
+	return nil, fmt.Errorf("%w: %s", ErrNotParticipant, userID)
 }
 
-// UpdateRecruitmentStatus updates the status of a recruitment
-func (rm *RecruitmentManager) UpdateRecruitmentStatus(recruitmentID string, status RecruitmentStatus) error {
+// UpdateRecruitmentStatus updates the status of a recruitment and returns a copy
+// of the updated recruitment
+func (rm *RecruitmentManager) UpdateRecruitmentStatus(recruitmentID string, status RecruitmentStatus) (*Recruitment, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
 	recruitment, exists := rm.recruitments[recruitmentID]
 	if !exists {
-		return fmt.Errorf("recruitment not found: %s", recruitmentID)
+		return nil, fmt.Errorf("recruitment not found: %s", recruitmentID)
 	}
 
 	recruitment.Status = status
 	recruitment.UpdatedAt = time.Now()
-	return nil
+	return recruitment.clone(), nil
 }
 
 // CleanupExpiredRecruitments removes expired recruitments
 func (rm *RecruitmentManager) CleanupExpiredRecruitments() []*Recruitment {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
 	var expired []*Recruitment
 	now := time.Now()
 
@@ -268,6 +383,14 @@ func (rm *RecruitmentManager) CleanupExpiredRecruitments() []*Recruitment {
 	return expired
 }
 
+// clone returns a copy of the recruitment that can be read without holding the
+// manager's lock
+func (r *Recruitment) clone() *Recruitment {
+	copied := *r
+	copied.Participants = slices.Clone(r.Participants)
+	return &copied
+}
+
 // GetParticipantCount returns the current number of participants
 func (r *Recruitment) GetParticipantCount() int {
 	return len(r.Participants)
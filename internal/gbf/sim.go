@@ -0,0 +1,257 @@
+package gbf
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// Charge bar rules
+const (
+	// ChargeBarMax is the charge bar a character needs for a charge attack
+	ChargeBarMax = 100
+	// DefaultChargeMultiplier is the charge attack multiplier of most SSR characters
+	DefaultChargeMultiplier = 4.5
+)
+
+// chargeGains are the charge bar gains of normal attacks with one, two and three hits
+var chargeGains = [4]float64{0, 10, 22, 37}
+
+// Simulation limits
+const (
+	DefaultSimTrials = 1000
+	MaxSimTrials     = 100000
+	MaxSimMembers    = 4
+	// simTurns is the length of a trial without a target HP
+	simTurns = 100
+	// maxSimTurns ends trials that cannot defeat the target
+	maxSimTurns = 1000
+)
+
+// SimInput is a party attacking an enemy every turn
+type SimInput struct {
+	// Attack is one normal attack hit of a party member; Kind and Cap are ignored
+	// for charge attacks
+	Attack DamageInput
+	// DoubleAttack and TripleAttack are the multi-attack rates, from 0 to 1.
+	// Triple attacks are rolled first.
+	DoubleAttack, TripleAttack float64
+
+	// ChargeMultiplier is the charge attack multiplier; 0 is treated as DefaultChargeMultiplier
+	ChargeMultiplier float64
+	// ChargeBoost raises charge bar gain, e.g. 0.2 for +20%
+	ChargeBoost float64
+
+	// Members is the number of party members with these stats; 0 is treated as 1
+	Members int
+	// TargetHP is the enemy HP for turns to kill; 0 skips it
+	TargetHP int
+
+	// Trials is the number of Monte Carlo runs; 0 is treated as DefaultSimTrials
+	Trials int
+	// Seed makes the Monte Carlo runs repeatable
+	Seed uint64
+}
+
+// SimResult is the expected outcome of a simulation, both calculated and sampled
+type SimResult struct {
+	// Normal and Charge are the per-hit breakdowns of normal and charge attacks
+	Normal, Charge *DamageBreakdown
+
+	// ExpectedHits is the average number of hits of a normal attack
+	ExpectedHits float64
+	// ChargeRate is the share of a member's turns spent on charge attacks.
+	// The analytic value ignores the bar overshooting ChargeBarMax.
+	ChargeRate float64
+	// TurnDamage is the party's expected damage per turn
+	TurnDamage float64
+	// TurnsToKill is the expected turns to defeat TargetHP, 0 without a target
+	TurnsToKill float64
+
+	// Simulated holds the Monte Carlo estimates of the same values
+	Simulated SimStats
+}
+
+// SimStats are the averages of the Monte Carlo runs
+type SimStats struct {
+	Trials      int
+	ChargeRate  float64
+	TurnDamage  float64
+	TurnsToKill float64
+	// MinTurns and MaxTurns are the fastest and slowest kills, 0 without a target
+	MinTurns, MaxTurns int
+}
+
+// Simulate estimates the damage per turn, charge attack frequency and turns to
+// kill of a party, analytically and with seeded Monte Carlo runs
+func (c *AttackCalculator) Simulate(input SimInput) (*SimResult, error) {
+	if err := validateSim(input); err != nil {
+		return nil, err
+	}
+	members := max(input.Members, 1)
+	trials := input.Trials
+	if trials == 0 {
+		trials = DefaultSimTrials
+	}
+
+	normal, err := c.Calculate(input.Attack)
+	if err != nil {
+		return nil, err
+	}
+	charge, err := c.Calculate(chargeAttack(input))
+	if err != nil {
+		return nil, err
+	}
+
+	// Chances of a normal attack hitting once, twice and three times
+	hits := [4]float64{}
+	hits[3] = input.TripleAttack
+	hits[2] = (1 - input.TripleAttack) * input.DoubleAttack
+	hits[1] = 1 - hits[2] - hits[3]
+
+	result := &SimResult{Normal: normal, Charge: charge}
+	var gain float64
+	for n := 1; n <= 3; n++ {
+		result.ExpectedHits += float64(n) * hits[n]
+		gain += chargeGains[n] * hits[n]
+	}
+	gain *= 1 + input.ChargeBoost
+
+	// A member attacks normally until the bar fills, then uses one charge attack
+	if gain > 0 {
+		result.ChargeRate = gain / (gain + ChargeBarMax)
+	}
+	memberDamage := (1-result.ChargeRate)*result.ExpectedHits*float64(normal.Damage) +
+		result.ChargeRate*float64(charge.Damage)
+	result.TurnDamage = memberDamage * float64(members)
+	if input.TargetHP > 0 && result.TurnDamage > 0 {
+		result.TurnsToKill = math.Ceil(float64(input.TargetHP) / result.TurnDamage)
+	}
+
+	result.Simulated = simulate(input, normal, charge, hits, members, trials)
+	return result, nil
+}
+
+// simulate runs the Monte Carlo trials
+func simulate(input SimInput, normal, charge *DamageBreakdown, hits [4]float64, members, trials int) SimStats {
+	rng := rand.New(rand.NewPCG(input.Seed, input.Seed))
+	stats := SimStats{Trials: trials}
+
+	// Sampled normal hits include echo and supplemental damage
+	hitDamage := func() float64 {
+		roll := rng.Float64()
+		outcome := normal.Crits[len(normal.Crits)-1]
+		for _, o := range normal.Crits {
+			if roll < o.Probability {
+				outcome = o
+				break
+			}
+			roll -= o.Probability
+		}
+		return outcome.Damage*(1+input.Attack.Echo) + float64(input.Attack.Supplemental)
+	}
+
+	var totalDamage float64
+	var totalTurns, memberTurns, charges int
+	for range trials {
+		bars := make([]float64, members)
+		hp := float64(input.TargetHP)
+		turns := 0
+		for {
+			turns++
+			for m := range bars {
+				memberTurns++
+				if bars[m] >= ChargeBarMax {
+					charges++
+					bars[m] = 0
+					hp -= float64(charge.Damage)
+					totalDamage += float64(charge.Damage)
+					continue
+				}
+
+				n := 1
+				switch roll := rng.Float64(); {
+				case roll < hits[3]:
+					n = 3
+				case roll < hits[3]+hits[2]:
+					n = 2
+				}
+				for range n {
+					damage := hitDamage()
+					hp -= damage
+					totalDamage += damage
+				}
+				bars[m] = min(bars[m]+chargeGains[n]*(1+input.ChargeBoost), ChargeBarMax)
+			}
+
+			if input.TargetHP == 0 && turns >= simTurns {
+				break
+			}
+			if input.TargetHP > 0 && (hp <= 0 || turns >= maxSimTurns) {
+				break
+			}
+		}
+
+		totalTurns += turns
+		if input.TargetHP > 0 {
+			if stats.MinTurns == 0 || turns < stats.MinTurns {
+				stats.MinTurns = turns
+			}
+			stats.MaxTurns = max(stats.MaxTurns, turns)
+		}
+	}
+
+	stats.ChargeRate = float64(charges) / float64(memberTurns)
+	stats.TurnDamage = totalDamage / float64(totalTurns)
+	if input.TargetHP > 0 {
+		stats.TurnsToKill = float64(totalTurns) / float64(trials)
+	}
+	return stats
+}
+
+// chargeAttack returns the charge attack hit of a party member. Charge attacks
+// do not crit, echo or get supplemental damage and use the charge attack cap.
+func chargeAttack(input SimInput) DamageInput {
+	attack := input.Attack
+	multiplier := input.ChargeMultiplier
+	if multiplier == 0 {
+		multiplier = DefaultChargeMultiplier
+	}
+	if attack.HitMultiplier != 0 {
+		multiplier *= attack.HitMultiplier
+	}
+
+	attack.HitMultiplier = multiplier
+	attack.Kind = DamageKindCharge
+	// A nil cap makes Calculate use the charge attack cap
+	attack.Cap = nil
+	attack.Crits = nil
+	attack.Echo = 0
+	attack.Supplemental = 0
+	return attack
+}
+
+// validateSim checks the simulation settings
+func validateSim(input SimInput) error {
+	for _, rate := range []float64{input.DoubleAttack, input.TripleAttack} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("multi-attack rates must be between 0 and 1: %g", rate)
+		}
+	}
+	if input.ChargeMultiplier < 0 {
+		return fmt.Errorf("charge multiplier cannot be negative: %g", input.ChargeMultiplier)
+	}
+	if input.ChargeBoost <= -1 {
+		return fmt.Errorf("charge boost must be greater than -1: %g", input.ChargeBoost)
+	}
+	if input.Members < 0 || input.Members > MaxSimMembers {
+		return fmt.Errorf("members must be between 1 and %d: %d", MaxSimMembers, input.Members)
+	}
+	if input.TargetHP < 0 {
+		return fmt.Errorf("target HP cannot be negative: %d", input.TargetHP)
+	}
+	if input.Trials < 0 || input.Trials > MaxSimTrials {
+		return fmt.Errorf("trials must be between 1 and %d: %d", MaxSimTrials, input.Trials)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package gbf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAttackCalculator_Simulate(t *testing.T) {
+	calc := NewAttackCalculator()
+
+	// 1000 per normal hit and 4500 per charge attack
+	plain := DamageInput{BaseAttack: 10000}
+
+	t.Run("single attacks fill the bar every ten turns", func(t *testing.T) {
+		result, err := calc.Simulate(SimInput{Attack: plain, TargetHP: 10000, Trials: 10})
+		if err != nil {
+			t.Fatalf("Simulate() unexpected error: %v", err)
+		}
+		if result.ExpectedHits != 1 {
+			t.Errorf("ExpectedHits = %f, expected 1", result.ExpectedHits)
+		}
+		if want := 10.0 / 110; math.Abs(result.ChargeRate-want) > 1e-9 {
+			t.Errorf("ChargeRate = %f, expected %f", result.ChargeRate, want)
+		}
+		if want := (100*1000 + 10*4500) / 110.0; math.Abs(result.TurnDamage-want) > 1e-9 {
+			t.Errorf("TurnDamage = %f, expected %f", result.TurnDamage, want)
+		}
+		if result.TurnsToKill != 8 {
+			t.Errorf("TurnsToKill = %f, expected 8", result.TurnsToKill)
+		}
+		// The bar fills on turn 11, after the target is down
+		sim := result.Simulated
+		if sim.TurnsToKill != 10 || sim.MinTurns != 10 || sim.MaxTurns != 10 || sim.ChargeRate != 0 {
+			t.Errorf("Simulated = %+v, expected 10 turns without charge attacks", sim)
+		}
+	})
+
+	t.Run("monte carlo agrees with the analytic estimate", func(t *testing.T) {
+		input := SimInput{
+			Attack: DamageInput{
+				BaseAttack: 10000,
+				Normal:     1.5,
+				Attacker:   ElementFire,
+				Defender:   ElementWind,
+				Crits:      []CritSource{{Rate: 0.3, Multiplier: 0.5}},
+				Echo:       0.2,
+			},
+			DoubleAttack: 0.5,
+			TripleAttack: 0.2,
+			ChargeBoost:  0.2,
+			Members:      4,
+			Trials:       2000,
+			Seed:         42,
+		}
+		result, err := calc.Simulate(input)
+		if err != nil {
+			t.Fatalf("Simulate() unexpected error: %v", err)
+		}
+		if want := 1*0.4 + 2*0.4 + 3*0.2; math.Abs(result.ExpectedHits-want) > 1e-9 {
+			t.Errorf("ExpectedHits = %f, expected %f", result.ExpectedHits, want)
+		}
+		// The analytic charge rate ignores the bar overshooting, so allow a few percent
+		if diff := math.Abs(result.Simulated.TurnDamage/result.TurnDamage - 1); diff > 0.05 {
+			t.Errorf("simulated turn damage %f is %.1f%% off the analytic %f", result.Simulated.TurnDamage, diff*100, result.TurnDamage)
+		}
+		if diff := math.Abs(result.Simulated.ChargeRate - result.ChargeRate); diff > 0.03 {
+			t.Errorf("simulated charge rate %f is off the analytic %f", result.Simulated.ChargeRate, result.ChargeRate)
+		}
+	})
+
+	t.Run("same seed gives the same result", func(t *testing.T) {
+		input := SimInput{
+			Attack:       DamageInput{BaseAttack: 10000, Attacker: ElementFire, Defender: ElementWind, Crits: []CritSource{{Rate: 0.5, Multiplier: 0.5}}},
+			DoubleAttack: 0.4,
+			TripleAttack: 0.1,
+			TargetHP:     500000,
+			Trials:       200,
+			Seed:         7,
+		}
+		first, err := calc.Simulate(input)
+		if err != nil {
+			t.Fatalf("Simulate() unexpected error: %v", err)
+		}
+		second, _ := calc.Simulate(input)
+		if first.Simulated != second.Simulated {
+			t.Errorf("Simulated = %+v and %+v, expected identical runs", first.Simulated, second.Simulated)
+		}
+
+		input.Seed = 8
+		other, _ := calc.Simulate(input)
+		if other.Simulated == first.Simulated {
+			t.Error("a different seed gave identical runs")
+		}
+	})
+}
+
+func TestAttackCalculator_Simulate_ChargeCap(t *testing.T) {
+	calc := NewAttackCalculator()
+	attack := DamageInput{BaseAttack: 1000000, Normal: 5}
+
+	result, err := calc.Simulate(SimInput{Attack: attack})
+	if err != nil {
+		t.Fatalf("Simulate() unexpected error: %v", err)
+	}
+
+	attack.Kind = DamageKindCharge
+	attack.HitMultiplier = DefaultChargeMultiplier
+	expected, err := calc.Calculate(attack)
+	if err != nil {
+		t.Fatalf("Calculate() unexpected error: %v", err)
+	}
+	if expected.Raw <= expected.Cap[0].Threshold {
+		t.Fatalf("Raw = %f, expected the hit to reach the charge attack cap", expected.Raw)
+	}
+	if result.Charge.Damage != expected.Damage {
+		t.Errorf("charge attack = %d, expected the capped %d", result.Charge.Damage, expected.Damage)
+	}
+}
+
+func TestAttackCalculator_Simulate_Errors(t *testing.T) {
+	calc := NewAttackCalculator()
+
+	tests := []struct {
+		name  string
+		input SimInput
+	}{
+		{name: "double attack rate above 1", input: SimInput{Attack: DamageInput{BaseAttack: 1000}, DoubleAttack: 1.5}},
+		{name: "negative triple attack rate", input: SimInput{Attack: DamageInput{BaseAttack: 1000}, TripleAttack: -0.1}},
+		{name: "too many members", input: SimInput{Attack: DamageInput{BaseAttack: 1000}, Members: 5}},
+		{name: "negative target HP", input: SimInput{Attack: DamageInput{BaseAttack: 1000}, TargetHP: -1}},
+		{name: "too many trials", input: SimInput{Attack: DamageInput{BaseAttack: 1000}, Trials: MaxSimTrials + 1}},
+		{name: "invalid attack", input: SimInput{Attack: DamageInput{BaseAttack: -1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := calc.Simulate(tt.input); err == nil {
+				t.Error("Simulate() expected error")
+			}
+		})
+	}
+}
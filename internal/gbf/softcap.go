@@ -0,0 +1,97 @@
+package gbf
+
+import "slices"
+
+// DamageKind is the kind of a hit, which decides its soft cap
+type DamageKind string
+
+const (
+	DamageKindNormal DamageKind = "normal"        // Normal attacks
+	DamageKindSkill  DamageKind = "skill"         // Damaging skills
+	DamageKindCharge DamageKind = "charge_attack" // Charge attacks
+)
+
+// DamageKinds are the valid damage kinds
+var DamageKinds = []DamageKind{DamageKindNormal, DamageKindSkill, DamageKindCharge}
+
+// IsValidDamageKind checks if the given damage kind is valid; empty means normal
+func IsValidDamageKind(kind DamageKind) bool {
+	return kind == "" || slices.Contains(DamageKinds, kind)
+}
+
+// CapTier is a band of a soft cap: damage above Threshold is multiplied by Rate
+type CapTier struct {
+	Threshold float64
+	Rate      float64
+}
+
+// SoftCap is a piecewise damage reduction with tiers ordered by threshold.
+// Damage below the first threshold is kept in full.
+type SoftCap []CapTier
+
+// NormalAttackCap is the soft cap of normal attacks
+var NormalAttackCap = SoftCap{
+	{Threshold: 300000, Rate: 0.8},
+	{Threshold: 400000, Rate: 0.6},
+	{Threshold: 500000, Rate: 0.05},
+	{Threshold: 600000, Rate: 0.01},
+}
+
+// SkillCap is the soft cap of damaging skills
+var SkillCap = SoftCap{
+	{Threshold: 400000, Rate: 0.8},
+	{Threshold: 500000, Rate: 0.6},
+	{Threshold: 650000, Rate: 0.05},
+	{Threshold: 800000, Rate: 0.01},
+}
+
+// ChargeAttackCap is the soft cap of charge attacks
+var ChargeAttackCap = SoftCap{
+	{Threshold: 1000000, Rate: 0.8},
+	{Threshold: 1500000, Rate: 0.6},
+	{Threshold: 1700000, Rate: 0.05},
+	{Threshold: 2000000, Rate: 0.01},
+}
+
+// CapFor returns the soft cap of a damage kind
+func CapFor(kind DamageKind) SoftCap {
+	switch kind {
+	case DamageKindSkill:
+		return SkillCap
+	case DamageKindCharge:
+		return ChargeAttackCap
+	default:
+		return NormalAttackCap
+	}
+}
+
+// Raise returns the cap with every threshold raised by capUp, e.g. 0.2 for +20%
+func (c SoftCap) Raise(capUp float64) SoftCap {
+	if capUp == 0 {
+		return c
+	}
+	raised := make(SoftCap, len(c))
+	for i, tier := range c {
+		raised[i] = CapTier{Threshold: tier.Threshold * (1 + capUp), Rate: tier.Rate}
+	}
+	return raised
+}
+
+// Apply reduces damage according to the cap tiers
+func (c SoftCap) Apply(damage float64) float64 {
+	if len(c) == 0 || damage <= c[0].Threshold {
+		return damage
+	}
+
+	capped := c[0].Threshold
+	for i, tier := range c {
+		upper := damage
+		if i+1 < len(c) && c[i+1].Threshold < damage {
+			upper = c[i+1].Threshold
+		}
+		if upper > tier.Threshold {
+			capped += (upper - tier.Threshold) * tier.Rate
+		}
+	}
+	return capped
+}
@@ -0,0 +1,92 @@
+package gbf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSoftCap_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		cap      SoftCap
+		damage   float64
+		expected float64
+	}{
+		{"normal below the cap", NormalAttackCap, 300000, 300000},
+		{"normal first tier", NormalAttackCap, 350000, 340000},
+		{"normal second tier", NormalAttackCap, 450000, 410000},
+		{"normal beyond every tier", NormalAttackCap, 700000, 446000}, // 300000 + 80000 + 60000 + 5000 + 1000
+		{"skill below the cap", SkillCap, 400000, 400000},
+		{"skill second tier", SkillCap, 600000, 540000}, // 400000 + 80000 + 60000
+		{"charge attack below the cap", ChargeAttackCap, 1000000, 1000000},
+		{"charge attack fourth tier", ChargeAttackCap, 1800000, 1525000},  // 1000000 + 400000 + 120000 + 5000
+		{"raised normal cap", NormalAttackCap.Raise(0.2), 450000, 432000}, // 360000 + 90000 * 0.8
+		{"no cap", nil, 1e9, 1e9},
+		{"zero damage", NormalAttackCap, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cap.Apply(tt.damage); math.Abs(got-tt.expected) > 1e-6 {
+				t.Errorf("Apply(%g) = %g, expected %g", tt.damage, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCapFor(t *testing.T) {
+	tests := []struct {
+		kind     DamageKind
+		expected SoftCap
+	}{
+		{"", NormalAttackCap},
+		{DamageKindNormal, NormalAttackCap},
+		{DamageKindSkill, SkillCap},
+		{DamageKindCharge, ChargeAttackCap},
+	}
+
+	for _, tt := range tests {
+		if got := CapFor(tt.kind); got[0] != tt.expected[0] {
+			t.Errorf("CapFor(%q) starts at %+v, expected %+v", tt.kind, got[0], tt.expected[0])
+		}
+	}
+}
+
+func TestSoftCap_Raise(t *testing.T) {
+	raised := ChargeAttackCap.Raise(0.5)
+	for i, tier := range raised {
+		if tier.Threshold != ChargeAttackCap[i].Threshold*1.5 || tier.Rate != ChargeAttackCap[i].Rate {
+			t.Errorf("tier %d = %+v, expected the threshold of %+v raised by 50%%", i, tier, ChargeAttackCap[i])
+		}
+	}
+	if ChargeAttackCap[0].Threshold != 1000000 {
+		t.Errorf("Raise() modified the shared table: %+v", ChargeAttackCap[0])
+	}
+}
+
+func TestDamageBreakdown_CapLoss(t *testing.T) {
+	calc := NewAttackCalculator()
+
+	tests := []struct {
+		name     string
+		input    DamageInput
+		expected float64
+	}{
+		{"below the cap", DamageInput{BaseAttack: 10000}, 0},
+		{"at the normal cap", DamageInput{BaseAttack: 4000000}, 0.05},                             // 380000 of 400000
+		{"cap up recovers damage", DamageInput{BaseAttack: 4000000, CapUp: 0.2}, 0.02},            // 392000 of 400000
+		{"charge attacks cap later", DamageInput{BaseAttack: 4000000, Kind: DamageKindCharge}, 0}, // 400000 raw
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(tt.input)
+			if err != nil {
+				t.Fatalf("Calculate() unexpected error: %v", err)
+			}
+			if got := result.CapLoss(); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("CapLoss() = %g, expected %g", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discordLocales are the Discord languages each locale is registered for
+var discordLocales = map[Locale][]discordgo.Locale{
+	English:  {discordgo.EnglishUS, discordgo.EnglishGB},
+	Japanese: {discordgo.Japanese},
+}
+
+// LocalizeCommand fills in the localized names and descriptions of a slash command,
+// its options and their choices from the commands.<name> section of the catalogs.
+// Choices named after their value are identifiers and are left as they are.
+// It returns the sorted keys missing from any catalog.
+func LocalizeCommand(command *discordgo.ApplicationCommand) []string {
+	l := &commandLocalizer{missing: make(map[string]bool)}
+
+	prefix := "commands." + command.Name
+	names := l.localize(prefix + ".name")
+	descriptions := l.localize(prefix + ".description")
+	command.NameLocalizations = &names
+	command.DescriptionLocalizations = &descriptions
+	for _, option := range command.Options {
+		l.localizeOption(prefix, option)
+	}
+
+	missing := make([]string, 0, len(l.missing))
+	for key := range l.missing {
+		missing = append(missing, key)
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// commandLocalizer collects the keys missing while localizing a command
+type commandLocalizer struct {
+	missing map[string]bool
+}
+
+// localizeOption localizes an option, a subcommand or a subcommand group and its children
+func (l *commandLocalizer) localizeOption(parent string, option *discordgo.ApplicationCommandOption) {
+	prefix := parent + ".options." + option.Name
+	option.NameLocalizations = l.localize(prefix + ".name")
+	option.DescriptionLocalizations = l.localize(prefix + ".description")
+
+	for _, choice := range option.Choices {
+		value := fmt.Sprint(choice.Value)
+		if choice.Name == value {
+			continue
+		}
+		choice.NameLocalizations = l.localize(prefix + ".choices." + value)
+	}
+	for _, child := range option.Options {
+		l.localizeOption(prefix, child)
+	}
+}
+
+// localize returns the message of a key for every Discord language, recording
+// the key if a catalog lacks it
+func (l *commandLocalizer) localize(key string) map[discordgo.Locale]string {
+	localizations := make(map[discordgo.Locale]string)
+	for locale, targets := range discordLocales {
+		message, exists := catalogs[locale][key]
+		if !exists {
+			l.missing[key] = true
+			continue
+		}
+		for _, target := range targets {
+			localizations[target] = message
+		}
+	}
+	return localizations
+}
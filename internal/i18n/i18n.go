@@ -0,0 +1,152 @@
+// Package i18n translates the bot's responses and slash command definitions.
+// Messages are looked up by key in per-locale catalogs embedded from locales/*.yaml.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Locale is a language the bot can respond in
+type Locale string
+
+// Supported locales
+const (
+	English  Locale = "en"
+	Japanese Locale = "ja"
+)
+
+// Default is the locale used when neither the user nor the guild chose a supported one
+const Default = English
+
+// Locales are the supported locales in display order
+var Locales = []Locale{Japanese, English}
+
+// localeNames are the names of the locales in their own language
+var localeNames = map[Locale]string{
+	English:  "English",
+	Japanese: "日本語",
+}
+
+// Name returns the name of the locale in its own language
+func (l Locale) Name() string {
+	if name, exists := localeNames[l]; exists {
+		return name
+	}
+	return string(l)
+}
+
+// Parse converts a language tag such as "ja" or Discord's "en-US" to a supported locale
+func Parse(tag string) (Locale, bool) {
+	language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	locale := Locale(language)
+	_, supported := catalogs[locale]
+	return locale, supported
+}
+
+//go:embed locales/*.yaml
+var catalogFiles embed.FS
+
+// catalogs are the flattened messages of every locale by key
+var catalogs = mustLoadCatalogs()
+
+// mustLoadCatalogs parses the embedded catalogs, panicking if any is invalid
+func mustLoadCatalogs() map[Locale]map[string]string {
+	files, err := catalogFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("failed to read message catalogs: %v", err))
+	}
+
+	loaded := make(map[Locale]map[string]string, len(files))
+	for _, file := range files {
+		data, err := catalogFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("failed to read message catalog %s: %v", file.Name(), err))
+		}
+		var tree map[string]any
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			panic(fmt.Sprintf("invalid message catalog %s: %v", file.Name(), err))
+		}
+
+		messages := make(map[string]string)
+		if err := flatten("", tree, messages); err != nil {
+			panic(fmt.Sprintf("invalid message catalog %s: %v", file.Name(), err))
+		}
+		loaded[Locale(strings.TrimSuffix(file.Name(), path.Ext(file.Name())))] = messages
+	}
+	return loaded
+}
+
+// flatten stores the strings of a nested catalog under dot separated keys
+func flatten(prefix string, tree map[string]any, messages map[string]string) error {
+	for name, value := range tree {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		switch value := value.(type) {
+		case string:
+			messages[key] = value
+		case map[string]any:
+			if err := flatten(key, value, messages); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s must be a string or a map, got %T", key, value)
+		}
+	}
+	return nil
+}
+
+// Keys returns the sorted keys of a locale's catalog
+func Keys(locale Locale) []string {
+	keys := make([]string, 0, len(catalogs[locale]))
+	for key := range catalogs[locale] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Localizer translates messages into one locale
+type Localizer struct {
+	locale Locale
+}
+
+// For returns the localizer of a locale
+func For(locale Locale) Localizer {
+	return Localizer{locale: locale}
+}
+
+// Locale returns the locale messages are translated into
+func (l Localizer) Locale() Locale {
+	if l.locale == "" {
+		return Default
+	}
+	return l.locale
+}
+
+// Lookup returns the message of a key, falling back to the default locale
+func (l Localizer) Lookup(key string) (string, bool) {
+	if message, exists := catalogs[l.Locale()][key]; exists {
+		return message, true
+	}
+	message, exists := catalogs[Default][key]
+	return message, exists
+}
+
+// T returns the message of a key formatted with args as by fmt.Sprintf, so catalog
+// messages write a literal percent sign as %%.
+// A key missing from every catalog is returned as is.
+func (l Localizer) T(key string, args ...any) string {
+	message, exists := l.Lookup(key)
+	if !exists {
+		return key
+	}
+	return fmt.Sprintf(message, args...)
+}
@@ -0,0 +1,220 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCatalogs_SameKeys(t *testing.T) {
+	reference := Keys(Default)
+	if len(reference) == 0 {
+		t.Fatal("default catalog is empty")
+	}
+
+	for _, locale := range Locales {
+		keys := Keys(locale)
+		for _, key := range reference {
+			if _, found := slices.BinarySearch(keys, key); !found {
+				t.Errorf("%s catalog is missing %s", locale, key)
+			}
+		}
+		for _, key := range keys {
+			if _, found := slices.BinarySearch(reference, key); !found {
+				t.Errorf("%s catalog has %s, which the %s catalog lacks", locale, key, Default)
+			}
+		}
+	}
+}
+
+// verbPattern matches fmt verbs, including the escaped percent sign
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+
+func TestCatalogs_SameVerbs(t *testing.T) {
+	verbs := func(message string) []string {
+		var found []string
+		for _, verb := range verbPattern.FindAllString(message, -1) {
+			if verb != "%%" {
+				found = append(found, verb)
+			}
+		}
+		return found
+	}
+
+	for _, locale := range Locales {
+		for _, key := range Keys(Default) {
+			message, exists := catalogs[locale][key]
+			if !exists {
+				continue // Reported by TestCatalogs_SameKeys
+			}
+			want := verbs(catalogs[Default][key])
+			if got := verbs(message); !slices.Equal(got, want) {
+				t.Errorf("%s %s has verbs %v, want %v", locale, key, got, want)
+			}
+		}
+	}
+}
+
+// keyPattern matches the literal keys passed to Localizer.T and Localizer.Lookup
+var keyPattern = regexp.MustCompile(`\.(?:T|Lookup)\("([a-z0-9_.]+)"`)
+
+func TestCatalogs_CoverCode(t *testing.T) {
+	err := filepath.WalkDir("..", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range keyPattern.FindAllStringSubmatch(string(source), -1) {
+			key := match[1]
+			// Prefixes completed at run time, e.g. "schedule.kinds." + kind
+			if strings.HasSuffix(key, ".") {
+				continue
+			}
+			if _, exists := catalogs[Default][key]; !exists {
+				t.Errorf("%s uses %s, which is not in the catalogs", path, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir() unexpected error: %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   Locale
+		wantOK bool
+	}{
+		{"ja", Japanese, true},
+		{"en-US", English, true},
+		{"en-GB", English, true},
+		{" JA ", Japanese, true},
+		{"fr", "fr", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, ok := Parse(tt.tag)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Parse(%q) = %q, %v, want %q, %v", tt.tag, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLocalizer_T(t *testing.T) {
+	if got := For(Japanese).T("data.exported", 2); got != "📤 2 件のファイルを書き出しました。" {
+		t.Errorf("ja T() = %q", got)
+	}
+	if got := For("").T("data.exported", 2); got != "📤 Exported 2 file(s)." {
+		t.Errorf("default T() = %q", got)
+	}
+	if got := For(Japanese).T("no.such.key"); got != "no.such.key" {
+		t.Errorf("T() of a missing key = %q, want the key", got)
+	}
+}
+
+// stubGuilds returns fixed guild locales
+type stubGuilds map[string]Locale
+
+func (g stubGuilds) GuildLocale(_ context.Context, guildID string) (Locale, error) {
+	if guildID == "broken" {
+		return "", errors.New("storage down")
+	}
+	return g[guildID], nil
+}
+
+func TestResolver_Interaction(t *testing.T) {
+	japanese := discordgo.Japanese
+	resolver := NewResolver(stubGuilds{"ja-guild": Japanese})
+
+	tests := []struct {
+		name        string
+		userLocale  discordgo.Locale
+		guildID     string
+		guildLocale *discordgo.Locale
+		want        Locale
+	}{
+		{"user language wins", discordgo.EnglishUS, "ja-guild", nil, English},
+		{"guild setting", discordgo.French, "ja-guild", nil, Japanese},
+		{"guild Discord language", "", "other", &japanese, Japanese},
+		{"storage error falls through", "", "broken", &japanese, Japanese},
+		{"default", discordgo.French, "other", nil, Default},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+				Locale:      tt.userLocale,
+				GuildID:     tt.guildID,
+				GuildLocale: tt.guildLocale,
+			}}
+			if got := resolver.Interaction(i).Locale(); got != tt.want {
+				t.Errorf("Interaction() locale = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := resolver.Guild(context.Background(), "ja-guild").Locale(); got != Japanese {
+		t.Errorf("Guild() locale = %q, want %q", got, Japanese)
+	}
+	var unset *Resolver
+	if got := unset.Guild(context.Background(), "ja-guild").Locale(); got != Default {
+		t.Errorf("nil Guild() locale = %q, want %q", got, Default)
+	}
+}
+
+func TestLocalizeCommand(t *testing.T) {
+	command := &discordgo.ApplicationCommand{
+		Name:        "language",
+		Description: "Shows or changes the language of the bot's messages (Admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type: discordgo.ApplicationCommandOptionString,
+				Name: "locale",
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "auto", Value: "auto"},
+					{Name: "Auto", Value: "auto"},
+				},
+			},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "missing"},
+		},
+	}
+
+	missing := LocalizeCommand(command)
+	want := []string{"commands.language.options.missing.description", "commands.language.options.missing.name"}
+	if !slices.Equal(missing, want) {
+		t.Errorf("LocalizeCommand() missing = %v, want %v", missing, want)
+	}
+
+	if got := (*command.NameLocalizations)[discordgo.Japanese]; got != "言語" {
+		t.Errorf("ja name = %q", got)
+	}
+	if got := (*command.NameLocalizations)[discordgo.EnglishGB]; got != "language" {
+		t.Errorf("en-GB name = %q", got)
+	}
+	choices := command.Options[0].Choices
+	if choices[0].NameLocalizations != nil {
+		t.Errorf("choice named after its value was localized: %v", choices[0].NameLocalizations)
+	}
+	if got := choices[1].NameLocalizations[discordgo.Japanese]; got == "" {
+		t.Error("ja choice name is empty")
+	}
+}
@@ -0,0 +1,66 @@
+package i18n
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// GuildLocales looks up the locale a guild has chosen, or "" if it has not chosen one
+type GuildLocales interface {
+	GuildLocale(ctx context.Context, guildID string) (Locale, error)
+}
+
+// Resolver picks the locale of a response from the user's Discord language and
+// the guild's settings
+type Resolver struct {
+	guilds GuildLocales
+}
+
+// NewResolver creates a new locale resolver. guilds may be nil, in which case
+// only the Discord languages are considered.
+func NewResolver(guilds GuildLocales) *Resolver {
+	return &Resolver{guilds: guilds}
+}
+
+// Guild returns the localizer of a guild: the locale chosen in its settings, or the default
+func (r *Resolver) Guild(ctx context.Context, guildID string) Localizer {
+	if locale, ok := r.guildSetting(ctx, guildID); ok {
+		return For(locale)
+	}
+	return For(Default)
+}
+
+// Interaction returns the localizer of an interaction. The user's Discord language
+// wins, then the locale chosen in the guild's settings, then the guild's Discord language.
+func (r *Resolver) Interaction(i *discordgo.InteractionCreate) Localizer {
+	if locale, ok := Parse(string(i.Locale)); ok {
+		return For(locale)
+	}
+	if locale, ok := r.guildSetting(context.Background(), i.GuildID); ok {
+		return For(locale)
+	}
+	if i.GuildLocale != nil {
+		if locale, ok := Parse(string(*i.GuildLocale)); ok {
+			return For(locale)
+		}
+	}
+	return For(Default)
+}
+
+// Message returns the localizer of a prefix command, which carries no user language
+func (r *Resolver) Message(m *discordgo.MessageCreate) Localizer {
+	return r.Guild(context.Background(), m.GuildID)
+}
+
+// guildSetting returns the supported locale chosen in a guild's settings
+func (r *Resolver) guildSetting(ctx context.Context, guildID string) (Locale, bool) {
+	if r == nil || r.guilds == nil || guildID == "" {
+		return "", false
+	}
+	locale, err := r.guilds.GuildLocale(ctx, guildID)
+	if err != nil || locale == "" {
+		return "", false
+	}
+	return Parse(string(locale))
+}
@@ -0,0 +1,128 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+// inventoriesCollection is the storage collection holding each user's owned materials
+const inventoriesCollection = "material_inventories"
+
+const (
+	// MaxCount limits the owned count of a material
+	MaxCount = 99999
+	// maxItems limits the different materials in an inventory
+	maxItems = 200
+)
+
+// Inventory is the materials a user owns, counted by material ID. Inventories
+// belong to users rather than guilds, so they are shared across servers.
+type Inventory struct {
+	UserID    string         `json:"user_id"`
+	Items     map[string]int `json:"items,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Manager records owned materials per user, caching inventories loaded from storage
+type Manager struct {
+	store storage.Store
+
+	// writeMu serializes changes so that concurrent updates are not lost
+	writeMu sync.Mutex
+
+	mu    sync.RWMutex
+	users map[string]*Inventory
+}
+
+// NewManager creates a new inventory manager
+func NewManager(store storage.Store) *Manager {
+	return &Manager{
+		store: store,
+		users: make(map[string]*Inventory),
+	}
+}
+
+// load returns the cached inventory of a user, loading it from storage on first use.
+// The returned value must not be modified.
+func (m *Manager) load(ctx context.Context, userID string) (*Inventory, error) {
+	m.mu.RLock()
+	inventory, cached := m.users[userID]
+	m.mu.RUnlock()
+	if cached {
+		return inventory, nil
+	}
+
+	inventory = &Inventory{UserID: userID}
+	err := m.store.Get(ctx, inventoriesCollection, userID, inventory)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load inventory of user %s: %w", userID, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[userID] = inventory
+	return inventory, nil
+}
+
+// Get returns a copy of a user's inventory, empty when nothing was recorded
+func (m *Manager) Get(ctx context.Context, userID string) (Inventory, error) {
+	inventory, err := m.load(ctx, userID)
+	if err != nil {
+		return Inventory{}, err
+	}
+	copied := *inventory
+	copied.Items = maps.Clone(inventory.Items)
+	return copied, nil
+}
+
+// Set records how many of a material a user owns and returns the updated
+// inventory. A count of 0 removes the material.
+func (m *Manager) Set(ctx context.Context, userID, materialID string, count int) (Inventory, error) {
+	if count < 0 || count > MaxCount {
+		return Inventory{}, fmt.Errorf("count must be between 0 and %d", MaxCount)
+	}
+
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	current, err := m.load(ctx, userID)
+	if err != nil {
+		return Inventory{}, err
+	}
+	next := &Inventory{UserID: userID, Items: maps.Clone(current.Items), UpdatedAt: time.Now()}
+	if count == 0 {
+		delete(next.Items, materialID)
+	} else {
+		if next.Items == nil {
+			next.Items = make(map[string]int)
+		}
+		if _, exists := next.Items[materialID]; !exists && len(next.Items) >= maxItems {
+			return Inventory{}, fmt.Errorf("up to %d different materials can be recorded", maxItems)
+		}
+		next.Items[materialID] = count
+	}
+
+	if err := m.store.Put(ctx, inventoriesCollection, userID, next); err != nil {
+		return Inventory{}, fmt.Errorf("failed to save inventory of user %s: %w", userID, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[userID] = next
+	copied := *next
+	copied.Items = maps.Clone(next.Items)
+	return copied, nil
+}
+
+// Reload drops the cached inventories so that they are read from storage again
+func (m *Manager) Reload() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users = make(map[string]*Inventory)
+}
@@ -0,0 +1,50 @@
+package inventory
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+func TestManager_Set(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	m := NewManager(store)
+
+	if _, err := m.Set(ctx, "alice", "ultima_unit", 30); err != nil {
+		t.Fatalf("Set() unexpected error: %v", err)
+	}
+	if _, err := m.Set(ctx, "alice", "hihiirokane", 2); err != nil {
+		t.Fatalf("Set() unexpected error: %v", err)
+	}
+	inventory, err := m.Set(ctx, "alice", "ultima_unit", 0)
+	if err != nil {
+		t.Fatalf("Set() to 0 unexpected error: %v", err)
+	}
+	if want := map[string]int{"hihiirokane": 2}; !maps.Equal(inventory.Items, want) {
+		t.Errorf("Set() items = %v, expected %v", inventory.Items, want)
+	}
+
+	// The returned inventory is a copy
+	inventory.Items["hihiirokane"] = 99
+
+	// Inventories are persisted per user
+	reloaded, err := NewManager(store).Get(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if reloaded.Items["hihiirokane"] != 2 {
+		t.Errorf("Get() items = %v, expected 2 hihiirokane", reloaded.Items)
+	}
+	if other, _ := m.Get(ctx, "bob"); len(other.Items) != 0 {
+		t.Errorf("Get(bob) items = %v, expected none", other.Items)
+	}
+
+	for _, count := range []int{-1, MaxCount + 1} {
+		if _, err := m.Set(ctx, "alice", "hihiirokane", count); err == nil {
+			t.Errorf("Set(%d) expected error", count)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+// ErrDrainTimeout is returned when in-flight work does not finish in time
+var ErrDrainTimeout = errors.New("timed out waiting for in-flight work")
+
+// shutdownHook is a named cleanup function run after in-flight work drains
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Manager tracks running event handlers and background workers so that
+// shutdown can stop new work, wait for in-flight work and then release resources
+type Manager struct {
+	logger *log.Logger
+
+	mu       sync.Mutex
+	closing  bool
+	inFlight sync.WaitGroup
+	hooks    []shutdownHook
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New creates a new lifecycle manager
+func New(logger *log.Logger) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Context returns a context that is cancelled when shutdown begins.
+// Background workers should stop when it is done.
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// IsClosing returns true once shutdown has begun
+func (m *Manager) IsClosing() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closing
+}
+
+// Track registers a unit of in-flight work such as an event handler.
+// It returns false once shutdown has begun, in which case the work must not start.
+// Otherwise the returned function must be called when the work is done.
+func (m *Manager) Track() (done func(), ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closing {
+		return nil, false
+	}
+
+	m.inFlight.Add(1)
+	return m.inFlight.Done, true
+}
+
+// Go runs a background worker until its context is cancelled.
+// Shutdown waits for the worker to return.
+func (m *Manager) Go(name string, fn func(ctx context.Context)) {
+	done, ok := m.Track()
+	if !ok {
+		m.logger.Warn("Worker not started during shutdown", "worker", name)
+		return
+	}
+
+	go func() {
+		defer done()
+		defer func() {
+			if r := recover(); r != nil {
+				m.logger.Error("Worker panicked", "worker", name, "panic", fmt.Sprint(r))
+			}
+		}()
+
+		m.logger.Debug("Worker started", "worker", name)
+		fn(m.ctx)
+		m.logger.Debug("Worker stopped", "worker", name)
+	}()
+}
+
+// OnShutdown registers a hook to run after in-flight work drains.
+// Hooks run in reverse registration order, like deferred calls.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, shutdownHook{name: name, fn: fn})
+}
+
+// Shutdown stops accepting new work, cancels background workers, waits up to
+// timeout for in-flight work to finish and then runs the shutdown hooks.
+// Hooks always run, even when draining timed out.
+func (m *Manager) Shutdown(timeout time.Duration) error {
+	m.mu.Lock()
+	if m.closing {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closing = true
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	m.logger.Info("Shutting down, waiting for in-flight work", "timeout", timeout.String())
+	m.cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+
+	drained := make(chan struct{})
+	go func() {
+		m.inFlight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		m.logger.Info("In-flight work drained")
+	case <-ctx.Done():
+		m.logger.Warn("In-flight work did not finish before timeout")
+		errs = append(errs, ErrDrainTimeout)
+		// Give hooks a fresh, short deadline so resources are still released
+		ctx, cancel = context.WithTimeout(context.Background(), timeout/2)
+		defer cancel()
+	}
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if err := hook.fn(ctx); err != nil {
+			m.logger.WithError(err).Error("Shutdown hook failed", "hook", hook.name)
+			errs = append(errs, fmt.Errorf("%s: %w", hook.name, err))
+			continue
+		}
+		m.logger.Debug("Shutdown hook completed", "hook", hook.name)
+	}
+
+	return errors.Join(errs...)
+}
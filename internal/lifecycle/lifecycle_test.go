@@ -0,0 +1,123 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+func newTestManager() *Manager {
+	return New(log.InitLogger("error"))
+}
+
+func TestManager_Track_RefusedAfterShutdown(t *testing.T) {
+	m := newTestManager()
+
+	if err := m.Shutdown(time.Second); err != nil {
+		t.Fatalf("Shutdown() unexpected error: %v", err)
+	}
+
+	if _, ok := m.Track(); ok {
+		t.Error("Track() after shutdown = true, expected false")
+	}
+	if !m.IsClosing() {
+		t.Error("IsClosing() after shutdown = false, expected true")
+	}
+}
+
+func TestManager_Shutdown_DrainsInFlightWork(t *testing.T) {
+	m := newTestManager()
+
+	done, ok := m.Track()
+	if !ok {
+		t.Fatal("Track() = false, expected true")
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(finished)
+		done()
+	}()
+
+	workerStopped := make(chan struct{})
+	m.Go("test worker", func(ctx context.Context) {
+		<-ctx.Done()
+		close(workerStopped)
+	})
+
+	var hookSawFinished bool
+	m.OnShutdown("check", func(ctx context.Context) error {
+		select {
+		case <-finished:
+			hookSawFinished = true
+		default:
+		}
+		return nil
+	})
+
+	if err := m.Shutdown(time.Second); err != nil {
+		t.Fatalf("Shutdown() unexpected error: %v", err)
+	}
+	if !hookSawFinished {
+		t.Error("shutdown hook ran before in-flight work finished")
+	}
+
+	select {
+	case <-workerStopped:
+	default:
+		t.Error("background worker did not stop before Shutdown returned")
+	}
+}
+
+func TestManager_Shutdown_Timeout(t *testing.T) {
+	m := newTestManager()
+
+	if _, ok := m.Track(); !ok {
+		t.Fatal("Track() = false, expected true")
+	}
+
+	hookRan := false
+	m.OnShutdown("storage", func(ctx context.Context) error {
+		hookRan = true
+		return nil
+	})
+
+	err := m.Shutdown(20 * time.Millisecond)
+	if !errors.Is(err, ErrDrainTimeout) {
+		t.Errorf("Shutdown() error = %v, expected ErrDrainTimeout", err)
+	}
+	if !hookRan {
+		t.Error("shutdown hook did not run after drain timeout")
+	}
+}
+
+func TestManager_Shutdown_HookOrder(t *testing.T) {
+	m := newTestManager()
+
+	var order []string
+	hookErr := errors.New("flush failed")
+	for _, name := range []string{"logs", "storage", "discord session"} {
+		m.OnShutdown(name, func(ctx context.Context) error {
+			order = append(order, name)
+			if name == "storage" {
+				return hookErr
+			}
+			return nil
+		})
+	}
+
+	err := m.Shutdown(time.Second)
+	if !errors.Is(err, hookErr) {
+		t.Errorf("Shutdown() error = %v, expected hook error", err)
+	}
+
+	expected := []string{"discord session", "storage", "logs"}
+	if !slices.Equal(order, expected) {
+		t.Errorf("hook order = %v, expected %v", order, expected)
+	}
+}
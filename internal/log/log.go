@@ -1,9 +1,11 @@
 package log
 
 import (
+	"errors"
 	"log/slog"
 	"os"
 	"strings"
+	"syscall"
 )
 
 // Logger wraps slog.Logger with additional context methods
@@ -11,26 +13,16 @@ type Logger struct {
 	*slog.Logger
 }
 
-// InitLogger initializes the global logger with the specified level
-func InitLogger(level string) *Logger {
-	var logLevel slog.Level
+// level is shared by all loggers so that it can be changed at runtime
+var level = new(slog.LevelVar)
 
-	switch strings.ToLower(level) {
-	case "debug":
-		logLevel = slog.LevelDebug
-	case "info":
-		logLevel = slog.LevelInfo
-	case "warn":
-		logLevel = slog.LevelWarn
-	case "error":
-		logLevel = slog.LevelError
-	default:
-		logLevel = slog.LevelInfo
-	}
+// InitLogger initializes the global logger with the specified level
+func InitLogger(logLevel string) *Logger {
+	SetLevel(logLevel)
 
 	// Create structured logger with JSON output
 	opts := &slog.HandlerOptions{
-		Level: logLevel,
+		Level: level,
 	}
 
 	handler := slog.NewJSONHandler(os.Stdout, opts)
@@ -42,6 +34,22 @@ func InitLogger(level string) *Logger {
 	return &Logger{Logger: logger}
 }
 
+// SetLevel changes the level of all loggers created by InitLogger
+func SetLevel(logLevel string) {
+	switch strings.ToLower(logLevel) {
+	case "debug":
+		level.Set(slog.LevelDebug)
+	case "info":
+		level.Set(slog.LevelInfo)
+	case "warn":
+		level.Set(slog.LevelWarn)
+	case "error":
+		level.Set(slog.LevelError)
+	default:
+		level.Set(slog.LevelInfo)
+	}
+}
+
 // WithGuild returns a logger with guild_id context
 func (l *Logger) WithGuild(guildID string) *Logger {
 	return &Logger{Logger: l.Logger.With("guild_id", guildID)}
@@ -78,6 +86,16 @@ func (l *Logger) WithError(err error) *Logger {
 	return &Logger{Logger: l.Logger.With("error", err.Error())}
 }
 
+// Sync flushes buffered log output.
+// Writers that cannot be synced, such as pipes and terminals, are ignored.
+func Sync() error {
+	err := os.Stdout.Sync()
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) {
+		return nil
+	}
+	return err
+}
+
 // Global logger instance
 var globalLogger *Logger
 
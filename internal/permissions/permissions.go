@@ -0,0 +1,455 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+// DefaultControlRoleName is the role that grants access to admin commands
+const DefaultControlRoleName = "gbf_bot_control" // Matches Python bot
+
+// aclCollection is the storage collection holding guild ACLs
+const aclCollection = "permissions"
+
+// Category groups commands so that access can be granted per category
+type Category string
+
+const (
+	CategoryGeneral Category = "General"
+	CategoryGBF     Category = "GBF"
+	CategoryAdmin   Category = "Admin"
+)
+
+// CommandSpec describes a command known to the permission system
+type CommandSpec struct {
+	Name     string
+	Category Category
+	// DefaultMemberPermissions mirrors the slash command definition.
+	// nil means everyone, 0 means administrators only.
+	DefaultMemberPermissions *int64
+}
+
+// Rule lists the roles and users allowed to use a command or category
+type Rule struct {
+	RoleIDs []string `json:"role_ids,omitempty"`
+	UserIDs []string `json:"user_ids,omitempty"`
+}
+
+// IsEmpty returns true if the rule grants access to nobody
+func (r *Rule) IsEmpty() bool {
+	return len(r.RoleIDs) == 0 && len(r.UserIDs) == 0
+}
+
+// matches returns true if the subject is listed in the rule
+func (r *Rule) matches(subject Subject) bool {
+	if slices.Contains(r.UserIDs, subject.UserID) {
+		return true
+	}
+	for _, roleID := range subject.RoleIDs {
+		if slices.Contains(r.RoleIDs, roleID) {
+			return true
+		}
+	}
+	return false
+}
+
+// GuildACL holds the access rules configured for a guild
+type GuildACL struct {
+	GuildID    string             `json:"guild_id"`
+	Commands   map[string]*Rule   `json:"commands,omitempty"`
+	Categories map[Category]*Rule `json:"categories,omitempty"`
+}
+
+// Subject is the member whose access is being checked
+type Subject struct {
+	GuildID string
+	UserID  string
+	RoleIDs []string
+	// Permissions are the member's computed guild permissions
+	Permissions int64
+}
+
+// IsAdministrator returns true if the subject has the Administrator permission
+func (s Subject) IsAdministrator() bool {
+	return s.Permissions&discordgo.PermissionAdministrator != 0
+}
+
+// Denial identifies why a permission check failed
+type Denial string
+
+const (
+	// DenialDiscordPermissions means the member lacks the command's default member permissions
+	DenialDiscordPermissions Denial = "discord_permissions"
+	// DenialControlRole means the member lacks the control role required by admin commands
+	DenialControlRole Denial = "control_role"
+	// DenialRestricted means a guild rule does not include the member
+	DenialRestricted Denial = "restricted"
+	// DenialUnavailable means the guild rules could not be loaded
+	DenialUnavailable Denial = "unavailable"
+)
+
+// Decision is the result of a permission check
+type Decision struct {
+	Allowed bool
+	// Denial identifies the reason of a denial so that it can be translated
+	Denial Denial
+	// Reason explains a denial to the user
+	Reason string
+	// Role is the name of the missing role for DenialControlRole
+	Role string
+	// Err is the error that caused DenialUnavailable
+	Err error
+}
+
+// Manager resolves command access using per-guild ACLs and Discord permissions
+type Manager struct {
+	mu              sync.RWMutex
+	store           storage.Store
+	specs           map[string]CommandSpec
+	acls            map[string]*GuildACL
+	controlRoleName string
+	// generation counts the ACL sets swapped in by Apply
+	generation int
+}
+
+// NewManager creates a new permission manager
+func NewManager(store storage.Store) *Manager {
+	return &Manager{
+		store:           store,
+		specs:           make(map[string]CommandSpec),
+		acls:            make(map[string]*GuildACL),
+		controlRoleName: DefaultControlRoleName,
+	}
+}
+
+// SetControlRoleName sets the role name that grants access to admin commands
+func (m *Manager) SetControlRoleName(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.controlRoleName = name
+}
+
+// ControlRoleName returns the role name that grants access to admin commands
+func (m *Manager) ControlRoleName() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.controlRoleName
+}
+
+// Register adds command specs to the manager
+func (m *Manager) Register(specs ...CommandSpec) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, spec := range specs {
+		m.specs[spec.Name] = spec
+	}
+}
+
+// RegisterCommand registers a slash command definition under a category
+func (m *Manager) RegisterCommand(def *discordgo.ApplicationCommand, category Category) {
+	m.Register(CommandSpec{
+		Name:                     def.Name,
+		Category:                 category,
+		DefaultMemberPermissions: def.DefaultMemberPermissions,
+	})
+}
+
+// Spec returns the spec of a registered command
+func (m *Manager) Spec(name string) (CommandSpec, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	spec, exists := m.specs[name]
+	return spec, exists
+}
+
+// Check decides whether the subject may run the command.
+// Administrators are always allowed. Otherwise a command rule takes precedence
+// over a category rule, and without any rule the command's default member
+// permissions and the control role (for admin commands) apply. Commands are
+// denied when the guild rules cannot be loaded.
+func (m *Manager) Check(ctx context.Context, state GuildState, subject Subject, command string) Decision {
+	if subject.IsAdministrator() {
+		return Decision{Allowed: true}
+	}
+
+	spec, exists := m.Spec(command)
+	if !exists {
+		spec = CommandSpec{Name: command, Category: CategoryGeneral}
+	}
+
+	if subject.GuildID != "" {
+		acl, err := m.ACL(ctx, subject.GuildID)
+		if err != nil {
+			return Decision{Denial: DenialUnavailable, Reason: "Permissions could not be checked. Please try again later.", Err: err}
+		}
+		if rule, ok := acl.Commands[spec.Name]; ok && !rule.IsEmpty() {
+			return ruleDecision(rule, subject)
+		}
+		if rule, ok := acl.Categories[spec.Category]; ok && !rule.IsEmpty() {
+			return ruleDecision(rule, subject)
+		}
+	}
+
+	if required := spec.DefaultMemberPermissions; required != nil {
+		if *required != 0 && subject.Permissions&*required == *required {
+			return Decision{Allowed: true}
+		}
+		return Decision{Denial: DenialDiscordPermissions, Reason: "This command requires additional server permissions."}
+	}
+
+	if spec.Category == CategoryAdmin {
+		controlRoleName := m.ControlRoleName()
+		if hasRoleNamed(state, subject, controlRoleName) {
+			return Decision{Allowed: true}
+		}
+		return Decision{Denial: DenialControlRole, Reason: "Required role: `" + controlRoleName + "`", Role: controlRoleName}
+	}
+
+	return Decision{Allowed: true}
+}
+
+// ruleDecision converts a rule match into a decision
+func ruleDecision(rule *Rule, subject Subject) Decision {
+	if rule.matches(subject) {
+		return Decision{Allowed: true}
+	}
+	return Decision{Denial: DenialRestricted, Reason: "Access to this command is restricted on this server."}
+}
+
+// hasRoleNamed checks the subject's roles against the state cache by name
+func hasRoleNamed(state GuildState, subject Subject, name string) bool {
+	if state == nil || subject.GuildID == "" {
+		return false
+	}
+	for _, roleID := range subject.RoleIDs {
+		role, err := state.Role(subject.GuildID, roleID)
+		if err == nil && role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ACL returns the access rules of a guild, loading them from storage on first use
+func (m *Manager) ACL(ctx context.Context, guildID string) (*GuildACL, error) {
+	m.mu.RLock()
+	acl, cached := m.acls[guildID]
+	generation := m.generation
+	m.mu.RUnlock()
+	if cached {
+		return acl, nil
+	}
+
+	acl = &GuildACL{GuildID: guildID}
+	err := m.store.Get(ctx, aclCollection, guildID, acl)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load permissions for guild %s: %w", guildID, err)
+	}
+
+	// Storage was read without the lock. An update or Apply meanwhile is newer
+	// than what was read, so keep theirs.
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current, cached := m.acls[guildID]; cached {
+		return current, nil
+	}
+	if m.generation == generation {
+		m.acls[guildID] = acl
+	}
+	return acl, nil
+}
+
+// Snapshot holds guild ACLs read by Load until they are applied
+type Snapshot struct {
+	acls map[string]*GuildACL
+}
+
+// Reload re-reads every guild ACL from storage and swaps them in at once.
+// It returns the IDs of guilds whose rules changed.
+func (m *Manager) Reload(ctx context.Context) ([]string, error) {
+	snapshot, err := m.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.Apply(snapshot), nil
+}
+
+// Load reads every guild ACL from storage without touching the cached rules
+func (m *Manager) Load(ctx context.Context) (*Snapshot, error) {
+	guildIDs, err := m.store.Keys(ctx, aclCollection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list guild permissions: %w", err)
+	}
+
+	next := make(map[string]*GuildACL, len(guildIDs))
+	for _, guildID := range guildIDs {
+		acl := &GuildACL{GuildID: guildID}
+		if err := m.store.Get(ctx, aclCollection, guildID, acl); err != nil {
+			return nil, fmt.Errorf("failed to load permissions for guild %s: %w", guildID, err)
+		}
+		next[guildID] = acl
+	}
+	return &Snapshot{acls: next}, nil
+}
+
+// Apply swaps in the rules of a snapshot and returns the IDs of guilds whose
+// rules changed
+func (m *Manager) Apply(snapshot *Snapshot) []string {
+	next := snapshot.acls
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var changed []string
+	for guildID, acl := range next {
+		if old, cached := m.acls[guildID]; !cached || !reflect.DeepEqual(old.clone(), acl.clone()) {
+			changed = append(changed, guildID)
+		}
+	}
+	for guildID, old := range m.acls {
+		if _, exists := next[guildID]; !exists && len(old.Targets()) > 0 {
+			changed = append(changed, guildID)
+		}
+	}
+	sort.Strings(changed)
+
+	m.acls = next
+	m.generation++
+	return changed
+}
+
+// ResolveTarget validates a command or category name and reports which one it is
+func (m *Manager) ResolveTarget(target string) (name string, isCategory bool, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, exists := m.specs[strings.ToLower(target)]; exists {
+		return strings.ToLower(target), false, nil
+	}
+	for _, category := range []Category{CategoryGeneral, CategoryGBF, CategoryAdmin} {
+		if strings.EqualFold(string(category), target) {
+			return string(category), true, nil
+		}
+	}
+	return "", false, fmt.Errorf("unknown command or category: %s", target)
+}
+
+// Grant allows a role or user to use a command or category
+func (m *Manager) Grant(ctx context.Context, guildID, target, roleID, userID string) error {
+	return m.update(ctx, guildID, target, func(rule *Rule) {
+		if roleID != "" && !slices.Contains(rule.RoleIDs, roleID) {
+			rule.RoleIDs = append(rule.RoleIDs, roleID)
+		}
+		if userID != "" && !slices.Contains(rule.UserIDs, userID) {
+			rule.UserIDs = append(rule.UserIDs, userID)
+		}
+	})
+}
+
+// Revoke removes a role or user from a command or category rule
+func (m *Manager) Revoke(ctx context.Context, guildID, target, roleID, userID string) error {
+	return m.update(ctx, guildID, target, func(rule *Rule) {
+		rule.RoleIDs = slices.DeleteFunc(rule.RoleIDs, func(id string) bool { return id == roleID })
+		rule.UserIDs = slices.DeleteFunc(rule.UserIDs, func(id string) bool { return id == userID })
+	})
+}
+
+// Reset removes the rule for a command or category, restoring default access
+func (m *Manager) Reset(ctx context.Context, guildID, target string) error {
+	return m.update(ctx, guildID, target, func(rule *Rule) {
+		rule.RoleIDs = nil
+		rule.UserIDs = nil
+	})
+}
+
+// update applies fn to the rule for target and persists the guild ACL
+func (m *Manager) update(ctx context.Context, guildID, target string, fn func(rule *Rule)) error {
+	name, isCategory, err := m.ResolveTarget(target)
+	if err != nil {
+		return err
+	}
+
+	current, err := m.ACL(ctx, guildID)
+	if err != nil {
+		return err
+	}
+
+	// Work on a copy so that readers never observe a partially updated ACL
+	acl := current.clone()
+	if isCategory {
+		rule := acl.Categories[Category(name)]
+		if rule == nil {
+			rule = &Rule{}
+		}
+		fn(rule)
+		acl.Categories[Category(name)] = rule
+		if rule.IsEmpty() {
+			delete(acl.Categories, Category(name))
+		}
+	} else {
+		rule := acl.Commands[name]
+		if rule == nil {
+			rule = &Rule{}
+		}
+		fn(rule)
+		acl.Commands[name] = rule
+		if rule.IsEmpty() {
+			delete(acl.Commands, name)
+		}
+	}
+
+	if err := m.store.Put(ctx, aclCollection, guildID, acl); err != nil {
+		return fmt.Errorf("failed to save permissions for guild %s: %w", guildID, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.acls[guildID] = acl
+	return nil
+}
+
+// clone returns a deep copy of the ACL
+func (a *GuildACL) clone() *GuildACL {
+	c := &GuildACL{
+		GuildID:    a.GuildID,
+		Commands:   make(map[string]*Rule, len(a.Commands)),
+		Categories: make(map[Category]*Rule, len(a.Categories)),
+	}
+	for name, rule := range a.Commands {
+		c.Commands[name] = &Rule{RoleIDs: slices.Clone(rule.RoleIDs), UserIDs: slices.Clone(rule.UserIDs)}
+	}
+	for category, rule := range a.Categories {
+		c.Categories[category] = &Rule{RoleIDs: slices.Clone(rule.RoleIDs), UserIDs: slices.Clone(rule.UserIDs)}
+	}
+	return c
+}
+
+// Targets returns the configured rule targets of an ACL in display order
+func (a *GuildACL) Targets() []string {
+	var targets []string
+	for category := range a.Categories {
+		targets = append(targets, string(category))
+	}
+	for name := range a.Commands {
+		targets = append(targets, name)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// Rule returns the rule configured for a command or category name
+func (a *GuildACL) Rule(target string) *Rule {
+	if rule, ok := a.Categories[Category(target)]; ok {
+		return rule
+	}
+	return a.Commands[target]
+}
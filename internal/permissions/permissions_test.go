@@ -0,0 +1,289 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+const testGuildID = "guild1"
+
+func newTestState(t *testing.T) *discordgo.State {
+	t.Helper()
+
+	state := discordgo.NewState()
+	err := state.GuildAdd(&discordgo.Guild{
+		ID:      testGuildID,
+		OwnerID: "owner",
+		Roles: []*discordgo.Role{
+			{ID: testGuildID, Name: "@everyone"},
+			{ID: "role_control", Name: DefaultControlRoleName},
+			{ID: "role_member", Name: "member"},
+			{ID: "role_admin", Name: "admin", Permissions: discordgo.PermissionAdministrator},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to seed state: %v", err)
+	}
+	return state
+}
+
+func newTestManager() *Manager {
+	manageGuild := int64(discordgo.PermissionManageGuild)
+
+	m := NewManager(storage.NewMemoryStore())
+	m.Register(
+		CommandSpec{Name: "ping", Category: CategoryGeneral},
+		CommandSpec{Name: "battles", Category: CategoryGBF},
+		CommandSpec{Name: "reload", Category: CategoryAdmin},
+		CommandSpec{Name: "permissions", Category: CategoryAdmin, DefaultMemberPermissions: &manageGuild},
+	)
+	return m
+}
+
+func TestManager_Check_Defaults(t *testing.T) {
+	state := newTestState(t)
+	m := newTestManager()
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		subject  Subject
+		command  string
+		expected bool
+	}{
+		{
+			name:     "general command allowed for everyone",
+			subject:  Subject{GuildID: testGuildID, UserID: "u1"},
+			command:  "ping",
+			expected: true,
+		},
+		{
+			name:     "admin command denied without control role",
+			subject:  Subject{GuildID: testGuildID, UserID: "u1", RoleIDs: []string{"role_member"}},
+			command:  "reload",
+			expected: false,
+		},
+		{
+			name:     "admin command allowed with control role",
+			subject:  Subject{GuildID: testGuildID, UserID: "u1", RoleIDs: []string{"role_control"}},
+			command:  "reload",
+			expected: true,
+		},
+		{
+			name:     "administrator always allowed",
+			subject:  Subject{GuildID: testGuildID, UserID: "u1", Permissions: discordgo.PermissionAdministrator},
+			command:  "reload",
+			expected: true,
+		},
+		{
+			name:     "default member permissions required",
+			subject:  Subject{GuildID: testGuildID, UserID: "u1", RoleIDs: []string{"role_control"}},
+			command:  "permissions",
+			expected: false,
+		},
+		{
+			name:     "default member permissions satisfied",
+			subject:  Subject{GuildID: testGuildID, UserID: "u1", Permissions: discordgo.PermissionManageGuild},
+			command:  "permissions",
+			expected: true,
+		},
+		{
+			name:     "admin command denied in DM",
+			subject:  Subject{UserID: "u1"},
+			command:  "reload",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := m.Check(ctx, state, tt.subject, tt.command)
+			if decision.Allowed != tt.expected {
+				t.Errorf("Check(%s) = %t, expected %t (reason: %s)",
+					tt.command, decision.Allowed, tt.expected, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestManager_Check_ACL(t *testing.T) {
+	state := newTestState(t)
+	m := newTestManager()
+	ctx := context.Background()
+
+	if err := m.Grant(ctx, testGuildID, "gbf", "role_member", ""); err != nil {
+		t.Fatalf("Grant(gbf) unexpected error: %v", err)
+	}
+	if err := m.Grant(ctx, testGuildID, "reload", "", "u2"); err != nil {
+		t.Fatalf("Grant(reload) unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		subject  Subject
+		command  string
+		expected bool
+	}{
+		{
+			name:     "category rule allows listed role",
+			subject:  Subject{GuildID: testGuildID, UserID: "u1", RoleIDs: []string{"role_member"}},
+			command:  "battles",
+			expected: true,
+		},
+		{
+			name:     "category rule denies other members",
+			subject:  Subject{GuildID: testGuildID, UserID: "u1"},
+			command:  "battles",
+			expected: false,
+		},
+		{
+			name:     "command rule allows listed user",
+			subject:  Subject{GuildID: testGuildID, UserID: "u2"},
+			command:  "reload",
+			expected: true,
+		},
+		{
+			name:     "command rule overrides control role",
+			subject:  Subject{GuildID: testGuildID, UserID: "u1", RoleIDs: []string{"role_control"}},
+			command:  "reload",
+			expected: false,
+		},
+		{
+			name:     "other guilds are unaffected",
+			subject:  Subject{GuildID: "guild2", UserID: "u1"},
+			command:  "battles",
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := m.Check(ctx, state, tt.subject, tt.command)
+			if decision.Allowed != tt.expected {
+				t.Errorf("Check(%s) = %t, expected %t (reason: %s)",
+					tt.command, decision.Allowed, tt.expected, decision.Reason)
+			}
+		})
+	}
+
+	t.Run("reset restores defaults", func(t *testing.T) {
+		if err := m.Reset(ctx, testGuildID, "GBF"); err != nil {
+			t.Fatalf("Reset(GBF) unexpected error: %v", err)
+		}
+		decision := m.Check(ctx, state, Subject{GuildID: testGuildID, UserID: "u1"}, "battles")
+		if !decision.Allowed {
+			t.Errorf("Check(battles) after reset = false, expected true")
+		}
+	})
+
+	t.Run("rules survive reload from storage", func(t *testing.T) {
+		if _, err := m.Reload(ctx); err != nil {
+			t.Fatalf("Reload() unexpected error: %v", err)
+		}
+		decision := m.Check(ctx, state, Subject{GuildID: testGuildID, UserID: "u2"}, "reload")
+		if !decision.Allowed {
+			t.Errorf("Check(reload) after Reload = false, expected true")
+		}
+	})
+
+	t.Run("unknown target", func(t *testing.T) {
+		if err := m.Grant(ctx, testGuildID, "unknown", "role_member", ""); err == nil {
+			t.Error("Grant(unknown) expected error but got none")
+		}
+	})
+}
+
+// failingStore fails every read, as a storage outage would
+type failingStore struct {
+	storage.Store
+}
+
+func (failingStore) Get(ctx context.Context, collection, key string, v any) error {
+	return errors.New("storage unavailable")
+}
+
+func TestManager_Check_StorageError(t *testing.T) {
+	state := newTestState(t)
+	m := NewManager(failingStore{storage.NewMemoryStore()})
+	m.Register(CommandSpec{Name: "battles", Category: CategoryGBF})
+
+	decision := m.Check(context.Background(), state, Subject{GuildID: testGuildID, UserID: "u1"}, "battles")
+	if decision.Allowed || decision.Denial != DenialUnavailable || decision.Err == nil {
+		t.Errorf("Check(battles) = %+v, expected a denial with the storage error", decision)
+	}
+
+	admin := Subject{GuildID: testGuildID, UserID: "u1", Permissions: discordgo.PermissionAdministrator}
+	if decision := m.Check(context.Background(), state, admin, "battles"); !decision.Allowed {
+		t.Errorf("Check(battles) for an administrator = %+v, expected allowed", decision)
+	}
+}
+
+// blockingStore holds the result of the first read until release is closed
+type blockingStore struct {
+	storage.Store
+	blocked atomic.Bool
+	reading chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingStore) Get(ctx context.Context, collection, key string, v any) error {
+	err := b.Store.Get(ctx, collection, key, v)
+	if b.blocked.CompareAndSwap(false, true) {
+		close(b.reading)
+		<-b.release
+	}
+	return err
+}
+
+func TestManager_ACL_StaleLoad(t *testing.T) {
+	ctx := context.Background()
+	store := &blockingStore{Store: storage.NewMemoryStore(), reading: make(chan struct{}), release: make(chan struct{})}
+	m := NewManager(store)
+	m.Register(CommandSpec{Name: "reload", Category: CategoryAdmin})
+
+	// A lazy load reads the empty ACL and stalls before caching it
+	loaded := make(chan struct{})
+	go func() {
+		defer close(loaded)
+		_, _ = m.ACL(ctx, testGuildID)
+	}()
+	<-store.reading
+
+	if err := m.Grant(ctx, testGuildID, "reload", "", "u2"); err != nil {
+		t.Fatalf("Grant(reload) unexpected error: %v", err)
+	}
+	close(store.release)
+	<-loaded
+
+	acl, err := m.ACL(ctx, testGuildID)
+	if err != nil {
+		t.Fatalf("ACL() unexpected error: %v", err)
+	}
+	if rule := acl.Commands["reload"]; rule == nil || !slices.Contains(rule.UserIDs, "u2") {
+		t.Errorf("ACL() = %+v, expected the grant made during the stalled load", acl)
+	}
+}
+
+func TestGuildPermissions(t *testing.T) {
+	state := newTestState(t)
+	guild, err := state.Guild(testGuildID)
+	if err != nil {
+		t.Fatalf("failed to load guild: %v", err)
+	}
+
+	if got := GuildPermissions(guild, "owner", nil); got != discordgo.PermissionAll {
+		t.Errorf("GuildPermissions(owner) = %d, expected PermissionAll", got)
+	}
+	if got := GuildPermissions(guild, "u1", []string{"role_admin"}); got&discordgo.PermissionAdministrator == 0 {
+		t.Errorf("GuildPermissions(admin role) missing Administrator")
+	}
+	if got := GuildPermissions(guild, "u1", []string{"role_member"}); got != 0 {
+		t.Errorf("GuildPermissions(member role) = %d, expected 0", got)
+	}
+}
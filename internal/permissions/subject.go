@@ -0,0 +1,77 @@
+package permissions
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// GuildState looks up cached guild data.
+// *discordgo.State satisfies it, as do session fakes used in tests.
+type GuildState interface {
+	Guild(guildID string) (*discordgo.Guild, error)
+	Role(guildID, roleID string) (*discordgo.Role, error)
+}
+
+// SubjectFromInteraction builds a subject from an interaction.
+// Discord resolves the member's permissions on interactions, so no lookup is needed.
+func SubjectFromInteraction(i *discordgo.InteractionCreate) Subject {
+	subject := Subject{GuildID: i.GuildID}
+	if i.Member == nil {
+		if i.User != nil {
+			subject.UserID = i.User.ID
+		}
+		return subject
+	}
+
+	if i.Member.User != nil {
+		subject.UserID = i.Member.User.ID
+	}
+	subject.RoleIDs = i.Member.Roles
+	subject.Permissions = i.Member.Permissions
+	return subject
+}
+
+// SubjectFromMessage builds a subject from a message using the state cache
+// to resolve the author's guild permissions
+func SubjectFromMessage(state GuildState, m *discordgo.MessageCreate) Subject {
+	subject := Subject{GuildID: m.GuildID}
+	if m.Author != nil {
+		subject.UserID = m.Author.ID
+	}
+	if m.Member == nil || m.GuildID == "" {
+		return subject
+	}
+
+	subject.RoleIDs = m.Member.Roles
+	if state != nil {
+		if guild, err := state.Guild(m.GuildID); err == nil {
+			subject.Permissions = GuildPermissions(guild, subject.UserID, subject.RoleIDs)
+		}
+	}
+	return subject
+}
+
+// GuildPermissions computes a member's guild-level permissions from cached roles
+func GuildPermissions(guild *discordgo.Guild, userID string, roleIDs []string) int64 {
+	if userID == guild.OwnerID {
+		return discordgo.PermissionAll
+	}
+
+	var permissions int64
+	for _, role := range guild.Roles {
+		if role.ID == guild.ID {
+			permissions |= role.Permissions
+			continue
+		}
+		for _, roleID := range roleIDs {
+			if role.ID == roleID {
+				permissions |= role.Permissions
+				break
+			}
+		}
+	}
+
+	if permissions&discordgo.PermissionAdministrator != 0 {
+		permissions |= discordgo.PermissionAll
+	}
+	return permissions
+}
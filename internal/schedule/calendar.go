@@ -0,0 +1,486 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+const (
+	// calendarCollection is the storage collection holding the game event calendar
+	calendarCollection = "event_calendar"
+	// calendarKey is the key of the calendar document; game events are the same for every guild
+	calendarKey = "global"
+	// calendarRetention is how long ended events are kept before they are pruned
+	calendarRetention = 24 * time.Hour
+
+	// DefaultEventStartHour is when game events start when only a date is given (JST)
+	DefaultEventStartHour = 19
+)
+
+// ErrCalendarEventNotFound is returned when a calendar event does not exist
+var ErrCalendarEventNotFound = errors.New("no calendar event with that ID")
+
+// ErrOperatorOnly is returned when a guild other than the operator guild changes
+// data shared by every guild, such as the event calendar
+var ErrOperatorOnly = errors.New("shared data can only be changed from the operator server")
+
+// CheckOperator returns ErrOperatorOnly unless guildID is the operator guild.
+// Nobody can change shared data while no operator guild is configured.
+func CheckOperator(guildID, operatorGuildID string) error {
+	if operatorGuildID == "" || guildID != operatorGuildID {
+		return ErrOperatorOnly
+	}
+	return nil
+}
+
+// CalendarKind is the kind of a game event
+type CalendarKind string
+
+const (
+	CalendarGuildWar       CalendarKind = "guild_war"       // 古戦場
+	CalendarDreadBarrage   CalendarKind = "dread_barrage"   // ドレッドバラージュ
+	CalendarStoryEvent     CalendarKind = "story_event"     // シナリオイベント
+	CalendarProvingGrounds CalendarKind = "proving_grounds" // ブレイブグラウンド
+	CalendarOther          CalendarKind = "other"
+)
+
+// CalendarKinds lists every calendar kind in display order
+var CalendarKinds = []CalendarKind{
+	CalendarGuildWar, CalendarDreadBarrage, CalendarStoryEvent, CalendarProvingGrounds, CalendarOther,
+}
+
+// defaultCalendarBattleTypes are the battle types activated by an event of a kind
+// when the event does not list its battles explicitly
+var defaultCalendarBattleTypes = map[CalendarKind][]gbf.BattleType{
+	CalendarGuildWar: {gbf.BattleTypeGWNM},
+}
+
+// Calendar notice keys
+const (
+	calendarNoticeStart = "start"
+	calendarNoticeEnd   = "end"
+)
+
+// CalendarEvent is a game event with a fixed period during which its battles are available
+type CalendarEvent struct {
+	ID    int          `json:"id"`
+	Name  string       `json:"name"`
+	Kind  CalendarKind `json:"kind"`
+	Start time.Time    `json:"start"`
+	End   time.Time    `json:"end"`
+	// BattleIDs are the battles available during the event; when empty the
+	// default battles of the kind are used
+	BattleIDs []string `json:"battle_ids,omitempty"`
+	// Fired maps notice keys to when they were sent or skipped
+	Fired     map[string]time.Time `json:"fired,omitempty"`
+	CreatedBy string               `json:"created_by,omitempty"`
+}
+
+// IsRunning reports whether the event is in progress at the given time
+func (e *CalendarEvent) IsRunning(now time.Time) bool {
+	return !now.Before(e.Start) && now.Before(e.End)
+}
+
+// Calendar is the stored list of game events
+type Calendar struct {
+	LastID int             `json:"last_id"`
+	Events []CalendarEvent `json:"events"`
+}
+
+// AddCalendarEvent adds a game event to the calendar.
+// Explicit battle IDs must exist in the catalog.
+func (e *Engine) AddCalendarEvent(ctx context.Context, event CalendarEvent) (*CalendarEvent, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := ValidateCalendarEvent(&event, e.battles); err != nil {
+		return nil, err
+	}
+	now := e.clock.Now()
+	if !event.End.After(now) {
+		return nil, errors.New("this event has already ended")
+	}
+
+	calendar, err := e.loadCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar.LastID++
+	event.ID = calendar.LastID
+	event.Fired = make(map[string]time.Time)
+	// An event added while running only gets its end notice
+	if !event.Start.After(now) {
+		event.Fired[calendarNoticeStart] = now
+	}
+	calendar.Events = append(calendar.Events, event)
+
+	if err := e.store.Put(ctx, calendarCollection, calendarKey, calendar); err != nil {
+		return nil, fmt.Errorf("failed to save event calendar: %w", err)
+	}
+	e.reconcileBattles(calendar, now)
+	return &event, nil
+}
+
+// ValidateCalendarEvent checks a game event and replaces its battle aliases with battle IDs.
+// Explicit battle IDs must exist in battles.
+func ValidateCalendarEvent(event *CalendarEvent, battles *gbf.BattleManager) error {
+	if strings.TrimSpace(event.Name) == "" {
+		return errors.New("event name is required")
+	}
+	if !slices.Contains(CalendarKinds, event.Kind) {
+		return fmt.Errorf("invalid event kind %q", event.Kind)
+	}
+	if !event.End.After(event.Start) {
+		return errors.New("the event must end after it starts")
+	}
+	for i, battleID := range event.BattleIDs {
+		battle, err := battles.GetBattle(battleID)
+		if err != nil {
+			return err
+		}
+		event.BattleIDs[i] = battle.ID
+	}
+	return nil
+}
+
+// ReplaceCalendar replaces every game event of the calendar, e.g. from an imported sheet.
+// Events keep their notice history when their ID exists and the notice time is
+// unchanged; other events get a new ID. Notices that are already due are not sent.
+func (e *Engine) ReplaceCalendar(ctx context.Context, events []CalendarEvent) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	calendar, err := e.loadCalendar(ctx)
+	if err != nil {
+		return err
+	}
+	existing := make(map[int]CalendarEvent, len(calendar.Events))
+	for _, event := range calendar.Events {
+		existing[event.ID] = event
+	}
+
+	now := e.clock.Now()
+	seen := make(map[int]bool, len(events))
+	replaced := make([]CalendarEvent, 0, len(events))
+	for _, event := range events {
+		if err := ValidateCalendarEvent(&event, e.battles); err != nil {
+			return fmt.Errorf("%s: %w", event.Name, err)
+		}
+
+		old, exists := existing[event.ID]
+		exists = exists && !seen[event.ID]
+		if !exists {
+			calendar.LastID++
+			event.ID = calendar.LastID
+		}
+		seen[event.ID] = true
+
+		event.Fired = make(map[string]time.Time)
+		keepFired := func(key string, at, oldAt time.Time) {
+			if fired, ok := old.Fired[key]; ok && exists && at.Equal(oldAt) {
+				event.Fired[key] = fired
+				return
+			}
+			if !at.After(now) {
+				event.Fired[key] = now
+			}
+		}
+		keepFired(calendarNoticeStart, event.Start, old.Start)
+		keepFired(calendarNoticeEnd, event.End, old.End)
+		if exists && event.CreatedBy == "" {
+			event.CreatedBy = old.CreatedBy
+		}
+		replaced = append(replaced, event)
+	}
+	calendar.Events = replaced
+
+	if err := e.store.Put(ctx, calendarCollection, calendarKey, calendar); err != nil {
+		return fmt.Errorf("failed to save event calendar: %w", err)
+	}
+
+	// Battles of removed events are switched off unless a remaining event needs them
+	wanted := wantedBattles(calendar, now, e.battles)
+	for _, old := range existing {
+		if !old.IsRunning(now) {
+			continue
+		}
+		for _, battleID := range calendarBattleIDs(&old, e.battles) {
+			if _, tied := wanted[battleID]; !tied {
+				e.setBattleActive(battleID, false)
+			}
+		}
+	}
+	e.reconcileBattles(calendar, now)
+	return nil
+}
+
+// CalendarEvents returns the game events of the calendar ordered by start time
+func (e *Engine) CalendarEvents(ctx context.Context) ([]CalendarEvent, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	calendar, err := e.loadCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events := calendar.Events
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+	return events, nil
+}
+
+// RemoveCalendarEvent removes a game event from the calendar and updates battle availability
+func (e *Engine) RemoveCalendarEvent(ctx context.Context, id int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	calendar, err := e.loadCalendar(ctx)
+	if err != nil {
+		return err
+	}
+
+	index := -1
+	for i := range calendar.Events {
+		if calendar.Events[i].ID == id {
+			index = i
+		}
+	}
+	if index < 0 {
+		return ErrCalendarEventNotFound
+	}
+	removed := calendar.Events[index]
+	calendar.Events = append(calendar.Events[:index], calendar.Events[index+1:]...)
+
+	if err := e.store.Put(ctx, calendarCollection, calendarKey, calendar); err != nil {
+		return fmt.Errorf("failed to save event calendar: %w", err)
+	}
+
+	// Battles of the removed event stay active only if another running event needs them
+	now := e.clock.Now()
+	e.reconcileBattles(calendar, now)
+	if removed.IsRunning(now) {
+		wanted := wantedBattles(calendar, now, e.battles)
+		for _, battleID := range calendarBattleIDs(&removed, e.battles) {
+			if !wanted[battleID] {
+				e.setBattleActive(battleID, false)
+			}
+		}
+	}
+	return nil
+}
+
+// ReplaceBattles swaps the battle catalog for battles with the event calendar
+// already applied, so readers never see the new catalog with default availability
+func (e *Engine) ReplaceBattles(ctx context.Context, battles []*gbf.BattleInfo) (gbf.CatalogDiff, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	calendar, err := e.loadCalendar(ctx)
+	if err != nil {
+		return gbf.CatalogDiff{}, err
+	}
+
+	// The calendar is resolved against the incoming catalog, not the one being replaced
+	staged := gbf.NewBattleManager()
+	staged.Replace(battles)
+	wanted := wantedBattles(calendar, e.clock.Now(), staged)
+	for _, battle := range battles {
+		if active, tied := wanted[battle.ID]; tied {
+			battle.IsActive = active
+		}
+	}
+	return e.battles.Replace(battles), nil
+}
+
+// loadCalendar reads the event calendar from storage
+func (e *Engine) loadCalendar(ctx context.Context) (*Calendar, error) {
+	calendar := &Calendar{}
+	err := e.store.Get(ctx, calendarCollection, calendarKey, calendar)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load event calendar: %w", err)
+	}
+	for i := range calendar.Events {
+		if calendar.Events[i].Fired == nil {
+			calendar.Events[i].Fired = make(map[string]time.Time)
+		}
+	}
+	return calendar, nil
+}
+
+// calendarBattleIDs returns the IDs of the battles in catalog available during an event
+func calendarBattleIDs(event *CalendarEvent, catalog *gbf.BattleManager) []string {
+	if len(event.BattleIDs) > 0 {
+		return event.BattleIDs
+	}
+	var ids []string
+	for _, battleType := range defaultCalendarBattleTypes[event.Kind] {
+		for _, battle := range catalog.GetBattlesByType(battleType) {
+			ids = append(ids, battle.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// wantedBattles maps every battle tied to a calendar event to whether one of its
+// events is running
+func wantedBattles(calendar *Calendar, now time.Time, catalog *gbf.BattleManager) map[string]bool {
+	wanted := make(map[string]bool)
+	for i := range calendar.Events {
+		event := &calendar.Events[i]
+		for _, battleID := range calendarBattleIDs(event, catalog) {
+			wanted[battleID] = wanted[battleID] || event.IsRunning(now)
+		}
+	}
+	return wanted
+}
+
+// reconcileBattles makes every battle tied to a calendar event active exactly
+// while one of its events is running. Battles not tied to any event are left alone.
+func (e *Engine) reconcileBattles(calendar *Calendar, now time.Time) {
+	for battleID, active := range wantedBattles(calendar, now, e.battles) {
+		e.setBattleActive(battleID, active)
+	}
+}
+
+// setBattleActive changes the availability of a battle if it differs
+func (e *Engine) setBattleActive(battleID string, active bool) {
+	battle, err := e.battles.GetBattle(battleID)
+	if err != nil {
+		e.logger.WithError(err).Warn("Calendar event refers to an unknown battle", "battle_id", battleID)
+		return
+	}
+	if battle.IsActive == active {
+		return
+	}
+	if err := e.battles.SetBattleActive(battleID, active); err != nil {
+		e.logger.WithError(err).Error("Failed to update battle availability", "battle_id", battleID)
+		return
+	}
+	e.logger.Info("Updated battle availability from event calendar", "battle_id", battleID, "active", active)
+}
+
+// tickCalendar updates battle availability and broadcasts start and end notices
+func (e *Engine) tickCalendar(ctx context.Context, now time.Time) error {
+	calendar, err := e.loadCalendar(ctx)
+	if err != nil {
+		return err
+	}
+	e.reconcileBattles(calendar, now)
+
+	var notices []calendarMessage
+	changed := false
+	for i := range calendar.Events {
+		event := &calendar.Events[i]
+		for _, notice := range []struct {
+			key string
+			at  time.Time
+		}{
+			{calendarNoticeStart, event.Start},
+			{calendarNoticeEnd, event.End},
+		} {
+			if notice.at.After(now) {
+				continue
+			}
+			if _, fired := event.Fired[notice.key]; fired {
+				continue
+			}
+			event.Fired[notice.key] = now
+			changed = true
+			if now.Sub(notice.at) > staleAfter {
+				e.logger.Warn("Skipped stale calendar notice", "event_id", event.ID, "notice", notice.key, "due", notice.at)
+				continue
+			}
+
+			name := templates.CalendarEnd
+			if notice.key == calendarNoticeStart {
+				name = templates.CalendarStart
+			}
+			notices = append(notices, calendarMessage{
+				template: name,
+				data:     CalendarData(*event, e.calendarBattleNames(event)),
+			})
+		}
+	}
+
+	// Drop events that ended a while ago
+	remaining := calendar.Events[:0]
+	for _, event := range calendar.Events {
+		if now.Sub(event.End) < calendarRetention {
+			remaining = append(remaining, event)
+		}
+	}
+	if len(remaining) != len(calendar.Events) {
+		calendar.Events = remaining
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	// Record the notices before broadcasting so that none is sent twice
+	if err := e.store.Put(ctx, calendarCollection, calendarKey, calendar); err != nil {
+		return fmt.Errorf("failed to save event calendar: %w", err)
+	}
+	if len(notices) == 0 {
+		return nil
+	}
+	return e.broadcast(ctx, notices)
+}
+
+// calendarMessage is a calendar notice waiting to be rendered for each guild
+type calendarMessage struct {
+	template string
+	data     templates.CalendarData
+}
+
+// calendarBattleNames returns the display names of an event's battles
+func (e *Engine) calendarBattleNames(event *CalendarEvent) []string {
+	var names []string
+	for _, battleID := range calendarBattleIDs(event, e.battles) {
+		if battle, err := e.battles.GetBattle(battleID); err == nil {
+			names = append(names, battle.Name)
+		}
+	}
+	return names
+}
+
+// broadcast renders notices with each guild's templates and posts them to the
+// notification channel of every guild that has one.
+// Failures are logged per guild and not retried.
+func (e *Engine) broadcast(ctx context.Context, notices []calendarMessage) error {
+	guilds, err := e.settings.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, guild := range guilds {
+		if guild.NotificationChannelID == "" {
+			continue
+		}
+		logger := e.logger.WithGuild(guild.GuildID).WithChannel(guild.NotificationChannelID)
+		for _, notice := range notices {
+			content, err := e.templates.Render(ctx, guild.GuildID, guild.Locale, notice.template, notice.data)
+			if err != nil {
+				logger.WithError(err).Error("Failed to render calendar notice")
+				break
+			}
+			if _, err := e.sender.ChannelMessageSend(guild.NotificationChannelID, content); err != nil {
+				logger.WithError(err).Error("Failed to send calendar notice")
+				break
+			}
+		}
+	}
+	return nil
+}
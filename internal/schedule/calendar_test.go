@@ -0,0 +1,146 @@
+package schedule
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/settings"
+)
+
+func TestEngine_CalendarActivatesBattles(t *testing.T) {
+	ctx := context.Background()
+	engine, clock, sender, settingsManager := newTestEngine(t, jst(2026, time.March, 1, 12, 0))
+	for _, guildID := range []string{"guild1", "guild2"} {
+		_ = settingsManager.Update(ctx, guildID, func(s *settings.GuildSettings) {
+			s.NotificationChannelID = "notify-" + guildID
+		})
+	}
+	// A guild without a channel receives nothing
+	_ = settingsManager.Update(ctx, "guild3", func(s *settings.GuildSettings) {})
+
+	gw, err := engine.AddCalendarEvent(ctx, CalendarEvent{
+		Name:  "Guild War",
+		Kind:  CalendarGuildWar,
+		Start: jst(2026, time.March, 1, 19, 0),
+		End:   jst(2026, time.March, 8, 23, 59),
+	})
+	if err != nil {
+		t.Fatalf("AddCalendarEvent: %v", err)
+	}
+
+	isActive := func(battleID string) bool {
+		t.Helper()
+		battle, err := engine.battles.GetBattle(battleID)
+		if err != nil {
+			t.Fatalf("GetBattle: %v", err)
+		}
+		return battle.IsActive
+	}
+	tick := func(at time.Time) {
+		t.Helper()
+		clock.Set(at)
+		if err := engine.Tick(ctx); err != nil {
+			t.Fatalf("Tick: %v", err)
+		}
+	}
+
+	tick(jst(2026, time.March, 1, 18, 59))
+	if isActive("gw_nm95") || isActive("gw_nm150") {
+		t.Fatal("NM battles should be inactive before the Guild War")
+	}
+
+	// The start activates the NM battles and is announced once to every guild
+	tick(jst(2026, time.March, 1, 19, 0))
+	tick(jst(2026, time.March, 1, 19, 1))
+	if !isActive("gw_nm95") || !isActive("gw_nm150") {
+		t.Fatal("NM battles should be active during the Guild War")
+	}
+	sent := sender.Sent()
+	if len(sent) != 2 {
+		t.Fatalf("sent %d start notices, want 2", len(sent))
+	}
+	if !strings.Contains(sent[0].Content, "Guild War NM100, Guild War NM150, Guild War NM200, Guild War NM250, Guild War NM90, Guild War NM95") {
+		t.Errorf("start notice should list the battles: %q", sent[0].Content)
+	}
+
+	// A reloaded catalog is swapped in with the calendar already applied
+	diff, err := engine.ReplaceBattles(ctx, gbf.DefaultBattles())
+	if err != nil {
+		t.Fatalf("ReplaceBattles: %v", err)
+	}
+	if !isActive("gw_nm95") {
+		t.Error("ReplaceBattles should keep availability from the calendar")
+	}
+	if !diff.IsEmpty() {
+		t.Errorf("reloading the same catalog reported changes: %+v", diff)
+	}
+
+	// The end deactivates the battles and is announced
+	tick(jst(2026, time.March, 8, 23, 59))
+	if isActive("gw_nm95") || isActive("gw_nm150") {
+		t.Fatal("NM battles should be inactive after the Guild War")
+	}
+	if got := len(sender.Sent()); got != 4 {
+		t.Errorf("sent %d notices, want 4", got)
+	}
+
+	// Ended events are pruned after a day
+	tick(jst(2026, time.March, 10, 0, 0))
+	events, _ := engine.CalendarEvents(ctx)
+	if len(events) != 0 {
+		t.Errorf("calendar still holds %d events", len(events))
+	}
+	if err := engine.RemoveCalendarEvent(ctx, gw.ID); err == nil {
+		t.Error("removing a pruned event should fail")
+	}
+}
+
+func TestEngine_CalendarOverlappingEvents(t *testing.T) {
+	ctx := context.Background()
+	engine, _, _, _ := newTestEngine(t, jst(2026, time.March, 2, 12, 0))
+
+	// Two running events share a battle; removing one keeps it active
+	first, err := engine.AddCalendarEvent(ctx, CalendarEvent{
+		Name: "Story event", Kind: CalendarStoryEvent, BattleIDs: []string{"GW_NM95"},
+		Start: jst(2026, time.March, 1, 19, 0), End: jst(2026, time.March, 9, 0, 0),
+	})
+	if err != nil {
+		t.Fatalf("AddCalendarEvent: %v", err)
+	}
+	if first.BattleIDs[0] != "gw_nm95" {
+		t.Errorf("battle IDs should be normalized, got %v", first.BattleIDs)
+	}
+	second, _ := engine.AddCalendarEvent(ctx, CalendarEvent{
+		Name: "Dread Barrage", Kind: CalendarDreadBarrage, BattleIDs: []string{"gw_nm95", "gw_nm150"},
+		Start: jst(2026, time.March, 2, 0, 0), End: jst(2026, time.March, 5, 0, 0),
+	})
+
+	if err := engine.RemoveCalendarEvent(ctx, second.ID); err != nil {
+		t.Fatalf("RemoveCalendarEvent: %v", err)
+	}
+	nm95, _ := engine.battles.GetBattle("gw_nm95")
+	nm150, _ := engine.battles.GetBattle("gw_nm150")
+	if !nm95.IsActive || nm150.IsActive {
+		t.Errorf("gw_nm95 active = %v (want true), gw_nm150 active = %v (want false)", nm95.IsActive, nm150.IsActive)
+	}
+
+	tests := []struct {
+		name  string
+		event CalendarEvent
+	}{
+		{"unknown battle", CalendarEvent{Name: "x", Kind: CalendarOther, BattleIDs: []string{"nope"}, Start: jst(2026, time.March, 3, 0, 0), End: jst(2026, time.March, 4, 0, 0)}},
+		{"ends before start", CalendarEvent{Name: "x", Kind: CalendarOther, Start: jst(2026, time.March, 4, 0, 0), End: jst(2026, time.March, 3, 0, 0)}},
+		{"already ended", CalendarEvent{Name: "x", Kind: CalendarOther, Start: jst(2026, time.February, 1, 0, 0), End: jst(2026, time.February, 2, 0, 0)}},
+		{"missing name", CalendarEvent{Kind: CalendarOther, Start: jst(2026, time.March, 3, 0, 0), End: jst(2026, time.March, 4, 0, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := engine.AddCalendarEvent(ctx, tt.event); err == nil {
+				t.Error("AddCalendarEvent succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JST is Japan Standard Time, the time zone all GBF events are scheduled in.
+// A fixed zone is used so that no tzdata is required at runtime.
+var JST = time.FixedZone("JST", 9*60*60)
+
+// Clock supplies the current time so that schedules can be tested deterministically
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a Clock backed by the system time
+type SystemClock struct{}
+
+// Now returns the current time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// inputLayouts are the accepted date/time formats for user input, interpreted in JST
+var inputLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006/01/02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// ParseJST parses a date or date and time in JST.
+// A date without a time is returned at defaultHour:00.
+func ParseJST(value string, defaultHour int) (time.Time, error) {
+	t, hasTime, err := parseJST(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !hasTime {
+		t = t.Add(time.Duration(defaultHour) * time.Hour)
+	}
+	return t, nil
+}
+
+// ParseJSTEnd parses the end of a period in JST.
+// A date without a time means the last minute of that day.
+func ParseJSTEnd(value string) (time.Time, error) {
+	t, hasTime, err := parseJST(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !hasTime {
+		t = t.Add(24*time.Hour - time.Minute)
+	}
+	return t, nil
+}
+
+// parseJST parses value with the first matching input layout and reports
+// whether it included a time of day
+func parseJST(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range inputLayouts {
+		t, err := time.ParseInLocation(layout, value, JST)
+		if err != nil {
+			continue
+		}
+		return t, strings.Contains(layout, "15"), nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid date %q: use YYYY-MM-DD or YYYY-MM-DD HH:MM (JST)", value)
+}
+
+// FormatJST formats a time for display in JST
+func FormatJST(t time.Time) string {
+	return t.In(JST).Format("2006-01-02 15:04") + " JST"
+}
@@ -0,0 +1,462 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/settings"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+// guildWarCollection is the storage collection holding each guild's Guild War schedule
+const guildWarCollection = "gw_schedules"
+
+const (
+	// tickInterval is how often the engine checks for due notifications
+	tickInterval = 30 * time.Second
+	// staleAfter is how late a notification may be before it is skipped,
+	// so that a restart after downtime does not flood channels with old news
+	staleAfter = 30 * time.Minute
+)
+
+// ErrNotScheduled is returned when a guild has no Guild War scheduled
+var ErrNotScheduled = errors.New("no Guild War is scheduled")
+
+// Sender posts messages to Discord channels
+type Sender interface {
+	ChannelMessageSend(channelID, content string) (*discordgo.Message, error)
+}
+
+// GuildWarSchedule is a guild's Guild War and the notifications already sent for it
+type GuildWarSchedule struct {
+	GuildID string   `json:"guild_id"`
+	Event   GuildWar `json:"event"`
+	// Fired maps notification keys to when they were sent or skipped
+	Fired     map[string]time.Time `json:"fired,omitempty"`
+	SetBy     string               `json:"set_by,omitempty"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// IsFired reports whether a notification has already been sent or skipped
+func (s *GuildWarSchedule) IsFired(key string) bool {
+	_, fired := s.Fired[key]
+	return fired
+}
+
+// Engine posts scheduled notifications to each guild's notification channel and
+// keeps battle availability in line with the event calendar.
+// Every notification is recorded as fired before it is sent, so it is never
+// posted twice, even across restarts.
+type Engine struct {
+	store     storage.Store
+	settings  *settings.Manager
+	battles   *gbf.BattleManager
+	templates *templates.Manager
+	sender    Sender
+	clock     Clock
+	logger    *log.Logger
+
+	// mu serializes ticks with schedule updates
+	mu sync.Mutex
+}
+
+// NewEngine creates a new notification engine
+func NewEngine(store storage.Store, settingsManager *settings.Manager, battleManager *gbf.BattleManager, templateManager *templates.Manager, sender Sender, clock Clock, logger *log.Logger) *Engine {
+	return &Engine{
+		store:     store,
+		settings:  settingsManager,
+		battles:   battleManager,
+		templates: templateManager,
+		sender:    sender,
+		clock:     clock,
+		logger:    logger,
+	}
+}
+
+// Now returns the current time of the engine's clock
+func (e *Engine) Now() time.Time {
+	return e.clock.Now()
+}
+
+// SetGuildWar schedules a Guild War for a guild, replacing any previous one.
+// Notifications that are already due are marked as fired and will not be sent.
+func (e *Engine) SetGuildWar(ctx context.Context, guildID string, event GuildWar, setBy string) (*GuildWarSchedule, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.clock.Now()
+	schedule := &GuildWarSchedule{
+		GuildID:   guildID,
+		Event:     event,
+		Fired:     make(map[string]time.Time),
+		SetBy:     setBy,
+		UpdatedAt: now,
+	}
+	for _, notification := range event.Notifications() {
+		if !notification.At.After(now) {
+			schedule.Fired[notification.Key] = now
+		}
+	}
+
+	if err := e.store.Put(ctx, guildWarCollection, guildID, schedule); err != nil {
+		return nil, fmt.Errorf("failed to save Guild War schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// GuildWar returns the Guild War scheduled for a guild
+func (e *Engine) GuildWar(ctx context.Context, guildID string) (*GuildWarSchedule, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.loadGuildWar(ctx, guildID)
+}
+
+// ClearGuildWar removes the Guild War scheduled for a guild
+func (e *Engine) ClearGuildWar(ctx context.Context, guildID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.store.Delete(ctx, guildWarCollection, guildID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return ErrNotScheduled
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete Guild War schedule: %w", err)
+	}
+	return nil
+}
+
+// loadGuildWar reads a guild's Guild War schedule from storage
+func (e *Engine) loadGuildWar(ctx context.Context, guildID string) (*GuildWarSchedule, error) {
+	schedule := &GuildWarSchedule{}
+	err := e.store.Get(ctx, guildWarCollection, guildID, schedule)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrNotScheduled
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Guild War schedule: %w", err)
+	}
+	if schedule.Fired == nil {
+		schedule.Fired = make(map[string]time.Time)
+	}
+	return schedule, nil
+}
+
+// Run checks for due notifications until ctx is cancelled
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Tick(ctx); err != nil {
+			e.logger.WithError(err).Error("Failed to process scheduled notifications")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick sends every notification that is due.
+// Errors of individual guilds are logged and do not stop other guilds.
+func (e *Engine) Tick(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	guildIDs, err := e.store.Keys(ctx, guildWarCollection)
+	if err != nil {
+		return fmt.Errorf("failed to list Guild War schedules: %w", err)
+	}
+
+	eventGuildIDs, err := e.store.Keys(ctx, eventsCollection)
+	if err != nil {
+		return fmt.Errorf("failed to list custom events: %w", err)
+	}
+
+	now := e.clock.Now()
+	if err := e.tickCalendar(ctx, now); err != nil {
+		e.logger.WithError(err).Error("Failed to process event calendar")
+	}
+	for _, guildID := range guildIDs {
+		if err := e.tickGuildWar(ctx, guildID, now); err != nil {
+			e.logger.WithGuild(guildID).WithError(err).Error("Failed to process Guild War notifications")
+		}
+	}
+	for _, guildID := range eventGuildIDs {
+		if err := e.tickEvents(ctx, guildID, now); err != nil {
+			e.logger.WithGuild(guildID).WithError(err).Error("Failed to process custom events")
+		}
+	}
+	return nil
+}
+
+// tickGuildWar sends the due notifications of one guild's Guild War
+func (e *Engine) tickGuildWar(ctx context.Context, guildID string, now time.Time) error {
+	schedule, err := e.loadGuildWar(ctx, guildID)
+	if err != nil {
+		return err
+	}
+
+	guildSettings, err := e.settings.Get(ctx, guildID)
+	if err != nil {
+		return err
+	}
+	logger := e.logger.WithGuild(guildID).WithChannel(guildSettings.NotificationChannelID)
+
+	for _, notification := range schedule.Event.Notifications() {
+		if notification.At.After(now) || schedule.IsFired(notification.Key) {
+			continue
+		}
+
+		if now.Sub(notification.At) > staleAfter {
+			schedule.Fired[notification.Key] = now
+			if err := e.store.Put(ctx, guildWarCollection, guildID, schedule); err != nil {
+				return fmt.Errorf("failed to save Guild War schedule: %w", err)
+			}
+			logger.Warn("Skipped stale notification", "notification", notification.Key, "due", notification.At)
+			continue
+		}
+
+		// Wait for a channel to be configured; the notification goes stale otherwise
+		if guildSettings.NotificationChannelID == "" {
+			logger.Debug("No notification channel configured", "notification", notification.Key)
+			continue
+		}
+
+		content, err := e.templates.Render(ctx, guildID, guildSettings.Locale, templates.GuildWarNotice(string(notification.Kind)), GuildWarData(schedule.Event, notification))
+		if err != nil {
+			return err
+		}
+
+		// Record the notification before sending so that it can never be sent twice
+		schedule.Fired[notification.Key] = now
+		if err := e.store.Put(ctx, guildWarCollection, guildID, schedule); err != nil {
+			return fmt.Errorf("failed to save Guild War schedule: %w", err)
+		}
+
+		if _, err := e.sender.ChannelMessageSend(guildSettings.NotificationChannelID, content); err != nil {
+			// Retry on the next tick
+			delete(schedule.Fired, notification.Key)
+			if saveErr := e.store.Put(ctx, guildWarCollection, guildID, schedule); saveErr != nil {
+				logger.WithError(saveErr).Error("Failed to save Guild War schedule")
+			}
+			return fmt.Errorf("failed to send %s notification: %w", notification.Key, err)
+		}
+
+		logger.Info("Sent scheduled notification", "notification", notification.Key)
+	}
+
+	return nil
+}
+
+// AddEvent schedules a custom event for a guild and returns it with its ID and first firing time
+func (e *Engine) AddEvent(ctx context.Context, guildID string, event CustomEvent) (*CustomEvent, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.clock.Now()
+	if err := ValidateEvent(&event, now); err != nil {
+		return nil, err
+	}
+	event.NextAt = event.Rule.Next(now)
+
+	events, err := e.loadEvents(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events.Events) >= MaxEventsPerGuild {
+		return nil, fmt.Errorf("a server can have at most %d scheduled events", MaxEventsPerGuild)
+	}
+
+	events.LastID++
+	event.ID = events.LastID
+	event.CreatedAt = now
+	events.Events = append(events.Events, event)
+
+	if err := e.store.Put(ctx, eventsCollection, guildID, events); err != nil {
+		return nil, fmt.Errorf("failed to save custom events: %w", err)
+	}
+	return &event, nil
+}
+
+// ReplaceEvents replaces every custom event of a guild, e.g. from an imported sheet.
+// Events whose ID exists keep their history, and their next firing time when the
+// rule is unchanged; other events get a new ID.
+func (e *Engine) ReplaceEvents(ctx context.Context, guildID string, events []CustomEvent) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(events) > MaxEventsPerGuild {
+		return fmt.Errorf("a server can have at most %d scheduled events", MaxEventsPerGuild)
+	}
+
+	stored, err := e.loadEvents(ctx, guildID)
+	if err != nil {
+		return err
+	}
+	existing := make(map[int]CustomEvent, len(stored.Events))
+	for _, event := range stored.Events {
+		existing[event.ID] = event
+	}
+
+	now := e.clock.Now()
+	seen := make(map[int]bool, len(events))
+	replaced := make([]CustomEvent, 0, len(events))
+	for _, event := range events {
+		if err := ValidateEvent(&event, now); err != nil {
+			return fmt.Errorf("%s: %w", event.Name, err)
+		}
+
+		old, exists := existing[event.ID]
+		exists = exists && !seen[event.ID]
+		if !exists {
+			stored.LastID++
+			event.ID = stored.LastID
+		}
+		seen[event.ID] = true
+
+		event.NextAt = event.Rule.Next(now)
+		event.CreatedAt = now
+		if exists {
+			if old.Rule == event.Rule && !old.NextAt.IsZero() {
+				event.NextAt = old.NextAt
+			}
+			event.CreatedAt = old.CreatedAt
+			event.LastSentAt = old.LastSentAt
+			if event.CreatedBy == "" {
+				event.CreatedBy = old.CreatedBy
+			}
+		}
+		replaced = append(replaced, event)
+	}
+	stored.Events = replaced
+
+	if err := e.store.Put(ctx, eventsCollection, guildID, stored); err != nil {
+		return fmt.Errorf("failed to save custom events: %w", err)
+	}
+	return nil
+}
+
+// Events returns the custom events of a guild ordered by ID
+func (e *Engine) Events(ctx context.Context, guildID string) ([]CustomEvent, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	events, err := e.loadEvents(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	return events.Events, nil
+}
+
+// RemoveEvent deletes a custom event of a guild
+func (e *Engine) RemoveEvent(ctx context.Context, guildID string, id int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	events, err := e.loadEvents(ctx, guildID)
+	if err != nil {
+		return err
+	}
+	index := events.find(id)
+	if index < 0 {
+		return ErrEventNotFound
+	}
+	events.Events = append(events.Events[:index], events.Events[index+1:]...)
+
+	if err := e.store.Put(ctx, eventsCollection, guildID, events); err != nil {
+		return fmt.Errorf("failed to save custom events: %w", err)
+	}
+	return nil
+}
+
+// loadEvents reads a guild's custom events from storage
+func (e *Engine) loadEvents(ctx context.Context, guildID string) (*GuildEvents, error) {
+	events := &GuildEvents{GuildID: guildID}
+	err := e.store.Get(ctx, eventsCollection, guildID, events)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load custom events: %w", err)
+	}
+	return events, nil
+}
+
+// tickEvents sends the due custom events of one guild.
+// One-shot events are removed once they have fired.
+func (e *Engine) tickEvents(ctx context.Context, guildID string, now time.Time) error {
+	events, err := e.loadEvents(ctx, guildID)
+	if err != nil {
+		return err
+	}
+
+	save := func() error {
+		if err := e.store.Put(ctx, eventsCollection, guildID, events); err != nil {
+			return fmt.Errorf("failed to save custom events: %w", err)
+		}
+		return nil
+	}
+
+	for i := range events.Events {
+		event := &events.Events[i]
+		if event.NextAt.IsZero() || event.NextAt.After(now) {
+			continue
+		}
+		logger := e.logger.WithGuild(guildID).WithChannel(event.ChannelID)
+		due := event.NextAt
+
+		if now.Sub(due) > staleAfter {
+			event.NextAt = event.Rule.Next(now)
+			if err := save(); err != nil {
+				return err
+			}
+			logger.Warn("Skipped stale custom event", "event_id", event.ID, "due", due)
+			continue
+		}
+
+		content, err := event.Render(due)
+		if err != nil {
+			return err
+		}
+
+		// Advance the event before sending so that it can never be sent twice
+		event.NextAt = event.Rule.Next(now)
+		event.LastSentAt = now
+		if err := save(); err != nil {
+			return err
+		}
+
+		if _, err := e.sender.ChannelMessageSend(event.ChannelID, content); err != nil {
+			// Retry on the next tick
+			event.NextAt = due
+			if saveErr := save(); saveErr != nil {
+				logger.WithError(saveErr).Error("Failed to save custom events")
+			}
+			return fmt.Errorf("failed to send event %d: %w", event.ID, err)
+		}
+
+		logger.Info("Sent custom event", "event_id", event.ID, "name", event.Name)
+	}
+
+	// Drop one-shot events that have fired
+	remaining := events.Events[:0]
+	for _, event := range events.Events {
+		if !event.NextAt.IsZero() {
+			remaining = append(remaining, event)
+		}
+	}
+	if len(remaining) != len(events.Events) {
+		events.Events = remaining
+		return save()
+	}
+	return nil
+}
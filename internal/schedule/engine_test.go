@@ -0,0 +1,279 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/settings"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+// fakeClock is a Clock whose time only moves when set
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// sentMessage is a message posted through fakeSender
+type sentMessage struct {
+	ChannelID string
+	Content   string
+}
+
+// fakeSender records sent messages and fails while err is set
+type fakeSender struct {
+	mu   sync.Mutex
+	sent []sentMessage
+	err  error
+}
+
+func (s *fakeSender) ChannelMessageSend(channelID, content string) (*discordgo.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.sent = append(s.sent, sentMessage{ChannelID: channelID, Content: content})
+	return &discordgo.Message{ChannelID: channelID, Content: content}, nil
+}
+
+func (s *fakeSender) Sent() []sentMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sentMessage(nil), s.sent...)
+}
+
+func newTestEngine(t *testing.T, now time.Time) (*Engine, *fakeClock, *fakeSender, *settings.Manager) {
+	t.Helper()
+
+	store := storage.NewMemoryStore()
+	settingsManager := settings.NewManager(store)
+	clock := &fakeClock{now: now}
+	sender := &fakeSender{}
+	logger := log.InitLogger("error")
+	engine := NewEngine(store, settingsManager, gbf.NewBattleManager(), templates.NewManager(store, logger), sender, clock, logger)
+	return engine, clock, sender, settingsManager
+}
+
+func TestEngine_GuildWarNotifications(t *testing.T) {
+	ctx := context.Background()
+	start := jst(2026, time.March, 1, 19, 0)
+	engine, clock, sender, settingsManager := newTestEngine(t, start.Add(-96*time.Hour))
+
+	err := settingsManager.Update(ctx, "guild1", func(s *settings.GuildSettings) {
+		s.NotificationChannelID = "channel1"
+	})
+	if err != nil {
+		t.Fatalf("Update settings: %v", err)
+	}
+
+	event, _ := NewGuildWar(start, 4)
+	if _, err := engine.SetGuildWar(ctx, "guild1", event, "admin"); err != nil {
+		t.Fatalf("SetGuildWar: %v", err)
+	}
+
+	tick := func(at time.Time) {
+		t.Helper()
+		clock.Set(at)
+		if err := engine.Tick(ctx); err != nil {
+			t.Fatalf("Tick: %v", err)
+		}
+	}
+
+	// Nothing is due yet
+	tick(start.Add(-73 * time.Hour))
+	if got := len(sender.Sent()); got != 0 {
+		t.Fatalf("sent %d messages before the first reminder", got)
+	}
+
+	// The 3 day reminder fires once, however often the engine ticks
+	tick(start.Add(-72 * time.Hour))
+	tick(start.Add(-72*time.Hour + time.Minute))
+	sent := sender.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(sent))
+	}
+	if sent[0].ChannelID != "channel1" {
+		t.Errorf("sent to %s, want channel1", sent[0].ChannelID)
+	}
+
+	// A failed send is retried on the next tick
+	sender.err = errors.New("discord unavailable")
+	tick(start.Add(-24 * time.Hour))
+	sender.err = nil
+	tick(start.Add(-24*time.Hour + time.Minute))
+	if got := len(sender.Sent()); got != 2 {
+		t.Fatalf("sent %d messages after retry, want 2", got)
+	}
+
+	// After downtime, stale notifications are skipped rather than flooded
+	tick(jst(2026, time.March, 4, 7, 5))
+	sent = sender.Sent()
+	if len(sent) != 3 {
+		t.Fatalf("sent %d messages after downtime, want 3", len(sent))
+	}
+
+	schedule, err := engine.GuildWar(ctx, "guild1")
+	if err != nil {
+		t.Fatalf("GuildWar: %v", err)
+	}
+	for _, key := range []string{"prelim_start", "prelim_end", "special_start", "special_end", "finals_start_1"} {
+		if !schedule.IsFired(key) {
+			t.Errorf("%s should be marked as fired", key)
+		}
+	}
+	if schedule.IsFired("finals_mid_1") {
+		t.Error("finals_mid_1 should still be pending")
+	}
+}
+
+func TestEngine_SetGuildWarSkipsPastNotifications(t *testing.T) {
+	ctx := context.Background()
+	start := jst(2026, time.March, 1, 19, 0)
+	engine, _, sender, settingsManager := newTestEngine(t, start.Add(time.Minute))
+
+	_ = settingsManager.Update(ctx, "guild1", func(s *settings.GuildSettings) {
+		s.NotificationChannelID = "channel1"
+	})
+
+	event, _ := NewGuildWar(start, 4)
+	schedule, err := engine.SetGuildWar(ctx, "guild1", event, "admin")
+	if err != nil {
+		t.Fatalf("SetGuildWar: %v", err)
+	}
+	if !schedule.IsFired("prelim_start") {
+		t.Error("prelim_start should be marked as fired when set after the start")
+	}
+
+	if err := engine.Tick(ctx); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+	if got := len(sender.Sent()); got != 0 {
+		t.Errorf("sent %d messages, want 0", got)
+	}
+
+	if err := engine.ClearGuildWar(ctx, "guild1"); err != nil {
+		t.Fatalf("ClearGuildWar: %v", err)
+	}
+	if _, err := engine.GuildWar(ctx, "guild1"); !errors.Is(err, ErrNotScheduled) {
+		t.Errorf("GuildWar after clear: got %v, want ErrNotScheduled", err)
+	}
+}
+
+func TestEngine_CustomEvents(t *testing.T) {
+	ctx := context.Background()
+	engine, clock, sender, _ := newTestEngine(t, jst(2026, time.March, 4, 12, 0))
+
+	daily, _ := ParseRule(RuleDaily, "21:00")
+	once, _ := ParseRule(RuleOnce, "2026-03-04 18:00")
+
+	support, err := engine.AddEvent(ctx, "guild1", CustomEvent{
+		Name:      "Crew support",
+		Rule:      daily,
+		ChannelID: "channel1",
+		RoleID:    "role1",
+		Message:   "{{.Name}} at {{jst .At}}",
+	})
+	if err != nil {
+		t.Fatalf("AddEvent: %v", err)
+	}
+	if support.ID != 1 || !support.NextAt.Equal(jst(2026, time.March, 4, 21, 0)) {
+		t.Errorf("got ID %d next %s", support.ID, support.NextAt)
+	}
+	if _, err := engine.AddEvent(ctx, "guild1", CustomEvent{Name: "Meeting", Rule: once, ChannelID: "channel2", Message: "Meeting now"}); err != nil {
+		t.Fatalf("AddEvent: %v", err)
+	}
+
+	tick := func(at time.Time) {
+		t.Helper()
+		clock.Set(at)
+		if err := engine.Tick(ctx); err != nil {
+			t.Fatalf("Tick: %v", err)
+		}
+	}
+
+	// The one-shot event fires once and is removed
+	tick(jst(2026, time.March, 4, 18, 0))
+	tick(jst(2026, time.March, 4, 18, 1))
+	sent := sender.Sent()
+	if len(sent) != 1 || sent[0] != (sentMessage{ChannelID: "channel2", Content: "Meeting now"}) {
+		t.Fatalf("sent %+v", sent)
+	}
+	events, _ := engine.Events(ctx, "guild1")
+	if len(events) != 1 || events[0].ID != 1 {
+		t.Fatalf("events after one-shot fired: %+v", events)
+	}
+
+	// The daily event fires every day with the role mentioned
+	tick(jst(2026, time.March, 4, 21, 0))
+	tick(jst(2026, time.March, 4, 21, 0).Add(30 * time.Second))
+	tick(jst(2026, time.March, 5, 21, 0))
+	sent = sender.Sent()
+	if len(sent) != 3 {
+		t.Fatalf("sent %d messages, want 3", len(sent))
+	}
+	if want := "<@&role1> Crew support at 03/05 21:00"; sent[2].Content != want {
+		t.Errorf("content = %q, want %q", sent[2].Content, want)
+	}
+
+	// After downtime the missed occurrence is skipped and the next one scheduled
+	tick(jst(2026, time.March, 7, 9, 0))
+	if got := len(sender.Sent()); got != 3 {
+		t.Errorf("sent %d messages after downtime, want 3", got)
+	}
+	events, _ = engine.Events(ctx, "guild1")
+	if !events[0].NextAt.Equal(jst(2026, time.March, 7, 21, 0)) {
+		t.Errorf("NextAt = %s, want 03/07 21:00", events[0].NextAt)
+	}
+
+	if err := engine.RemoveEvent(ctx, "guild1", 1); err != nil {
+		t.Fatalf("RemoveEvent: %v", err)
+	}
+	if err := engine.RemoveEvent(ctx, "guild1", 1); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("second RemoveEvent: got %v, want ErrEventNotFound", err)
+	}
+}
+
+func TestEngine_AddEventValidation(t *testing.T) {
+	ctx := context.Background()
+	engine, _, _, _ := newTestEngine(t, jst(2026, time.March, 4, 12, 0))
+	daily, _ := ParseRule(RuleDaily, "21:00")
+	past, _ := ParseRule(RuleOnce, "2026-03-01 21:00")
+
+	tests := []struct {
+		name  string
+		event CustomEvent
+	}{
+		{"past one-shot", CustomEvent{Name: "Old", Rule: past, ChannelID: "channel1", Message: "hi"}},
+		{"missing channel", CustomEvent{Name: "No channel", Rule: daily, Message: "hi"}},
+		{"empty message", CustomEvent{Name: "Empty", Rule: daily, ChannelID: "channel1", Message: " "}},
+		{"broken template", CustomEvent{Name: "Broken", Rule: daily, ChannelID: "channel1", Message: "{{.Name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := engine.AddEvent(ctx, "guild1", tt.event); err == nil {
+				t.Error("AddEvent succeeded, want an error")
+			}
+		})
+	}
+}
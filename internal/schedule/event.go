@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+// eventsCollection is the storage collection holding each guild's custom events
+const eventsCollection = "custom_events"
+
+// MaxEventsPerGuild limits how many custom events a guild may schedule
+const MaxEventsPerGuild = 25
+
+// ErrEventNotFound is returned when a custom event does not exist
+var ErrEventNotFound = errors.New("no scheduled event with that ID")
+
+// CustomEvent is a guild-defined reminder, such as a crew support skill or a weekly meeting
+type CustomEvent struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Rule      Rule   `json:"rule"`
+	ChannelID string `json:"channel_id"`
+	// RoleID is mentioned at the start of the message when set
+	RoleID string `json:"role_id,omitempty"`
+	// Message is a text/template rendered with EventData
+	Message   string    `json:"message"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// NextAt is when the event fires next; zero once a one-shot event has fired
+	NextAt     time.Time `json:"next_at"`
+	LastSentAt time.Time `json:"last_sent_at,omitempty"`
+}
+
+// EventData is the data available to custom event message templates
+type EventData struct {
+	Name string
+	At   time.Time
+}
+
+// GuildEvents is the stored list of a guild's custom events
+type GuildEvents struct {
+	GuildID string        `json:"guild_id"`
+	LastID  int           `json:"last_id"`
+	Events  []CustomEvent `json:"events"`
+}
+
+// find returns the index of the event with the given ID, or -1
+func (g *GuildEvents) find(id int) int {
+	for i := range g.Events {
+		if g.Events[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseMessageTemplate parses a custom event message template
+func ParseMessageTemplate(source string) (*template.Template, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, errors.New("message is required")
+	}
+	tmpl, err := template.New("message").Funcs(templates.Funcs).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// ValidateEvent checks that a custom event has a channel, a valid message and
+// a rule that fires after now
+func ValidateEvent(event *CustomEvent, now time.Time) error {
+	if strings.TrimSpace(event.Name) == "" {
+		return errors.New("event name is required")
+	}
+	if _, err := ParseMessageTemplate(event.Message); err != nil {
+		return err
+	}
+	if event.ChannelID == "" {
+		return errors.New("a channel is required")
+	}
+	if event.Rule.Next(now).IsZero() {
+		return errors.New("this schedule never fires: the time is in the past")
+	}
+	return nil
+}
+
+// Render renders the message of an event firing at the given time
+func (e *CustomEvent) Render(at time.Time) (string, error) {
+	tmpl, err := ParseMessageTemplate(e.Message)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if e.RoleID != "" {
+		b.WriteString("<@&" + e.RoleID + "> ")
+	}
+	if err := tmpl.Execute(&b, EventData{Name: e.Name, At: at}); err != nil {
+		return "", fmt.Errorf("failed to render message of event %d: %w", e.ID, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,147 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Guild War timeline defaults (JST)
+const (
+	DefaultFinalsDays = 4
+	MaxFinalsDays     = 5
+
+	// gwStartHour is when the preliminaries open on the first day
+	gwStartHour = 19
+	// gwBattleOpenHour is when finals and special battles open each day
+	gwBattleOpenHour = 7
+	// gwFinalsMidOffset is the middle of a finals day (07:00 to 24:00)
+	gwFinalsMidOffset = 8*time.Hour + 30*time.Minute
+)
+
+// NotificationKind identifies a point in a Guild War timeline
+type NotificationKind string
+
+const (
+	KindReminder3Days NotificationKind = "reminder_3d"  // 3 days before the start
+	KindReminder1Day  NotificationKind = "reminder_1d"  // 1 day before the start
+	KindPrelimStart   NotificationKind = "prelim_start" // Preliminaries open
+	KindPrelimEnd     NotificationKind = "prelim_end"   // Preliminaries close
+	KindSpecialStart  NotificationKind = "special_start"
+	KindSpecialEnd    NotificationKind = "special_end"
+	KindFinalsStart   NotificationKind = "finals_start" // Start of each finals day
+	KindFinalsMid     NotificationKind = "finals_mid"   // Middle of each finals day
+	KindFinalsEnd     NotificationKind = "finals_end"   // End of each finals day
+	KindEventEnd      NotificationKind = "event_end"    // Event closes
+)
+
+// GuildWar is a Guild War (古戦場) event.
+//
+// The default timeline, counted in JST days from the start date, is:
+//   - Day 0 19:00 to day 2 00:00: preliminaries
+//   - Day 2 07:00 to day 3 00:00: interlude with special battles
+//   - Day 3 onwards, 07:00 to 24:00: one finals day per day
+//   - The day after the last finals day, 23:59: event end
+type GuildWar struct {
+	// Start is when the preliminaries open
+	Start time.Time `json:"start"`
+	// FinalsDays is the number of finals days
+	FinalsDays int `json:"finals_days"`
+}
+
+// Notification is a single scheduled message of an event
+type Notification struct {
+	// Key uniquely identifies the notification within its event
+	Key  string
+	Kind NotificationKind
+	// Day is the finals day number, or 0 for other notifications
+	Day int
+	At  time.Time
+	// PhaseStart and PhaseEnd bound the phase the notification belongs to
+	PhaseStart time.Time
+	PhaseEnd   time.Time
+}
+
+// NewGuildWar creates a Guild War starting at start with the given number of finals days
+func NewGuildWar(start time.Time, finalsDays int) (GuildWar, error) {
+	if finalsDays == 0 {
+		finalsDays = DefaultFinalsDays
+	}
+	if finalsDays < 1 || finalsDays > MaxFinalsDays {
+		return GuildWar{}, fmt.Errorf("finals days must be between 1 and %d", MaxFinalsDays)
+	}
+	if start.IsZero() {
+		return GuildWar{}, fmt.Errorf("start time is required")
+	}
+	return GuildWar{Start: start.In(JST), FinalsDays: finalsDays}, nil
+}
+
+// ParseGuildWarStart parses a start date in JST; a date alone means 19:00 on that day
+func ParseGuildWarStart(value string) (time.Time, error) {
+	return ParseJST(value, gwStartHour)
+}
+
+// day returns midnight JST of the given day relative to the start date
+func (g GuildWar) day(n int) time.Time {
+	start := g.Start.In(JST)
+	return time.Date(start.Year(), start.Month(), start.Day()+n, 0, 0, 0, 0, JST)
+}
+
+// PrelimEnd returns when the preliminaries close
+func (g GuildWar) PrelimEnd() time.Time {
+	return g.day(2)
+}
+
+// End returns when the event closes
+func (g GuildWar) End() time.Time {
+	return g.day(3 + g.FinalsDays + 1).Add(-time.Minute)
+}
+
+// Notifications returns every notification of the event in chronological order
+func (g GuildWar) Notifications() []Notification {
+	open := time.Duration(gwBattleOpenHour) * time.Hour
+	prelimEnd := g.PrelimEnd()
+	specialStart := g.day(2).Add(open)
+	specialEnd := g.day(3)
+
+	notifications := []Notification{
+		{Key: string(KindReminder3Days), Kind: KindReminder3Days, At: g.Start.Add(-72 * time.Hour), PhaseStart: g.Start, PhaseEnd: prelimEnd},
+		{Key: string(KindReminder1Day), Kind: KindReminder1Day, At: g.Start.Add(-24 * time.Hour), PhaseStart: g.Start, PhaseEnd: prelimEnd},
+		{Key: string(KindPrelimStart), Kind: KindPrelimStart, At: g.Start, PhaseStart: g.Start, PhaseEnd: prelimEnd},
+		{Key: string(KindPrelimEnd), Kind: KindPrelimEnd, At: prelimEnd, PhaseStart: g.Start, PhaseEnd: prelimEnd},
+		{Key: string(KindSpecialStart), Kind: KindSpecialStart, At: specialStart, PhaseStart: specialStart, PhaseEnd: specialEnd},
+		{Key: string(KindSpecialEnd), Kind: KindSpecialEnd, At: specialEnd, PhaseStart: specialStart, PhaseEnd: specialEnd},
+	}
+
+	for day := 1; day <= g.FinalsDays; day++ {
+		start := g.day(2 + day).Add(open)
+		end := g.day(3 + day)
+		for _, n := range []struct {
+			kind NotificationKind
+			at   time.Time
+		}{
+			{KindFinalsStart, start},
+			{KindFinalsMid, g.day(2 + day).Add(open + gwFinalsMidOffset)},
+			{KindFinalsEnd, end},
+		} {
+			notifications = append(notifications, Notification{
+				Key:        fmt.Sprintf("%s_%d", n.kind, day),
+				Kind:       n.kind,
+				Day:        day,
+				At:         n.at,
+				PhaseStart: start,
+				PhaseEnd:   end,
+			})
+		}
+	}
+
+	end := g.End()
+	notifications = append(notifications, Notification{
+		Key: string(KindEventEnd), Kind: KindEventEnd, At: end, PhaseStart: g.Start, PhaseEnd: end,
+	})
+
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].At.Before(notifications[j].At)
+	})
+	return notifications
+}
@@ -0,0 +1,124 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+func jst(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, JST)
+}
+
+func TestGuildWar_Notifications(t *testing.T) {
+	event, err := NewGuildWar(jst(2026, time.March, 1, 19, 0), 4)
+	if err != nil {
+		t.Fatalf("NewGuildWar: %v", err)
+	}
+
+	notifications := event.Notifications()
+	byKey := make(map[string]Notification, len(notifications))
+	for i, notification := range notifications {
+		byKey[notification.Key] = notification
+		if i > 0 && notification.At.Before(notifications[i-1].At) {
+			t.Errorf("notifications out of order at %s", notification.Key)
+		}
+	}
+
+	// 3 reminders/prelims + 2 special + 3 per finals day + event end
+	if want := 6 + 3*4 + 1; len(notifications) != want {
+		t.Errorf("got %d notifications, want %d", len(notifications), want)
+	}
+
+	tests := []struct {
+		key  string
+		want time.Time
+	}{
+		{"reminder_3d", jst(2026, time.February, 26, 19, 0)},
+		{"reminder_1d", jst(2026, time.February, 28, 19, 0)},
+		{"prelim_start", jst(2026, time.March, 1, 19, 0)},
+		{"prelim_end", jst(2026, time.March, 3, 0, 0)},
+		{"special_start", jst(2026, time.March, 3, 7, 0)},
+		{"special_end", jst(2026, time.March, 4, 0, 0)},
+		{"finals_start_1", jst(2026, time.March, 4, 7, 0)},
+		{"finals_mid_1", jst(2026, time.March, 4, 15, 30)},
+		{"finals_end_1", jst(2026, time.March, 5, 0, 0)},
+		{"finals_start_4", jst(2026, time.March, 7, 7, 0)},
+		{"finals_end_4", jst(2026, time.March, 8, 0, 0)},
+		{"event_end", jst(2026, time.March, 8, 23, 59)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			notification, exists := byKey[tt.key]
+			if !exists {
+				t.Fatalf("notification %s not found", tt.key)
+			}
+			if !notification.At.Equal(tt.want) {
+				t.Errorf("At = %s, want %s", FormatJST(notification.At), FormatJST(tt.want))
+			}
+			if _, err := templates.Render(templates.GuildWarNotice(string(notification.Kind)), i18n.English, GuildWarData(event, notification)); err != nil {
+				t.Errorf("Render: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewGuildWar_Validation(t *testing.T) {
+	start := jst(2026, time.March, 1, 19, 0)
+
+	tests := []struct {
+		name       string
+		start      time.Time
+		finalsDays int
+		wantDays   int
+		wantErr    bool
+	}{
+		{"default finals days", start, 0, DefaultFinalsDays, false},
+		{"explicit finals days", start, 5, 5, false},
+		{"too many finals days", start, MaxFinalsDays + 1, 0, true},
+		{"negative finals days", start, -1, 0, true},
+		{"missing start", time.Time{}, 4, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewGuildWar(tt.start, tt.finalsDays)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && event.FinalsDays != tt.wantDays {
+				t.Errorf("FinalsDays = %d, want %d", event.FinalsDays, tt.wantDays)
+			}
+		})
+	}
+}
+
+func TestParseGuildWarStart(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2026-03-01", jst(2026, time.March, 1, 19, 0), false},
+		{"2026/03/01", jst(2026, time.March, 1, 19, 0), false},
+		{"2026-03-01 12:30", jst(2026, time.March, 1, 12, 30), false},
+		{" 2026-03-01T08:00 ", jst(2026, time.March, 1, 8, 0), false},
+		{"March 1st", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseGuildWarStart(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RuleKind is how often a custom event repeats
+type RuleKind string
+
+const (
+	RuleOnce   RuleKind = "once"   // A single date and time
+	RuleDaily  RuleKind = "daily"  // Every day at a time, e.g. "21:00"
+	RuleWeekly RuleKind = "weekly" // Every week on a day at a time, e.g. "sat 21:00"
+	RuleCron   RuleKind = "cron"   // A 5-field cron expression, e.g. "0 21 * * 1-5"
+)
+
+// cronSearchDays bounds how far ahead a cron expression is searched for its next match
+const cronSearchDays = 5 * 366
+
+// weekdayNames maps accepted weekday names to weekdays
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday, "日": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday, "月": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday, "火": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday, "水": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday, "木": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday, "金": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday, "土": time.Saturday,
+}
+
+// Rule decides when a custom event fires. All times are in JST.
+// The spec is kept as entered so that it round-trips through storage unchanged.
+type Rule struct {
+	Kind RuleKind `json:"kind"`
+	Spec string   `json:"spec"`
+}
+
+// ParseRule validates a rule of the given kind
+func ParseRule(kind RuleKind, spec string) (Rule, error) {
+	rule := Rule{Kind: kind, Spec: strings.TrimSpace(spec)}
+	if _, _, err := rule.compile(); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+// Next returns the first time the rule fires strictly after the given time,
+// or the zero time if it never fires again
+func (r Rule) Next(after time.Time) time.Time {
+	once, cron, err := r.compile()
+	if err != nil {
+		return time.Time{}
+	}
+	if cron == nil {
+		if once.After(after) {
+			return once
+		}
+		return time.Time{}
+	}
+	return cron.next(after)
+}
+
+// String describes the rule for display
+func (r Rule) String() string {
+	switch r.Kind {
+	case RuleOnce:
+		if once, _, err := r.compile(); err == nil {
+			return "once at " + FormatJST(once)
+		}
+	case RuleDaily:
+		return "daily at " + r.Spec + " JST"
+	case RuleWeekly:
+		return "weekly on " + r.Spec + " JST"
+	}
+	return fmt.Sprintf("%s `%s`", r.Kind, r.Spec)
+}
+
+// compile turns the rule into either a single time or a cron schedule
+func (r Rule) compile() (time.Time, *cronSchedule, error) {
+	switch r.Kind {
+	case RuleOnce:
+		t, err := ParseJST(r.Spec, 0)
+		return t, nil, err
+	case RuleDaily:
+		hour, minute, err := parseClock(r.Spec)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		cron, err := parseCron(fmt.Sprintf("%d %d * * *", minute, hour))
+		return time.Time{}, cron, err
+	case RuleWeekly:
+		fields := strings.Fields(r.Spec)
+		if len(fields) != 2 {
+			return time.Time{}, nil, fmt.Errorf("invalid weekly rule %q: use <day> HH:MM, e.g. sat 21:00", r.Spec)
+		}
+		weekday, exists := weekdayNames[strings.ToLower(fields[0])]
+		if !exists {
+			return time.Time{}, nil, fmt.Errorf("invalid weekday %q", fields[0])
+		}
+		hour, minute, err := parseClock(fields[1])
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		cron, err := parseCron(fmt.Sprintf("%d %d * * %d", minute, hour, weekday))
+		return time.Time{}, cron, err
+	case RuleCron:
+		cron, err := parseCron(r.Spec)
+		return time.Time{}, cron, err
+	default:
+		return time.Time{}, nil, fmt.Errorf("unknown repeat rule: %s", r.Kind)
+	}
+}
+
+// parseClock parses a time of day in HH:MM format
+func parseClock(value string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q: use HH:MM", value)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// cronSchedule is a parsed cron expression; each field is a bit set of allowed values
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record unrestricted day fields, which change how days match
+	domAny, dowAny bool
+}
+
+// parseCron parses a standard 5-field cron expression (minute hour day-of-month month day-of-week).
+// Each field accepts *, numbers, ranges (1-5), lists (1,3) and steps (*/15).
+func parseCron(spec string) (*cronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields (minute hour day month weekday)", spec)
+	}
+
+	bounds := []struct {
+		name     string
+		min, max int
+	}{
+		{"minute", 0, 59},
+		{"hour", 0, 23},
+		{"day of month", 1, 31},
+		{"month", 1, 12},
+		{"day of week", 0, 7},
+	}
+
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron %s %q: %w", bounds[i].name, field, err)
+		}
+		sets[i] = set
+	}
+
+	// 7 is an alias for Sunday
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = sets[4]&^(1<<7) | 1
+	}
+
+	return &cronSchedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField parses one cron field into a bit set
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		low, high := min, max
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+			n, err := strconv.Atoi(lowPart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", lowPart)
+			}
+			low, high = n, n
+			if isRange {
+				if high, err = strconv.Atoi(highPart); err != nil {
+					return 0, fmt.Errorf("invalid value %q", highPart)
+				}
+			} else if hasStep {
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("out of range %d-%d", min, max)
+		}
+
+		for v := low; v <= high; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// matchesDay reports whether the schedule fires on the given date.
+// As in standard cron, a day matches either day field when both are restricted.
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	if c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next returns the first matching minute strictly after the given time
+func (c *cronSchedule) next(after time.Time) time.Time {
+	from := after.In(JST).Truncate(time.Minute).Add(time.Minute)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, JST)
+
+	for i := 0; i < cronSearchDays; i++ {
+		if c.matchesDay(day) {
+			for hour := 0; hour < 24; hour++ {
+				if c.hour&(1<<hour) == 0 {
+					continue
+				}
+				for minute := 0; minute < 60; minute++ {
+					if c.minute&(1<<minute) == 0 {
+						continue
+					}
+					candidate := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, JST)
+					if !candidate.Before(from) {
+						return candidate
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}
+}
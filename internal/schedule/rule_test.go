@@ -0,0 +1,71 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRule_Next(t *testing.T) {
+	// 2026-03-04 is a Wednesday
+	after := jst(2026, time.March, 4, 12, 0)
+
+	tests := []struct {
+		name string
+		kind RuleKind
+		spec string
+		want time.Time
+	}{
+		{"once in the future", RuleOnce, "2026-03-05 21:00", jst(2026, time.March, 5, 21, 0)},
+		{"once in the past", RuleOnce, "2026-03-01 21:00", time.Time{}},
+		{"daily later today", RuleDaily, "21:00", jst(2026, time.March, 4, 21, 0)},
+		{"daily tomorrow", RuleDaily, "09:30", jst(2026, time.March, 5, 9, 30)},
+		{"daily at the current minute", RuleDaily, "12:00", jst(2026, time.March, 5, 12, 0)},
+		{"weekly this week", RuleWeekly, "sat 21:00", jst(2026, time.March, 7, 21, 0)},
+		{"weekly japanese weekday", RuleWeekly, "月 07:00", jst(2026, time.March, 9, 7, 0)},
+		{"weekly next week", RuleWeekly, "Wednesday 11:00", jst(2026, time.March, 11, 11, 0)},
+		{"cron every 15 minutes", RuleCron, "*/15 * * * *", jst(2026, time.March, 4, 12, 15)},
+		{"cron weekdays", RuleCron, "0 7 * * 1-5", jst(2026, time.March, 5, 7, 0)},
+		{"cron sunday as 7", RuleCron, "0 0 * * 7", jst(2026, time.March, 8, 0, 0)},
+		{"cron day of month", RuleCron, "30 20 1,15 * *", jst(2026, time.March, 15, 20, 30)},
+		{"cron day of month or weekday", RuleCron, "0 8 1 * 5", jst(2026, time.March, 6, 8, 0)},
+		{"cron specific month", RuleCron, "0 0 29 2 *", jst(2028, time.February, 29, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRule(tt.kind, tt.spec)
+			if err != nil {
+				t.Fatalf("ParseRule: %v", err)
+			}
+			if got := rule.Next(after); !got.Equal(tt.want) {
+				t.Errorf("Next = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRule_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		kind RuleKind
+		spec string
+	}{
+		{"bad date", RuleOnce, "tomorrow"},
+		{"bad clock", RuleDaily, "25:00"},
+		{"missing weekday", RuleWeekly, "21:00"},
+		{"unknown weekday", RuleWeekly, "someday 21:00"},
+		{"too few cron fields", RuleCron, "0 21 * *"},
+		{"cron out of range", RuleCron, "60 * * * *"},
+		{"cron bad step", RuleCron, "*/0 * * * *"},
+		{"cron reversed range", RuleCron, "0 5-1 * * *"},
+		{"unknown kind", RuleKind("hourly"), "00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseRule(tt.kind, tt.spec); err == nil {
+				t.Errorf("ParseRule(%s, %q) succeeded, want an error", tt.kind, tt.spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package schedule
+
+import (
+	"strings"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+// GuildWarData returns the template data of a Guild War notification
+func GuildWarData(event GuildWar, notification Notification) templates.GuildWarData {
+	return templates.GuildWarData{
+		Kind:       string(notification.Kind),
+		Day:        notification.Day,
+		At:         notification.At,
+		PhaseStart: notification.PhaseStart,
+		PhaseEnd:   notification.PhaseEnd,
+		EventStart: event.Start,
+		EventEnd:   event.End(),
+	}
+}
+
+// CalendarData returns the template data of a calendar event notice
+func CalendarData(event CalendarEvent, battleNames []string) templates.CalendarData {
+	return templates.CalendarData{
+		Name:    event.Name,
+		Kind:    string(event.Kind),
+		Start:   event.Start,
+		End:     event.End,
+		Battles: strings.Join(battleNames, ", "),
+	}
+}
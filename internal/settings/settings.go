@@ -0,0 +1,180 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+// settingsCollection is the storage collection holding guild settings
+const settingsCollection = "guild_settings"
+
+// GuildSettings holds per-guild bot configuration
+type GuildSettings struct {
+	GuildID string `json:"guild_id"`
+	// NotificationChannelID is where scheduled notifications are posted
+	NotificationChannelID string `json:"notification_channel_id,omitempty"`
+	// Locale is the language of the guild's messages; empty follows each member's Discord language
+	Locale i18n.Locale `json:"locale,omitempty"`
+}
+
+// Manager caches guild settings and persists changes to storage
+type Manager struct {
+	store storage.Store
+
+	mu     sync.RWMutex
+	guilds map[string]*GuildSettings
+	// generation counts the settings swapped in by Apply
+	generation int
+}
+
+// NewManager creates a new guild settings manager
+func NewManager(store storage.Store) *Manager {
+	return &Manager{
+		store:  store,
+		guilds: make(map[string]*GuildSettings),
+	}
+}
+
+// Get returns the settings of a guild, loading them from storage on first use.
+// The returned value is a copy and may be modified freely.
+func (m *Manager) Get(ctx context.Context, guildID string) (GuildSettings, error) {
+	m.mu.RLock()
+	settings, cached := m.guilds[guildID]
+	generation := m.generation
+	m.mu.RUnlock()
+	if cached {
+		return *settings, nil
+	}
+
+	settings = &GuildSettings{GuildID: guildID}
+	err := m.store.Get(ctx, settingsCollection, guildID, settings)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return GuildSettings{}, fmt.Errorf("failed to load settings for guild %s: %w", guildID, err)
+	}
+
+	// Storage was read without the lock. An Update or Apply meanwhile is newer
+	// than what was read, so keep theirs.
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current, cached := m.guilds[guildID]; cached {
+		return *current, nil
+	}
+	if m.generation == generation {
+		m.guilds[guildID] = settings
+	}
+	return *settings, nil
+}
+
+// Update applies fn to the settings of a guild and persists the result
+func (m *Manager) Update(ctx context.Context, guildID string, fn func(settings *GuildSettings)) error {
+	settings, err := m.Get(ctx, guildID)
+	if err != nil {
+		return err
+	}
+
+	fn(&settings)
+	settings.GuildID = guildID
+
+	if err := m.store.Put(ctx, settingsCollection, guildID, &settings); err != nil {
+		return fmt.Errorf("failed to save settings for guild %s: %w", guildID, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.guilds[guildID] = &settings
+	return nil
+}
+
+// GuildLocale returns the locale chosen in a guild's settings, or "" if none is chosen
+func (m *Manager) GuildLocale(ctx context.Context, guildID string) (i18n.Locale, error) {
+	settings, err := m.Get(ctx, guildID)
+	if err != nil {
+		return "", err
+	}
+	return settings.Locale, nil
+}
+
+// List returns the settings of every guild that has saved any
+func (m *Manager) List(ctx context.Context) ([]GuildSettings, error) {
+	guildIDs, err := m.store.Keys(ctx, settingsCollection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list guild settings: %w", err)
+	}
+	sort.Strings(guildIDs)
+
+	list := make([]GuildSettings, 0, len(guildIDs))
+	for _, guildID := range guildIDs {
+		settings, err := m.Get(ctx, guildID)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, settings)
+	}
+	return list, nil
+}
+
+// Snapshot holds guild settings read by Load until they are applied
+type Snapshot struct {
+	guilds map[string]*GuildSettings
+}
+
+// Reload re-reads every guild's settings from storage and swaps them in at once.
+// It returns the IDs of guilds whose settings changed.
+func (m *Manager) Reload(ctx context.Context) ([]string, error) {
+	snapshot, err := m.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.Apply(snapshot), nil
+}
+
+// Load reads every guild's settings from storage without touching the cache
+func (m *Manager) Load(ctx context.Context) (*Snapshot, error) {
+	guildIDs, err := m.store.Keys(ctx, settingsCollection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list guild settings: %w", err)
+	}
+
+	next := make(map[string]*GuildSettings, len(guildIDs))
+	for _, guildID := range guildIDs {
+		settings := &GuildSettings{GuildID: guildID}
+		if err := m.store.Get(ctx, settingsCollection, guildID, settings); err != nil {
+			return nil, fmt.Errorf("failed to load settings for guild %s: %w", guildID, err)
+		}
+		next[guildID] = settings
+	}
+	return &Snapshot{guilds: next}, nil
+}
+
+// Apply swaps in the settings of a snapshot and returns the IDs of guilds whose
+// settings changed
+func (m *Manager) Apply(snapshot *Snapshot) []string {
+	next := snapshot.guilds
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var changed []string
+	for guildID, settings := range next {
+		if old, cached := m.guilds[guildID]; !cached || !reflect.DeepEqual(old, settings) {
+			changed = append(changed, guildID)
+		}
+	}
+	for guildID, old := range m.guilds {
+		if _, exists := next[guildID]; !exists && !reflect.DeepEqual(old, &GuildSettings{GuildID: guildID}) {
+			changed = append(changed, guildID)
+		}
+	}
+	sort.Strings(changed)
+
+	m.guilds = next
+	m.generation++
+	return changed
+}
@@ -0,0 +1,55 @@
+package sheets
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// utf8BOM is written by spreadsheet applications at the start of UTF-8 CSV files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// CSV holds a single sheet per file; the sheet is named after the file, e.g. "calendar.csv"
+type CSV struct{}
+
+// Extension returns ".csv"
+func (CSV) Extension() string {
+	return ".csv"
+}
+
+// Decode reads a CSV file as a workbook with one sheet
+func (CSV) Decode(data []byte, name string) (*Workbook, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("%s:%d: %w", name, parseErr.StartLine, parseErr.Err)
+		}
+		return nil, err
+	}
+
+	sheetName := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	return &Workbook{Sheets: []*Sheet{{Name: sheetName, Rows: rows}}}, nil
+}
+
+// Encode writes the only sheet of a workbook as CSV with a BOM so that
+// spreadsheet applications detect UTF-8
+func (CSV) Encode(workbook *Workbook) ([]byte, error) {
+	if len(workbook.Sheets) != 1 {
+		return nil, fmt.Errorf("a CSV file holds exactly one sheet, got %d", len(workbook.Sheets))
+	}
+
+	var b bytes.Buffer
+	b.Write(utf8BOM)
+	writer := csv.NewWriter(&b)
+	if err := writer.WriteAll(workbook.Sheets[0].Rows); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,821 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/schedule"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+// Sheet names of the workbook layout
+const (
+	// SheetBattles is the shared battle list, merged over the built-in catalog
+	SheetBattles = "battles"
+	// SheetCalendar is the shared game event calendar
+	SheetCalendar = "calendar"
+	// SheetSchedules holds the custom reminders of the importing server
+	SheetSchedules = "schedules"
+	// SheetTemplates holds the message templates of the importing server
+	SheetTemplates = "templates"
+)
+
+// SheetNames lists the sheets in export order
+var SheetNames = []string{SheetBattles, SheetCalendar, SheetSchedules, SheetTemplates}
+
+const (
+	// importedCatalogCollection is the storage collection holding the last imported battles sheet
+	importedCatalogCollection = "battle_catalog"
+	// importedCatalogKey is the key of the imported battles sheet; battles are shared by every guild
+	importedCatalogKey = "imported"
+)
+
+// Columns of each sheet. The battles sheet uses the CSV battle catalog columns.
+var (
+	battleColumns   = []string{"id", "name", "type", "level", "min_rank", "max_players", "element", "raid_code", "honors", "ap_cost", "meat_cost", "clear_time", "active", "aliases", "description"}
+	calendarColumns = []string{"id", "name", "kind", "start", "end", "battles"}
+	scheduleColumns = []string{"id", "name", "repeat", "when", "channel", "role", "message"}
+	// templateColumns include the description for reference; it is ignored on import
+	templateColumns = []string{"name", "text", "description"}
+)
+
+// mentionPattern matches a channel or role mention, or a bare snowflake ID
+var mentionPattern = regexp.MustCompile(`^(?:<#|<@&)?([0-9]+)>?$`)
+
+// RowError is a problem with a row of an imported sheet
+type RowError struct {
+	Sheet   string
+	Row     int
+	Message string
+}
+
+// String returns the error as "sheet:row: message"
+func (e RowError) String() string {
+	switch {
+	case e.Sheet == "":
+		return e.Message
+	case e.Row == 0:
+		return fmt.Sprintf("%s: %s", e.Sheet, e.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.Sheet, e.Row, e.Message)
+}
+
+// SheetResult summarizes the changes an imported sheet makes
+type SheetResult struct {
+	Name    string
+	Rows    int
+	Added   int
+	Updated int
+	Removed int
+}
+
+// Report is the outcome of an import. Nothing is applied when it has errors.
+type Report struct {
+	Sheets []SheetResult
+	Errors []RowError
+	// Ignored lists sheets that are not part of the layout
+	Ignored []string
+	Applied bool
+}
+
+// addError records a problem with a row of a sheet
+func (r *Report) addError(sheet string, row int, format string, args ...any) {
+	r.Errors = append(r.Errors, RowError{Sheet: sheet, Row: row, Message: fmt.Sprintf(format, args...)})
+}
+
+// importedCatalog is the battles sheet of the last import
+type importedCatalog struct {
+	Rows       [][]string `json:"rows"`
+	ImportedBy string     `json:"imported_by,omitempty"`
+	ImportedAt time.Time  `json:"imported_at"`
+}
+
+// Manager imports and exports the battle list, event calendar, custom reminders
+// and message templates as workbooks
+type Manager struct {
+	mu          sync.Mutex
+	store       storage.Store
+	battles     *gbf.BattleManager
+	engine      *schedule.Engine
+	templates   *templates.Manager
+	catalogPath func() string
+	// operatorGuildID returns the only guild allowed to import shared sheets
+	operatorGuildID func() string
+}
+
+// NewManager creates a new import/export manager. catalogPath returns the
+// configured battle catalog file that imported battles are merged over, and
+// operatorGuildID the guild allowed to import the battles and calendar sheets,
+// which are shared by every guild.
+func NewManager(store storage.Store, battleManager *gbf.BattleManager, engine *schedule.Engine, templateManager *templates.Manager, catalogPath, operatorGuildID func() string) *Manager {
+	return &Manager{
+		store:           store,
+		battles:         battleManager,
+		engine:          engine,
+		templates:       templateManager,
+		catalogPath:     catalogPath,
+		operatorGuildID: operatorGuildID,
+	}
+}
+
+// Catalog returns the battle catalog built from catalogPath with the last
+// imported battles sheet applied
+func (m *Manager) Catalog(ctx context.Context, catalogPath string) ([]*gbf.BattleInfo, error) {
+	base, err := gbf.LoadCatalog(catalogPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var imported importedCatalog
+	err = m.store.Get(ctx, importedCatalogCollection, importedCatalogKey, &imported)
+	if errors.Is(err, storage.ErrNotFound) {
+		return base, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load imported battles: %w", err)
+	}
+
+	battles, err := parseBattles(imported.Rows)
+	if err != nil {
+		return nil, fmt.Errorf("invalid imported battles sheet: %w", err)
+	}
+	merged, err := gbf.MergeCatalog(base, battles)
+	if err != nil {
+		return nil, fmt.Errorf("imported battles conflict with the battle catalog: %w", err)
+	}
+	return merged, nil
+}
+
+// Import validates every known sheet of a workbook and, when all rows are valid
+// and dryRun is false, replaces the data of each sheet present.
+// Sheets that are absent are left untouched.
+func (m *Manager) Import(ctx context.Context, guildID, userID string, workbook *Workbook, dryRun bool) (*Report, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	report := &Report{}
+	var battlesSheet, calendarSheet, schedulesSheet, templatesSheet *Sheet
+	for _, sheet := range workbook.Sheets {
+		switch strings.ToLower(strings.TrimSpace(sheet.Name)) {
+		case SheetBattles:
+			battlesSheet = sheet
+		case SheetCalendar:
+			calendarSheet = sheet
+		case SheetSchedules:
+			schedulesSheet = sheet
+		case SheetTemplates:
+			templatesSheet = sheet
+		default:
+			report.Ignored = append(report.Ignored, sheet.Name)
+		}
+	}
+	if battlesSheet == nil && calendarSheet == nil && schedulesSheet == nil && templatesSheet == nil {
+		report.addError("", 0, "no sheet to import: name the sheets or CSV files %s", strings.Join(SheetNames, ", "))
+		return report, nil
+	}
+
+	// The battles and calendar sheets are shared by every guild
+	if err := schedule.CheckOperator(guildID, m.operatorGuildID()); err != nil {
+		for _, sheet := range []*Sheet{battlesSheet, calendarSheet} {
+			if sheet != nil {
+				report.addError(sheet.Name, 0, "this sheet is shared by every server and can only be imported from the operator server")
+			}
+		}
+	}
+
+	// Calendar battles are checked against the catalog being imported
+	catalog := m.battles
+	var battles []*gbf.BattleInfo
+	if battlesSheet != nil {
+		var err error
+		battles, err = m.checkBattles(ctx, battlesSheet, report)
+		if err != nil {
+			return nil, err
+		}
+		if battles != nil {
+			catalog = gbf.NewBattleManager()
+			catalog.Replace(battles)
+		}
+	}
+
+	var calendar []schedule.CalendarEvent
+	if calendarSheet != nil {
+		var err error
+		calendar, err = m.checkCalendar(ctx, calendarSheet, catalog, userID, report)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var events []schedule.CustomEvent
+	if schedulesSheet != nil {
+		var err error
+		events, err = m.checkSchedules(ctx, guildID, schedulesSheet, userID, report)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var texts map[string]string
+	if templatesSheet != nil {
+		var err error
+		texts, err = m.checkTemplates(ctx, guildID, templatesSheet, report)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(report.Errors) > 0 || dryRun {
+		return report, nil
+	}
+
+	if battlesSheet != nil {
+		imported := importedCatalog{Rows: battlesSheet.Rows, ImportedBy: userID, ImportedAt: time.Now()}
+		if err := m.store.Put(ctx, importedCatalogCollection, importedCatalogKey, imported); err != nil {
+			return nil, fmt.Errorf("failed to save imported battles: %w", err)
+		}
+		// The catalog is swapped in with the current event calendar already applied
+		if _, err := m.engine.ReplaceBattles(ctx, battles); err != nil {
+			return nil, err
+		}
+	}
+	if calendarSheet != nil {
+		if err := m.engine.ReplaceCalendar(ctx, calendar); err != nil {
+			return nil, err
+		}
+	}
+	if schedulesSheet != nil {
+		if err := m.engine.ReplaceEvents(ctx, guildID, events); err != nil {
+			return nil, err
+		}
+	}
+	if templatesSheet != nil {
+		if err := m.templates.Replace(ctx, guildID, texts, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	report.Applied = true
+	return report, nil
+}
+
+// checkBattles validates the battles sheet and returns the resulting catalog,
+// or nil if the sheet has errors
+func (m *Manager) checkBattles(ctx context.Context, sheet *Sheet, report *Report) ([]*gbf.BattleInfo, error) {
+	result := SheetResult{Name: SheetBattles, Rows: countRows(sheet)}
+	defer func() { report.Sheets = append(report.Sheets, result) }()
+
+	imported, err := parseBattles(sheet.Rows)
+	if err != nil {
+		addCatalogErrors(report, err)
+		return nil, nil
+	}
+	base, err := gbf.LoadCatalog(m.catalogPath())
+	if err != nil {
+		return nil, err
+	}
+	merged, err := gbf.MergeCatalog(base, imported)
+	if err != nil {
+		addCatalogErrors(report, err)
+		return nil, nil
+	}
+
+	current := make(map[string]bool)
+	for _, battle := range m.battles.GetAllBattles() {
+		current[battle.ID] = true
+	}
+	next := make(map[string]bool, len(merged))
+	for _, battle := range merged {
+		next[battle.ID] = true
+	}
+	for _, battle := range imported {
+		if current[battle.ID] {
+			result.Updated++
+		} else {
+			result.Added++
+		}
+	}
+	for id := range current {
+		if !next[id] {
+			result.Removed++
+		}
+	}
+	return merged, nil
+}
+
+// parseBattles parses the rows of a battles sheet with the CSV catalog parser
+func parseBattles(rows [][]string) ([]*gbf.BattleInfo, error) {
+	width := 0
+	if len(rows) > 0 {
+		width = len(rows[0])
+	}
+
+	var b bytes.Buffer
+	writer := csv.NewWriter(&b)
+	for _, row := range rows {
+		// Blank rows become empty lines, which the parser skips without shifting line numbers
+		if isBlank(row) {
+			row = nil
+		} else if len(row) < width {
+			row = append(row, make([]string, width-len(row))...)
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return gbf.ParseCatalog(b.Bytes(), ".csv", SheetBattles)
+}
+
+// addCatalogErrors adds battle catalog errors to a report
+func addCatalogErrors(report *Report, err error) {
+	var errs gbf.CatalogErrors
+	if !errors.As(err, &errs) {
+		report.addError(SheetBattles, 0, "%v", err)
+		return
+	}
+	for _, e := range errs {
+		report.addError(SheetBattles, e.Line, "%s", e.Message)
+	}
+}
+
+// checkCalendar validates the calendar sheet and returns its events
+func (m *Manager) checkCalendar(ctx context.Context, sheet *Sheet, catalog *gbf.BattleManager, userID string, report *Report) ([]schedule.CalendarEvent, error) {
+	result := SheetResult{Name: SheetCalendar, Rows: countRows(sheet)}
+	defer func() { report.Sheets = append(report.Sheets, result) }()
+
+	columns, ok := checkHeader(sheet, calendarColumns, []string{"name", "kind", "start", "end"}, report)
+	if !ok {
+		return nil, nil
+	}
+
+	current, err := m.engine.CalendarEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[int]bool, len(current))
+	for _, event := range current {
+		existing[event.ID] = true
+	}
+
+	var events []schedule.CalendarEvent
+	seen := make(map[int]int)
+	for index, row := range sheet.Rows[1:] {
+		line := index + 2
+		if isBlank(row) {
+			continue
+		}
+		cell := columns.reader(row)
+
+		id, err := parseID(cell("id"))
+		if err != nil {
+			report.addError(SheetCalendar, line, "%v", err)
+			continue
+		}
+		if first, exists := seen[id]; exists && id != 0 {
+			report.addError(SheetCalendar, line, "duplicate id %d (first used at row %d)", id, first)
+			continue
+		}
+		seen[id] = line
+
+		start, err := schedule.ParseJST(cell("start"), schedule.DefaultEventStartHour)
+		if err != nil {
+			report.addError(SheetCalendar, line, "start: %v", err)
+			continue
+		}
+		end, err := schedule.ParseJSTEnd(cell("end"))
+		if err != nil {
+			report.addError(SheetCalendar, line, "end: %v", err)
+			continue
+		}
+
+		event := schedule.CalendarEvent{
+			ID:        id,
+			Name:      cell("name"),
+			Kind:      schedule.CalendarKind(strings.ToLower(cell("kind"))),
+			Start:     start,
+			End:       end,
+			BattleIDs: splitList(cell("battles")),
+			CreatedBy: userID,
+		}
+		if err := schedule.ValidateCalendarEvent(&event, catalog); err != nil {
+			report.addError(SheetCalendar, line, "%v", err)
+			continue
+		}
+		if existing[id] {
+			result.Updated++
+		} else {
+			result.Added++
+		}
+		events = append(events, event)
+	}
+	for id := range existing {
+		if _, kept := seen[id]; !kept {
+			result.Removed++
+		}
+	}
+	return events, nil
+}
+
+// checkSchedules validates the schedules sheet and returns its custom events
+func (m *Manager) checkSchedules(ctx context.Context, guildID string, sheet *Sheet, userID string, report *Report) ([]schedule.CustomEvent, error) {
+	result := SheetResult{Name: SheetSchedules, Rows: countRows(sheet)}
+	defer func() { report.Sheets = append(report.Sheets, result) }()
+
+	columns, ok := checkHeader(sheet, scheduleColumns, []string{"name", "repeat", "when", "channel", "message"}, report)
+	if !ok {
+		return nil, nil
+	}
+	if result.Rows > schedule.MaxEventsPerGuild {
+		report.addError(SheetSchedules, 0, "a server can have at most %d scheduled events, got %d", schedule.MaxEventsPerGuild, result.Rows)
+		return nil, nil
+	}
+
+	current, err := m.engine.Events(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[int]bool, len(current))
+	for _, event := range current {
+		existing[event.ID] = true
+	}
+
+	now := m.engine.Now()
+	var events []schedule.CustomEvent
+	seen := make(map[int]int)
+	for index, row := range sheet.Rows[1:] {
+		line := index + 2
+		if isBlank(row) {
+			continue
+		}
+		cell := columns.reader(row)
+
+		id, err := parseID(cell("id"))
+		if err != nil {
+			report.addError(SheetSchedules, line, "%v", err)
+			continue
+		}
+		if first, exists := seen[id]; exists && id != 0 {
+			report.addError(SheetSchedules, line, "duplicate id %d (first used at row %d)", id, first)
+			continue
+		}
+		seen[id] = line
+
+		rule, err := schedule.ParseRule(schedule.RuleKind(strings.ToLower(cell("repeat"))), cell("when"))
+		if err != nil {
+			report.addError(SheetSchedules, line, "%v", err)
+			continue
+		}
+		channelID, err := parseMention("channel", cell("channel"))
+		if err != nil {
+			report.addError(SheetSchedules, line, "%v", err)
+			continue
+		}
+		roleID, err := parseMention("role", cell("role"))
+		if err != nil {
+			report.addError(SheetSchedules, line, "%v", err)
+			continue
+		}
+
+		event := schedule.CustomEvent{
+			ID:        id,
+			Name:      cell("name"),
+			Rule:      rule,
+			ChannelID: channelID,
+			RoleID:    roleID,
+			Message:   cell("message"),
+			CreatedBy: userID,
+		}
+		if err := schedule.ValidateEvent(&event, now); err != nil {
+			report.addError(SheetSchedules, line, "%v", err)
+			continue
+		}
+		if existing[id] {
+			result.Updated++
+		} else {
+			result.Added++
+		}
+		events = append(events, event)
+	}
+	for id := range existing {
+		if _, kept := seen[id]; !kept {
+			result.Removed++
+		}
+	}
+	return events, nil
+}
+
+// checkTemplates validates the templates sheet and returns the text of each
+// listed template. Only texts that differ from the built-in default are counted
+// as changes, since an export lists every template.
+func (m *Manager) checkTemplates(ctx context.Context, guildID string, sheet *Sheet, report *Report) (map[string]string, error) {
+	result := SheetResult{Name: SheetTemplates, Rows: countRows(sheet)}
+	defer func() { report.Sheets = append(report.Sheets, result) }()
+
+	columns, ok := checkHeader(sheet, templateColumns, []string{"name", "text"}, report)
+	if !ok {
+		return nil, nil
+	}
+
+	current, err := m.templates.Overrides(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := make(map[string]string)
+	seen := make(map[string]int)
+	for index, row := range sheet.Rows[1:] {
+		line := index + 2
+		if isBlank(row) {
+			continue
+		}
+		cell := columns.reader(row)
+
+		name := cell("name")
+		definition, exists := templates.Lookup(name)
+		if !exists {
+			report.addError(SheetTemplates, line, "unknown template %q", name)
+			continue
+		}
+		if first, exists := seen[name]; exists {
+			report.addError(SheetTemplates, line, "duplicate template %s (first used at row %d)", name, first)
+			continue
+		}
+		seen[name] = line
+
+		// Cells are trimmed, so the raw text keeps intended leading and trailing line breaks
+		text := ""
+		if i, exists := columns["text"]; exists && i < len(row) {
+			text = row[i]
+		}
+		// Exports carry the defaults in the guild's language, which are not customizations
+		if strings.TrimSpace(text) == "" || definition.IsDefault(text) {
+			text = ""
+		} else if err := templates.Validate(name, text); err != nil {
+			report.addError(SheetTemplates, line, "%v", err)
+			continue
+		}
+		texts[name] = text
+
+		old, customized := current[name]
+		switch {
+		case text == "" && customized:
+			result.Removed++
+		case text != "" && !customized:
+			result.Added++
+		case text != "" && old.Text != text:
+			result.Updated++
+		}
+	}
+	for name := range current {
+		if _, kept := seen[name]; !kept {
+			result.Removed++
+		}
+	}
+	return texts, nil
+}
+
+// columnIndex maps column names to their position in a sheet
+type columnIndex map[string]int
+
+// reader returns a function reading trimmed cells of a row by column name
+func (c columnIndex) reader(row []string) func(name string) string {
+	return func(name string) string {
+		i, exists := c[name]
+		if !exists || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+}
+
+// checkHeader validates the header of a sheet against its known and required columns
+func checkHeader(sheet *Sheet, known, required []string, report *Report) (columnIndex, bool) {
+	header := sheet.Header()
+	if header == nil {
+		report.addError(sheet.Name, 0, "missing header row: expected %s", strings.Join(known, ","))
+		return nil, false
+	}
+
+	columns := make(columnIndex, len(header))
+	ok := true
+	for i, column := range header {
+		if column == "" {
+			continue
+		}
+		if !slices.Contains(known, column) {
+			report.addError(sheet.Name, 1, "unknown column %q", column)
+			ok = false
+			continue
+		}
+		columns[column] = i
+	}
+	for _, column := range required {
+		if _, exists := columns[column]; !exists {
+			report.addError(sheet.Name, 1, "missing column %q", column)
+			ok = false
+		}
+	}
+	return columns, ok
+}
+
+// countRows returns the number of non-blank data rows of a sheet
+func countRows(sheet *Sheet) int {
+	count := 0
+	for i, row := range sheet.Rows {
+		if i > 0 && !isBlank(row) {
+			count++
+		}
+	}
+	return count
+}
+
+// isBlank reports whether every cell of a row is empty
+func isBlank(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseID parses an optional numeric ID; empty means a new entry
+func parseID(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid id %q: leave it empty for new entries", value)
+	}
+	return id, nil
+}
+
+// parseMention returns the ID of a channel or role given as a mention or a bare ID
+func parseMention(kind, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	match := mentionPattern.FindStringSubmatch(value)
+	if match == nil {
+		return "", fmt.Errorf("invalid %s %q: use a %s ID", kind, value, kind)
+	}
+	return match[1], nil
+}
+
+// splitList splits a comma or "|" separated list, dropping empty items
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == '|' || r == ' '
+	})
+}
+
+// Export returns the current battle list, event calendar and the custom
+// reminders and message templates of a guild as a workbook. Templates the guild
+// has not customized are written in locale.
+func (m *Manager) Export(ctx context.Context, guildID string, locale i18n.Locale) (*Workbook, error) {
+	calendar, err := m.engine.CalendarEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events, err := m.engine.Events(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := m.templates.Overrides(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Workbook{Sheets: []*Sheet{
+		battlesSheet(m.battles.GetAllBattles()),
+		calendarSheet(calendar),
+		schedulesSheet(events),
+		templatesSheet(overrides, locale),
+	}}, nil
+}
+
+// battlesSheet lays out battles in the catalog columns, with a name_<locale>
+// column for every localized name
+func battlesSheet(battles []*gbf.BattleInfo) *Sheet {
+	localeSet := make(map[string]bool)
+	for _, battle := range battles {
+		for locale := range battle.Names {
+			localeSet[locale] = true
+		}
+	}
+	locales := make([]string, 0, len(localeSet))
+	for locale := range localeSet {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	header := []string{"id", "name"}
+	for _, locale := range locales {
+		header = append(header, "name_"+locale)
+	}
+	header = append(header, battleColumns[2:]...)
+
+	sheet := &Sheet{Name: SheetBattles, Rows: [][]string{header}}
+	for _, battle := range battles {
+		row := []string{battle.ID, battle.Name}
+		for _, locale := range locales {
+			row = append(row, battle.Names[locale])
+		}
+		row = append(row,
+			string(battle.Type),
+			strconv.Itoa(battle.Level),
+			strconv.Itoa(battle.MinRank),
+			strconv.Itoa(battle.MaxPlayers),
+			string(battle.Element),
+			battle.RaidCode,
+		)
+		// Guild War columns are empty for other battles
+		guildWar := make([]string, 4)
+		if gw := battle.GuildWar; gw != nil {
+			guildWar = []string{
+				strconv.Itoa(gw.Honors),
+				strconv.Itoa(gw.APCost),
+				strconv.Itoa(gw.MeatCost),
+				strconv.Itoa(int(gw.ClearTime.Seconds())),
+			}
+		}
+		row = append(row, guildWar...)
+		row = append(row,
+			strconv.FormatBool(battle.IsActive),
+			strings.Join(battle.Aliases, "|"),
+			battle.Description,
+		)
+		sheet.Rows = append(sheet.Rows, row)
+	}
+	return sheet
+}
+
+// calendarSheet lays out game events in the calendar columns
+func calendarSheet(events []schedule.CalendarEvent) *Sheet {
+	sheet := &Sheet{Name: SheetCalendar, Rows: [][]string{calendarColumns}}
+	for _, event := range events {
+		sheet.Rows = append(sheet.Rows, []string{
+			strconv.Itoa(event.ID),
+			event.Name,
+			string(event.Kind),
+			formatTime(event.Start),
+			formatTime(event.End),
+			strings.Join(event.BattleIDs, ","),
+		})
+	}
+	return sheet
+}
+
+// schedulesSheet lays out custom events in the schedule columns
+func schedulesSheet(events []schedule.CustomEvent) *Sheet {
+	sheet := &Sheet{Name: SheetSchedules, Rows: [][]string{scheduleColumns}}
+	for _, event := range events {
+		sheet.Rows = append(sheet.Rows, []string{
+			strconv.Itoa(event.ID),
+			event.Name,
+			string(event.Rule.Kind),
+			event.Rule.Spec,
+			event.ChannelID,
+			event.RoleID,
+			event.Message,
+		})
+	}
+	return sheet
+}
+
+// templatesSheet lists every template with the text the guild uses
+func templatesSheet(overrides map[string]templates.Override, locale i18n.Locale) *Sheet {
+	sheet := &Sheet{Name: SheetTemplates, Rows: [][]string{templateColumns}}
+	for _, definition := range templates.Definitions() {
+		definition = definition.In(locale)
+		text := definition.Text
+		if override, exists := overrides[definition.Name]; exists {
+			text = override.Text
+		}
+		sheet.Rows = append(sheet.Rows, []string{definition.Name, text, definition.Description})
+	}
+	return sheet
+}
+
+// formatTime formats a time in the JST input format accepted on import
+func formatTime(t time.Time) string {
+	return t.In(schedule.JST).Format("2006-01-02 15:04")
+}
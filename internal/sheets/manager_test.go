@@ -0,0 +1,316 @@
+package sheets
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/varubogu/gbf_discord_bot_go/internal/gbf"
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/schedule"
+	"github.com/varubogu/gbf_discord_bot_go/internal/settings"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+	"github.com/varubogu/gbf_discord_bot_go/internal/templates"
+)
+
+const testGuildID = "guild1"
+
+// fixedClock is a Clock that always returns the same time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// nopSender discards scheduled notifications
+type nopSender struct{}
+
+func (nopSender) ChannelMessageSend(channelID, content string) (*discordgo.Message, error) {
+	return nil, nil
+}
+
+func newTestManager(t *testing.T) (*Manager, *gbf.BattleManager, *schedule.Engine, *templates.Manager) {
+	t.Helper()
+	store := storage.NewMemoryStore()
+	battles := gbf.NewBattleManager()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, schedule.JST)
+	logger := log.InitLogger("error")
+	templateManager := templates.NewManager(store, logger)
+	engine := schedule.NewEngine(store, settings.NewManager(store), battles, templateManager, nopSender{}, fixedClock(now), logger)
+	return NewManager(store, battles, engine, templateManager, func() string { return "" }, func() string { return testGuildID }), battles, engine, templateManager
+}
+
+// errorLines returns the errors of a report as strings
+func errorLines(report *Report) []string {
+	var lines []string
+	for _, e := range report.Errors {
+		lines = append(lines, e.String())
+	}
+	return lines
+}
+
+func TestManager_ImportErrors(t *testing.T) {
+	manager, _, engine, _ := newTestManager(t)
+	ctx := context.Background()
+
+	workbook := &Workbook{Sheets: []*Sheet{
+		{Name: "battles", Rows: [][]string{
+			{"id", "name", "type", "level", "max_players"},
+			{"my_raid", "My Raid", "custom", "150", "6"},
+			{"broken", "Broken", "raid", "150"},
+		}},
+		{Name: "Calendar", Rows: [][]string{
+			{"id", "name", "kind", "start", "end", "battles"},
+			{"", "Guild War", "guild_war", "2026-03-10", "2026-03-17"},
+			{"", "Bad dates", "other", "2026-03-10", "2026-03-01"},
+			{"x", "Bad id", "other", "2026-03-10", "2026-03-11"},
+			{"", "Unknown battle", "other", "2026-03-10", "2026-03-11", "nope"},
+		}},
+		{Name: "schedules", Rows: [][]string{
+			{"id", "name", "repeat", "when", "channel", "role", "message", "note"},
+		}},
+		{Name: "notes", Rows: [][]string{{"anything"}}},
+	}}
+
+	report, err := manager.Import(ctx, testGuildID, "admin1", workbook, false)
+	if err != nil {
+		t.Fatalf("Import unexpected error: %v", err)
+	}
+	expected := []string{
+		`battles:3: invalid type "raid"`,
+		"battles:3: max_players must be between 1 and 30",
+		"calendar:3: the event must end after it starts",
+		`calendar:4: invalid id "x": leave it empty for new entries`,
+		"calendar:5: battle not found: nope",
+		`schedules:1: unknown column "note"`,
+	}
+	if got := errorLines(report); !slices.Equal(got, expected) {
+		t.Errorf("Import errors =\n%s\nexpected\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+	if report.Applied {
+		t.Error("an import with errors must not be applied")
+	}
+	if !slices.Equal(report.Ignored, []string{"notes"}) {
+		t.Errorf("Ignored = %v, expected [notes]", report.Ignored)
+	}
+	if events, _ := engine.CalendarEvents(ctx); len(events) != 0 {
+		t.Errorf("calendar changed despite errors: %+v", events)
+	}
+
+	report, err = manager.Import(ctx, testGuildID, "admin1", &Workbook{Sheets: []*Sheet{{Name: "other"}}}, false)
+	if err != nil {
+		t.Fatalf("Import unexpected error: %v", err)
+	}
+	if len(report.Errors) != 1 || !strings.HasPrefix(report.Errors[0].Message, "no sheet to import") {
+		t.Errorf("Import errors = %v, expected no sheet to import", errorLines(report))
+	}
+}
+
+func TestManager_ImportSharedSheets(t *testing.T) {
+	manager, battles, engine, _ := newTestManager(t)
+	ctx := context.Background()
+
+	workbook := &Workbook{Sheets: []*Sheet{
+		{Name: "battles", Rows: [][]string{
+			{"id", "name", "type", "level", "max_players"},
+			{"my_raid", "My Raid", "custom", "150", "6"},
+		}},
+		{Name: "calendar", Rows: [][]string{
+			{"name", "kind", "start", "end"},
+			{"Guild War", "guild_war", "2026-03-10", "2026-03-17"},
+		}},
+	}}
+
+	// Only the operator guild may replace data shared by every guild
+	report, err := manager.Import(ctx, "guild2", "admin1", workbook, false)
+	if err != nil {
+		t.Fatalf("Import unexpected error: %v", err)
+	}
+	expected := []string{
+		"battles: this sheet is shared by every server and can only be imported from the operator server",
+		"calendar: this sheet is shared by every server and can only be imported from the operator server",
+	}
+	if got := errorLines(report); !slices.Equal(got, expected) || report.Applied {
+		t.Errorf("Import errors = %v, expected %v", got, expected)
+	}
+	if _, err := battles.GetBattle("my_raid"); err == nil {
+		t.Error("another guild must not change the catalog")
+	}
+	if events, _ := engine.CalendarEvents(ctx); len(events) != 0 {
+		t.Errorf("another guild must not change the calendar: %+v", events)
+	}
+}
+
+func TestManager_ImportApply(t *testing.T) {
+	manager, battles, engine, _ := newTestManager(t)
+	ctx := context.Background()
+
+	// The calendar may refer to a battle added by the same import
+	workbook := &Workbook{Sheets: []*Sheet{
+		{Name: "battles", Rows: [][]string{
+			{"id", "name", "name_ja", "type", "level", "max_players", "aliases", "active"},
+			{"my_raid", "My Raid", "マイレイド", "custom", "150", "6", "mine", "false"},
+		}},
+		{Name: "calendar", Rows: [][]string{
+			{"name", "kind", "start", "end", "battles"},
+			{"Special Event", "other", "2026-02-28", "2026-03-05", "mine"},
+		}},
+		{Name: "schedules", Rows: [][]string{
+			{"name", "repeat", "when", "channel", "role", "message"},
+			{"Crew support", "daily", "21:00", "<#123>", "<@&456>", "{{.Name}} at {{jst .At}}"},
+			{},
+		}},
+	}}
+
+	report, err := manager.Import(ctx, testGuildID, "admin1", workbook, true)
+	if err != nil {
+		t.Fatalf("Import unexpected error: %v", err)
+	}
+	if len(report.Errors) != 0 || report.Applied {
+		t.Fatalf("dry run report = %+v", report)
+	}
+	if _, err := battles.GetBattle("my_raid"); err == nil {
+		t.Fatal("a dry run must not change the catalog")
+	}
+
+	report, err = manager.Import(ctx, testGuildID, "admin1", workbook, false)
+	if err != nil {
+		t.Fatalf("Import unexpected error: %v", err)
+	}
+	if len(report.Errors) != 0 || !report.Applied {
+		t.Fatalf("import report errors = %v", errorLines(report))
+	}
+	for _, result := range report.Sheets {
+		if result.Rows != 1 || result.Added != 1 || result.Updated != 0 || result.Removed != 0 {
+			t.Errorf("sheet result = %+v, expected one added row", result)
+		}
+	}
+
+	battle, err := battles.GetBattle("mine")
+	if err != nil {
+		t.Fatalf("GetBattle(mine) unexpected error: %v", err)
+	}
+	if !battle.IsActive || battle.Names["ja"] != "マイレイド" {
+		t.Errorf("imported battle = %+v, expected it to be activated by the running calendar event", battle)
+	}
+	events, _ := engine.Events(ctx, testGuildID)
+	if len(events) != 1 || events[0].ChannelID != "123" || events[0].RoleID != "456" || events[0].ID != 1 {
+		t.Errorf("imported events = %+v", events)
+	}
+
+	// Imported battles survive a reload of the catalog
+	catalog, err := manager.Catalog(ctx, "")
+	if err != nil {
+		t.Fatalf("Catalog unexpected error: %v", err)
+	}
+	if !slices.ContainsFunc(catalog, func(b *gbf.BattleInfo) bool { return b.ID == "my_raid" }) {
+		t.Error("Catalog should include the imported battles")
+	}
+
+	// Exported data imports back unchanged
+	exported, err := manager.Export(ctx, testGuildID, i18n.English)
+	if err != nil {
+		t.Fatalf("Export unexpected error: %v", err)
+	}
+	var names []string
+	for _, sheet := range exported.Sheets {
+		names = append(names, sheet.Name)
+	}
+	if !slices.Equal(names, SheetNames) {
+		t.Errorf("exported sheets = %v, expected %v", names, SheetNames)
+	}
+	report, err = manager.Import(ctx, testGuildID, "admin1", exported, false)
+	if err != nil {
+		t.Fatalf("Import unexpected error: %v", err)
+	}
+	if len(report.Errors) != 0 {
+		t.Fatalf("re-import errors = %v", errorLines(report))
+	}
+	calendar := report.Sheets[1]
+	if calendar.Updated != 1 || calendar.Added != 0 || calendar.Removed != 0 {
+		t.Errorf("re-imported calendar = %+v, expected one updated event", calendar)
+	}
+	if reimported, _ := engine.Events(ctx, testGuildID); len(reimported) != 1 || reimported[0].ID != 1 || !reimported[0].NextAt.Equal(events[0].NextAt) {
+		t.Errorf("re-imported events = %+v, expected the same event", reimported)
+	}
+
+	// An empty sheet removes everything it holds
+	report, err = manager.Import(ctx, testGuildID, "admin1", &Workbook{Sheets: []*Sheet{
+		{Name: "schedules", Rows: [][]string{scheduleColumns}},
+	}}, false)
+	if err != nil {
+		t.Fatalf("Import unexpected error: %v", err)
+	}
+	if report.Sheets[0].Removed != 1 {
+		t.Errorf("schedules result = %+v, expected one removed event", report.Sheets[0])
+	}
+	if events, _ := engine.Events(ctx, testGuildID); len(events) != 0 {
+		t.Errorf("events = %+v, expected none", events)
+	}
+}
+
+func TestManager_ImportTemplates(t *testing.T) {
+	manager, _, _, templateManager := newTestManager(t)
+	ctx := context.Background()
+	if err := templateManager.Set(ctx, testGuildID, templates.CalendarEnd, "{{.Name}} 終了", "admin1"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	posted, _ := templates.Lookup(templates.RecruitmentPosted)
+
+	workbook := &Workbook{Sheets: []*Sheet{{Name: "templates", Rows: [][]string{
+		{"name", "text"},
+		{templates.RecruitmentPosted, posted.Text},
+		{templates.CalendarStart, "{{.Name}} 開始\n{{.Battles}}"},
+		{"recruitment.cancelled", "text"},
+		{templates.PermissionDenied, "{{.Reson}}"},
+		{templates.CalendarStart, "again"},
+	}}}}
+	report, err := manager.Import(ctx, testGuildID, "admin1", workbook, false)
+	if err != nil {
+		t.Fatalf("Import unexpected error: %v", err)
+	}
+	expected := []string{
+		`templates:4: unknown template "recruitment.cancelled"`,
+		"templates:5: failed to render permission.denied: template: permission.denied:1:2: executing \"permission.denied\" at <.Reson>: can't evaluate field Reson in type templates.PermissionData",
+		"templates:6: duplicate template calendar.start (first used at row 3)",
+	}
+	if got := errorLines(report); !slices.Equal(got, expected) {
+		t.Errorf("Import errors =\n%s\nexpected\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+
+	workbook.Sheets[0].Rows = workbook.Sheets[0].Rows[:3]
+	report, err = manager.Import(ctx, testGuildID, "admin1", workbook, false)
+	if err != nil {
+		t.Fatalf("Import unexpected error: %v", err)
+	}
+	if len(report.Errors) != 0 || !report.Applied {
+		t.Fatalf("import report errors = %v", errorLines(report))
+	}
+	// The default text is not an override and calendar.end is no longer listed
+	if result := report.Sheets[0]; result.Rows != 2 || result.Added != 1 || result.Updated != 0 || result.Removed != 1 {
+		t.Errorf("templates result = %+v, expected one added and one removed", result)
+	}
+	overrides, _ := templateManager.Overrides(ctx, testGuildID)
+	if len(overrides) != 1 || overrides[templates.CalendarStart].Text != "{{.Name}} 開始\n{{.Battles}}" {
+		t.Errorf("overrides = %+v", overrides)
+	}
+
+	// Exports list every template with the text in use
+	exported, err := manager.Export(ctx, testGuildID, i18n.English)
+	if err != nil {
+		t.Fatalf("Export unexpected error: %v", err)
+	}
+	sheet := exported.Sheet(SheetTemplates)
+	if sheet == nil || len(sheet.Rows) != len(templates.Definitions())+1 {
+		t.Fatalf("exported templates sheet = %+v", sheet)
+	}
+	for _, row := range sheet.Rows[1:] {
+		if row[0] == templates.CalendarStart && row[1] != "{{.Name}} 開始\n{{.Battles}}" {
+			t.Errorf("exported calendar.start = %q, expected the override", row[1])
+		}
+	}
+}
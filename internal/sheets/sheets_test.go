@@ -0,0 +1,156 @@
+package sheets
+
+import (
+	"archive/zip"
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestXLSX_RoundTrip(t *testing.T) {
+	workbook := &Workbook{Sheets: []*Sheet{
+		{Name: "battles", Rows: [][]string{
+			{"id", "name", "level"},
+			{"faa_hl", "Lucilius <Hard> & \"friends\"", "200"},
+			{"", "", ""},
+			{"baha_hl", "プロトバハムート\nHL", "0150"},
+		}},
+		{Name: "calendar", Rows: [][]string{{"id", "name"}}},
+	}}
+
+	data, err := XLSX{}.Encode(workbook)
+	if err != nil {
+		t.Fatalf("Encode unexpected error: %v", err)
+	}
+	decoded, err := XLSX{}.Decode(data, "data.xlsx")
+	if err != nil {
+		t.Fatalf("Decode unexpected error: %v", err)
+	}
+
+	if len(decoded.Sheets) != 2 || decoded.Sheets[0].Name != "battles" || decoded.Sheets[1].Name != "calendar" {
+		t.Fatalf("decoded sheets = %+v", decoded.Sheets)
+	}
+	rows := decoded.Sheets[0].Rows
+	expected := [][]string{
+		{"id", "name", "level"},
+		{"faa_hl", "Lucilius <Hard> & \"friends\"", "200"},
+		nil,
+		{"baha_hl", "プロトバハムート\nHL", "0150"},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("decoded %d rows, expected %d: %q", len(rows), len(expected), rows)
+	}
+	for i := range expected {
+		if !slices.Equal(rows[i], expected[i]) {
+			t.Errorf("row %d = %q, expected %q", i+1, rows[i], expected[i])
+		}
+	}
+}
+
+func TestXLSX_DecodeSpreadsheetFeatures(t *testing.T) {
+	// A minimal workbook as saved by a spreadsheet application: shared strings,
+	// rich text runs, skipped cells, booleans and date-formatted numbers
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="` + xlsxMainNS + `" xmlns:r="` + xlsxRelationshipsNS + `">` +
+			`<sheets><sheet name="Calendar" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="` + xlsxPackageRelsNS + `">` +
+			`<Relationship Id="rId3" Type="` + xlsxRelationshipsNS + `/worksheet" Target="/xl/worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="` + xlsxMainNS + `"><si><t>name</t></si><si><t>start</t></si>` +
+			`<si><r><t>Guild </t></r><r><t>War</t></r></si></sst>`,
+		"xl/styles.xml": `<styleSheet xmlns="` + xlsxMainNS + `"><numFmts><numFmt numFmtId="164" formatCode="yyyy/mm/dd hh:mm"/></numFmts>` +
+			`<cellXfs><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/></cellXfs></styleSheet>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="` + xlsxMainNS + `"><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+			`<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3" t="b"><v>1</v></c><c r="C3" s="1"><v>46082</v></c><c r="D3" s="2"><v>46082.8125</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	var b bytes.Buffer
+	archive := zip.NewWriter(&b)
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	workbook, err := XLSX{}.Decode(b.Bytes(), "data.xlsx")
+	if err != nil {
+		t.Fatalf("Decode unexpected error: %v", err)
+	}
+	sheet := workbook.Sheet("calendar")
+	if sheet == nil {
+		t.Fatal("Sheet(calendar) should match case-insensitively")
+	}
+	expected := [][]string{
+		{"name", "", "start"},
+		nil,
+		{"Guild War", "true", "2026-03-01", "2026-03-01 19:30"},
+	}
+	if len(sheet.Rows) != len(expected) {
+		t.Fatalf("decoded rows = %q", sheet.Rows)
+	}
+	for i := range expected {
+		if !slices.Equal(sheet.Rows[i], expected[i]) {
+			t.Errorf("row %d = %q, expected %q", i+1, sheet.Rows[i], expected[i])
+		}
+	}
+}
+
+func TestCSV(t *testing.T) {
+	data := append([]byte{0xEF, 0xBB, 0xBF}, "id,name\n1,\"団, サポート\"\n2\n"...)
+	workbook, err := CSV{}.Decode(data, "uploads/Schedules.csv")
+	if err != nil {
+		t.Fatalf("Decode unexpected error: %v", err)
+	}
+	if workbook.Sheet("schedules") == nil {
+		t.Fatalf("sheet should be named after the file, got %q", workbook.Sheets[0].Name)
+	}
+	rows := workbook.Sheets[0].Rows
+	if len(rows) != 3 || rows[0][0] != "id" || rows[1][1] != "団, サポート" || len(rows[2]) != 1 {
+		t.Errorf("decoded rows = %q", rows)
+	}
+
+	encoded, err := CSV{}.Encode(workbook)
+	if err != nil {
+		t.Fatalf("Encode unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(encoded, utf8BOM) {
+		t.Error("encoded CSV should start with a BOM")
+	}
+
+	if _, err := (CSV{}).Decode([]byte("id,name\n1,\"open\n"), "battles.csv"); err == nil {
+		t.Error("Decode expected error for an unterminated quote")
+	}
+	if _, err := (CSV{}).Encode(&Workbook{Sheets: []*Sheet{{}, {}}}); err == nil {
+		t.Error("Encode expected error for more than one sheet")
+	}
+}
+
+func TestFormatFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		wantErr  bool
+	}{
+		{"data.xlsx", ".xlsx", false},
+		{"Calendar.CSV", ".csv", false},
+		{"csv", ".csv", false},
+		{"data.ods", "", true},
+	}
+	for _, tt := range tests {
+		format, err := FormatFor(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("FormatFor(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && format.Extension() != tt.expected {
+			t.Errorf("FormatFor(%q) = %s, expected %s", tt.name, format.Extension(), tt.expected)
+		}
+	}
+}
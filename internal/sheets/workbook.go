@@ -0,0 +1,73 @@
+// Package sheets imports and exports bot data as spreadsheet-style workbooks.
+//
+// Data is exchanged as a Workbook of named sheets, each a grid of text cells
+// whose first row is a header. File formats (CSV, XLSX) convert files to and
+// from workbooks; other backends, such as an online spreadsheet service, only
+// need to do the same to reuse the importer.
+package sheets
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Sheet is a named grid of cells; the first row is the header
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
+
+// Header returns the normalized column names of the sheet
+func (s *Sheet) Header() []string {
+	if len(s.Rows) == 0 {
+		return nil
+	}
+	header := make([]string, len(s.Rows[0]))
+	for i, column := range s.Rows[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+	}
+	return header
+}
+
+// Workbook is an ordered set of sheets
+type Workbook struct {
+	Sheets []*Sheet
+}
+
+// Sheet returns the sheet with the given name, ignoring case, or nil
+func (w *Workbook) Sheet(name string) *Sheet {
+	for _, sheet := range w.Sheets {
+		if strings.EqualFold(sheet.Name, name) {
+			return sheet
+		}
+	}
+	return nil
+}
+
+// Format converts workbooks to and from a file format
+type Format interface {
+	// Extension returns the file extension including the dot, e.g. ".csv"
+	Extension() string
+	// Decode reads a workbook from a file; name is the file name
+	Decode(data []byte, name string) (*Workbook, error)
+	// Encode writes a workbook to a file
+	Encode(workbook *Workbook) ([]byte, error)
+}
+
+// formats are the supported file formats
+var formats = []Format{CSV{}, XLSX{}}
+
+// FormatFor returns the format of a file name or extension such as "xlsx"
+func FormatFor(name string) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == "" {
+		ext = "." + strings.ToLower(name)
+	}
+	for _, format := range formats {
+		if format.Extension() == ext {
+			return format, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported file type %q: use .csv or .xlsx", ext)
+}
@@ -0,0 +1,387 @@
+package sheets
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Office Open XML namespaces and content types
+const (
+	xlsxMainNS          = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xlsxRelationshipsNS = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	xlsxPackageRelsNS   = "http://schemas.openxmlformats.org/package/2006/relationships"
+)
+
+// maxXLSXPartSize bounds the uncompressed size of a single part of an XLSX file
+const maxXLSXPartSize = 16 << 20
+
+// XLSX holds one sheet per worksheet of an Excel workbook.
+// Only cell values are read and written; formatting is ignored.
+type XLSX struct{}
+
+// Extension returns ".xlsx"
+func (XLSX) Extension() string {
+	return ".xlsx"
+}
+
+// xlsxRichText is a shared or inline string, either plain or split into runs
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String returns the text of a string with its runs joined
+func (t *xlsxRichText) String() string {
+	var b strings.Builder
+	b.WriteString(t.Text)
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+type xlsxWorkbookXML struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationshipsXML struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStringsXML struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxStylesXML struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxWorksheetXML struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string        `xml:"r,attr"`
+			Type   string        `xml:"t,attr"`
+			Style  int           `xml:"s,attr"`
+			Value  string        `xml:"v"`
+			Inline *xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// Decode reads every worksheet of an XLSX file
+func (XLSX) Decode(data []byte, name string) (*Workbook, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid XLSX file: %w", name, err)
+	}
+	parts := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		parts[file.Name] = file
+	}
+
+	readXML := func(partName string, v any, required bool) error {
+		file, exists := parts[partName]
+		if !exists {
+			if required {
+				return fmt.Errorf("%s is not a valid XLSX file: missing %s", name, partName)
+			}
+			return nil
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		content, err := io.ReadAll(io.LimitReader(reader, maxXLSXPartSize+1))
+		if err != nil {
+			return err
+		}
+		if len(content) > maxXLSXPartSize {
+			return fmt.Errorf("%s: %s is too large", name, partName)
+		}
+		if err := xml.Unmarshal(content, v); err != nil {
+			return fmt.Errorf("%s: invalid %s: %w", name, partName, err)
+		}
+		return nil
+	}
+
+	var workbookXML xlsxWorkbookXML
+	if err := readXML("xl/workbook.xml", &workbookXML, true); err != nil {
+		return nil, err
+	}
+	var relsXML xlsxRelationshipsXML
+	if err := readXML("xl/_rels/workbook.xml.rels", &relsXML, true); err != nil {
+		return nil, err
+	}
+	var sharedStrings xlsxSharedStringsXML
+	if err := readXML("xl/sharedStrings.xml", &sharedStrings, false); err != nil {
+		return nil, err
+	}
+	var styles xlsxStylesXML
+	if err := readXML("xl/styles.xml", &styles, false); err != nil {
+		return nil, err
+	}
+	dateStyles := xlsxDateStyles(&styles)
+
+	targets := make(map[string]string, len(relsXML.Relationships))
+	for _, rel := range relsXML.Relationships {
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join("xl", target)
+		}
+		targets[rel.ID] = target
+	}
+
+	workbook := &Workbook{}
+	for _, sheetInfo := range workbookXML.Sheets {
+		target, exists := targets[sheetInfo.RID]
+		if !exists {
+			return nil, fmt.Errorf("%s: sheet %q has no worksheet", name, sheetInfo.Name)
+		}
+		var worksheet xlsxWorksheetXML
+		if err := readXML(target, &worksheet, true); err != nil {
+			return nil, err
+		}
+
+		sheet := &Sheet{Name: sheetInfo.Name}
+		for i, row := range worksheet.Rows {
+			rowIndex := row.Index - 1
+			if row.Index == 0 {
+				rowIndex = i
+			}
+			for len(sheet.Rows) <= rowIndex {
+				sheet.Rows = append(sheet.Rows, nil)
+			}
+
+			cells := sheet.Rows[rowIndex]
+			for j, cell := range row.Cells {
+				column := j
+				if cell.Ref != "" {
+					if column, err = xlsxColumnIndex(cell.Ref); err != nil {
+						return nil, fmt.Errorf("%s: sheet %q: %w", name, sheetInfo.Name, err)
+					}
+				}
+				for len(cells) <= column {
+					cells = append(cells, "")
+				}
+
+				switch cell.Type {
+				case "s":
+					index, err := strconv.Atoi(cell.Value)
+					if err != nil || index < 0 || index >= len(sharedStrings.Items) {
+						return nil, fmt.Errorf("%s: sheet %q: cell %s refers to a missing shared string", name, sheetInfo.Name, cell.Ref)
+					}
+					cells[column] = sharedStrings.Items[index].String()
+				case "inlineStr":
+					if cell.Inline != nil {
+						cells[column] = cell.Inline.String()
+					}
+				case "b":
+					cells[column] = strconv.FormatBool(cell.Value == "1")
+				default:
+					cells[column] = cell.Value
+					if cell.Type == "" && dateStyles[cell.Style] {
+						cells[column] = xlsxFormatDate(cell.Value)
+					}
+				}
+			}
+			sheet.Rows[rowIndex] = cells
+		}
+		workbook.Sheets = append(workbook.Sheets, sheet)
+	}
+	return workbook, nil
+}
+
+// xlsxColumnIndex returns the 0-based column of a cell reference such as "AB12"
+func xlsxColumnIndex(ref string) (int, error) {
+	column := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A') + 1
+		letters++
+	}
+	if letters == 0 || letters > 3 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return column - 1, nil
+}
+
+// xlsxColumnName returns the letters of a 0-based column, e.g. 27 is "AB"
+func xlsxColumnName(column int) string {
+	var name []byte
+	for column++; column > 0; column = (column - 1) / 26 {
+		name = append([]byte{byte('A' + (column-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+// xlsxDateFormatPattern matches number format codes that display dates or times
+var xlsxDateFormatPattern = regexp.MustCompile(`[dmyhs]`)
+
+// xlsxFormatLiteralPattern matches quoted text and bracketed colors in number format codes
+var xlsxFormatLiteralPattern = regexp.MustCompile(`"[^"]*"|\[[^\]]*\]`)
+
+// xlsxDateStyles reports which cell styles format numbers as dates
+func xlsxDateStyles(styles *xlsxStylesXML) map[int]bool {
+	custom := make(map[int]string, len(styles.NumFmts))
+	for _, numFmt := range styles.NumFmts {
+		custom[numFmt.ID] = numFmt.Code
+	}
+
+	dates := make(map[int]bool)
+	for index, xf := range styles.CellXfs {
+		id := xf.NumFmtID
+		// Built-in date and time formats
+		if (id >= 14 && id <= 22) || (id >= 45 && id <= 47) {
+			dates[index] = true
+			continue
+		}
+		if code, exists := custom[id]; exists {
+			code = xlsxFormatLiteralPattern.ReplaceAllString(strings.ToLower(code), "")
+			dates[index] = xlsxDateFormatPattern.MatchString(code)
+		}
+	}
+	return dates
+}
+
+// xlsxEpoch is day zero of Excel date serial numbers (1900 date system)
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// xlsxFormatDate converts a date serial number to "2006-01-02" or "2006-01-02 15:04"
+func xlsxFormatDate(value string) string {
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil || serial < 0 {
+		return value
+	}
+	days, fraction := math.Modf(serial)
+	minutes := math.Round(fraction * 24 * 60)
+	t := xlsxEpoch.AddDate(0, 0, int(days)).Add(time.Duration(minutes) * time.Minute)
+	if minutes == 0 {
+		return t.Format("2006-01-02")
+	}
+	if days == 0 {
+		return t.Format("15:04")
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// xlsxNumberPattern matches values written as numeric cells
+var xlsxNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]{0,14})$`)
+
+// Encode writes a workbook as an XLSX file with one worksheet per sheet
+func (XLSX) Encode(workbook *Workbook) ([]byte, error) {
+	if len(workbook.Sheets) == 0 {
+		return nil, errors.New("a workbook needs at least one sheet")
+	}
+
+	var b bytes.Buffer
+	archive := zip.NewWriter(&b)
+	write := func(name, content string) error {
+		w, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, xml.Header+content)
+		return err
+	}
+
+	var contentTypes, sheets, rels strings.Builder
+	contentTypes.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	rels.WriteString(`<Relationships xmlns="` + xlsxPackageRelsNS + `">`)
+
+	for i, sheet := range workbook.Sheets {
+		n := i + 1
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(sheet.Name), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="%s/worksheet" Target="worksheets/sheet%d.xml"/>`, n, xlsxRelationshipsNS, n)
+
+		if err := write(fmt.Sprintf("xl/worksheets/sheet%d.xml", n), xlsxWorksheet(sheet)); err != nil {
+			return nil, err
+		}
+	}
+	contentTypes.WriteString(`</Types>`)
+	rels.WriteString(`</Relationships>`)
+
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", `<Relationships xmlns="` + xlsxPackageRelsNS + `">` +
+			`<Relationship Id="rId1" Type="` + xlsxRelationshipsNS + `/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="` + xlsxMainNS + `" xmlns:r="` + xlsxRelationshipsNS + `">` +
+			`<sheets>` + sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+	} {
+		if err := write(part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// xlsxWorksheet renders the XML of a worksheet. Integers are written as numbers
+// and everything else as inline strings.
+func xlsxWorksheet(sheet *Sheet) string {
+	var b strings.Builder
+	b.WriteString(`<worksheet xmlns="` + xlsxMainNS + `"><sheetData>`)
+	for i, row := range sheet.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, i+1)
+		for j, value := range row {
+			if value == "" {
+				continue
+			}
+			ref := xlsxColumnName(j) + strconv.Itoa(i+1)
+			if xlsxNumberPattern.MatchString(value) {
+				fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, value)
+				continue
+			}
+			fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, xmlEscape(value))
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// xmlEscape escapes text for use in XML content and attributes
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MemoryStore is an in-memory Store implementation
+type MemoryStore struct {
+	mu          sync.RWMutex
+	collections map[string]map[string][]byte
+}
+
+// NewMemoryStore creates a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		collections: make(map[string]map[string][]byte),
+	}
+}
+
+// Get loads the document stored under collection/key into v
+func (s *MemoryStore) Get(ctx context.Context, collection, key string, v any) error {
+	s.mu.RLock()
+	data, exists := s.collections[collection][key]
+	s.mu.RUnlock()
+
+	if !exists {
+		return ErrNotFound
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s/%s: %w", collection, key, err)
+	}
+	return nil
+}
+
+// Put stores v as the document under collection/key
+func (s *MemoryStore) Put(ctx context.Context, collection, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s/%s: %w", collection, key, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.collections[collection] == nil {
+		s.collections[collection] = make(map[string][]byte)
+	}
+	s.collections[collection][key] = data
+	return nil
+}
+
+// Delete removes the document under collection/key
+func (s *MemoryStore) Delete(ctx context.Context, collection, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.collections[collection][key]; !exists {
+		return ErrNotFound
+	}
+	delete(s.collections[collection], key)
+	return nil
+}
+
+// Keys returns all document keys in a collection
+func (s *MemoryStore) Keys(ctx context.Context, collection string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.collections[collection]))
+	for key := range s.collections[collection] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Ping always succeeds for the in-memory store
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryStore) Close() error {
+	return nil
+}
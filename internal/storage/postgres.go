@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/varubogu/gbf_discord_bot_go/internal/config"
+)
+
+// schema creates the document table used by PostgresStore
+const schema = `
+CREATE TABLE IF NOT EXISTS bot_documents (
+	collection TEXT NOT NULL,
+	key        TEXT NOT NULL,
+	value      JSONB NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (collection, key)
+)`
+
+// PostgresStore is a Store backed by a PostgreSQL table
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore connects to PostgreSQL and ensures the schema exists
+func NewPostgresStore(ctx context.Context, cfg *config.Config) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", buildDSN(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(10)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	return &PostgresStore{db: db}, nil
+}
+
+// buildDSN builds a PostgreSQL connection URL from configuration
+func buildDSN(cfg *config.Config) string {
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(cfg.DBUser, cfg.DBPassword),
+		Host:   cfg.DBHost + ":" + cfg.DBPort,
+		Path:   cfg.DBName,
+	}
+	query := dsn.Query()
+	query.Set("sslmode", "disable")
+	dsn.RawQuery = query.Encode()
+	return dsn.String()
+}
+
+// Get loads the document stored under collection/key into v
+func (s *PostgresStore) Get(ctx context.Context, collection, key string, v any) error {
+	var data []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT value FROM bot_documents WHERE collection = $1 AND key = $2`,
+		collection, key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query %s/%s: %w", collection, key, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s/%s: %w", collection, key, err)
+	}
+	return nil
+}
+
+// Put stores v as the document under collection/key
+func (s *PostgresStore) Put(ctx context.Context, collection, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s/%s: %w", collection, key, err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO bot_documents (collection, key, value, updated_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (collection, key) DO UPDATE SET value = EXCLUDED.value, updated_at = now()`,
+		collection, key, data)
+	if err != nil {
+		return fmt.Errorf("failed to store %s/%s: %w", collection, key, err)
+	}
+	return nil
+}
+
+// Delete removes the document under collection/key
+func (s *PostgresStore) Delete(ctx context.Context, collection, key string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM bot_documents WHERE collection = $1 AND key = $2`,
+		collection, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s/%s: %w", collection, key, err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Keys returns all document keys in a collection
+func (s *PostgresStore) Keys(ctx context.Context, collection string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT key FROM bot_documents WHERE collection = $1 ORDER BY key`,
+		collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", collection, err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", collection, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// Ping checks that the database is reachable
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// Close closes the database connection pool
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
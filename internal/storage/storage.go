@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/config"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+)
+
+// ErrNotFound is returned when a document does not exist
+var ErrNotFound = errors.New("document not found")
+
+// Store persists JSON documents grouped by collection
+type Store interface {
+	// Get loads the document stored under collection/key into v
+	Get(ctx context.Context, collection, key string, v any) error
+	// Put stores v as the document under collection/key
+	Put(ctx context.Context, collection, key string, v any) error
+	// Delete removes the document under collection/key
+	Delete(ctx context.Context, collection, key string) error
+	// Keys returns all document keys in a collection
+	Keys(ctx context.Context, collection string) ([]string, error)
+	// Ping checks that the backing storage is reachable
+	Ping(ctx context.Context) error
+	// Close releases the underlying resources
+	Close() error
+}
+
+// Open creates the store configured by cfg.
+// A PostgreSQL store is used when a database name is configured, otherwise
+// documents are kept in memory and lost on restart.
+func Open(ctx context.Context, cfg *config.Config, logger *log.Logger) (Store, error) {
+	if cfg.DBName == "" {
+		logger.Warn("DB_NAME is not set, using in-memory storage")
+		return NewMemoryStore(), nil
+	}
+
+	store, err := NewPostgresStore(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	logger.Info("Connected to database", "host", cfg.DBHost, "database", cfg.DBName)
+	return store, nil
+}
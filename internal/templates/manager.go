@@ -0,0 +1,226 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+// templatesCollection is the storage collection holding each guild's template overrides
+const templatesCollection = "message_templates"
+
+// ErrNotCustomized is returned when resetting a template the guild has not overridden
+var ErrNotCustomized = errors.New("this template is not customized")
+
+// Override is a guild's replacement for a built-in template
+type Override struct {
+	Text      string    `json:"text"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GuildTemplates is the stored set of a guild's template overrides
+type GuildTemplates struct {
+	GuildID   string              `json:"guild_id"`
+	Templates map[string]Override `json:"templates"`
+}
+
+// Manager renders templates with per-guild overrides, caching overrides loaded from storage
+type Manager struct {
+	store  storage.Store
+	logger *log.Logger
+
+	// writeMu serializes changes so that concurrent updates are not lost
+	writeMu sync.Mutex
+
+	mu     sync.RWMutex
+	guilds map[string]*GuildTemplates
+}
+
+// NewManager creates a new template manager
+func NewManager(store storage.Store, logger *log.Logger) *Manager {
+	return &Manager{
+		store:  store,
+		logger: logger,
+		guilds: make(map[string]*GuildTemplates),
+	}
+}
+
+// load returns the cached overrides of a guild, loading them from storage on first use.
+// The returned value must not be modified.
+func (m *Manager) load(ctx context.Context, guildID string) (*GuildTemplates, error) {
+	m.mu.RLock()
+	guild, cached := m.guilds[guildID]
+	m.mu.RUnlock()
+	if cached {
+		return guild, nil
+	}
+
+	guild = &GuildTemplates{GuildID: guildID}
+	err := m.store.Get(ctx, templatesCollection, guildID, guild)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load templates for guild %s: %w", guildID, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.guilds[guildID] = guild
+	return guild, nil
+}
+
+// Overrides returns a copy of a guild's template overrides by template name
+func (m *Manager) Overrides(ctx context.Context, guildID string) (map[string]Override, error) {
+	guild, err := m.load(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	return maps.Clone(guild.Templates), nil
+}
+
+// Text returns the source of a template as used by a guild and whether it is an override.
+// Without an override it is the default text of locale.
+func (m *Manager) Text(ctx context.Context, guildID string, locale i18n.Locale, name string) (string, bool, error) {
+	definition := lookup(name)
+	if definition == nil {
+		return "", false, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	guild, err := m.load(ctx, guildID)
+	if err != nil {
+		return "", false, err
+	}
+	if override, exists := guild.Templates[name]; exists {
+		return override.Text, true, nil
+	}
+	return definition.In(locale).Text, false, nil
+}
+
+// Set validates text and saves it as a guild's override of the named template
+func (m *Manager) Set(ctx context.Context, guildID, name, text, userID string) error {
+	if strings.TrimSpace(text) == "" {
+		return errors.New("template text is required")
+	}
+	if err := Validate(name, text); err != nil {
+		return err
+	}
+
+	return m.update(ctx, guildID, func(overrides map[string]Override) error {
+		overrides[name] = Override{Text: text, UpdatedBy: userID, UpdatedAt: time.Now()}
+		return nil
+	})
+}
+
+// Reset removes a guild's override of the named template
+func (m *Manager) Reset(ctx context.Context, guildID, name string) error {
+	if lookup(name) == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	return m.update(ctx, guildID, func(overrides map[string]Override) error {
+		if _, exists := overrides[name]; !exists {
+			return ErrNotCustomized
+		}
+		delete(overrides, name)
+		return nil
+	})
+}
+
+// Replace validates and saves texts as the complete set of a guild's overrides.
+// Templates missing from texts, or whose text is empty or equals a default, use the default.
+// Unchanged overrides keep their author.
+func (m *Manager) Replace(ctx context.Context, guildID string, texts map[string]string, userID string) error {
+	for name, text := range texts {
+		if err := Validate(name, text); err != nil {
+			return err
+		}
+	}
+
+	return m.update(ctx, guildID, func(overrides map[string]Override) error {
+		previous := maps.Clone(overrides)
+		clear(overrides)
+		for name, text := range texts {
+			if text == "" || lookup(name).IsDefault(text) {
+				continue
+			}
+			if old, exists := previous[name]; exists && old.Text == text {
+				overrides[name] = old
+				continue
+			}
+			overrides[name] = Override{Text: text, UpdatedBy: userID, UpdatedAt: time.Now()}
+		}
+		return nil
+	})
+}
+
+// update applies fn to a copy of a guild's overrides and saves the result
+func (m *Manager) update(ctx context.Context, guildID string, fn func(overrides map[string]Override) error) error {
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	guild, err := m.load(ctx, guildID)
+	if err != nil {
+		return err
+	}
+	next := &GuildTemplates{GuildID: guildID, Templates: maps.Clone(guild.Templates)}
+	if next.Templates == nil {
+		next.Templates = make(map[string]Override)
+	}
+	if err := fn(next.Templates); err != nil {
+		return err
+	}
+
+	if err := m.store.Put(ctx, templatesCollection, guildID, next); err != nil {
+		return fmt.Errorf("failed to save templates for guild %s: %w", guildID, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.guilds[guildID] = next
+	return nil
+}
+
+// Render renders the named template for a guild, using the built-in default of
+// locale unless the guild overrides it. If the override fails to render, the
+// failure is logged and the default is used instead.
+func (m *Manager) Render(ctx context.Context, guildID string, locale i18n.Locale, name string, data any) (string, error) {
+	definition := lookup(name)
+	if definition == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+
+	if guildID != "" {
+		guild, err := m.load(ctx, guildID)
+		if err != nil {
+			m.logger.WithGuild(guildID).WithError(err).Warn("Failed to load templates, using default", "template", name)
+		} else if override, exists := guild.Templates[name]; exists {
+			rendered, err := renderText(name, override.Text, data)
+			if err == nil {
+				return rendered, nil
+			}
+			m.logger.WithGuild(guildID).WithError(err).Warn("Failed to render template override, using default", "template", name)
+		}
+	}
+	return definition.In(locale).render(data)
+}
+
+// renderText parses and renders a template source
+func renderText(name, text string, data any) (string, error) {
+	tmpl, err := parse(name, text)
+	if err != nil {
+		return "", err
+	}
+	return execute(tmpl, data)
+}
+
+// Reload drops the cached overrides so that they are read from storage again
+func (m *Manager) Reload() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.guilds = make(map[string]*GuildTemplates)
+}
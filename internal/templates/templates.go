@@ -0,0 +1,441 @@
+// Package templates renders the bot's user-facing messages from text/template
+// sources. Every message has a built-in default that a guild may override.
+package templates
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"text/template"
+	parsetree "text/template/parse"
+	"time"
+	"unicode/utf8"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the built-in templates
+const (
+	RecruitmentPosted = "recruitment.posted"
+	RecruitmentJoined = "recruitment.joined"
+	RecruitmentLeft   = "recruitment.left"
+	RecruitmentFull   = "recruitment.full"
+	RecruitmentClosed = "recruitment.closed"
+	CalendarStart     = "calendar.start"
+	CalendarEnd       = "calendar.end"
+	PermissionDenied  = "permission.denied"
+)
+
+// MaxLength is the longest message a template may render, Discord's message limit
+const MaxLength = 2000
+
+// ErrUnknownTemplate is returned for a template name that has no default
+var ErrUnknownTemplate = errors.New("unknown template")
+
+// GuildWarNotice returns the name of the template of a Guild War notification kind
+func GuildWarNotice(kind string) string {
+	return "gw." + kind
+}
+
+// RecruitmentData is the data available to recruitment.* templates
+type RecruitmentData struct {
+	Title      string
+	BattleID   string
+	BattleName string
+	// Host is the user ID of the recruitment host
+	Host string
+	// User is the user ID of the member who joined or left
+	User       string
+	Players    int
+	MaxPlayers int
+	// Participants are the user IDs of every participant, host first
+	Participants []string
+}
+
+// GuildWarData is the data available to gw.* templates
+type GuildWarData struct {
+	Kind string
+	// Day is the finals day number, or 0 for other notifications
+	Day        int
+	At         time.Time
+	PhaseStart time.Time
+	PhaseEnd   time.Time
+	EventStart time.Time
+	EventEnd   time.Time
+}
+
+// CalendarData is the data available to calendar.* templates
+type CalendarData struct {
+	Name  string
+	Kind  string
+	Start time.Time
+	End   time.Time
+	// Battles lists the names of the battles opened by the event, comma separated
+	Battles string
+}
+
+// PermissionData is the data available to permission.* templates
+type PermissionData struct {
+	Command string
+	Reason  string
+}
+
+// jst is the time zone template times are shown in
+var jst = time.FixedZone("JST", 9*60*60)
+
+// Funcs are the helper functions available to every template
+var Funcs = template.FuncMap{
+	// jst formats a time as "01/02 15:04" in JST
+	"jst": func(t time.Time) string {
+		return t.In(jst).Format("01/02 15:04")
+	},
+	// mention formats a user ID as a mention
+	"mention": func(userID string) string {
+		return "<@" + userID + ">"
+	},
+}
+
+// samples are the data each template group is validated and previewed with
+var samples = map[string]any{
+	"recruitment": RecruitmentData{
+		Title:        "Lucilius (Hard)",
+		BattleID:     "faa_hl",
+		BattleName:   "Lucilius (Hard)",
+		Host:         "100000000000000001",
+		User:         "100000000000000002",
+		Players:      2,
+		MaxPlayers:   6,
+		Participants: []string{"100000000000000001", "100000000000000002"},
+	},
+	"gw": GuildWarData{
+		Kind:       "finals_start",
+		Day:        1,
+		At:         time.Date(2026, 3, 4, 7, 0, 0, 0, jst),
+		PhaseStart: time.Date(2026, 3, 4, 7, 0, 0, 0, jst),
+		PhaseEnd:   time.Date(2026, 3, 5, 0, 0, 0, 0, jst),
+		EventStart: time.Date(2026, 3, 1, 19, 0, 0, 0, jst),
+		EventEnd:   time.Date(2026, 3, 8, 23, 59, 0, 0, jst),
+	},
+	"calendar": CalendarData{
+		Name:    "Dread Barrage",
+		Kind:    "dread_barrage",
+		Start:   time.Date(2026, 3, 20, 19, 0, 0, 0, jst),
+		End:     time.Date(2026, 3, 27, 23, 59, 0, 0, jst),
+		Battles: "Dread Barrage Lv95, Dread Barrage Lv135",
+	},
+	"permission": PermissionData{
+		Command: "reload",
+		Reason:  "Requires the Manage Server permission.",
+	},
+}
+
+// Definition is a built-in template
+type Definition struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Text        string `yaml:"text"`
+	// Translations are the description and default text in other locales
+	Translations map[i18n.Locale]Translation `yaml:"translations"`
+
+	locale i18n.Locale
+	parsed map[i18n.Locale]*template.Template
+}
+
+// Translation is the description and default text of a template in another locale
+type Translation struct {
+	Description string `yaml:"description"`
+	Text        string `yaml:"text"`
+}
+
+// In returns the definition with the description and default text of a locale,
+// keeping the English ones where no translation exists
+func (d Definition) In(locale i18n.Locale) Definition {
+	if translation, exists := d.Translations[locale]; exists {
+		d.Description = translation.Description
+		d.Text = translation.Text
+		d.locale = locale
+	}
+	return d
+}
+
+// IsDefault reports whether text is the default text of the template in any locale
+func (d Definition) IsDefault(text string) bool {
+	if text == d.Text {
+		return true
+	}
+	for _, translation := range d.Translations {
+		if text == translation.Text {
+			return true
+		}
+	}
+	return false
+}
+
+// render renders the default text of the definition's locale
+func (d Definition) render(data any) (string, error) {
+	tmpl, exists := d.parsed[d.locale]
+	if !exists {
+		tmpl = d.parsed[i18n.Default]
+	}
+	return execute(tmpl, data)
+}
+
+//go:embed data/defaults.yaml
+var defaultsYAML []byte
+
+// definitions are the built-in templates in display order
+var definitions = mustLoadDefinitions(defaultsYAML)
+
+// mustLoadDefinitions parses the embedded defaults, panicking if any is invalid
+func mustLoadDefinitions(data []byte) []*Definition {
+	var file struct {
+		Templates []*Definition `yaml:"templates"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		panic(fmt.Sprintf("invalid built-in templates: %v", err))
+	}
+
+	seen := make(map[string]bool, len(file.Templates))
+	for _, definition := range file.Templates {
+		if seen[definition.Name] {
+			panic(fmt.Sprintf("duplicate built-in template %s", definition.Name))
+		}
+		seen[definition.Name] = true
+
+		texts := map[i18n.Locale]string{i18n.Default: definition.Text}
+		for locale, translation := range definition.Translations {
+			if _, supported := i18n.Parse(string(locale)); !supported {
+				panic(fmt.Sprintf("built-in template %s has a translation for unsupported locale %q", definition.Name, locale))
+			}
+			texts[locale] = translation.Text
+		}
+		definition.parsed = make(map[i18n.Locale]*template.Template, len(texts))
+		for locale, text := range texts {
+			parsed, err := parse(definition.Name, text)
+			if err != nil {
+				panic(fmt.Sprintf("invalid built-in template (%s): %v", locale, err))
+			}
+			if _, err := execute(parsed, sampleFor(definition.Name)); err != nil {
+				panic(fmt.Sprintf("invalid built-in template (%s): %v", locale, err))
+			}
+			definition.parsed[locale] = parsed
+		}
+	}
+	return file.Templates
+}
+
+// Definitions returns the built-in templates in display order
+func Definitions() []Definition {
+	list := make([]Definition, len(definitions))
+	for i, definition := range definitions {
+		list[i] = *definition
+	}
+	return list
+}
+
+// Lookup returns the built-in template with the given name
+func Lookup(name string) (Definition, bool) {
+	definition := lookup(name)
+	if definition == nil {
+		return Definition{}, false
+	}
+	return *definition, true
+}
+
+// lookup returns the built-in template with the given name, or nil
+func lookup(name string) *Definition {
+	for _, definition := range definitions {
+		if definition.Name == name {
+			return definition
+		}
+	}
+	return nil
+}
+
+// sampleFor returns the sample data of a template's group
+func sampleFor(name string) any {
+	group, _, _ := strings.Cut(name, ".")
+	sample, exists := samples[group]
+	if !exists {
+		panic(fmt.Sprintf("no sample data for template %s", name))
+	}
+	return sample
+}
+
+// parse parses a template source with the helper functions and charges its
+// loops to the iteration budget of execute
+func parse(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(Funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	if err := guard(tmpl.Tree, tmpl.Root); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// maxIterations bounds the loop iterations of a single render. The output cap
+// alone does not stop loops that print nothing, such as nested {{range 100000000}}.
+const maxIterations = 10000
+
+// iterationsFunc is appended by guard to the pipeline of every range so that
+// execute can count its iterations
+const iterationsFunc = "_iterations"
+
+// errTooManyIterations is returned once the loops of a template run more than
+// maxIterations times
+var errTooManyIterations = fmt.Errorf("loops run more than %d times", maxIterations)
+
+// guard walks a parsed template, routing every range through iterationsFunc.
+// Calls of other templates are rejected, as they can recurse without bound.
+func guard(tree *parsetree.Tree, node parsetree.Node) error {
+	switch node := node.(type) {
+	case *parsetree.ListNode:
+		if node == nil {
+			return nil
+		}
+		for _, child := range node.Nodes {
+			if err := guard(tree, child); err != nil {
+				return err
+			}
+		}
+	case *parsetree.IfNode:
+		return guardBranch(tree, &node.BranchNode)
+	case *parsetree.WithNode:
+		return guardBranch(tree, &node.BranchNode)
+	case *parsetree.RangeNode:
+		node.Pipe.Cmds = append(node.Pipe.Cmds, &parsetree.CommandNode{
+			NodeType: parsetree.NodeCommand,
+			Pos:      node.Pipe.Pos,
+			Args:     []parsetree.Node{parsetree.NewIdentifier(iterationsFunc).SetTree(tree).SetPos(node.Pipe.Pos)},
+		})
+		return guardBranch(tree, &node.BranchNode)
+	case *parsetree.TemplateNode:
+		return errors.New("templates cannot call other templates")
+	}
+	return nil
+}
+
+// guardBranch guards both lists of an if, with or range
+func guardBranch(tree *parsetree.Tree, branch *parsetree.BranchNode) error {
+	if err := guard(tree, branch.List); err != nil {
+		return err
+	}
+	return guard(tree, branch.ElseList)
+}
+
+// iterations returns how many times a range over v runs
+func iterations(v reflect.Value) int64 {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return 0
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return max(v.Int(), 0)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(min(v.Uint(), math.MaxInt64))
+	case reflect.Array, reflect.Slice, reflect.Map, reflect.Chan:
+		return int64(v.Len())
+	}
+	return 0
+}
+
+// maxOutputBytes stops rendering long before a template can exhaust memory;
+// MaxLength characters take at most 4 bytes each
+const maxOutputBytes = 4 * MaxLength
+
+// errOutputTooLong is returned once a template renders more than maxOutputBytes
+var errOutputTooLong = fmt.Errorf("output exceeds %d characters", MaxLength)
+
+// limitedBuilder is a strings.Builder that fails once it holds maxOutputBytes
+type limitedBuilder struct {
+	strings.Builder
+}
+
+func (b *limitedBuilder) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > maxOutputBytes {
+		return 0, errOutputTooLong
+	}
+	return b.Builder.Write(p)
+}
+
+// execute renders a parsed template, failing as soon as the output grows past
+// maxOutputBytes or its loops run more than maxIterations times
+func execute(tmpl *template.Template, data any) (string, error) {
+	budget := int64(maxIterations)
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	tmpl.Funcs(template.FuncMap{
+		iterationsFunc: func(v reflect.Value) (reflect.Value, error) {
+			budget -= iterations(v)
+			if budget < 0 {
+				return v, errTooManyIterations
+			}
+			return v, nil
+		},
+	})
+
+	var b limitedBuilder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// Validate checks that text parses and renders the sample data of the named
+// template within the message length limit
+func Validate(name, text string) error {
+	_, err := Preview(name, text)
+	return err
+}
+
+// Preview renders text as the named template with its sample data.
+// An empty text previews the built-in default.
+func Preview(name, text string) (string, error) {
+	definition := lookup(name)
+	if definition == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	if text == "" {
+		return definition.render(sampleFor(name))
+	}
+
+	tmpl, err := parse(name, text)
+	if err != nil {
+		return "", err
+	}
+	// Executing against the sample catches references to fields that do not exist
+	rendered, err := execute(tmpl, sampleFor(name))
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(rendered) == "" {
+		return "", fmt.Errorf("template %s renders an empty message", name)
+	}
+	if length := utf8.RuneCountInString(rendered); length > MaxLength {
+		return "", fmt.Errorf("template %s renders %d characters, at most %d are allowed", name, length, MaxLength)
+	}
+	return rendered, nil
+}
+
+// Render renders the built-in default of the named template in a locale
+func Render(name string, locale i18n.Locale, data any) (string, error) {
+	definition := lookup(name)
+	if definition == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	return definition.In(locale).render(data)
+}
@@ -0,0 +1,166 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/varubogu/gbf_discord_bot_go/internal/i18n"
+	"github.com/varubogu/gbf_discord_bot_go/internal/log"
+	"github.com/varubogu/gbf_discord_bot_go/internal/storage"
+)
+
+func TestDefinitions(t *testing.T) {
+	for _, definition := range Definitions() {
+		if definition.Description == "" {
+			t.Errorf("%s has no description", definition.Name)
+		}
+		if _, err := Preview(definition.Name, definition.Text); err != nil {
+			t.Errorf("default of %s: %v", definition.Name, err)
+		}
+	}
+
+	// Every name constant has a default
+	for _, name := range []string{RecruitmentPosted, RecruitmentJoined, RecruitmentLeft, RecruitmentFull,
+		RecruitmentClosed, CalendarStart, CalendarEnd, PermissionDenied, GuildWarNotice("finals_mid")} {
+		if _, exists := Lookup(name); !exists {
+			t.Errorf("no default for %s", name)
+		}
+	}
+}
+
+func TestPreview(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		text     string
+		expected string
+		wantErr  string
+	}{
+		{"default", PermissionDenied, "", "❌ You don't have permission to use this command. Requires the Manage Server permission.", ""},
+		{"override", RecruitmentJoined, "{{mention .User}} が参加 {{.Players}}/{{.MaxPlayers}}", "<@100000000000000002> が参加 2/6", ""},
+		{"functions", GuildWarNotice("finals_start"), "本戦{{.Day}}日目 {{jst .PhaseEnd}}まで", "本戦1日目 03/05 00:00まで", ""},
+		{"unknown template", "recruitment.cancelled", "text", "", "unknown template"},
+		{"syntax error", RecruitmentPosted, "{{.Title", "", "invalid template"},
+		{"unknown field", RecruitmentPosted, "{{.Battle}}", "", "can't evaluate field Battle"},
+		{"field of another group", CalendarStart, "{{.Host}}", "", "can't evaluate field Host"},
+		{"unknown function", CalendarEnd, "{{upper .Name}}", "", `function "upper" not defined`},
+		{"empty output", CalendarEnd, "{{if false}}x{{end}}", "", "renders an empty message"},
+		{"too long", CalendarEnd, strings.Repeat("あ", MaxLength+1), "", "at most 2000"},
+		{"runaway output", CalendarEnd, "{{range 5000}}xxxxxxxxxx{{end}}", "", "output exceeds 2000 characters"},
+		{"runaway loop", CalendarEnd, "{{range 100000000}}{{range 100000000}}{{end}}{{end}}x", "", "loops run more than 10000 times"},
+		{"loop over a variable", CalendarEnd, "{{$n := 100}}{{range $n}}{{range $n}}{{range $n}}{{end}}{{end}}{{end}}x", "", "loops run more than 10000 times"},
+		{"template call", CalendarEnd, `{{define "x"}}{{template "x" .}}{{template "x" .}}{{end}}{{template "x" .}}`, "", "cannot call other templates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Preview(tt.template, tt.text)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Preview() error = %v, expected %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Preview() unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Preview() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	m := NewManager(store, log.InitLogger("error"))
+	data := CalendarData{Name: "Dread Barrage"}
+
+	if err := m.Set(ctx, "guild1", CalendarEnd, "{{.Nmae}} 終了", "admin1"); err == nil {
+		t.Error("Set should reject a template that does not render")
+	}
+	if err := m.Set(ctx, "guild1", CalendarEnd, "**{{.Name}}** 終了", "admin1"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	got, err := m.Render(ctx, "guild1", i18n.English, CalendarEnd, data)
+	if err != nil || got != "**Dread Barrage** 終了" {
+		t.Errorf("Render(guild1) = %q, %v; expected the override", got, err)
+	}
+	got, err = m.Render(ctx, "guild2", i18n.English, CalendarEnd, data)
+	if err != nil || got != "🏁 **Dread Barrage** has ended." {
+		t.Errorf("Render(guild2) = %q, %v; expected the default", got, err)
+	}
+
+	// Overrides are persisted
+	reloaded := NewManager(store, log.InitLogger("error"))
+	text, custom, err := reloaded.Text(ctx, "guild1", i18n.English, CalendarEnd)
+	if err != nil || !custom || text != "**{{.Name}}** 終了" {
+		t.Errorf("Text() after reload = %q, %t, %v", text, custom, err)
+	}
+
+	// An override that fails on real data falls back to the default
+	if err := m.Set(ctx, "guild1", RecruitmentFull, "{{index .Participants 2}}", "admin1"); err == nil {
+		t.Error("Set should reject an index out of range of the sample")
+	}
+	if err := m.Set(ctx, "guild1", RecruitmentFull, "{{index .Participants 1}} fills it", "admin1"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, err = m.Render(ctx, "guild1", i18n.English, RecruitmentFull, RecruitmentData{Title: "Raid", Participants: []string{"host"}})
+	if err != nil || !strings.HasPrefix(got, "🟡 **Raid** is full!") {
+		t.Errorf("Render() with a failing override = %q, %v; expected the default", got, err)
+	}
+
+	if err := m.Reset(ctx, "guild1", CalendarEnd); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if err := m.Reset(ctx, "guild1", CalendarEnd); !errors.Is(err, ErrNotCustomized) {
+		t.Errorf("second Reset() error = %v, expected ErrNotCustomized", err)
+	}
+	if _, err := m.Render(ctx, "guild1", i18n.English, "nope", data); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("Render(nope) error = %v, expected ErrUnknownTemplate", err)
+	}
+}
+
+func TestManager_Replace(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(storage.NewMemoryStore(), log.InitLogger("error"))
+	if err := m.Set(ctx, "guild1", CalendarStart, "{{.Name}} 開始", "alice"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := m.Set(ctx, "guild1", CalendarEnd, "{{.Name}} 終了", "alice"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	before, _ := m.Overrides(ctx, "guild1")
+
+	defaultText := Definitions()[0].Text
+	err := m.Replace(ctx, "guild1", map[string]string{
+		CalendarStart:     "{{.Name}} 開始", // unchanged
+		RecruitmentPosted: defaultText,    // same as the default
+		PermissionDenied:  "権限がありません: {{.Reason}}",
+	}, "bob")
+	if err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+
+	overrides, _ := m.Overrides(ctx, "guild1")
+	if len(overrides) != 2 {
+		t.Fatalf("overrides = %+v, expected calendar.start and permission.denied", overrides)
+	}
+	if overrides[CalendarStart] != before[CalendarStart] {
+		t.Errorf("unchanged override = %+v, expected %+v", overrides[CalendarStart], before[CalendarStart])
+	}
+	if overrides[PermissionDenied].UpdatedBy != "bob" {
+		t.Errorf("new override = %+v, expected to be updated by bob", overrides[PermissionDenied])
+	}
+
+	if err := m.Replace(ctx, "guild1", map[string]string{CalendarEnd: "{{.Title}}"}, "bob"); err == nil {
+		t.Error("Replace should reject an invalid template")
+	}
+	if overrides, _ := m.Overrides(ctx, "guild1"); len(overrides) != 2 {
+		t.Errorf("a failed Replace changed the overrides: %+v", overrides)
+	}
+}
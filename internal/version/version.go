@@ -0,0 +1,41 @@
+package version
+
+import (
+	"runtime/debug"
+)
+
+// Build information, injected at link time:
+//
+//	go build -ldflags "-X github.com/varubogu/gbf_discord_bot_go/internal/version.Version=v0.2.0 \
+//	  -X github.com/varubogu/gbf_discord_bot_go/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/varubogu/gbf_discord_bot_go/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/bot
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// GetCommit returns the commit the binary was built from.
+// It falls back to the VCS information embedded by the Go toolchain.
+func GetCommit() string {
+	if Commit != "" {
+		return Commit
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && len(setting.Value) >= 7 {
+				return setting.Value[:7]
+			}
+		}
+	}
+	return "unknown"
+}
+
+// String returns a human readable version string
+func String() string {
+	if BuildDate != "" {
+		return Version + " (" + GetCommit() + ", built " + BuildDate + ")"
+	}
+	return Version + " (" + GetCommit() + ")"
+}